@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net"
 	"os"
+	"time"
 
 	"google.golang.org/grpc"
 	pb "lastmile/gen/go/driver"
@@ -26,7 +29,7 @@ func main() {
 	s := grpc.NewServer()
 
 	// Create a new driver server
-	driverServer := driver.NewServer(logger.With("component", "driver-server"))
+	driverServer := driver.NewServerWithRepository(driverRepository(logger), logger.With("component", "driver-server"))
 
 	// Register the driver server with the gRPC server
 	pb.RegisterDriverServiceServer(s, driverServer)
@@ -38,6 +41,25 @@ func main() {
 	}
 }
 
+// driverRepository keeps drivers and routes in Postgres when DRIVER_DSN (or
+// DATABASE_URL) is set, so restarted and parallel replicas serve the same
+// drivers, and in memory otherwise.
+func driverRepository(logger *slog.Logger) driver.Repository {
+	dsn := getenv("DRIVER_DSN", os.Getenv("DATABASE_URL"))
+	if dsn == "" {
+		return driver.NewMemoryRepository()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	repo, err := driver.NewPostgresRepository(ctx, dsn)
+	if err != nil {
+		logger.Error("driver store unavailable, keeping drivers in memory only", "err", err)
+		return driver.NewMemoryRepository()
+	}
+	logger.Info("driver store enabled")
+	return repo
+}
+
 func getenv(key, def string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -16,10 +17,13 @@ import (
 	driverpb "lastmile/gen/go/driver"
 	gatewaypb "lastmile/gen/go/gateway"
 	locationpb "lastmile/gen/go/location"
+	matchingpb "lastmile/gen/go/matching"
 	userpb "lastmile/gen/go/user"
 	"lastmile/internal/api"
 	"lastmile/internal/gateway"
+	"lastmile/internal/pkg/drivepath"
 	"lastmile/internal/pkg/logging"
+	"lastmile/internal/pkg/matchpolicy"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -50,6 +54,66 @@ func main() {
 	driverClient := driverpb.NewDriverServiceClient(driverConn)
 
 	gw := api.NewGateway(logger.With("component", "gateway-state"), driverClient, locClient, userClient)
+	if window := os.Getenv("MATCH_WINDOW"); window != "" {
+		if d, err := time.ParseDuration(window); err == nil {
+			gw.SetMatchWindow(d)
+		} else {
+			logger.Warn("invalid MATCH_WINDOW, using default", "value", window, "err", err)
+		}
+	}
+
+	// PRESENCE_IDLE_AFTER, PRESENCE_STALE_AFTER and PRESENCE_OFFLINE_AFTER set
+	// how long after their last heartbeat a driver changes presence state.
+	var presence api.PresenceConfig
+	for key, target := range map[string]*time.Duration{
+		"PRESENCE_IDLE_AFTER":    &presence.IdleAfter,
+		"PRESENCE_STALE_AFTER":   &presence.StaleAfter,
+		"PRESENCE_OFFLINE_AFTER": &presence.OfflineAfter,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if d, err := time.ParseDuration(raw); err == nil {
+				*target = d
+			} else {
+				logger.Warn("invalid "+key+", using default", "value", raw, "err", err)
+			}
+		}
+	}
+	gw.SetPresenceConfig(presence)
+	gw.WatchPresence(context.Background(), 15*time.Second)
+	gw.SetSimulatorConfig(simulatorConfig(logger))
+	gw.SetSimulatorToken(os.Getenv("SIMULATOR_TOKEN"))
+	gw.AttachDriverStatusFeed(context.Background(), driverClient)
+	gw.SetScheduleConfig(scheduleConfig(logger))
+	gw.SetRatingRule(ratingRule(logger))
+
+	if mode := os.Getenv("MATCH_MODE"); mode != "" {
+		batchWindow, _ := time.ParseDuration(os.Getenv("MATCH_BATCH_WINDOW"))
+		if err := gw.SetMatchMode(mode, batchWindow); err != nil {
+			logger.Warn("invalid MATCH_MODE, using greedy matching", "value", mode, "err", err)
+		}
+	}
+
+	// MATCH_POLICY picks the default ranking; MATCH_POLICY_STATIONS overrides
+	// it per station, e.g. "station-ecity=fairness,station-hsr=seat-fill".
+	if policy, perStation := os.Getenv("MATCH_POLICY"), os.Getenv("MATCH_POLICY_STATIONS"); policy != "" || perStation != "" {
+		overrides, err := matchpolicy.ParseStationPolicies(perStation)
+		if err == nil {
+			err = gw.SetMatchPolicy(policy, overrides)
+		}
+		if err != nil {
+			logger.Warn("invalid match policy config, ranking nearest first", "policy", policy, "stations", perStation, "err", err)
+		}
+	}
+
+	// Relay the matching service's event stream into /matching/watch when configured.
+	if matchingAddr := os.Getenv("MATCHING_ADDR"); matchingAddr != "" {
+		matchingConn, err := grpc.NewClient(matchingAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.Warn("failed to dial matching service", "err", err)
+		} else {
+			gw.AttachMatchingFeed(context.Background(), matchingpb.NewMatchingServiceClient(matchingConn))
+		}
+	}
 
 	hub := api.NewRealtimeHub(logger.With("component", "realtime-hub"))
 	defer hub.Close()
@@ -65,9 +129,19 @@ func main() {
 		} else {
 			defer store.Close()
 			gw.AttachStore(store)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := gw.LoadRouteTemplates(ctx); err != nil {
+				logger.Warn("failed to load route templates", "err", err)
+			}
+			if err := gw.LoadRatings(ctx); err != nil {
+				logger.Warn("failed to load ratings", "err", err)
+			}
+			cancel()
 		}
 	}
 
+	gw.RunRouteScheduler(context.Background())
+
 	grpcAddr := getenv("GATEWAY_GRPC_ADDR", ":50060")
 	httpAddr := getenv("GATEWAY_HTTP_ADDR", ":8082")
 
@@ -91,6 +165,8 @@ func main() {
 	httpMux := http.NewServeMux()
 	httpMux.HandleFunc("/aggregates/snapshot", gw.SnapshotHandler)
 	httpMux.HandleFunc("/matching/match", gw.MatchHandler)
+	httpMux.HandleFunc("/matching/batch", gw.BatchMatchHandler)
+	httpMux.HandleFunc("/matching/watch", gw.MatchWatchHandler)
 	httpMux.HandleFunc("/drivers/requests", gw.DriverRequestsHandler)
 	httpMux.HandleFunc("/drivers/requests/accept", gw.DriverAcceptHandler)
 	httpMux.HandleFunc("/rides/book", gw.BookRideHandler)
@@ -106,6 +182,17 @@ func main() {
 	httpMux.HandleFunc("/user/profile", gw.GetUserHandler)
 	httpMux.HandleFunc("/trips/pickup", gw.TripPickupHandler)
 	httpMux.HandleFunc("/trips/dropoff", gw.TripDropoffHandler)
+	httpMux.HandleFunc("/trips/leg", gw.TripLegHandler)
+	httpMux.HandleFunc("/trips/track", gw.TripTrackHandler)
+	httpMux.HandleFunc("/location/anomalies", gw.LocationAnomaliesHandler)
+	httpMux.HandleFunc("/drivers/presence", gw.DriverPresenceHandler)
+	httpMux.HandleFunc("/drivers/presence/watch", gw.DriverPresenceWatchHandler)
+	httpMux.HandleFunc("/drivers/status", gw.DriverStatusHandler)
+	httpMux.HandleFunc("/drivers/schedules", gw.DriverSchedulesHandler)
+	httpMux.HandleFunc("/drivers/vehicles", gw.DriverVehiclesHandler)
+	httpMux.HandleFunc("/drivers/vehicles/select", gw.DriverVehicleSelectHandler)
+	httpMux.HandleFunc("/trips/ratings", gw.TripRatingsHandler)
+	httpMux.HandleFunc("/drivers/itinerary", gw.DriverItineraryHandler)
 	httpMux.HandleFunc("/trips/simulate", gw.SimulateTripHandler)
 
 	restHandler := withCORS(requestLogger(logger, httpMux))
@@ -133,7 +220,7 @@ func main() {
 
 func requestLogger(logger *slog.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/location/stream" || strings.HasPrefix(r.URL.Path, "/socket.io/") {
+		if r.URL.Path == "/location/stream" || r.URL.Path == "/matching/watch" || strings.HasPrefix(r.URL.Path, "/socket.io/") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -235,6 +322,89 @@ func waitForShutdown(logger *slog.Logger, grpcServer *grpc.Server, httpServer *h
 	_ = httpServer.Shutdown(ctx)
 }
 
+// simulatorConfig reads SIM_SPEED_MPS, SIM_SAMPLE_INTERVAL and
+// SIM_PICKUP_DWELL, and snaps simulated drives to roads through the OSRM
+// server at SIM_OSRM_URL when set.
+func simulatorConfig(logger *slog.Logger) api.SimulatorConfig {
+	var cfg api.SimulatorConfig
+	if raw := os.Getenv("SIM_SPEED_MPS"); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			cfg.SpeedMPS = v
+		} else {
+			logger.Warn("invalid SIM_SPEED_MPS, using default", "value", raw, "err", err)
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"SIM_SAMPLE_INTERVAL": &cfg.SampleInterval,
+		"SIM_PICKUP_DWELL":    &cfg.PickupDwell,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if d, err := time.ParseDuration(raw); err == nil {
+				*target = d
+			} else {
+				logger.Warn("invalid "+key+", using default", "value", raw, "err", err)
+			}
+		}
+	}
+	if osrmURL := os.Getenv("SIM_OSRM_URL"); osrmURL != "" {
+		cfg.Router = drivepath.NewOSRM(osrmURL, &http.Client{Timeout: 5 * time.Second})
+	}
+	return cfg
+}
+
+// scheduleConfig reads SCHEDULE_INTERVAL, SCHEDULE_START_TIMEOUT and
+// SCHEDULE_TIMEZONE, the timezone of route templates that don't name one.
+func scheduleConfig(logger *slog.Logger) api.ScheduleConfig {
+	var cfg api.ScheduleConfig
+	for key, target := range map[string]*time.Duration{
+		"SCHEDULE_INTERVAL":      &cfg.Interval,
+		"SCHEDULE_START_TIMEOUT": &cfg.StartTimeout,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if d, err := time.ParseDuration(raw); err == nil {
+				*target = d
+			} else {
+				logger.Warn("invalid "+key+", using default", "value", raw, "err", err)
+			}
+		}
+	}
+	if tz := os.Getenv("SCHEDULE_TIMEZONE"); tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			cfg.Location = loc
+		} else {
+			logger.Warn("invalid SCHEDULE_TIMEZONE, using local time", "value", tz, "err", err)
+		}
+	}
+	return cfg
+}
+
+// ratingRule reads RATING_MIN_COUNT, RATING_DOWNRANK_BELOW,
+// RATING_DOWNRANK_WEIGHT and RATING_EXCLUDE_BELOW over the default rule.
+func ratingRule(logger *slog.Logger) matchpolicy.RatingRule {
+	rule := api.DefaultRatingRule
+	if raw := os.Getenv("RATING_MIN_COUNT"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil {
+			rule.MinRatings = v
+		} else {
+			logger.Warn("invalid RATING_MIN_COUNT, using default", "value", raw, "err", err)
+		}
+	}
+	for key, target := range map[string]*float64{
+		"RATING_DOWNRANK_BELOW":  &rule.DownRankBelow,
+		"RATING_DOWNRANK_WEIGHT": &rule.Weight,
+		"RATING_EXCLUDE_BELOW":   &rule.ExcludeBelow,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if v, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = v
+			} else {
+				logger.Warn("invalid "+key+", using default", "value", raw, "err", err)
+			}
+		}
+	}
+	return rule
+}
+
 func getenv(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
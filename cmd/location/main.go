@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net"
 	"os"
+	"strconv"
+	"time"
 
 	pb "lastmile/gen/go/location"
+	stationpb "lastmile/gen/go/station"
 	"lastmile/internal/location"
+	"lastmile/internal/pkg/gpsfilter"
 	"lastmile/internal/pkg/logging"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func main() {
@@ -29,6 +36,19 @@ func main() {
 
 	// Create a new location server
 	locationServer := location.NewServerWithMatching(matchingTarget, logger.With("component", "location-server"))
+	configureGeofences(logger, locationServer)
+	configureHistory(logger, locationServer)
+	configurePositions(logger, locationServer)
+	locationServer.SetMaxAccuracy(getenvFloat(logger, "LOCATION_MAX_ACCURACY"))
+	locationServer.SetGPSFilter(gpsfilter.Config{MaxSpeedMPS: getenvFloat(logger, "GPS_MAX_SPEED")})
+	locationServer.SetSimulatorToken(os.Getenv("SIMULATOR_TOKEN"))
+	if lag := os.Getenv("LOCATION_SUBSCRIBER_MAX_LAG"); lag != "" {
+		if d, err := time.ParseDuration(lag); err == nil {
+			locationServer.SetSubscriberMaxLag(d)
+		} else {
+			logger.Warn("invalid LOCATION_SUBSCRIBER_MAX_LAG, using default", "value", lag, "err", err)
+		}
+	}
 
 	// Register the location server with the gRPC server
 	pb.RegisterLocationServiceServer(s, locationServer)
@@ -40,6 +60,98 @@ func main() {
 	}
 }
 
+// configureGeofences loads station and pickup fences from StationService and
+// keeps them fresh.
+func configureGeofences(logger *slog.Logger, server *location.Server) {
+	cfg := location.GeofenceConfig{
+		StationRadius: getenvFloat(logger, "GEOFENCE_STATION_RADIUS"),
+		PickupRadius:  getenvFloat(logger, "GEOFENCE_PICKUP_RADIUS"),
+	}
+	if spec := os.Getenv("GEOFENCE_STATION_RADII"); spec != "" {
+		radii, err := location.ParseStationRadii(spec)
+		if err != nil {
+			logger.Warn("invalid GEOFENCE_STATION_RADII, ignoring", "value", spec, "err", err)
+		} else {
+			cfg.StationRadii = radii
+		}
+	}
+	if dwell := os.Getenv("GEOFENCE_DWELL"); dwell != "" {
+		if d, err := time.ParseDuration(dwell); err == nil {
+			cfg.DwellAfter = d
+		} else {
+			logger.Warn("invalid GEOFENCE_DWELL, using default", "value", dwell, "err", err)
+		}
+	}
+	server.SetGeofenceConfig(cfg)
+
+	refresh, err := time.ParseDuration(getenv("GEOFENCE_REFRESH", "1m"))
+	if err != nil {
+		logger.Warn("invalid GEOFENCE_REFRESH, using 1m", "err", err)
+		refresh = time.Minute
+	}
+	stationAddr := getenv("STATION_ADDR", ":50056")
+	conn, err := grpc.NewClient(stationAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Error("failed to dial station service, geofences disabled", "addr", stationAddr, "err", err)
+		return
+	}
+	server.WatchStations(context.Background(), stationpb.NewStationServiceClient(conn), refresh)
+}
+
+// configureHistory sizes the in-memory track buffer and, when a database is
+// configured, persists every point for GetDriverTrack.
+func configureHistory(logger *slog.Logger, server *location.Server) {
+	size, err := strconv.Atoi(getenv("LOCATION_HISTORY_SIZE", "1000"))
+	if err != nil {
+		logger.Warn("invalid LOCATION_HISTORY_SIZE, using default", "err", err)
+		size = 0
+	}
+	var store location.TrackStore
+	if dsn := getenv("LOCATION_DSN", os.Getenv("DATABASE_URL")); dsn != "" {
+		pg, err := location.NewPostgresTrackStore(context.Background(), dsn)
+		if err != nil {
+			logger.Error("track store unavailable, keeping history in memory only", "err", err)
+		} else {
+			store = pg
+			logger.Info("track store enabled")
+		}
+	}
+	server.SetTrackHistory(size, store)
+}
+
+// configurePositions persists last-known positions, in Postgres when a
+// database is configured so every replica shares them, and reloads them.
+func configurePositions(logger *slog.Logger, server *location.Server) {
+	var store location.PositionStore = location.NewMemoryPositionStore()
+	if dsn := getenv("LOCATION_DSN", os.Getenv("DATABASE_URL")); dsn != "" {
+		pg, err := location.NewPostgresPositionStore(context.Background(), dsn)
+		if err != nil {
+			logger.Error("position store unavailable, keeping positions in memory only", "err", err)
+		} else {
+			store = pg
+			logger.Info("position store enabled")
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.SetPositionStore(ctx, store); err != nil {
+		logger.Warn("failed to restore positions", "err", err)
+	}
+}
+
+func getenvFloat(logger *slog.Logger, key string) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		logger.Warn("invalid "+key+", using default", "value", raw, "err", err)
+		return 0
+	}
+	return v
+}
+
 func getenv(key, def string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -2,13 +2,22 @@ package main
 
 import (
 	"log"
+	"log/slog"
 	"net"
 	"os"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	driverpb "lastmile/gen/go/driver"
 	pb "lastmile/gen/go/matching"
+	riderpb "lastmile/gen/go/rider"
+	stationpb "lastmile/gen/go/station"
+	tripb "lastmile/gen/go/trip"
 	"lastmile/internal/matching"
 	"lastmile/internal/pkg/logging"
+	"lastmile/internal/pkg/matchpolicy"
 )
 
 func main() {
@@ -25,8 +34,42 @@ func main() {
 	// Create a new gRPC server
 	s := grpc.NewServer()
 
+	// Dial the services the matcher reads drivers, riders and stations from
+	clients := matching.Clients{
+		Drivers:  driverpb.NewDriverServiceClient(dial(logger, getenv("DRIVER_ADDR", ":50051"))),
+		Riders:   riderpb.NewRiderServiceClient(dial(logger, getenv("RIDER_ADDR", ":50055"))),
+		Stations: stationpb.NewStationServiceClient(dial(logger, getenv("STATION_ADDR", ":50056"))),
+		Trips:    tripb.NewTripServiceClient(dial(logger, getenv("TRIP_ADDR", ":50057"))),
+	}
+
 	// Create a new matching server
-	matchingServer := matching.NewServer(logger.With("component", "matching-server"))
+	matchingServer := matching.NewServerWithClients(clients, logger.With("component", "matching-server"))
+	if window := os.Getenv("MATCH_WINDOW"); window != "" {
+		if d, err := time.ParseDuration(window); err == nil {
+			matchingServer.SetArrivalWindow(d)
+		} else {
+			logger.Warn("invalid MATCH_WINDOW, using default", "value", window, "err", err)
+		}
+	}
+	if expiry := os.Getenv("RIDER_EXPIRY"); expiry != "" {
+		if d, err := time.ParseDuration(expiry); err == nil {
+			matchingServer.SetRiderExpiry(d)
+		} else {
+			logger.Warn("invalid RIDER_EXPIRY, using default", "value", expiry, "err", err)
+		}
+	}
+	if policy, perStation := os.Getenv("MATCH_POLICY"), os.Getenv("MATCH_POLICY_STATIONS"); policy != "" || perStation != "" {
+		overrides, err := matchpolicy.ParseStationPolicies(perStation)
+		if err == nil {
+			err = matchingServer.SetMatchPolicy(policy, overrides)
+		}
+		if err != nil {
+			logger.Warn("invalid match policy config, ranking nearest first", "policy", policy, "stations", perStation, "err", err)
+		}
+	}
+
+	// Shard stations across replicas when peers are configured
+	configureSharding(logger, matchingServer, addr)
 
 	// Register the matching server with the gRPC server
 	pb.RegisterMatchingServiceServer(s, matchingServer)
@@ -38,6 +81,78 @@ func main() {
 	}
 }
 
+// configureSharding enables station ownership when MATCHING_PEERS (a static
+// list of replica addresses) or MATCHING_PEERS_DNS (a headless service
+// host:port) is set. DNS membership is re-resolved every
+// MATCHING_PEERS_REFRESH so stations rebalance as pods scale in and out.
+func configureSharding(logger *slog.Logger, server *matching.Server, listenAddr string) {
+	static := os.Getenv("MATCHING_PEERS")
+	dnsTarget := os.Getenv("MATCHING_PEERS_DNS")
+	if static == "" && dnsTarget == "" {
+		return
+	}
+
+	self := os.Getenv("MATCHING_REPLICA_ADDR")
+	if self == "" {
+		host, _ := os.Hostname()
+		_, port, _ := net.SplitHostPort(listenAddr)
+		self = net.JoinHostPort(host, port)
+	}
+	forward := getenv("MATCHING_NON_OWNER", "forward") != "reject"
+	server.EnableSharding(self, func(addr string) (*grpc.ClientConn, error) {
+		return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}, forward)
+	logger.Info("station sharding enabled", "self", self, "forward", forward)
+
+	if static != "" {
+		members := make([]string, 0)
+		for _, m := range strings.Split(static, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				members = append(members, m)
+			}
+		}
+		if err := server.SetMembers(members); err != nil {
+			logger.Warn("membership update failed", "err", err)
+		}
+		return
+	}
+
+	refresh, err := time.ParseDuration(getenv("MATCHING_PEERS_REFRESH", "5s"))
+	if err != nil || refresh <= 0 {
+		refresh = 5 * time.Second
+	}
+	host, port, err := net.SplitHostPort(dnsTarget)
+	if err != nil {
+		logger.Warn("invalid MATCHING_PEERS_DNS, this replica owns every station", "value", dnsTarget, "err", err)
+		return
+	}
+	go func() {
+		for {
+			if ips, err := net.LookupHost(host); err != nil {
+				logger.Warn("peer lookup failed", "host", host, "err", err)
+			} else {
+				members := make([]string, 0, len(ips))
+				for _, ip := range ips {
+					members = append(members, net.JoinHostPort(ip, port))
+				}
+				if err := server.SetMembers(members); err != nil {
+					logger.Warn("membership update failed", "err", err)
+				}
+			}
+			time.Sleep(refresh)
+		}
+	}()
+}
+
+func dial(logger *slog.Logger, addr string) *grpc.ClientConn {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Error("failed to dial dependency", "addr", addr, "err", err)
+		log.Fatalf("failed to dial %s: %v", addr, err)
+	}
+	return conn
+}
+
 func getenv(key, def string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -27,6 +27,9 @@ func main() {
 
 	// Create a new station server
 	stationServer := station.NewServer(logger.With("component", "station-server"))
+	if getenv("STATION_SEED_CATALOG", "true") == "true" {
+		stationServer.Seed(station.DefaultCatalog())
+	}
 
 	// Register the station server with the gRPC server
 	pb.RegisterStationServiceServer(s, stationServer)
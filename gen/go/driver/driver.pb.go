@@ -21,13 +21,79 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// DriverStatus is where a driver is in their working day. Allowed changes:
+// offline -> available; available -> offline, en route to pickup or full;
+// en route to pickup -> available or full; full -> available or en route to
+// pickup.
+type DriverStatus int32
+
+const (
+	DriverStatus_DRIVER_STATUS_UNSPECIFIED        DriverStatus = 0
+	DriverStatus_DRIVER_STATUS_OFFLINE            DriverStatus = 1
+	DriverStatus_DRIVER_STATUS_AVAILABLE          DriverStatus = 2
+	DriverStatus_DRIVER_STATUS_EN_ROUTE_TO_PICKUP DriverStatus = 3
+	DriverStatus_DRIVER_STATUS_FULL               DriverStatus = 4
+)
+
+// Enum value maps for DriverStatus.
+var (
+	DriverStatus_name = map[int32]string{
+		0: "DRIVER_STATUS_UNSPECIFIED",
+		1: "DRIVER_STATUS_OFFLINE",
+		2: "DRIVER_STATUS_AVAILABLE",
+		3: "DRIVER_STATUS_EN_ROUTE_TO_PICKUP",
+		4: "DRIVER_STATUS_FULL",
+	}
+	DriverStatus_value = map[string]int32{
+		"DRIVER_STATUS_UNSPECIFIED":        0,
+		"DRIVER_STATUS_OFFLINE":            1,
+		"DRIVER_STATUS_AVAILABLE":          2,
+		"DRIVER_STATUS_EN_ROUTE_TO_PICKUP": 3,
+		"DRIVER_STATUS_FULL":               4,
+	}
+)
+
+func (x DriverStatus) Enum() *DriverStatus {
+	p := new(DriverStatus)
+	*p = x
+	return p
+}
+
+func (x DriverStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DriverStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_driver_proto_enumTypes[0].Descriptor()
+}
+
+func (DriverStatus) Type() protoreflect.EnumType {
+	return &file_api_driver_proto_enumTypes[0]
+}
+
+func (x DriverStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DriverStatus.Descriptor instead.
+func (DriverStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{0}
+}
+
 type Driver struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	CarDetails    string                 `protobuf:"bytes,3,opt,name=car_details,json=carDetails,proto3" json:"car_details,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CarDetails string                 `protobuf:"bytes,3,opt,name=car_details,json=carDetails,proto3" json:"car_details,omitempty"`
+	// status is read-only here; change it with SetDriverStatus. New drivers
+	// start offline.
+	Status DriverStatus `protobuf:"varint,4,opt,name=status,proto3,enum=driver.DriverStatus" json:"status,omitempty"`
+	// vehicle_ids are the vehicles linked to the driver, and
+	// active_vehicle_id the one they are driving, if any.
+	VehicleIds      []string `protobuf:"bytes,5,rep,name=vehicle_ids,json=vehicleIds,proto3" json:"vehicle_ids,omitempty"`
+	ActiveVehicleId string   `protobuf:"bytes,6,opt,name=active_vehicle_id,json=activeVehicleId,proto3" json:"active_vehicle_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Driver) Reset() {
@@ -81,6 +147,139 @@ func (x *Driver) GetCarDetails() string {
 	return ""
 }
 
+func (x *Driver) GetStatus() DriverStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DriverStatus_DRIVER_STATUS_UNSPECIFIED
+}
+
+func (x *Driver) GetVehicleIds() []string {
+	if x != nil {
+		return x.VehicleIds
+	}
+	return nil
+}
+
+func (x *Driver) GetActiveVehicleId() string {
+	if x != nil {
+		return x.ActiveVehicleId
+	}
+	return ""
+}
+
+// Vehicle is a registered car. Plates are unique, compared ignoring case
+// and spaces.
+type Vehicle struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Plate  string                 `protobuf:"bytes,2,opt,name=plate,proto3" json:"plate,omitempty"`
+	Make   string                 `protobuf:"bytes,3,opt,name=make,proto3" json:"make,omitempty"`
+	Model  string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`
+	Colour string                 `protobuf:"bytes,5,opt,name=colour,proto3" json:"colour,omitempty"`
+	// max_seats is how many riders the vehicle can take; routes driven in it
+	// offer at most this many seats.
+	MaxSeats             int32 `protobuf:"varint,6,opt,name=max_seats,json=maxSeats,proto3" json:"max_seats,omitempty"`
+	AirConditioned       bool  `protobuf:"varint,7,opt,name=air_conditioned,json=airConditioned,proto3" json:"air_conditioned,omitempty"`
+	WheelchairAccessible bool  `protobuf:"varint,8,opt,name=wheelchair_accessible,json=wheelchairAccessible,proto3" json:"wheelchair_accessible,omitempty"`
+	LuggageSpace         bool  `protobuf:"varint,9,opt,name=luggage_space,json=luggageSpace,proto3" json:"luggage_space,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_api_driver_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Vehicle) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Vehicle) GetPlate() string {
+	if x != nil {
+		return x.Plate
+	}
+	return ""
+}
+
+func (x *Vehicle) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetColour() string {
+	if x != nil {
+		return x.Colour
+	}
+	return ""
+}
+
+func (x *Vehicle) GetMaxSeats() int32 {
+	if x != nil {
+		return x.MaxSeats
+	}
+	return 0
+}
+
+func (x *Vehicle) GetAirConditioned() bool {
+	if x != nil {
+		return x.AirConditioned
+	}
+	return false
+}
+
+func (x *Vehicle) GetWheelchairAccessible() bool {
+	if x != nil {
+		return x.WheelchairAccessible
+	}
+	return false
+}
+
+func (x *Vehicle) GetLuggageSpace() bool {
+	if x != nil {
+		return x.LuggageSpace
+	}
+	return false
+}
+
 type Route struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -88,13 +287,17 @@ type Route struct {
 	TargetStationIds []string               `protobuf:"bytes,3,rep,name=target_station_ids,json=targetStationIds,proto3" json:"target_station_ids,omitempty"`
 	AvailableSeats   int32                  `protobuf:"varint,4,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
 	Destination      string                 `protobuf:"bytes,5,opt,name=destination,proto3" json:"destination,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// seats_total is the route's capacity. RegisterRoute changes the stored
+	// capacity only when it is set, e.g. for a new route or vehicle; updates
+	// that leave it 0 only change available_seats, capped at the capacity.
+	SeatsTotal    int32 `protobuf:"varint,6,opt,name=seats_total,json=seatsTotal,proto3" json:"seats_total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Route) Reset() {
 	*x = Route{}
-	mi := &file_api_driver_proto_msgTypes[1]
+	mi := &file_api_driver_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -106,7 +309,7 @@ func (x *Route) String() string {
 func (*Route) ProtoMessage() {}
 
 func (x *Route) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[1]
+	mi := &file_api_driver_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -119,7 +322,7 @@ func (x *Route) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Route.ProtoReflect.Descriptor instead.
 func (*Route) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{1}
+	return file_api_driver_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Route) GetId() string {
@@ -157,6 +360,13 @@ func (x *Route) GetDestination() string {
 	return ""
 }
 
+func (x *Route) GetSeatsTotal() int32 {
+	if x != nil {
+		return x.SeatsTotal
+	}
+	return 0
+}
+
 type RegisterDriverRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Driver        *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
@@ -166,7 +376,7 @@ type RegisterDriverRequest struct {
 
 func (x *RegisterDriverRequest) Reset() {
 	*x = RegisterDriverRequest{}
-	mi := &file_api_driver_proto_msgTypes[2]
+	mi := &file_api_driver_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -178,7 +388,7 @@ func (x *RegisterDriverRequest) String() string {
 func (*RegisterDriverRequest) ProtoMessage() {}
 
 func (x *RegisterDriverRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[2]
+	mi := &file_api_driver_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +401,7 @@ func (x *RegisterDriverRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterDriverRequest.ProtoReflect.Descriptor instead.
 func (*RegisterDriverRequest) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{2}
+	return file_api_driver_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *RegisterDriverRequest) GetDriver() *Driver {
@@ -210,7 +420,7 @@ type RegisterDriverResponse struct {
 
 func (x *RegisterDriverResponse) Reset() {
 	*x = RegisterDriverResponse{}
-	mi := &file_api_driver_proto_msgTypes[3]
+	mi := &file_api_driver_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -222,7 +432,7 @@ func (x *RegisterDriverResponse) String() string {
 func (*RegisterDriverResponse) ProtoMessage() {}
 
 func (x *RegisterDriverResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[3]
+	mi := &file_api_driver_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -235,7 +445,7 @@ func (x *RegisterDriverResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterDriverResponse.ProtoReflect.Descriptor instead.
 func (*RegisterDriverResponse) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{3}
+	return file_api_driver_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *RegisterDriverResponse) GetId() string {
@@ -254,7 +464,7 @@ type RegisterRouteRequest struct {
 
 func (x *RegisterRouteRequest) Reset() {
 	*x = RegisterRouteRequest{}
-	mi := &file_api_driver_proto_msgTypes[4]
+	mi := &file_api_driver_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -266,7 +476,7 @@ func (x *RegisterRouteRequest) String() string {
 func (*RegisterRouteRequest) ProtoMessage() {}
 
 func (x *RegisterRouteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[4]
+	mi := &file_api_driver_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -279,7 +489,7 @@ func (x *RegisterRouteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterRouteRequest.ProtoReflect.Descriptor instead.
 func (*RegisterRouteRequest) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{4}
+	return file_api_driver_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RegisterRouteRequest) GetRoute() *Route {
@@ -298,7 +508,7 @@ type RegisterRouteResponse struct {
 
 func (x *RegisterRouteResponse) Reset() {
 	*x = RegisterRouteResponse{}
-	mi := &file_api_driver_proto_msgTypes[5]
+	mi := &file_api_driver_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -310,7 +520,7 @@ func (x *RegisterRouteResponse) String() string {
 func (*RegisterRouteResponse) ProtoMessage() {}
 
 func (x *RegisterRouteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[5]
+	mi := &file_api_driver_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,7 +533,7 @@ func (x *RegisterRouteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterRouteResponse.ProtoReflect.Descriptor instead.
 func (*RegisterRouteResponse) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{5}
+	return file_api_driver_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RegisterRouteResponse) GetId() string {
@@ -341,7 +551,7 @@ type ListDriversRequest struct {
 
 func (x *ListDriversRequest) Reset() {
 	*x = ListDriversRequest{}
-	mi := &file_api_driver_proto_msgTypes[6]
+	mi := &file_api_driver_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -353,7 +563,7 @@ func (x *ListDriversRequest) String() string {
 func (*ListDriversRequest) ProtoMessage() {}
 
 func (x *ListDriversRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[6]
+	mi := &file_api_driver_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +576,7 @@ func (x *ListDriversRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDriversRequest.ProtoReflect.Descriptor instead.
 func (*ListDriversRequest) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{6}
+	return file_api_driver_proto_rawDescGZIP(), []int{7}
 }
 
 type ListDriversResponse struct {
@@ -379,7 +589,7 @@ type ListDriversResponse struct {
 
 func (x *ListDriversResponse) Reset() {
 	*x = ListDriversResponse{}
-	mi := &file_api_driver_proto_msgTypes[7]
+	mi := &file_api_driver_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -391,7 +601,7 @@ func (x *ListDriversResponse) String() string {
 func (*ListDriversResponse) ProtoMessage() {}
 
 func (x *ListDriversResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_driver_proto_msgTypes[7]
+	mi := &file_api_driver_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -404,7 +614,7 @@ func (x *ListDriversResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDriversResponse.ProtoReflect.Descriptor instead.
 func (*ListDriversResponse) Descriptor() ([]byte, []int) {
-	return file_api_driver_proto_rawDescGZIP(), []int{7}
+	return file_api_driver_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListDriversResponse) GetDrivers() []*Driver {
@@ -421,38 +631,828 @@ func (x *ListDriversResponse) GetRoutes() []*Route {
 	return nil
 }
 
-var File_api_driver_proto protoreflect.FileDescriptor
+type GetRouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_driver_proto_rawDesc = "" +
-	"\n" +
-	"\x10api/driver.proto\x12\x06driver\"M\n" +
-	"\x06Driver\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
-	"\vcar_details\x18\x03 \x01(\tR\n" +
-	"carDetails\"\xad\x01\n" +
-	"\x05Route\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
-	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12,\n" +
-	"\x12target_station_ids\x18\x03 \x03(\tR\x10targetStationIds\x12'\n" +
-	"\x0favailable_seats\x18\x04 \x01(\x05R\x0eavailableSeats\x12 \n" +
-	"\vdestination\x18\x05 \x01(\tR\vdestination\"?\n" +
-	"\x15RegisterDriverRequest\x12&\n" +
-	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"(\n" +
-	"\x16RegisterDriverResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
-	"\x14RegisterRouteRequest\x12#\n" +
-	"\x05route\x18\x01 \x01(\v2\r.driver.RouteR\x05route\"'\n" +
-	"\x15RegisterRouteResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
-	"\x12ListDriversRequest\"f\n" +
-	"\x13ListDriversResponse\x12(\n" +
-	"\adrivers\x18\x01 \x03(\v2\x0e.driver.DriverR\adrivers\x12%\n" +
-	"\x06routes\x18\x02 \x03(\v2\r.driver.RouteR\x06routes2\xf6\x01\n" +
+func (x *GetRouteRequest) Reset() {
+	*x = GetRouteRequest{}
+	mi := &file_api_driver_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRouteRequest) ProtoMessage() {}
+
+func (x *GetRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRouteRequest.ProtoReflect.Descriptor instead.
+func (*GetRouteRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRouteRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+type GetRouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Driver        *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	Route         *Route                 `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRouteResponse) Reset() {
+	*x = GetRouteResponse{}
+	mi := &file_api_driver_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRouteResponse) ProtoMessage() {}
+
+func (x *GetRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRouteResponse.ProtoReflect.Descriptor instead.
+func (*GetRouteResponse) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetRouteResponse) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+func (x *GetRouteResponse) GetRoute() *Route {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+type SetDriverStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Status        DriverStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=driver.DriverStatus" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDriverStatusRequest) Reset() {
+	*x = SetDriverStatusRequest{}
+	mi := &file_api_driver_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDriverStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDriverStatusRequest) ProtoMessage() {}
+
+func (x *SetDriverStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDriverStatusRequest.ProtoReflect.Descriptor instead.
+func (*SetDriverStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetDriverStatusRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *SetDriverStatusRequest) GetStatus() DriverStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DriverStatus_DRIVER_STATUS_UNSPECIFIED
+}
+
+func (x *SetDriverStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetDriverStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Change        *DriverStatusEvent     `protobuf:"bytes,1,opt,name=change,proto3" json:"change,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDriverStatusResponse) Reset() {
+	*x = SetDriverStatusResponse{}
+	mi := &file_api_driver_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDriverStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDriverStatusResponse) ProtoMessage() {}
+
+func (x *SetDriverStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDriverStatusResponse.ProtoReflect.Descriptor instead.
+func (*SetDriverStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetDriverStatusResponse) GetChange() *DriverStatusEvent {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+// DriverStatusEvent records one status change. Setting the status a driver
+// already has changes nothing and emits no event.
+type DriverStatusEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Status        DriverStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=driver.DriverStatus" json:"status,omitempty"`
+	Previous      DriverStatus           `protobuf:"varint,3,opt,name=previous,proto3,enum=driver.DriverStatus" json:"previous,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt     string                 `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"` // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverStatusEvent) Reset() {
+	*x = DriverStatusEvent{}
+	mi := &file_api_driver_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverStatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverStatusEvent) ProtoMessage() {}
+
+func (x *DriverStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverStatusEvent.ProtoReflect.Descriptor instead.
+func (*DriverStatusEvent) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DriverStatusEvent) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *DriverStatusEvent) GetStatus() DriverStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DriverStatus_DRIVER_STATUS_UNSPECIFIED
+}
+
+func (x *DriverStatusEvent) GetPrevious() DriverStatus {
+	if x != nil {
+		return x.Previous
+	}
+	return DriverStatus_DRIVER_STATUS_UNSPECIFIED
+}
+
+func (x *DriverStatusEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DriverStatusEvent) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+type RegisterVehicleRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Vehicle *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// driver_id, when set, links the vehicle to that driver.
+	DriverId      string `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterVehicleRequest) Reset() {
+	*x = RegisterVehicleRequest{}
+	mi := &file_api_driver_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterVehicleRequest) ProtoMessage() {}
+
+func (x *RegisterVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterVehicleRequest.ProtoReflect.Descriptor instead.
+func (*RegisterVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RegisterVehicleRequest) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *RegisterVehicleRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+type RegisterVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterVehicleResponse) Reset() {
+	*x = RegisterVehicleResponse{}
+	mi := &file_api_driver_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterVehicleResponse) ProtoMessage() {}
+
+func (x *RegisterVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterVehicleResponse.ProtoReflect.Descriptor instead.
+func (*RegisterVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RegisterVehicleResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type LinkVehicleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	VehicleId     string                 `protobuf:"bytes,2,opt,name=vehicle_id,json=vehicleId,proto3" json:"vehicle_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkVehicleRequest) Reset() {
+	*x = LinkVehicleRequest{}
+	mi := &file_api_driver_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkVehicleRequest) ProtoMessage() {}
+
+func (x *LinkVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkVehicleRequest.ProtoReflect.Descriptor instead.
+func (*LinkVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LinkVehicleRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *LinkVehicleRequest) GetVehicleId() string {
+	if x != nil {
+		return x.VehicleId
+	}
+	return ""
+}
+
+type LinkVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Driver        *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkVehicleResponse) Reset() {
+	*x = LinkVehicleResponse{}
+	mi := &file_api_driver_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkVehicleResponse) ProtoMessage() {}
+
+func (x *LinkVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkVehicleResponse.ProtoReflect.Descriptor instead.
+func (*LinkVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LinkVehicleResponse) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+// SelectVehicleRequest picks which linked vehicle the driver is driving.
+type SelectVehicleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	VehicleId     string                 `protobuf:"bytes,2,opt,name=vehicle_id,json=vehicleId,proto3" json:"vehicle_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectVehicleRequest) Reset() {
+	*x = SelectVehicleRequest{}
+	mi := &file_api_driver_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectVehicleRequest) ProtoMessage() {}
+
+func (x *SelectVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectVehicleRequest.ProtoReflect.Descriptor instead.
+func (*SelectVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SelectVehicleRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *SelectVehicleRequest) GetVehicleId() string {
+	if x != nil {
+		return x.VehicleId
+	}
+	return ""
+}
+
+type SelectVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Driver        *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	Vehicle       *Vehicle               `protobuf:"bytes,2,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectVehicleResponse) Reset() {
+	*x = SelectVehicleResponse{}
+	mi := &file_api_driver_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectVehicleResponse) ProtoMessage() {}
+
+func (x *SelectVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectVehicleResponse.ProtoReflect.Descriptor instead.
+func (*SelectVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SelectVehicleResponse) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+func (x *SelectVehicleResponse) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type ListVehiclesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"` // empty lists every vehicle
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVehiclesRequest) Reset() {
+	*x = ListVehiclesRequest{}
+	mi := &file_api_driver_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVehiclesRequest) ProtoMessage() {}
+
+func (x *ListVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*ListVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListVehiclesRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+type ListVehiclesResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Vehicles []*Vehicle             `protobuf:"bytes,1,rep,name=vehicles,proto3" json:"vehicles,omitempty"`
+	// active_vehicle_id is the driver's current vehicle when driver_id is set.
+	ActiveVehicleId string `protobuf:"bytes,2,opt,name=active_vehicle_id,json=activeVehicleId,proto3" json:"active_vehicle_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListVehiclesResponse) Reset() {
+	*x = ListVehiclesResponse{}
+	mi := &file_api_driver_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVehiclesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVehiclesResponse) ProtoMessage() {}
+
+func (x *ListVehiclesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVehiclesResponse.ProtoReflect.Descriptor instead.
+func (*ListVehiclesResponse) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListVehiclesResponse) GetVehicles() []*Vehicle {
+	if x != nil {
+		return x.Vehicles
+	}
+	return nil
+}
+
+func (x *ListVehiclesResponse) GetActiveVehicleId() string {
+	if x != nil {
+		return x.ActiveVehicleId
+	}
+	return ""
+}
+
+type WatchDriverStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverIds     []string               `protobuf:"bytes,1,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"` // empty watches every driver
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDriverStatusRequest) Reset() {
+	*x = WatchDriverStatusRequest{}
+	mi := &file_api_driver_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDriverStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDriverStatusRequest) ProtoMessage() {}
+
+func (x *WatchDriverStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_driver_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDriverStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchDriverStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_driver_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchDriverStatusRequest) GetDriverIds() []string {
+	if x != nil {
+		return x.DriverIds
+	}
+	return nil
+}
+
+var File_api_driver_proto protoreflect.FileDescriptor
+
+const file_api_driver_proto_rawDesc = "" +
+	"\n" +
+	"\x10api/driver.proto\x12\x06driver\"\xc8\x01\n" +
+	"\x06Driver\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vcar_details\x18\x03 \x01(\tR\n" +
+	"carDetails\x12,\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x14.driver.DriverStatusR\x06status\x12\x1f\n" +
+	"\vvehicle_ids\x18\x05 \x03(\tR\n" +
+	"vehicleIds\x12*\n" +
+	"\x11active_vehicle_id\x18\x06 \x01(\tR\x0factiveVehicleId\"\x91\x02\n" +
+	"\aVehicle\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05plate\x18\x02 \x01(\tR\x05plate\x12\x12\n" +
+	"\x04make\x18\x03 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\x12\x16\n" +
+	"\x06colour\x18\x05 \x01(\tR\x06colour\x12\x1b\n" +
+	"\tmax_seats\x18\x06 \x01(\x05R\bmaxSeats\x12'\n" +
+	"\x0fair_conditioned\x18\a \x01(\bR\x0eairConditioned\x123\n" +
+	"\x15wheelchair_accessible\x18\b \x01(\bR\x14wheelchairAccessible\x12#\n" +
+	"\rluggage_space\x18\t \x01(\bR\fluggageSpace\"\xce\x01\n" +
+	"\x05Route\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12,\n" +
+	"\x12target_station_ids\x18\x03 \x03(\tR\x10targetStationIds\x12'\n" +
+	"\x0favailable_seats\x18\x04 \x01(\x05R\x0eavailableSeats\x12 \n" +
+	"\vdestination\x18\x05 \x01(\tR\vdestination\x12\x1f\n" +
+	"\vseats_total\x18\x06 \x01(\x05R\n" +
+	"seatsTotal\"?\n" +
+	"\x15RegisterDriverRequest\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"(\n" +
+	"\x16RegisterDriverResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x14RegisterRouteRequest\x12#\n" +
+	"\x05route\x18\x01 \x01(\v2\r.driver.RouteR\x05route\"'\n" +
+	"\x15RegisterRouteResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12ListDriversRequest\"f\n" +
+	"\x13ListDriversResponse\x12(\n" +
+	"\adrivers\x18\x01 \x03(\v2\x0e.driver.DriverR\adrivers\x12%\n" +
+	"\x06routes\x18\x02 \x03(\v2\r.driver.RouteR\x06routes\".\n" +
+	"\x0fGetRouteRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\"_\n" +
+	"\x10GetRouteResponse\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\x12#\n" +
+	"\x05route\x18\x02 \x01(\v2\r.driver.RouteR\x05route\"{\n" +
+	"\x16SetDriverStatusRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12,\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x14.driver.DriverStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"L\n" +
+	"\x17SetDriverStatusResponse\x121\n" +
+	"\x06change\x18\x01 \x01(\v2\x19.driver.DriverStatusEventR\x06change\"\xc7\x01\n" +
+	"\x11DriverStatusEvent\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12,\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x14.driver.DriverStatusR\x06status\x120\n" +
+	"\bprevious\x18\x03 \x01(\x0e2\x14.driver.DriverStatusR\bprevious\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\tR\tchangedAt\"`\n" +
+	"\x16RegisterVehicleRequest\x12)\n" +
+	"\avehicle\x18\x01 \x01(\v2\x0f.driver.VehicleR\avehicle\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\")\n" +
+	"\x17RegisterVehicleResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"P\n" +
+	"\x12LinkVehicleRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1d\n" +
+	"\n" +
+	"vehicle_id\x18\x02 \x01(\tR\tvehicleId\"=\n" +
+	"\x13LinkVehicleResponse\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"R\n" +
+	"\x14SelectVehicleRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1d\n" +
+	"\n" +
+	"vehicle_id\x18\x02 \x01(\tR\tvehicleId\"j\n" +
+	"\x15SelectVehicleResponse\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\x12)\n" +
+	"\avehicle\x18\x02 \x01(\v2\x0f.driver.VehicleR\avehicle\"2\n" +
+	"\x13ListVehiclesRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\"o\n" +
+	"\x14ListVehiclesResponse\x12+\n" +
+	"\bvehicles\x18\x01 \x03(\v2\x0f.driver.VehicleR\bvehicles\x12*\n" +
+	"\x11active_vehicle_id\x18\x02 \x01(\tR\x0factiveVehicleId\"9\n" +
+	"\x18WatchDriverStatusRequest\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x01 \x03(\tR\tdriverIds*\xa3\x01\n" +
+	"\fDriverStatus\x12\x1d\n" +
+	"\x19DRIVER_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15DRIVER_STATUS_OFFLINE\x10\x01\x12\x1b\n" +
+	"\x17DRIVER_STATUS_AVAILABLE\x10\x02\x12$\n" +
+	" DRIVER_STATUS_EN_ROUTE_TO_PICKUP\x10\x03\x12\x16\n" +
+	"\x12DRIVER_STATUS_FULL\x10\x042\x92\x06\n" +
 	"\rDriverService\x12O\n" +
 	"\x0eRegisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponse\x12L\n" +
 	"\rRegisterRoute\x12\x1c.driver.RegisterRouteRequest\x1a\x1d.driver.RegisterRouteResponse\x12F\n" +
-	"\vListDrivers\x12\x1a.driver.ListDriversRequest\x1a\x1b.driver.ListDriversResponseB\x18Z\x16lastmile/gen/go/driverb\x06proto3"
+	"\vListDrivers\x12\x1a.driver.ListDriversRequest\x1a\x1b.driver.ListDriversResponse\x12=\n" +
+	"\bGetRoute\x12\x17.driver.GetRouteRequest\x1a\x18.driver.GetRouteResponse\x12R\n" +
+	"\x0fSetDriverStatus\x12\x1e.driver.SetDriverStatusRequest\x1a\x1f.driver.SetDriverStatusResponse\x12R\n" +
+	"\x11WatchDriverStatus\x12 .driver.WatchDriverStatusRequest\x1a\x19.driver.DriverStatusEvent0\x01\x12R\n" +
+	"\x0fRegisterVehicle\x12\x1e.driver.RegisterVehicleRequest\x1a\x1f.driver.RegisterVehicleResponse\x12F\n" +
+	"\vLinkVehicle\x12\x1a.driver.LinkVehicleRequest\x1a\x1b.driver.LinkVehicleResponse\x12L\n" +
+	"\rSelectVehicle\x12\x1c.driver.SelectVehicleRequest\x1a\x1d.driver.SelectVehicleResponse\x12I\n" +
+	"\fListVehicles\x12\x1b.driver.ListVehiclesRequest\x1a\x1c.driver.ListVehiclesResponseB\x18Z\x16lastmile/gen/go/driverb\x06proto3"
 
 var (
 	file_api_driver_proto_rawDescOnce sync.Once
@@ -466,33 +1466,76 @@ func file_api_driver_proto_rawDescGZIP() []byte {
 	return file_api_driver_proto_rawDescData
 }
 
-var file_api_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_api_driver_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_api_driver_proto_goTypes = []any{
-	(*Driver)(nil),                 // 0: driver.Driver
-	(*Route)(nil),                  // 1: driver.Route
-	(*RegisterDriverRequest)(nil),  // 2: driver.RegisterDriverRequest
-	(*RegisterDriverResponse)(nil), // 3: driver.RegisterDriverResponse
-	(*RegisterRouteRequest)(nil),   // 4: driver.RegisterRouteRequest
-	(*RegisterRouteResponse)(nil),  // 5: driver.RegisterRouteResponse
-	(*ListDriversRequest)(nil),     // 6: driver.ListDriversRequest
-	(*ListDriversResponse)(nil),    // 7: driver.ListDriversResponse
+	(DriverStatus)(0),                // 0: driver.DriverStatus
+	(*Driver)(nil),                   // 1: driver.Driver
+	(*Vehicle)(nil),                  // 2: driver.Vehicle
+	(*Route)(nil),                    // 3: driver.Route
+	(*RegisterDriverRequest)(nil),    // 4: driver.RegisterDriverRequest
+	(*RegisterDriverResponse)(nil),   // 5: driver.RegisterDriverResponse
+	(*RegisterRouteRequest)(nil),     // 6: driver.RegisterRouteRequest
+	(*RegisterRouteResponse)(nil),    // 7: driver.RegisterRouteResponse
+	(*ListDriversRequest)(nil),       // 8: driver.ListDriversRequest
+	(*ListDriversResponse)(nil),      // 9: driver.ListDriversResponse
+	(*GetRouteRequest)(nil),          // 10: driver.GetRouteRequest
+	(*GetRouteResponse)(nil),         // 11: driver.GetRouteResponse
+	(*SetDriverStatusRequest)(nil),   // 12: driver.SetDriverStatusRequest
+	(*SetDriverStatusResponse)(nil),  // 13: driver.SetDriverStatusResponse
+	(*DriverStatusEvent)(nil),        // 14: driver.DriverStatusEvent
+	(*RegisterVehicleRequest)(nil),   // 15: driver.RegisterVehicleRequest
+	(*RegisterVehicleResponse)(nil),  // 16: driver.RegisterVehicleResponse
+	(*LinkVehicleRequest)(nil),       // 17: driver.LinkVehicleRequest
+	(*LinkVehicleResponse)(nil),      // 18: driver.LinkVehicleResponse
+	(*SelectVehicleRequest)(nil),     // 19: driver.SelectVehicleRequest
+	(*SelectVehicleResponse)(nil),    // 20: driver.SelectVehicleResponse
+	(*ListVehiclesRequest)(nil),      // 21: driver.ListVehiclesRequest
+	(*ListVehiclesResponse)(nil),     // 22: driver.ListVehiclesResponse
+	(*WatchDriverStatusRequest)(nil), // 23: driver.WatchDriverStatusRequest
 }
 var file_api_driver_proto_depIdxs = []int32{
-	0, // 0: driver.RegisterDriverRequest.driver:type_name -> driver.Driver
-	1, // 1: driver.RegisterRouteRequest.route:type_name -> driver.Route
-	0, // 2: driver.ListDriversResponse.drivers:type_name -> driver.Driver
-	1, // 3: driver.ListDriversResponse.routes:type_name -> driver.Route
-	2, // 4: driver.DriverService.RegisterDriver:input_type -> driver.RegisterDriverRequest
-	4, // 5: driver.DriverService.RegisterRoute:input_type -> driver.RegisterRouteRequest
-	6, // 6: driver.DriverService.ListDrivers:input_type -> driver.ListDriversRequest
-	3, // 7: driver.DriverService.RegisterDriver:output_type -> driver.RegisterDriverResponse
-	5, // 8: driver.DriverService.RegisterRoute:output_type -> driver.RegisterRouteResponse
-	7, // 9: driver.DriverService.ListDrivers:output_type -> driver.ListDriversResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: driver.Driver.status:type_name -> driver.DriverStatus
+	1,  // 1: driver.RegisterDriverRequest.driver:type_name -> driver.Driver
+	3,  // 2: driver.RegisterRouteRequest.route:type_name -> driver.Route
+	1,  // 3: driver.ListDriversResponse.drivers:type_name -> driver.Driver
+	3,  // 4: driver.ListDriversResponse.routes:type_name -> driver.Route
+	1,  // 5: driver.GetRouteResponse.driver:type_name -> driver.Driver
+	3,  // 6: driver.GetRouteResponse.route:type_name -> driver.Route
+	0,  // 7: driver.SetDriverStatusRequest.status:type_name -> driver.DriverStatus
+	14, // 8: driver.SetDriverStatusResponse.change:type_name -> driver.DriverStatusEvent
+	0,  // 9: driver.DriverStatusEvent.status:type_name -> driver.DriverStatus
+	0,  // 10: driver.DriverStatusEvent.previous:type_name -> driver.DriverStatus
+	2,  // 11: driver.RegisterVehicleRequest.vehicle:type_name -> driver.Vehicle
+	1,  // 12: driver.LinkVehicleResponse.driver:type_name -> driver.Driver
+	1,  // 13: driver.SelectVehicleResponse.driver:type_name -> driver.Driver
+	2,  // 14: driver.SelectVehicleResponse.vehicle:type_name -> driver.Vehicle
+	2,  // 15: driver.ListVehiclesResponse.vehicles:type_name -> driver.Vehicle
+	4,  // 16: driver.DriverService.RegisterDriver:input_type -> driver.RegisterDriverRequest
+	6,  // 17: driver.DriverService.RegisterRoute:input_type -> driver.RegisterRouteRequest
+	8,  // 18: driver.DriverService.ListDrivers:input_type -> driver.ListDriversRequest
+	10, // 19: driver.DriverService.GetRoute:input_type -> driver.GetRouteRequest
+	12, // 20: driver.DriverService.SetDriverStatus:input_type -> driver.SetDriverStatusRequest
+	23, // 21: driver.DriverService.WatchDriverStatus:input_type -> driver.WatchDriverStatusRequest
+	15, // 22: driver.DriverService.RegisterVehicle:input_type -> driver.RegisterVehicleRequest
+	17, // 23: driver.DriverService.LinkVehicle:input_type -> driver.LinkVehicleRequest
+	19, // 24: driver.DriverService.SelectVehicle:input_type -> driver.SelectVehicleRequest
+	21, // 25: driver.DriverService.ListVehicles:input_type -> driver.ListVehiclesRequest
+	5,  // 26: driver.DriverService.RegisterDriver:output_type -> driver.RegisterDriverResponse
+	7,  // 27: driver.DriverService.RegisterRoute:output_type -> driver.RegisterRouteResponse
+	9,  // 28: driver.DriverService.ListDrivers:output_type -> driver.ListDriversResponse
+	11, // 29: driver.DriverService.GetRoute:output_type -> driver.GetRouteResponse
+	13, // 30: driver.DriverService.SetDriverStatus:output_type -> driver.SetDriverStatusResponse
+	14, // 31: driver.DriverService.WatchDriverStatus:output_type -> driver.DriverStatusEvent
+	16, // 32: driver.DriverService.RegisterVehicle:output_type -> driver.RegisterVehicleResponse
+	18, // 33: driver.DriverService.LinkVehicle:output_type -> driver.LinkVehicleResponse
+	20, // 34: driver.DriverService.SelectVehicle:output_type -> driver.SelectVehicleResponse
+	22, // 35: driver.DriverService.ListVehicles:output_type -> driver.ListVehiclesResponse
+	26, // [26:36] is the sub-list for method output_type
+	16, // [16:26] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_api_driver_proto_init() }
@@ -505,13 +1548,14 @@ func file_api_driver_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_driver_proto_rawDesc), len(file_api_driver_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_driver_proto_goTypes,
 		DependencyIndexes: file_api_driver_proto_depIdxs,
+		EnumInfos:         file_api_driver_proto_enumTypes,
 		MessageInfos:      file_api_driver_proto_msgTypes,
 	}.Build()
 	File_api_driver_proto = out.File
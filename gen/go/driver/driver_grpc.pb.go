@@ -19,9 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DriverService_RegisterDriver_FullMethodName = "/driver.DriverService/RegisterDriver"
-	DriverService_RegisterRoute_FullMethodName  = "/driver.DriverService/RegisterRoute"
-	DriverService_ListDrivers_FullMethodName    = "/driver.DriverService/ListDrivers"
+	DriverService_RegisterDriver_FullMethodName    = "/driver.DriverService/RegisterDriver"
+	DriverService_RegisterRoute_FullMethodName     = "/driver.DriverService/RegisterRoute"
+	DriverService_ListDrivers_FullMethodName       = "/driver.DriverService/ListDrivers"
+	DriverService_GetRoute_FullMethodName          = "/driver.DriverService/GetRoute"
+	DriverService_SetDriverStatus_FullMethodName   = "/driver.DriverService/SetDriverStatus"
+	DriverService_WatchDriverStatus_FullMethodName = "/driver.DriverService/WatchDriverStatus"
+	DriverService_RegisterVehicle_FullMethodName   = "/driver.DriverService/RegisterVehicle"
+	DriverService_LinkVehicle_FullMethodName       = "/driver.DriverService/LinkVehicle"
+	DriverService_SelectVehicle_FullMethodName     = "/driver.DriverService/SelectVehicle"
+	DriverService_ListVehicles_FullMethodName      = "/driver.DriverService/ListVehicles"
 )
 
 // DriverServiceClient is the client API for DriverService service.
@@ -31,6 +38,13 @@ type DriverServiceClient interface {
 	RegisterDriver(ctx context.Context, in *RegisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
 	RegisterRoute(ctx context.Context, in *RegisterRouteRequest, opts ...grpc.CallOption) (*RegisterRouteResponse, error)
 	ListDrivers(ctx context.Context, in *ListDriversRequest, opts ...grpc.CallOption) (*ListDriversResponse, error)
+	GetRoute(ctx context.Context, in *GetRouteRequest, opts ...grpc.CallOption) (*GetRouteResponse, error)
+	SetDriverStatus(ctx context.Context, in *SetDriverStatusRequest, opts ...grpc.CallOption) (*SetDriverStatusResponse, error)
+	WatchDriverStatus(ctx context.Context, in *WatchDriverStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DriverStatusEvent], error)
+	RegisterVehicle(ctx context.Context, in *RegisterVehicleRequest, opts ...grpc.CallOption) (*RegisterVehicleResponse, error)
+	LinkVehicle(ctx context.Context, in *LinkVehicleRequest, opts ...grpc.CallOption) (*LinkVehicleResponse, error)
+	SelectVehicle(ctx context.Context, in *SelectVehicleRequest, opts ...grpc.CallOption) (*SelectVehicleResponse, error)
+	ListVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (*ListVehiclesResponse, error)
 }
 
 type driverServiceClient struct {
@@ -71,6 +85,85 @@ func (c *driverServiceClient) ListDrivers(ctx context.Context, in *ListDriversRe
 	return out, nil
 }
 
+func (c *driverServiceClient) GetRoute(ctx context.Context, in *GetRouteRequest, opts ...grpc.CallOption) (*GetRouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRouteResponse)
+	err := c.cc.Invoke(ctx, DriverService_GetRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) SetDriverStatus(ctx context.Context, in *SetDriverStatusRequest, opts ...grpc.CallOption) (*SetDriverStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDriverStatusResponse)
+	err := c.cc.Invoke(ctx, DriverService_SetDriverStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) WatchDriverStatus(ctx context.Context, in *WatchDriverStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DriverStatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DriverService_ServiceDesc.Streams[0], DriverService_WatchDriverStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDriverStatusRequest, DriverStatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DriverService_WatchDriverStatusClient = grpc.ServerStreamingClient[DriverStatusEvent]
+
+func (c *driverServiceClient) RegisterVehicle(ctx context.Context, in *RegisterVehicleRequest, opts ...grpc.CallOption) (*RegisterVehicleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterVehicleResponse)
+	err := c.cc.Invoke(ctx, DriverService_RegisterVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) LinkVehicle(ctx context.Context, in *LinkVehicleRequest, opts ...grpc.CallOption) (*LinkVehicleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkVehicleResponse)
+	err := c.cc.Invoke(ctx, DriverService_LinkVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) SelectVehicle(ctx context.Context, in *SelectVehicleRequest, opts ...grpc.CallOption) (*SelectVehicleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectVehicleResponse)
+	err := c.cc.Invoke(ctx, DriverService_SelectVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) ListVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (*ListVehiclesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVehiclesResponse)
+	err := c.cc.Invoke(ctx, DriverService_ListVehicles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriverServiceServer is the server API for DriverService service.
 // All implementations must embed UnimplementedDriverServiceServer
 // for forward compatibility.
@@ -78,6 +171,13 @@ type DriverServiceServer interface {
 	RegisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error)
 	RegisterRoute(context.Context, *RegisterRouteRequest) (*RegisterRouteResponse, error)
 	ListDrivers(context.Context, *ListDriversRequest) (*ListDriversResponse, error)
+	GetRoute(context.Context, *GetRouteRequest) (*GetRouteResponse, error)
+	SetDriverStatus(context.Context, *SetDriverStatusRequest) (*SetDriverStatusResponse, error)
+	WatchDriverStatus(*WatchDriverStatusRequest, grpc.ServerStreamingServer[DriverStatusEvent]) error
+	RegisterVehicle(context.Context, *RegisterVehicleRequest) (*RegisterVehicleResponse, error)
+	LinkVehicle(context.Context, *LinkVehicleRequest) (*LinkVehicleResponse, error)
+	SelectVehicle(context.Context, *SelectVehicleRequest) (*SelectVehicleResponse, error)
+	ListVehicles(context.Context, *ListVehiclesRequest) (*ListVehiclesResponse, error)
 	mustEmbedUnimplementedDriverServiceServer()
 }
 
@@ -97,6 +197,27 @@ func (UnimplementedDriverServiceServer) RegisterRoute(context.Context, *Register
 func (UnimplementedDriverServiceServer) ListDrivers(context.Context, *ListDriversRequest) (*ListDriversResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDrivers not implemented")
 }
+func (UnimplementedDriverServiceServer) GetRoute(context.Context, *GetRouteRequest) (*GetRouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoute not implemented")
+}
+func (UnimplementedDriverServiceServer) SetDriverStatus(context.Context, *SetDriverStatusRequest) (*SetDriverStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDriverStatus not implemented")
+}
+func (UnimplementedDriverServiceServer) WatchDriverStatus(*WatchDriverStatusRequest, grpc.ServerStreamingServer[DriverStatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDriverStatus not implemented")
+}
+func (UnimplementedDriverServiceServer) RegisterVehicle(context.Context, *RegisterVehicleRequest) (*RegisterVehicleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterVehicle not implemented")
+}
+func (UnimplementedDriverServiceServer) LinkVehicle(context.Context, *LinkVehicleRequest) (*LinkVehicleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkVehicle not implemented")
+}
+func (UnimplementedDriverServiceServer) SelectVehicle(context.Context, *SelectVehicleRequest) (*SelectVehicleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectVehicle not implemented")
+}
+func (UnimplementedDriverServiceServer) ListVehicles(context.Context, *ListVehiclesRequest) (*ListVehiclesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVehicles not implemented")
+}
 func (UnimplementedDriverServiceServer) mustEmbedUnimplementedDriverServiceServer() {}
 func (UnimplementedDriverServiceServer) testEmbeddedByValue()                       {}
 
@@ -172,6 +293,125 @@ func _DriverService_ListDrivers_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DriverService_GetRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetRoute(ctx, req.(*GetRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_SetDriverStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDriverStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).SetDriverStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_SetDriverStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).SetDriverStatus(ctx, req.(*SetDriverStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_WatchDriverStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDriverStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServiceServer).WatchDriverStatus(m, &grpc.GenericServerStream[WatchDriverStatusRequest, DriverStatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DriverService_WatchDriverStatusServer = grpc.ServerStreamingServer[DriverStatusEvent]
+
+func _DriverService_RegisterVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).RegisterVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_RegisterVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).RegisterVehicle(ctx, req.(*RegisterVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_LinkVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).LinkVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_LinkVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).LinkVehicle(ctx, req.(*LinkVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_SelectVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).SelectVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_SelectVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).SelectVehicle(ctx, req.(*SelectVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_ListVehicles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVehiclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).ListVehicles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_ListVehicles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).ListVehicles(ctx, req.(*ListVehiclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DriverService_ServiceDesc is the grpc.ServiceDesc for DriverService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,7 +431,37 @@ var DriverService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListDrivers",
 			Handler:    _DriverService_ListDrivers_Handler,
 		},
+		{
+			MethodName: "GetRoute",
+			Handler:    _DriverService_GetRoute_Handler,
+		},
+		{
+			MethodName: "SetDriverStatus",
+			Handler:    _DriverService_SetDriverStatus_Handler,
+		},
+		{
+			MethodName: "RegisterVehicle",
+			Handler:    _DriverService_RegisterVehicle_Handler,
+		},
+		{
+			MethodName: "LinkVehicle",
+			Handler:    _DriverService_LinkVehicle_Handler,
+		},
+		{
+			MethodName: "SelectVehicle",
+			Handler:    _DriverService_SelectVehicle_Handler,
+		},
+		{
+			MethodName: "ListVehicles",
+			Handler:    _DriverService_ListVehicles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDriverStatus",
+			Handler:       _DriverService_WatchDriverStatus_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/driver.proto",
 }
@@ -220,8 +220,14 @@ type GatewayDriver struct {
 	Route          *GatewayRoute          `protobuf:"bytes,7,opt,name=route,proto3" json:"route,omitempty"`
 	Latitude       float64                `protobuf:"fixed64,8,opt,name=latitude,proto3" json:"latitude,omitempty"`
 	Longitude      float64                `protobuf:"fixed64,9,opt,name=longitude,proto3" json:"longitude,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	Presence       string                 `protobuf:"bytes,10,opt,name=presence,proto3" json:"presence,omitempty"`
+	LastSeenAt     string                 `protobuf:"bytes,11,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	// rating_average is the mean of the stars riders gave the driver, over
+	// rating_count ratings; both are zero until the driver is first rated.
+	RatingAverage float64 `protobuf:"fixed64,12,opt,name=rating_average,json=ratingAverage,proto3" json:"rating_average,omitempty"`
+	RatingCount   int32   `protobuf:"varint,13,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GatewayDriver) Reset() {
@@ -317,14 +323,45 @@ func (x *GatewayDriver) GetLongitude() float64 {
 	return 0
 }
 
+func (x *GatewayDriver) GetPresence() string {
+	if x != nil {
+		return x.Presence
+	}
+	return ""
+}
+
+func (x *GatewayDriver) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+func (x *GatewayDriver) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *GatewayDriver) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
 type GatewayRider struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
-	ArrivalTime   string                 `protobuf:"bytes,4,opt,name=arrival_time,json=arrivalTime,proto3" json:"arrival_time,omitempty"` // ISO timestamp
-	StationId     string                 `protobuf:"bytes,5,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
-	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Destination string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	ArrivalTime string                 `protobuf:"bytes,4,opt,name=arrival_time,json=arrivalTime,proto3" json:"arrival_time,omitempty"` // ISO timestamp
+	StationId   string                 `protobuf:"bytes,5,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	Status      string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// rating_average is the mean of the stars drivers gave the rider.
+	RatingAverage float64 `protobuf:"fixed64,7,opt,name=rating_average,json=ratingAverage,proto3" json:"rating_average,omitempty"`
+	RatingCount   int32   `protobuf:"varint,8,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -401,6 +438,20 @@ func (x *GatewayRider) GetStatus() string {
 	return ""
 }
 
+func (x *GatewayRider) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *GatewayRider) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
 type GatewayTrip struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -413,6 +464,9 @@ type GatewayTrip struct {
 	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO timestamp
 	PickupPointId string                 `protobuf:"bytes,9,opt,name=pickup_point_id,json=pickupPointId,proto3" json:"pickup_point_id,omitempty"`
 	Pickup        *GatewayPickupPoint    `protobuf:"bytes,10,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	// vehicle_plate and vehicle_colour identify the car picking the rider up.
+	VehiclePlate  string `protobuf:"bytes,11,opt,name=vehicle_plate,json=vehiclePlate,proto3" json:"vehicle_plate,omitempty"`
+	VehicleColour string `protobuf:"bytes,12,opt,name=vehicle_colour,json=vehicleColour,proto3" json:"vehicle_colour,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -517,6 +571,20 @@ func (x *GatewayTrip) GetPickup() *GatewayPickupPoint {
 	return nil
 }
 
+func (x *GatewayTrip) GetVehiclePlate() string {
+	if x != nil {
+		return x.VehiclePlate
+	}
+	return ""
+}
+
+func (x *GatewayTrip) GetVehicleColour() string {
+	if x != nil {
+		return x.VehicleColour
+	}
+	return ""
+}
+
 type GatewayStation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -602,14 +670,16 @@ func (x *GatewayStation) GetLongitude() float64 {
 }
 
 type BackendMetrics struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	PendingMatches int32                  `protobuf:"varint,1,opt,name=pending_matches,json=pendingMatches,proto3" json:"pending_matches,omitempty"`
-	RidersWaiting  int32                  `protobuf:"varint,2,opt,name=riders_waiting,json=ridersWaiting,proto3" json:"riders_waiting,omitempty"`
-	SeatsOpen      int32                  `protobuf:"varint,3,opt,name=seats_open,json=seatsOpen,proto3" json:"seats_open,omitempty"`
-	AvgWaitMinutes float64                `protobuf:"fixed64,4,opt,name=avg_wait_minutes,json=avgWaitMinutes,proto3" json:"avg_wait_minutes,omitempty"`
-	Version        string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	PendingMatches     int32                  `protobuf:"varint,1,opt,name=pending_matches,json=pendingMatches,proto3" json:"pending_matches,omitempty"`
+	RidersWaiting      int32                  `protobuf:"varint,2,opt,name=riders_waiting,json=ridersWaiting,proto3" json:"riders_waiting,omitempty"`
+	SeatsOpen          int32                  `protobuf:"varint,3,opt,name=seats_open,json=seatsOpen,proto3" json:"seats_open,omitempty"`
+	AvgWaitMinutes     float64                `protobuf:"fixed64,4,opt,name=avg_wait_minutes,json=avgWaitMinutes,proto3" json:"avg_wait_minutes,omitempty"`
+	Version            string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	MatchWindowMinutes float64                `protobuf:"fixed64,6,opt,name=match_window_minutes,json=matchWindowMinutes,proto3" json:"match_window_minutes,omitempty"`
+	RidersDeferred     int32                  `protobuf:"varint,7,opt,name=riders_deferred,json=ridersDeferred,proto3" json:"riders_deferred,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *BackendMetrics) Reset() {
@@ -677,6 +747,20 @@ func (x *BackendMetrics) GetVersion() string {
 	return ""
 }
 
+func (x *BackendMetrics) GetMatchWindowMinutes() float64 {
+	if x != nil {
+		return x.MatchWindowMinutes
+	}
+	return 0
+}
+
+func (x *BackendMetrics) GetRidersDeferred() int32 {
+	if x != nil {
+		return x.RidersDeferred
+	}
+	return 0
+}
+
 type BackendSnapshot struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Drivers       []*GatewayDriver       `protobuf:"bytes,1,rep,name=drivers,proto3" json:"drivers,omitempty"`
@@ -770,11 +854,12 @@ func (x *BackendSnapshot) GetLastUpdated() string {
 }
 
 type TriggerMatchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
-	StationId     string                 `protobuf:"bytes,2,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	DriverId             string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	StationId            string                 `protobuf:"bytes,2,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	ArrivalWindowMinutes int32                  `protobuf:"varint,3,opt,name=arrival_window_minutes,json=arrivalWindowMinutes,proto3" json:"arrival_window_minutes,omitempty"` // 0 uses the gateway default
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *TriggerMatchRequest) Reset() {
@@ -821,6 +906,13 @@ func (x *TriggerMatchRequest) GetStationId() string {
 	return ""
 }
 
+func (x *TriggerMatchRequest) GetArrivalWindowMinutes() int32 {
+	if x != nil {
+		return x.ArrivalWindowMinutes
+	}
+	return 0
+}
+
 var File_api_gateway_proto protoreflect.FileDescriptor
 
 const file_api_gateway_proto_rawDesc = "" +
@@ -839,7 +931,7 @@ const file_api_gateway_proto_rawDesc = "" +
 	"station_id\x18\x03 \x01(\tR\tstationId\x12!\n" +
 	"\fstation_name\x18\x04 \x01(\tR\vstationName\x12\x1a\n" +
 	"\blatitude\x18\x05 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\"\x9d\x02\n" +
+	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\"\xa5\x03\n" +
 	"\rGatewayDriver\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
@@ -851,7 +943,13 @@ const file_api_gateway_proto_rawDesc = "" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x12+\n" +
 	"\x05route\x18\a \x01(\v2\x15.gateway.GatewayRouteR\x05route\x12\x1a\n" +
 	"\blatitude\x18\b \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\t \x01(\x01R\tlongitude\"\xae\x01\n" +
+	"\tlongitude\x18\t \x01(\x01R\tlongitude\x12\x1a\n" +
+	"\bpresence\x18\n" +
+	" \x01(\tR\bpresence\x12 \n" +
+	"\flast_seen_at\x18\v \x01(\tR\n" +
+	"lastSeenAt\x12%\n" +
+	"\x0erating_average\x18\f \x01(\x01R\rratingAverage\x12!\n" +
+	"\frating_count\x18\r \x01(\x05R\vratingCount\"\xf8\x01\n" +
 	"\fGatewayRider\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -859,7 +957,9 @@ const file_api_gateway_proto_rawDesc = "" +
 	"\farrival_time\x18\x04 \x01(\tR\varrivalTime\x12\x1d\n" +
 	"\n" +
 	"station_id\x18\x05 \x01(\tR\tstationId\x12\x16\n" +
-	"\x06status\x18\x06 \x01(\tR\x06status\"\xcb\x02\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12%\n" +
+	"\x0erating_average\x18\a \x01(\x01R\rratingAverage\x12!\n" +
+	"\frating_count\x18\b \x01(\x05R\vratingCount\"\x97\x03\n" +
 	"\vGatewayTrip\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12\x19\n" +
@@ -874,7 +974,9 @@ const file_api_gateway_proto_rawDesc = "" +
 	"created_at\x18\b \x01(\tR\tcreatedAt\x12&\n" +
 	"\x0fpickup_point_id\x18\t \x01(\tR\rpickupPointId\x123\n" +
 	"\x06pickup\x18\n" +
-	" \x01(\v2\x1b.gateway.GatewayPickupPointR\x06pickup\"\xb2\x01\n" +
+	" \x01(\v2\x1b.gateway.GatewayPickupPointR\x06pickup\x12#\n" +
+	"\rvehicle_plate\x18\v \x01(\tR\fvehiclePlate\x12%\n" +
+	"\x0evehicle_colour\x18\f \x01(\tR\rvehicleColour\"\xb2\x01\n" +
 	"\x0eGatewayStation\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
@@ -882,14 +984,16 @@ const file_api_gateway_proto_rawDesc = "" +
 	"\vload_factor\x18\x04 \x01(\x01R\n" +
 	"loadFactor\x12\x1a\n" +
 	"\blatitude\x18\x05 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\"\xc3\x01\n" +
+	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\"\x9e\x02\n" +
 	"\x0eBackendMetrics\x12'\n" +
 	"\x0fpending_matches\x18\x01 \x01(\x05R\x0ependingMatches\x12%\n" +
 	"\x0eriders_waiting\x18\x02 \x01(\x05R\rridersWaiting\x12\x1d\n" +
 	"\n" +
 	"seats_open\x18\x03 \x01(\x05R\tseatsOpen\x12(\n" +
 	"\x10avg_wait_minutes\x18\x04 \x01(\x01R\x0eavgWaitMinutes\x12\x18\n" +
-	"\aversion\x18\x05 \x01(\tR\aversion\"\xe6\x02\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x120\n" +
+	"\x14match_window_minutes\x18\x06 \x01(\x01R\x12matchWindowMinutes\x12'\n" +
+	"\x0friders_deferred\x18\a \x01(\x05R\x0eridersDeferred\"\xe6\x02\n" +
 	"\x0fBackendSnapshot\x120\n" +
 	"\adrivers\x18\x01 \x03(\v2\x16.gateway.GatewayDriverR\adrivers\x12-\n" +
 	"\x06riders\x18\x02 \x03(\v2\x15.gateway.GatewayRiderR\x06riders\x12*\n" +
@@ -897,11 +1001,12 @@ const file_api_gateway_proto_rawDesc = "" +
 	"\bstations\x18\x04 \x03(\v2\x17.gateway.GatewayStationR\bstations\x121\n" +
 	"\ametrics\x18\x05 \x01(\v2\x17.gateway.BackendMetricsR\ametrics\x12;\n" +
 	"\x0ehighlight_trip\x18\x06 \x01(\v2\x14.gateway.GatewayTripR\rhighlightTrip\x12!\n" +
-	"\flast_updated\x18\a \x01(\tR\vlastUpdated\"Q\n" +
+	"\flast_updated\x18\a \x01(\tR\vlastUpdated\"\x87\x01\n" +
 	"\x13TriggerMatchRequest\x12\x1b\n" +
 	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1d\n" +
 	"\n" +
-	"station_id\x18\x02 \x01(\tR\tstationId2\x97\x01\n" +
+	"station_id\x18\x02 \x01(\tR\tstationId\x124\n" +
+	"\x16arrival_window_minutes\x18\x03 \x01(\x05R\x14arrivalWindowMinutes2\x97\x01\n" +
 	"\x0eGatewayService\x12A\n" +
 	"\vGetSnapshot\x12\x18.gateway.SnapshotRequest\x1a\x18.gateway.BackendSnapshot\x12B\n" +
 	"\fTriggerMatch\x12\x1c.gateway.TriggerMatchRequest\x1a\x14.gateway.GatewayTripB\x19Z\x17lastmile/gen/go/gatewayb\x06proto3"
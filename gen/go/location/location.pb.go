@@ -21,11 +21,75 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type GeofenceEventType int32
+
+const (
+	GeofenceEventType_GEOFENCE_EVENT_TYPE_UNSPECIFIED GeofenceEventType = 0
+	GeofenceEventType_GEOFENCE_EVENT_TYPE_ENTER       GeofenceEventType = 1
+	GeofenceEventType_GEOFENCE_EVENT_TYPE_DWELL       GeofenceEventType = 2
+	GeofenceEventType_GEOFENCE_EVENT_TYPE_EXIT        GeofenceEventType = 3
+)
+
+// Enum value maps for GeofenceEventType.
+var (
+	GeofenceEventType_name = map[int32]string{
+		0: "GEOFENCE_EVENT_TYPE_UNSPECIFIED",
+		1: "GEOFENCE_EVENT_TYPE_ENTER",
+		2: "GEOFENCE_EVENT_TYPE_DWELL",
+		3: "GEOFENCE_EVENT_TYPE_EXIT",
+	}
+	GeofenceEventType_value = map[string]int32{
+		"GEOFENCE_EVENT_TYPE_UNSPECIFIED": 0,
+		"GEOFENCE_EVENT_TYPE_ENTER":       1,
+		"GEOFENCE_EVENT_TYPE_DWELL":       2,
+		"GEOFENCE_EVENT_TYPE_EXIT":        3,
+	}
+)
+
+func (x GeofenceEventType) Enum() *GeofenceEventType {
+	p := new(GeofenceEventType)
+	*p = x
+	return p
+}
+
+func (x GeofenceEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GeofenceEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_location_proto_enumTypes[0].Descriptor()
+}
+
+func (GeofenceEventType) Type() protoreflect.EnumType {
+	return &file_api_location_proto_enumTypes[0]
+}
+
+func (x GeofenceEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GeofenceEventType.Descriptor instead.
+func (GeofenceEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{0}
+}
+
 type Location struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
-	Latitude      float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Longitude     float64                `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	DriverId  string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Latitude  float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// recorded_at is when the device took the fix, as an RFC3339 timestamp.
+	// The server stamps samples that arrive without one.
+	RecordedAt string  `protobuf:"bytes,4,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	SpeedMps   float64 `protobuf:"fixed64,5,opt,name=speed_mps,json=speedMps,proto3" json:"speed_mps,omitempty"`
+	// heading_degrees is clockwise from true north.
+	HeadingDegrees float64 `protobuf:"fixed64,6,opt,name=heading_degrees,json=headingDegrees,proto3" json:"heading_degrees,omitempty"`
+	// accuracy_meters is the horizontal accuracy radius; 0 means unknown.
+	AccuracyMeters float64 `protobuf:"fixed64,7,opt,name=accuracy_meters,json=accuracyMeters,proto3" json:"accuracy_meters,omitempty"`
+	// simulated marks samples from the gateway's built-in simulator and trace
+	// replays, which can jump when seeking or resuming and skip teleport
+	// detection.
+	Simulated     bool `protobuf:"varint,8,opt,name=simulated,proto3" json:"simulated,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,6 +145,41 @@ func (x *Location) GetLongitude() float64 {
 	return 0
 }
 
+func (x *Location) GetRecordedAt() string {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return ""
+}
+
+func (x *Location) GetSpeedMps() float64 {
+	if x != nil {
+		return x.SpeedMps
+	}
+	return 0
+}
+
+func (x *Location) GetHeadingDegrees() float64 {
+	if x != nil {
+		return x.HeadingDegrees
+	}
+	return 0
+}
+
+func (x *Location) GetAccuracyMeters() float64 {
+	if x != nil {
+		return x.AccuracyMeters
+	}
+	return 0
+}
+
+func (x *Location) GetSimulated() bool {
+	if x != nil {
+		return x.Simulated
+	}
+	return false
+}
+
 type UpdateLocationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
@@ -126,8 +225,15 @@ func (x *UpdateLocationRequest) GetLocation() *Location {
 }
 
 type UpdateLocationResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Success  bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Accepted int32                  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// dropped counts samples rejected as out of order or too inaccurate.
+	Dropped int32 `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	// quarantined counts samples held back as impossible jumps.
+	Quarantined int32 `protobuf:"varint,4,opt,name=quarantined,proto3" json:"quarantined,omitempty"`
+	// location is the smoothed position after the last accepted sample.
+	Location      *Location `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,6 +275,34 @@ func (x *UpdateLocationResponse) GetSuccess() bool {
 	return false
 }
 
+func (x *UpdateLocationResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *UpdateLocationResponse) GetDropped() int32 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *UpdateLocationResponse) GetQuarantined() int32 {
+	if x != nil {
+		return x.Quarantined
+	}
+	return 0
+}
+
+func (x *UpdateLocationResponse) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
 type GetDriverLocationsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DriverIds     []string               `protobuf:"bytes,1,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"`
@@ -257,9 +391,16 @@ func (x *GetDriverLocationsResponse) GetLocations() []*Location {
 	return nil
 }
 
+// SubscribeLocationRequest selects the drivers a stream follows. A sample is
+// delivered when it matches any of the set filters; an empty request follows
+// every driver.
 type SubscribeLocationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	DriverId  string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	DriverIds []string               `protobuf:"bytes,2,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"`
+	Bounds    *BoundingBox           `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	// station_ids follows drivers inside those stations' geofences.
+	StationIds    []string `protobuf:"bytes,4,rep,name=station_ids,json=stationIds,proto3" json:"station_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -301,18 +442,114 @@ func (x *SubscribeLocationRequest) GetDriverId() string {
 	return ""
 }
 
-type LocationUpdate struct {
+func (x *SubscribeLocationRequest) GetDriverIds() []string {
+	if x != nil {
+		return x.DriverIds
+	}
+	return nil
+}
+
+func (x *SubscribeLocationRequest) GetBounds() *BoundingBox {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *SubscribeLocationRequest) GetStationIds() []string {
+	if x != nil {
+		return x.StationIds
+	}
+	return nil
+}
+
+type BoundingBox struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
-	Latitude      float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Longitude     float64                `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	MinLatitude   float64                `protobuf:"fixed64,1,opt,name=min_latitude,json=minLatitude,proto3" json:"min_latitude,omitempty"`
+	MinLongitude  float64                `protobuf:"fixed64,2,opt,name=min_longitude,json=minLongitude,proto3" json:"min_longitude,omitempty"`
+	MaxLatitude   float64                `protobuf:"fixed64,3,opt,name=max_latitude,json=maxLatitude,proto3" json:"max_latitude,omitempty"`
+	MaxLongitude  float64                `protobuf:"fixed64,4,opt,name=max_longitude,json=maxLongitude,proto3" json:"max_longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoundingBox) Reset() {
+	*x = BoundingBox{}
+	mi := &file_api_location_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoundingBox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoundingBox) ProtoMessage() {}
+
+func (x *BoundingBox) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoundingBox.ProtoReflect.Descriptor instead.
+func (*BoundingBox) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BoundingBox) GetMinLatitude() float64 {
+	if x != nil {
+		return x.MinLatitude
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMinLongitude() float64 {
+	if x != nil {
+		return x.MinLongitude
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLatitude() float64 {
+	if x != nil {
+		return x.MaxLatitude
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLongitude() float64 {
+	if x != nil {
+		return x.MaxLongitude
+	}
+	return 0
+}
+
+type LocationUpdate struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DriverId       string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Latitude       float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude      float64                `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	RecordedAt     string                 `protobuf:"bytes,4,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	SpeedMps       float64                `protobuf:"fixed64,5,opt,name=speed_mps,json=speedMps,proto3" json:"speed_mps,omitempty"`
+	HeadingDegrees float64                `protobuf:"fixed64,6,opt,name=heading_degrees,json=headingDegrees,proto3" json:"heading_degrees,omitempty"`
+	AccuracyMeters float64                `protobuf:"fixed64,7,opt,name=accuracy_meters,json=accuracyMeters,proto3" json:"accuracy_meters,omitempty"`
+	// left_area is set on the sample that took the driver out of the
+	// subscribed bounds or stations; none follow until they come back.
+	LeftArea      bool `protobuf:"varint,8,opt,name=left_area,json=leftArea,proto3" json:"left_area,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LocationUpdate) Reset() {
 	*x = LocationUpdate{}
-	mi := &file_api_location_proto_msgTypes[6]
+	mi := &file_api_location_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -324,7 +561,7 @@ func (x *LocationUpdate) String() string {
 func (*LocationUpdate) ProtoMessage() {}
 
 func (x *LocationUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_api_location_proto_msgTypes[6]
+	mi := &file_api_location_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,7 +574,7 @@ func (x *LocationUpdate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LocationUpdate.ProtoReflect.Descriptor instead.
 func (*LocationUpdate) Descriptor() ([]byte, []int) {
-	return file_api_location_proto_rawDescGZIP(), []int{6}
+	return file_api_location_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *LocationUpdate) GetDriverId() string {
@@ -361,34 +598,1073 @@ func (x *LocationUpdate) GetLongitude() float64 {
 	return 0
 }
 
-var File_api_location_proto protoreflect.FileDescriptor
+func (x *LocationUpdate) GetRecordedAt() string {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return ""
+}
 
-const file_api_location_proto_rawDesc = "" +
-	"\n" +
-	"\x12api/location.proto\x12\blocation\"a\n" +
-	"\bLocation\x12\x1b\n" +
-	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1a\n" +
-	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x03 \x01(\x01R\tlongitude\"G\n" +
-	"\x15UpdateLocationRequest\x12.\n" +
-	"\blocation\x18\x01 \x01(\v2\x12.location.LocationR\blocation\"2\n" +
-	"\x16UpdateLocationResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\":\n" +
-	"\x19GetDriverLocationsRequest\x12\x1d\n" +
-	"\n" +
-	"driver_ids\x18\x01 \x03(\tR\tdriverIds\"N\n" +
-	"\x1aGetDriverLocationsResponse\x120\n" +
-	"\tlocations\x18\x01 \x03(\v2\x12.location.LocationR\tlocations\"7\n" +
-	"\x18SubscribeLocationRequest\x12\x1b\n" +
-	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\"g\n" +
-	"\x0eLocationUpdate\x12\x1b\n" +
-	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1a\n" +
-	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x03 \x01(\x01R\tlongitude2\xa5\x02\n" +
+func (x *LocationUpdate) GetSpeedMps() float64 {
+	if x != nil {
+		return x.SpeedMps
+	}
+	return 0
+}
+
+func (x *LocationUpdate) GetHeadingDegrees() float64 {
+	if x != nil {
+		return x.HeadingDegrees
+	}
+	return 0
+}
+
+func (x *LocationUpdate) GetAccuracyMeters() float64 {
+	if x != nil {
+		return x.AccuracyMeters
+	}
+	return 0
+}
+
+func (x *LocationUpdate) GetLeftArea() bool {
+	if x != nil {
+		return x.LeftArea
+	}
+	return false
+}
+
+type GeofenceEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Type     GeofenceEventType      `protobuf:"varint,1,opt,name=type,proto3,enum=location.GeofenceEventType" json:"type,omitempty"`
+	DriverId string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	FenceId  string                 `protobuf:"bytes,3,opt,name=fence_id,json=fenceId,proto3" json:"fence_id,omitempty"`
+	// kind is "station" or "pickup".
+	Kind       string  `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	StationId  string  `protobuf:"bytes,5,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	Latitude   float64 `protobuf:"fixed64,6,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude  float64 `protobuf:"fixed64,7,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	OccurredAt string  `protobuf:"bytes,8,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// dwell_seconds is how long the driver had been inside, set on dwell and exit.
+	DwellSeconds  int64 `protobuf:"varint,9,opt,name=dwell_seconds,json=dwellSeconds,proto3" json:"dwell_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeofenceEvent) Reset() {
+	*x = GeofenceEvent{}
+	mi := &file_api_location_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeofenceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeofenceEvent) ProtoMessage() {}
+
+func (x *GeofenceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeofenceEvent.ProtoReflect.Descriptor instead.
+func (*GeofenceEvent) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GeofenceEvent) GetType() GeofenceEventType {
+	if x != nil {
+		return x.Type
+	}
+	return GeofenceEventType_GEOFENCE_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *GeofenceEvent) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetFenceId() string {
+	if x != nil {
+		return x.FenceId
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetStationId() string {
+	if x != nil {
+		return x.StationId
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeofenceEvent) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *GeofenceEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetDwellSeconds() int64 {
+	if x != nil {
+		return x.DwellSeconds
+	}
+	return 0
+}
+
+type WatchGeofenceEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverIds     []string               `protobuf:"bytes,1,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"`
+	StationIds    []string               `protobuf:"bytes,2,rep,name=station_ids,json=stationIds,proto3" json:"station_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchGeofenceEventsRequest) Reset() {
+	*x = WatchGeofenceEventsRequest{}
+	mi := &file_api_location_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchGeofenceEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGeofenceEventsRequest) ProtoMessage() {}
+
+func (x *WatchGeofenceEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGeofenceEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchGeofenceEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchGeofenceEventsRequest) GetDriverIds() []string {
+	if x != nil {
+		return x.DriverIds
+	}
+	return nil
+}
+
+func (x *WatchGeofenceEventsRequest) GetStationIds() []string {
+	if x != nil {
+		return x.StationIds
+	}
+	return nil
+}
+
+type FindDriversNearRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Latitude     float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude    float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	RadiusMeters float64                `protobuf:"fixed64,3,opt,name=radius_meters,json=radiusMeters,proto3" json:"radius_meters,omitempty"`
+	// limit caps the number of drivers returned; 0 means no cap.
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// unpositioned_candidates are drivers the caller has no position for;
+	// those this service has none for either come back in
+	// unpositioned_driver_ids so the caller can still consider them.
+	UnpositionedCandidates []string `protobuf:"bytes,5,rep,name=unpositioned_candidates,json=unpositionedCandidates,proto3" json:"unpositioned_candidates,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *FindDriversNearRequest) Reset() {
+	*x = FindDriversNearRequest{}
+	mi := &file_api_location_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindDriversNearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindDriversNearRequest) ProtoMessage() {}
+
+func (x *FindDriversNearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindDriversNearRequest.ProtoReflect.Descriptor instead.
+func (*FindDriversNearRequest) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FindDriversNearRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *FindDriversNearRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *FindDriversNearRequest) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
+
+func (x *FindDriversNearRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FindDriversNearRequest) GetUnpositionedCandidates() []string {
+	if x != nil {
+		return x.UnpositionedCandidates
+	}
+	return nil
+}
+
+type NearbyDriver struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Location       *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	DistanceMeters float64                `protobuf:"fixed64,2,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NearbyDriver) Reset() {
+	*x = NearbyDriver{}
+	mi := &file_api_location_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearbyDriver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyDriver) ProtoMessage() {}
+
+func (x *NearbyDriver) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyDriver.ProtoReflect.Descriptor instead.
+func (*NearbyDriver) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *NearbyDriver) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *NearbyDriver) GetDistanceMeters() float64 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+type FindDriversNearResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// drivers are sorted nearest first.
+	Drivers []*NearbyDriver `protobuf:"bytes,1,rep,name=drivers,proto3" json:"drivers,omitempty"`
+	// unpositioned_driver_ids are the unpositioned_candidates with no known
+	// position anywhere.
+	UnpositionedDriverIds []string `protobuf:"bytes,2,rep,name=unpositioned_driver_ids,json=unpositionedDriverIds,proto3" json:"unpositioned_driver_ids,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *FindDriversNearResponse) Reset() {
+	*x = FindDriversNearResponse{}
+	mi := &file_api_location_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindDriversNearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindDriversNearResponse) ProtoMessage() {}
+
+func (x *FindDriversNearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindDriversNearResponse.ProtoReflect.Descriptor instead.
+func (*FindDriversNearResponse) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FindDriversNearResponse) GetDrivers() []*NearbyDriver {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
+func (x *FindDriversNearResponse) GetUnpositionedDriverIds() []string {
+	if x != nil {
+		return x.UnpositionedDriverIds
+	}
+	return nil
+}
+
+type GetDriverTrackRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverId string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	// from and to bound the track as RFC3339 timestamps; empty means unbounded.
+	From          string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverTrackRequest) Reset() {
+	*x = GetDriverTrackRequest{}
+	mi := &file_api_location_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverTrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverTrackRequest) ProtoMessage() {}
+
+func (x *GetDriverTrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverTrackRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverTrackRequest) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDriverTrackRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *GetDriverTrackRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetDriverTrackRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type TrackPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	RecordedAt    string                 `protobuf:"bytes,3,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackPoint) Reset() {
+	*x = TrackPoint{}
+	mi := &file_api_location_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackPoint) ProtoMessage() {}
+
+func (x *TrackPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackPoint.ProtoReflect.Descriptor instead.
+func (*TrackPoint) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TrackPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *TrackPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *TrackPoint) GetRecordedAt() string {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return ""
+}
+
+type GetDriverTrackResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverId string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	// points are in chronological order.
+	Points         []*TrackPoint `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	DistanceMeters float64       `protobuf:"fixed64,3,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetDriverTrackResponse) Reset() {
+	*x = GetDriverTrackResponse{}
+	mi := &file_api_location_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverTrackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverTrackResponse) ProtoMessage() {}
+
+func (x *GetDriverTrackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverTrackResponse.ProtoReflect.Descriptor instead.
+func (*GetDriverTrackResponse) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetDriverTrackResponse) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *GetDriverTrackResponse) GetPoints() []*TrackPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *GetDriverTrackResponse) GetDistanceMeters() float64 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+type GetLocationAnomaliesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// driver_ids limits the report; empty means every driver with anomalies.
+	DriverIds     []string `protobuf:"bytes,1,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLocationAnomaliesRequest) Reset() {
+	*x = GetLocationAnomaliesRequest{}
+	mi := &file_api_location_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLocationAnomaliesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLocationAnomaliesRequest) ProtoMessage() {}
+
+func (x *GetLocationAnomaliesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLocationAnomaliesRequest.ProtoReflect.Descriptor instead.
+func (*GetLocationAnomaliesRequest) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetLocationAnomaliesRequest) GetDriverIds() []string {
+	if x != nil {
+		return x.DriverIds
+	}
+	return nil
+}
+
+// DriverAnomalies counts the samples a driver sent that were not taken as truth.
+type DriverAnomalies struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverId string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	// teleports are samples quarantined as impossible jumps.
+	Teleports int64 `protobuf:"varint,2,opt,name=teleports,proto3" json:"teleports,omitempty"`
+	// reanchors count runs of jumps that were accepted as a genuine relocation.
+	Reanchors     int64  `protobuf:"varint,3,opt,name=reanchors,proto3" json:"reanchors,omitempty"`
+	OutOfOrder    int64  `protobuf:"varint,4,opt,name=out_of_order,json=outOfOrder,proto3" json:"out_of_order,omitempty"`
+	LowAccuracy   int64  `protobuf:"varint,5,opt,name=low_accuracy,json=lowAccuracy,proto3" json:"low_accuracy,omitempty"`
+	Invalid       int64  `protobuf:"varint,6,opt,name=invalid,proto3" json:"invalid,omitempty"`
+	LastKind      string `protobuf:"bytes,7,opt,name=last_kind,json=lastKind,proto3" json:"last_kind,omitempty"`
+	LastAt        string `protobuf:"bytes,8,opt,name=last_at,json=lastAt,proto3" json:"last_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverAnomalies) Reset() {
+	*x = DriverAnomalies{}
+	mi := &file_api_location_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverAnomalies) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverAnomalies) ProtoMessage() {}
+
+func (x *DriverAnomalies) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverAnomalies.ProtoReflect.Descriptor instead.
+func (*DriverAnomalies) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DriverAnomalies) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *DriverAnomalies) GetTeleports() int64 {
+	if x != nil {
+		return x.Teleports
+	}
+	return 0
+}
+
+func (x *DriverAnomalies) GetReanchors() int64 {
+	if x != nil {
+		return x.Reanchors
+	}
+	return 0
+}
+
+func (x *DriverAnomalies) GetOutOfOrder() int64 {
+	if x != nil {
+		return x.OutOfOrder
+	}
+	return 0
+}
+
+func (x *DriverAnomalies) GetLowAccuracy() int64 {
+	if x != nil {
+		return x.LowAccuracy
+	}
+	return 0
+}
+
+func (x *DriverAnomalies) GetInvalid() int64 {
+	if x != nil {
+		return x.Invalid
+	}
+	return 0
+}
+
+func (x *DriverAnomalies) GetLastKind() string {
+	if x != nil {
+		return x.LastKind
+	}
+	return ""
+}
+
+func (x *DriverAnomalies) GetLastAt() string {
+	if x != nil {
+		return x.LastAt
+	}
+	return ""
+}
+
+type GetLocationAnomaliesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Drivers       []*DriverAnomalies     `protobuf:"bytes,1,rep,name=drivers,proto3" json:"drivers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLocationAnomaliesResponse) Reset() {
+	*x = GetLocationAnomaliesResponse{}
+	mi := &file_api_location_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLocationAnomaliesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLocationAnomaliesResponse) ProtoMessage() {}
+
+func (x *GetLocationAnomaliesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLocationAnomaliesResponse.ProtoReflect.Descriptor instead.
+func (*GetLocationAnomaliesResponse) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetLocationAnomaliesResponse) GetDrivers() []*DriverAnomalies {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
+type ListLocationSubscribersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocationSubscribersRequest) Reset() {
+	*x = ListLocationSubscribersRequest{}
+	mi := &file_api_location_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocationSubscribersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocationSubscribersRequest) ProtoMessage() {}
+
+func (x *ListLocationSubscribersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocationSubscribersRequest.ProtoReflect.Descriptor instead.
+func (*ListLocationSubscribersRequest) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{19}
+}
+
+// LocationSubscriberStats describes one SubscribeLocationUpdates stream.
+// Updates replaced by a newer one for the same driver before they were sent
+// count as dropped; lag is how long the oldest unsent update has waited.
+type LocationSubscriberStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ConnectedAt   string                 `protobuf:"bytes,2,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	Delivered     int64                  `protobuf:"varint,3,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Dropped       int64                  `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Pending       int32                  `protobuf:"varint,5,opt,name=pending,proto3" json:"pending,omitempty"`
+	LagSeconds    float64                `protobuf:"fixed64,6,opt,name=lag_seconds,json=lagSeconds,proto3" json:"lag_seconds,omitempty"`
+	MaxLagSeconds float64                `protobuf:"fixed64,7,opt,name=max_lag_seconds,json=maxLagSeconds,proto3" json:"max_lag_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationSubscriberStats) Reset() {
+	*x = LocationSubscriberStats{}
+	mi := &file_api_location_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationSubscriberStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationSubscriberStats) ProtoMessage() {}
+
+func (x *LocationSubscriberStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationSubscriberStats.ProtoReflect.Descriptor instead.
+func (*LocationSubscriberStats) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LocationSubscriberStats) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LocationSubscriberStats) GetConnectedAt() string {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return ""
+}
+
+func (x *LocationSubscriberStats) GetDelivered() int64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *LocationSubscriberStats) GetDropped() int64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *LocationSubscriberStats) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *LocationSubscriberStats) GetLagSeconds() float64 {
+	if x != nil {
+		return x.LagSeconds
+	}
+	return 0
+}
+
+func (x *LocationSubscriberStats) GetMaxLagSeconds() float64 {
+	if x != nil {
+		return x.MaxLagSeconds
+	}
+	return 0
+}
+
+type ListLocationSubscribersResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Subscribers   []*LocationSubscriberStats `protobuf:"bytes,1,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocationSubscribersResponse) Reset() {
+	*x = ListLocationSubscribersResponse{}
+	mi := &file_api_location_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocationSubscribersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocationSubscribersResponse) ProtoMessage() {}
+
+func (x *ListLocationSubscribersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_location_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocationSubscribersResponse.ProtoReflect.Descriptor instead.
+func (*ListLocationSubscribersResponse) Descriptor() ([]byte, []int) {
+	return file_api_location_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListLocationSubscribersResponse) GetSubscribers() []*LocationSubscriberStats {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+var File_api_location_proto protoreflect.FileDescriptor
+
+const file_api_location_proto_rawDesc = "" +
+	"\n" +
+	"\x12api/location.proto\x12\blocation\"\x8f\x02\n" +
+	"\bLocation\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1a\n" +
+	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x03 \x01(\x01R\tlongitude\x12\x1f\n" +
+	"\vrecorded_at\x18\x04 \x01(\tR\n" +
+	"recordedAt\x12\x1b\n" +
+	"\tspeed_mps\x18\x05 \x01(\x01R\bspeedMps\x12'\n" +
+	"\x0fheading_degrees\x18\x06 \x01(\x01R\x0eheadingDegrees\x12'\n" +
+	"\x0faccuracy_meters\x18\a \x01(\x01R\x0eaccuracyMeters\x12\x1c\n" +
+	"\tsimulated\x18\b \x01(\bR\tsimulated\"G\n" +
+	"\x15UpdateLocationRequest\x12.\n" +
+	"\blocation\x18\x01 \x01(\v2\x12.location.LocationR\blocation\"\xba\x01\n" +
+	"\x16UpdateLocationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x05R\baccepted\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\x05R\adropped\x12 \n" +
+	"\vquarantined\x18\x04 \x01(\x05R\vquarantined\x12.\n" +
+	"\blocation\x18\x05 \x01(\v2\x12.location.LocationR\blocation\":\n" +
+	"\x19GetDriverLocationsRequest\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x01 \x03(\tR\tdriverIds\"N\n" +
+	"\x1aGetDriverLocationsResponse\x120\n" +
+	"\tlocations\x18\x01 \x03(\v2\x12.location.LocationR\tlocations\"\xa6\x01\n" +
+	"\x18SubscribeLocationRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x02 \x03(\tR\tdriverIds\x12-\n" +
+	"\x06bounds\x18\x03 \x01(\v2\x15.location.BoundingBoxR\x06bounds\x12\x1f\n" +
+	"\vstation_ids\x18\x04 \x03(\tR\n" +
+	"stationIds\"\x9d\x01\n" +
+	"\vBoundingBox\x12!\n" +
+	"\fmin_latitude\x18\x01 \x01(\x01R\vminLatitude\x12#\n" +
+	"\rmin_longitude\x18\x02 \x01(\x01R\fminLongitude\x12!\n" +
+	"\fmax_latitude\x18\x03 \x01(\x01R\vmaxLatitude\x12#\n" +
+	"\rmax_longitude\x18\x04 \x01(\x01R\fmaxLongitude\"\x94\x02\n" +
+	"\x0eLocationUpdate\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1a\n" +
+	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x03 \x01(\x01R\tlongitude\x12\x1f\n" +
+	"\vrecorded_at\x18\x04 \x01(\tR\n" +
+	"recordedAt\x12\x1b\n" +
+	"\tspeed_mps\x18\x05 \x01(\x01R\bspeedMps\x12'\n" +
+	"\x0fheading_degrees\x18\x06 \x01(\x01R\x0eheadingDegrees\x12'\n" +
+	"\x0faccuracy_meters\x18\a \x01(\x01R\x0eaccuracyMeters\x12\x1b\n" +
+	"\tleft_area\x18\b \x01(\bR\bleftArea\"\xab\x02\n" +
+	"\rGeofenceEvent\x12/\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1b.location.GeofenceEventTypeR\x04type\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12\x19\n" +
+	"\bfence_id\x18\x03 \x01(\tR\afenceId\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x05 \x01(\tR\tstationId\x12\x1a\n" +
+	"\blatitude\x18\x06 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\a \x01(\x01R\tlongitude\x12\x1f\n" +
+	"\voccurred_at\x18\b \x01(\tR\n" +
+	"occurredAt\x12#\n" +
+	"\rdwell_seconds\x18\t \x01(\x03R\fdwellSeconds\"\\\n" +
+	"\x1aWatchGeofenceEventsRequest\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x01 \x03(\tR\tdriverIds\x12\x1f\n" +
+	"\vstation_ids\x18\x02 \x03(\tR\n" +
+	"stationIds\"\xc6\x01\n" +
+	"\x16FindDriversNearRequest\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12#\n" +
+	"\rradius_meters\x18\x03 \x01(\x01R\fradiusMeters\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x127\n" +
+	"\x17unpositioned_candidates\x18\x05 \x03(\tR\x16unpositionedCandidates\"g\n" +
+	"\fNearbyDriver\x12.\n" +
+	"\blocation\x18\x01 \x01(\v2\x12.location.LocationR\blocation\x12'\n" +
+	"\x0fdistance_meters\x18\x02 \x01(\x01R\x0edistanceMeters\"\x83\x01\n" +
+	"\x17FindDriversNearResponse\x120\n" +
+	"\adrivers\x18\x01 \x03(\v2\x16.location.NearbyDriverR\adrivers\x126\n" +
+	"\x17unpositioned_driver_ids\x18\x02 \x03(\tR\x15unpositionedDriverIds\"X\n" +
+	"\x15GetDriverTrackRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\"g\n" +
+	"\n" +
+	"TrackPoint\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x1f\n" +
+	"\vrecorded_at\x18\x03 \x01(\tR\n" +
+	"recordedAt\"\x8c\x01\n" +
+	"\x16GetDriverTrackResponse\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12,\n" +
+	"\x06points\x18\x02 \x03(\v2\x14.location.TrackPointR\x06points\x12'\n" +
+	"\x0fdistance_meters\x18\x03 \x01(\x01R\x0edistanceMeters\"<\n" +
+	"\x1bGetLocationAnomaliesRequest\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x01 \x03(\tR\tdriverIds\"\xff\x01\n" +
+	"\x0fDriverAnomalies\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1c\n" +
+	"\tteleports\x18\x02 \x01(\x03R\tteleports\x12\x1c\n" +
+	"\treanchors\x18\x03 \x01(\x03R\treanchors\x12 \n" +
+	"\fout_of_order\x18\x04 \x01(\x03R\n" +
+	"outOfOrder\x12!\n" +
+	"\flow_accuracy\x18\x05 \x01(\x03R\vlowAccuracy\x12\x18\n" +
+	"\ainvalid\x18\x06 \x01(\x03R\ainvalid\x12\x1b\n" +
+	"\tlast_kind\x18\a \x01(\tR\blastKind\x12\x17\n" +
+	"\alast_at\x18\b \x01(\tR\x06lastAt\"S\n" +
+	"\x1cGetLocationAnomaliesResponse\x123\n" +
+	"\adrivers\x18\x01 \x03(\v2\x19.location.DriverAnomaliesR\adrivers\" \n" +
+	"\x1eListLocationSubscribersRequest\"\xe7\x01\n" +
+	"\x17LocationSubscriberStats\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fconnected_at\x18\x02 \x01(\tR\vconnectedAt\x12\x1c\n" +
+	"\tdelivered\x18\x03 \x01(\x03R\tdelivered\x12\x18\n" +
+	"\adropped\x18\x04 \x01(\x03R\adropped\x12\x18\n" +
+	"\apending\x18\x05 \x01(\x05R\apending\x12\x1f\n" +
+	"\vlag_seconds\x18\x06 \x01(\x01R\n" +
+	"lagSeconds\x12&\n" +
+	"\x0fmax_lag_seconds\x18\a \x01(\x01R\rmaxLagSeconds\"f\n" +
+	"\x1fListLocationSubscribersResponse\x12C\n" +
+	"\vsubscribers\x18\x01 \x03(\v2!.location.LocationSubscriberStatsR\vsubscribers*\x94\x01\n" +
+	"\x11GeofenceEventType\x12#\n" +
+	"\x1fGEOFENCE_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19GEOFENCE_EVENT_TYPE_ENTER\x10\x01\x12\x1d\n" +
+	"\x19GEOFENCE_EVENT_TYPE_DWELL\x10\x02\x12\x1c\n" +
+	"\x18GEOFENCE_EVENT_TYPE_EXIT\x10\x032\x81\x06\n" +
 	"\x0fLocationService\x12U\n" +
 	"\x0eUpdateLocation\x12\x1f.location.UpdateLocationRequest\x1a .location.UpdateLocationResponse(\x01\x12Z\n" +
 	"\x18SubscribeLocationUpdates\x12\".location.SubscribeLocationRequest\x1a\x18.location.LocationUpdate0\x01\x12_\n" +
-	"\x12GetDriverLocations\x12#.location.GetDriverLocationsRequest\x1a$.location.GetDriverLocationsResponseB\x1aZ\x18lastmile/gen/go/locationb\x06proto3"
+	"\x12GetDriverLocations\x12#.location.GetDriverLocationsRequest\x1a$.location.GetDriverLocationsResponse\x12V\n" +
+	"\x13WatchGeofenceEvents\x12$.location.WatchGeofenceEventsRequest\x1a\x17.location.GeofenceEvent0\x01\x12V\n" +
+	"\x0fFindDriversNear\x12 .location.FindDriversNearRequest\x1a!.location.FindDriversNearResponse\x12S\n" +
+	"\x0eGetDriverTrack\x12\x1f.location.GetDriverTrackRequest\x1a .location.GetDriverTrackResponse\x12e\n" +
+	"\x14GetLocationAnomalies\x12%.location.GetLocationAnomaliesRequest\x1a&.location.GetLocationAnomaliesResponse\x12n\n" +
+	"\x17ListLocationSubscribers\x12(.location.ListLocationSubscribersRequest\x1a).location.ListLocationSubscribersResponseB\x1aZ\x18lastmile/gen/go/locationb\x06proto3"
 
 var (
 	file_api_location_proto_rawDescOnce sync.Once
@@ -402,30 +1678,65 @@ func file_api_location_proto_rawDescGZIP() []byte {
 	return file_api_location_proto_rawDescData
 }
 
-var file_api_location_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_api_location_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_location_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_api_location_proto_goTypes = []any{
-	(*Location)(nil),                   // 0: location.Location
-	(*UpdateLocationRequest)(nil),      // 1: location.UpdateLocationRequest
-	(*UpdateLocationResponse)(nil),     // 2: location.UpdateLocationResponse
-	(*GetDriverLocationsRequest)(nil),  // 3: location.GetDriverLocationsRequest
-	(*GetDriverLocationsResponse)(nil), // 4: location.GetDriverLocationsResponse
-	(*SubscribeLocationRequest)(nil),   // 5: location.SubscribeLocationRequest
-	(*LocationUpdate)(nil),             // 6: location.LocationUpdate
+	(GeofenceEventType)(0),                  // 0: location.GeofenceEventType
+	(*Location)(nil),                        // 1: location.Location
+	(*UpdateLocationRequest)(nil),           // 2: location.UpdateLocationRequest
+	(*UpdateLocationResponse)(nil),          // 3: location.UpdateLocationResponse
+	(*GetDriverLocationsRequest)(nil),       // 4: location.GetDriverLocationsRequest
+	(*GetDriverLocationsResponse)(nil),      // 5: location.GetDriverLocationsResponse
+	(*SubscribeLocationRequest)(nil),        // 6: location.SubscribeLocationRequest
+	(*BoundingBox)(nil),                     // 7: location.BoundingBox
+	(*LocationUpdate)(nil),                  // 8: location.LocationUpdate
+	(*GeofenceEvent)(nil),                   // 9: location.GeofenceEvent
+	(*WatchGeofenceEventsRequest)(nil),      // 10: location.WatchGeofenceEventsRequest
+	(*FindDriversNearRequest)(nil),          // 11: location.FindDriversNearRequest
+	(*NearbyDriver)(nil),                    // 12: location.NearbyDriver
+	(*FindDriversNearResponse)(nil),         // 13: location.FindDriversNearResponse
+	(*GetDriverTrackRequest)(nil),           // 14: location.GetDriverTrackRequest
+	(*TrackPoint)(nil),                      // 15: location.TrackPoint
+	(*GetDriverTrackResponse)(nil),          // 16: location.GetDriverTrackResponse
+	(*GetLocationAnomaliesRequest)(nil),     // 17: location.GetLocationAnomaliesRequest
+	(*DriverAnomalies)(nil),                 // 18: location.DriverAnomalies
+	(*GetLocationAnomaliesResponse)(nil),    // 19: location.GetLocationAnomaliesResponse
+	(*ListLocationSubscribersRequest)(nil),  // 20: location.ListLocationSubscribersRequest
+	(*LocationSubscriberStats)(nil),         // 21: location.LocationSubscriberStats
+	(*ListLocationSubscribersResponse)(nil), // 22: location.ListLocationSubscribersResponse
 }
 var file_api_location_proto_depIdxs = []int32{
-	0, // 0: location.UpdateLocationRequest.location:type_name -> location.Location
-	0, // 1: location.GetDriverLocationsResponse.locations:type_name -> location.Location
-	1, // 2: location.LocationService.UpdateLocation:input_type -> location.UpdateLocationRequest
-	5, // 3: location.LocationService.SubscribeLocationUpdates:input_type -> location.SubscribeLocationRequest
-	3, // 4: location.LocationService.GetDriverLocations:input_type -> location.GetDriverLocationsRequest
-	2, // 5: location.LocationService.UpdateLocation:output_type -> location.UpdateLocationResponse
-	6, // 6: location.LocationService.SubscribeLocationUpdates:output_type -> location.LocationUpdate
-	4, // 7: location.LocationService.GetDriverLocations:output_type -> location.GetDriverLocationsResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: location.UpdateLocationRequest.location:type_name -> location.Location
+	1,  // 1: location.UpdateLocationResponse.location:type_name -> location.Location
+	1,  // 2: location.GetDriverLocationsResponse.locations:type_name -> location.Location
+	7,  // 3: location.SubscribeLocationRequest.bounds:type_name -> location.BoundingBox
+	0,  // 4: location.GeofenceEvent.type:type_name -> location.GeofenceEventType
+	1,  // 5: location.NearbyDriver.location:type_name -> location.Location
+	12, // 6: location.FindDriversNearResponse.drivers:type_name -> location.NearbyDriver
+	15, // 7: location.GetDriverTrackResponse.points:type_name -> location.TrackPoint
+	18, // 8: location.GetLocationAnomaliesResponse.drivers:type_name -> location.DriverAnomalies
+	21, // 9: location.ListLocationSubscribersResponse.subscribers:type_name -> location.LocationSubscriberStats
+	2,  // 10: location.LocationService.UpdateLocation:input_type -> location.UpdateLocationRequest
+	6,  // 11: location.LocationService.SubscribeLocationUpdates:input_type -> location.SubscribeLocationRequest
+	4,  // 12: location.LocationService.GetDriverLocations:input_type -> location.GetDriverLocationsRequest
+	10, // 13: location.LocationService.WatchGeofenceEvents:input_type -> location.WatchGeofenceEventsRequest
+	11, // 14: location.LocationService.FindDriversNear:input_type -> location.FindDriversNearRequest
+	14, // 15: location.LocationService.GetDriverTrack:input_type -> location.GetDriverTrackRequest
+	17, // 16: location.LocationService.GetLocationAnomalies:input_type -> location.GetLocationAnomaliesRequest
+	20, // 17: location.LocationService.ListLocationSubscribers:input_type -> location.ListLocationSubscribersRequest
+	3,  // 18: location.LocationService.UpdateLocation:output_type -> location.UpdateLocationResponse
+	8,  // 19: location.LocationService.SubscribeLocationUpdates:output_type -> location.LocationUpdate
+	5,  // 20: location.LocationService.GetDriverLocations:output_type -> location.GetDriverLocationsResponse
+	9,  // 21: location.LocationService.WatchGeofenceEvents:output_type -> location.GeofenceEvent
+	13, // 22: location.LocationService.FindDriversNear:output_type -> location.FindDriversNearResponse
+	16, // 23: location.LocationService.GetDriverTrack:output_type -> location.GetDriverTrackResponse
+	19, // 24: location.LocationService.GetLocationAnomalies:output_type -> location.GetLocationAnomaliesResponse
+	22, // 25: location.LocationService.ListLocationSubscribers:output_type -> location.ListLocationSubscribersResponse
+	18, // [18:26] is the sub-list for method output_type
+	10, // [10:18] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_location_proto_init() }
@@ -438,13 +1749,14 @@ func file_api_location_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_location_proto_rawDesc), len(file_api_location_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_location_proto_goTypes,
 		DependencyIndexes: file_api_location_proto_depIdxs,
+		EnumInfos:         file_api_location_proto_enumTypes,
 		MessageInfos:      file_api_location_proto_msgTypes,
 	}.Build()
 	File_api_location_proto = out.File
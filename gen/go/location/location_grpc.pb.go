@@ -22,6 +22,11 @@ const (
 	LocationService_UpdateLocation_FullMethodName           = "/location.LocationService/UpdateLocation"
 	LocationService_SubscribeLocationUpdates_FullMethodName = "/location.LocationService/SubscribeLocationUpdates"
 	LocationService_GetDriverLocations_FullMethodName       = "/location.LocationService/GetDriverLocations"
+	LocationService_WatchGeofenceEvents_FullMethodName      = "/location.LocationService/WatchGeofenceEvents"
+	LocationService_FindDriversNear_FullMethodName          = "/location.LocationService/FindDriversNear"
+	LocationService_GetDriverTrack_FullMethodName           = "/location.LocationService/GetDriverTrack"
+	LocationService_GetLocationAnomalies_FullMethodName     = "/location.LocationService/GetLocationAnomalies"
+	LocationService_ListLocationSubscribers_FullMethodName  = "/location.LocationService/ListLocationSubscribers"
 )
 
 // LocationServiceClient is the client API for LocationService service.
@@ -31,6 +36,11 @@ type LocationServiceClient interface {
 	UpdateLocation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UpdateLocationRequest, UpdateLocationResponse], error)
 	SubscribeLocationUpdates(ctx context.Context, in *SubscribeLocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LocationUpdate], error)
 	GetDriverLocations(ctx context.Context, in *GetDriverLocationsRequest, opts ...grpc.CallOption) (*GetDriverLocationsResponse, error)
+	WatchGeofenceEvents(ctx context.Context, in *WatchGeofenceEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GeofenceEvent], error)
+	FindDriversNear(ctx context.Context, in *FindDriversNearRequest, opts ...grpc.CallOption) (*FindDriversNearResponse, error)
+	GetDriverTrack(ctx context.Context, in *GetDriverTrackRequest, opts ...grpc.CallOption) (*GetDriverTrackResponse, error)
+	GetLocationAnomalies(ctx context.Context, in *GetLocationAnomaliesRequest, opts ...grpc.CallOption) (*GetLocationAnomaliesResponse, error)
+	ListLocationSubscribers(ctx context.Context, in *ListLocationSubscribersRequest, opts ...grpc.CallOption) (*ListLocationSubscribersResponse, error)
 }
 
 type locationServiceClient struct {
@@ -83,6 +93,65 @@ func (c *locationServiceClient) GetDriverLocations(ctx context.Context, in *GetD
 	return out, nil
 }
 
+func (c *locationServiceClient) WatchGeofenceEvents(ctx context.Context, in *WatchGeofenceEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GeofenceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LocationService_ServiceDesc.Streams[2], LocationService_WatchGeofenceEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchGeofenceEventsRequest, GeofenceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LocationService_WatchGeofenceEventsClient = grpc.ServerStreamingClient[GeofenceEvent]
+
+func (c *locationServiceClient) FindDriversNear(ctx context.Context, in *FindDriversNearRequest, opts ...grpc.CallOption) (*FindDriversNearResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindDriversNearResponse)
+	err := c.cc.Invoke(ctx, LocationService_FindDriversNear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationServiceClient) GetDriverTrack(ctx context.Context, in *GetDriverTrackRequest, opts ...grpc.CallOption) (*GetDriverTrackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDriverTrackResponse)
+	err := c.cc.Invoke(ctx, LocationService_GetDriverTrack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationServiceClient) GetLocationAnomalies(ctx context.Context, in *GetLocationAnomaliesRequest, opts ...grpc.CallOption) (*GetLocationAnomaliesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLocationAnomaliesResponse)
+	err := c.cc.Invoke(ctx, LocationService_GetLocationAnomalies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationServiceClient) ListLocationSubscribers(ctx context.Context, in *ListLocationSubscribersRequest, opts ...grpc.CallOption) (*ListLocationSubscribersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLocationSubscribersResponse)
+	err := c.cc.Invoke(ctx, LocationService_ListLocationSubscribers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LocationServiceServer is the server API for LocationService service.
 // All implementations must embed UnimplementedLocationServiceServer
 // for forward compatibility.
@@ -90,6 +159,11 @@ type LocationServiceServer interface {
 	UpdateLocation(grpc.ClientStreamingServer[UpdateLocationRequest, UpdateLocationResponse]) error
 	SubscribeLocationUpdates(*SubscribeLocationRequest, grpc.ServerStreamingServer[LocationUpdate]) error
 	GetDriverLocations(context.Context, *GetDriverLocationsRequest) (*GetDriverLocationsResponse, error)
+	WatchGeofenceEvents(*WatchGeofenceEventsRequest, grpc.ServerStreamingServer[GeofenceEvent]) error
+	FindDriversNear(context.Context, *FindDriversNearRequest) (*FindDriversNearResponse, error)
+	GetDriverTrack(context.Context, *GetDriverTrackRequest) (*GetDriverTrackResponse, error)
+	GetLocationAnomalies(context.Context, *GetLocationAnomaliesRequest) (*GetLocationAnomaliesResponse, error)
+	ListLocationSubscribers(context.Context, *ListLocationSubscribersRequest) (*ListLocationSubscribersResponse, error)
 	mustEmbedUnimplementedLocationServiceServer()
 }
 
@@ -109,6 +183,21 @@ func (UnimplementedLocationServiceServer) SubscribeLocationUpdates(*SubscribeLoc
 func (UnimplementedLocationServiceServer) GetDriverLocations(context.Context, *GetDriverLocationsRequest) (*GetDriverLocationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDriverLocations not implemented")
 }
+func (UnimplementedLocationServiceServer) WatchGeofenceEvents(*WatchGeofenceEventsRequest, grpc.ServerStreamingServer[GeofenceEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGeofenceEvents not implemented")
+}
+func (UnimplementedLocationServiceServer) FindDriversNear(context.Context, *FindDriversNearRequest) (*FindDriversNearResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindDriversNear not implemented")
+}
+func (UnimplementedLocationServiceServer) GetDriverTrack(context.Context, *GetDriverTrackRequest) (*GetDriverTrackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverTrack not implemented")
+}
+func (UnimplementedLocationServiceServer) GetLocationAnomalies(context.Context, *GetLocationAnomaliesRequest) (*GetLocationAnomaliesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocationAnomalies not implemented")
+}
+func (UnimplementedLocationServiceServer) ListLocationSubscribers(context.Context, *ListLocationSubscribersRequest) (*ListLocationSubscribersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLocationSubscribers not implemented")
+}
 func (UnimplementedLocationServiceServer) mustEmbedUnimplementedLocationServiceServer() {}
 func (UnimplementedLocationServiceServer) testEmbeddedByValue()                         {}
 
@@ -166,6 +255,89 @@ func _LocationService_GetDriverLocations_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LocationService_WatchGeofenceEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGeofenceEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocationServiceServer).WatchGeofenceEvents(m, &grpc.GenericServerStream[WatchGeofenceEventsRequest, GeofenceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LocationService_WatchGeofenceEventsServer = grpc.ServerStreamingServer[GeofenceEvent]
+
+func _LocationService_FindDriversNear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindDriversNearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).FindDriversNear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_FindDriversNear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).FindDriversNear(ctx, req.(*FindDriversNearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationService_GetDriverTrack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverTrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).GetDriverTrack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_GetDriverTrack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).GetDriverTrack(ctx, req.(*GetDriverTrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationService_GetLocationAnomalies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLocationAnomaliesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).GetLocationAnomalies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_GetLocationAnomalies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).GetLocationAnomalies(ctx, req.(*GetLocationAnomaliesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationService_ListLocationSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLocationSubscribersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).ListLocationSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_ListLocationSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).ListLocationSubscribers(ctx, req.(*ListLocationSubscribersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LocationService_ServiceDesc is the grpc.ServiceDesc for LocationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,6 +349,22 @@ var LocationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDriverLocations",
 			Handler:    _LocationService_GetDriverLocations_Handler,
 		},
+		{
+			MethodName: "FindDriversNear",
+			Handler:    _LocationService_FindDriversNear_Handler,
+		},
+		{
+			MethodName: "GetDriverTrack",
+			Handler:    _LocationService_GetDriverTrack_Handler,
+		},
+		{
+			MethodName: "GetLocationAnomalies",
+			Handler:    _LocationService_GetLocationAnomalies_Handler,
+		},
+		{
+			MethodName: "ListLocationSubscribers",
+			Handler:    _LocationService_ListLocationSubscribers_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -189,6 +377,11 @@ var LocationService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LocationService_SubscribeLocationUpdates_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchGeofenceEvents",
+			Handler:       _LocationService_WatchGeofenceEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/location.proto",
 }
@@ -22,12 +22,68 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MatchEventType int32
+
+const (
+	MatchEventType_MATCH_EVENT_TYPE_UNSPECIFIED   MatchEventType = 0
+	MatchEventType_MATCH_EVENT_TYPE_CREATED       MatchEventType = 1 // a rider was matched and a trip created
+	MatchEventType_MATCH_EVENT_TYPE_REJECTED      MatchEventType = 2 // a match could not be made or was withdrawn
+	MatchEventType_MATCH_EVENT_TYPE_RIDER_EXPIRED MatchEventType = 3 // a waiting rider aged out without a match
+)
+
+// Enum value maps for MatchEventType.
+var (
+	MatchEventType_name = map[int32]string{
+		0: "MATCH_EVENT_TYPE_UNSPECIFIED",
+		1: "MATCH_EVENT_TYPE_CREATED",
+		2: "MATCH_EVENT_TYPE_REJECTED",
+		3: "MATCH_EVENT_TYPE_RIDER_EXPIRED",
+	}
+	MatchEventType_value = map[string]int32{
+		"MATCH_EVENT_TYPE_UNSPECIFIED":   0,
+		"MATCH_EVENT_TYPE_CREATED":       1,
+		"MATCH_EVENT_TYPE_REJECTED":      2,
+		"MATCH_EVENT_TYPE_RIDER_EXPIRED": 3,
+	}
+)
+
+func (x MatchEventType) Enum() *MatchEventType {
+	p := new(MatchEventType)
+	*p = x
+	return p
+}
+
+func (x MatchEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_matching_proto_enumTypes[0].Descriptor()
+}
+
+func (MatchEventType) Type() protoreflect.EnumType {
+	return &file_api_matching_proto_enumTypes[0]
+}
+
+func (x MatchEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchEventType.Descriptor instead.
+func (MatchEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_matching_proto_rawDescGZIP(), []int{0}
+}
+
 type MatchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
-	StationId     string                 `protobuf:"bytes,2,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	DriverId             string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	StationId            string                 `protobuf:"bytes,2,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	ArrivalWindowMinutes int32                  `protobuf:"varint,3,opt,name=arrival_window_minutes,json=arrivalWindowMinutes,proto3" json:"arrival_window_minutes,omitempty"` // 0 uses the server default
+	DriverEtaMinutes     int32                  `protobuf:"varint,4,opt,name=driver_eta_minutes,json=driverEtaMinutes,proto3" json:"driver_eta_minutes,omitempty"`             // driver's projected arrival at the station, from now
+	IdempotencyKey       string                 `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`                      // repeated requests with the same key return the first result
+	Forwarded            bool                   `protobuf:"varint,6,opt,name=forwarded,proto3" json:"forwarded,omitempty"`                                                     // set by a replica passing the request to the station's owner
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *MatchRequest) Reset() {
@@ -74,11 +130,44 @@ func (x *MatchRequest) GetStationId() string {
 	return ""
 }
 
+func (x *MatchRequest) GetArrivalWindowMinutes() int32 {
+	if x != nil {
+		return x.ArrivalWindowMinutes
+	}
+	return 0
+}
+
+func (x *MatchRequest) GetDriverEtaMinutes() int32 {
+	if x != nil {
+		return x.DriverEtaMinutes
+	}
+	return 0
+}
+
+func (x *MatchRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *MatchRequest) GetForwarded() bool {
+	if x != nil {
+		return x.Forwarded
+	}
+	return false
+}
+
 type MatchResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Trips         []*trip.Trip           `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Trips                []*trip.Trip           `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
+	ArrivalWindowMinutes int32                  `protobuf:"varint,2,opt,name=arrival_window_minutes,json=arrivalWindowMinutes,proto3" json:"arrival_window_minutes,omitempty"` // window that was applied
+	DeferredRiderIds     []string               `protobuf:"bytes,3,rep,name=deferred_rider_ids,json=deferredRiderIds,proto3" json:"deferred_rider_ids,omitempty"`              // compatible riders left queued because they arrive outside the window
+	Policy               string                 `protobuf:"bytes,4,opt,name=policy,proto3" json:"policy,omitempty"`                                                            // match policy that ranked the riders
+	Replayed             bool                   `protobuf:"varint,5,opt,name=replayed,proto3" json:"replayed,omitempty"`                                                       // true when answered from an earlier request with the same idempotency key
+	ServedBy             string                 `protobuf:"bytes,6,opt,name=served_by,json=servedBy,proto3" json:"served_by,omitempty"`                                        // replica that owns the station and ran the match
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *MatchResponse) Reset() {
@@ -118,20 +207,343 @@ func (x *MatchResponse) GetTrips() []*trip.Trip {
 	return nil
 }
 
+func (x *MatchResponse) GetArrivalWindowMinutes() int32 {
+	if x != nil {
+		return x.ArrivalWindowMinutes
+	}
+	return 0
+}
+
+func (x *MatchResponse) GetDeferredRiderIds() []string {
+	if x != nil {
+		return x.DeferredRiderIds
+	}
+	return nil
+}
+
+func (x *MatchResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *MatchResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *MatchResponse) GetServedBy() string {
+	if x != nil {
+		return x.ServedBy
+	}
+	return ""
+}
+
+type GetOwnerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StationId     string                 `protobuf:"bytes,1,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOwnerRequest) Reset() {
+	*x = GetOwnerRequest{}
+	mi := &file_api_matching_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOwnerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOwnerRequest) ProtoMessage() {}
+
+func (x *GetOwnerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_matching_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOwnerRequest.ProtoReflect.Descriptor instead.
+func (*GetOwnerRequest) Descriptor() ([]byte, []int) {
+	return file_api_matching_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetOwnerRequest) GetStationId() string {
+	if x != nil {
+		return x.StationId
+	}
+	return ""
+}
+
+type GetOwnerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Owner         string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`     // replica responsible for the station
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"` // replicas currently on the ring
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOwnerResponse) Reset() {
+	*x = GetOwnerResponse{}
+	mi := &file_api_matching_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOwnerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOwnerResponse) ProtoMessage() {}
+
+func (x *GetOwnerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_matching_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOwnerResponse.ProtoReflect.Descriptor instead.
+func (*GetOwnerResponse) Descriptor() ([]byte, []int) {
+	return file_api_matching_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetOwnerResponse) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *GetOwnerResponse) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type MatchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          MatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=matching.MatchEventType" json:"type,omitempty"`
+	StationId     string                 `protobuf:"bytes,2,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,3,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	RiderId       string                 `protobuf:"bytes,4,opt,name=rider_id,json=riderId,proto3" json:"rider_id,omitempty"`
+	Trip          *trip.Trip             `protobuf:"bytes,5,opt,name=trip,proto3" json:"trip,omitempty"` // set for created events and rejections of a concrete trip
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	OccurredAt    string                 `protobuf:"bytes,7,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"` // RFC3339
+	ServedBy      string                 `protobuf:"bytes,8,opt,name=served_by,json=servedBy,proto3" json:"served_by,omitempty"`       // replica that produced the event
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchEvent) Reset() {
+	*x = MatchEvent{}
+	mi := &file_api_matching_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchEvent) ProtoMessage() {}
+
+func (x *MatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_matching_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchEvent.ProtoReflect.Descriptor instead.
+func (*MatchEvent) Descriptor() ([]byte, []int) {
+	return file_api_matching_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MatchEvent) GetType() MatchEventType {
+	if x != nil {
+		return x.Type
+	}
+	return MatchEventType_MATCH_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *MatchEvent) GetStationId() string {
+	if x != nil {
+		return x.StationId
+	}
+	return ""
+}
+
+func (x *MatchEvent) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *MatchEvent) GetRiderId() string {
+	if x != nil {
+		return x.RiderId
+	}
+	return ""
+}
+
+func (x *MatchEvent) GetTrip() *trip.Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+func (x *MatchEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MatchEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *MatchEvent) GetServedBy() string {
+	if x != nil {
+		return x.ServedBy
+	}
+	return ""
+}
+
+type WatchMatchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StationIds    []string               `protobuf:"bytes,1,rep,name=station_ids,json=stationIds,proto3" json:"station_ids,omitempty"` // empty watches every station
+	LocalOnly     bool                   `protobuf:"varint,2,opt,name=local_only,json=localOnly,proto3" json:"local_only,omitempty"`   // only events produced by this replica; used between replicas
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMatchesRequest) Reset() {
+	*x = WatchMatchesRequest{}
+	mi := &file_api_matching_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMatchesRequest) ProtoMessage() {}
+
+func (x *WatchMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_matching_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMatchesRequest.ProtoReflect.Descriptor instead.
+func (*WatchMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_api_matching_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchMatchesRequest) GetStationIds() []string {
+	if x != nil {
+		return x.StationIds
+	}
+	return nil
+}
+
+func (x *WatchMatchesRequest) GetLocalOnly() bool {
+	if x != nil {
+		return x.LocalOnly
+	}
+	return false
+}
+
 var File_api_matching_proto protoreflect.FileDescriptor
 
 const file_api_matching_proto_rawDesc = "" +
 	"\n" +
-	"\x12api/matching.proto\x12\bmatching\x1a\x0eapi/trip.proto\"J\n" +
+	"\x12api/matching.proto\x12\bmatching\x1a\x0eapi/trip.proto\"\xf5\x01\n" +
 	"\fMatchRequest\x12\x1b\n" +
 	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1d\n" +
 	"\n" +
-	"station_id\x18\x02 \x01(\tR\tstationId\"1\n" +
+	"station_id\x18\x02 \x01(\tR\tstationId\x124\n" +
+	"\x16arrival_window_minutes\x18\x03 \x01(\x05R\x14arrivalWindowMinutes\x12,\n" +
+	"\x12driver_eta_minutes\x18\x04 \x01(\x05R\x10driverEtaMinutes\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\x12\x1c\n" +
+	"\tforwarded\x18\x06 \x01(\bR\tforwarded\"\xe6\x01\n" +
 	"\rMatchResponse\x12 \n" +
 	"\x05trips\x18\x01 \x03(\v2\n" +
-	".trip.TripR\x05trips2K\n" +
+	".trip.TripR\x05trips\x124\n" +
+	"\x16arrival_window_minutes\x18\x02 \x01(\x05R\x14arrivalWindowMinutes\x12,\n" +
+	"\x12deferred_rider_ids\x18\x03 \x03(\tR\x10deferredRiderIds\x12\x16\n" +
+	"\x06policy\x18\x04 \x01(\tR\x06policy\x12\x1a\n" +
+	"\breplayed\x18\x05 \x01(\bR\breplayed\x12\x1b\n" +
+	"\tserved_by\x18\x06 \x01(\tR\bservedBy\"0\n" +
+	"\x0fGetOwnerRequest\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x01 \x01(\tR\tstationId\"B\n" +
+	"\x10GetOwnerResponse\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\"\x87\x02\n" +
+	"\n" +
+	"MatchEvent\x12,\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x18.matching.MatchEventTypeR\x04type\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x02 \x01(\tR\tstationId\x12\x1b\n" +
+	"\tdriver_id\x18\x03 \x01(\tR\bdriverId\x12\x19\n" +
+	"\brider_id\x18\x04 \x01(\tR\ariderId\x12\x1e\n" +
+	"\x04trip\x18\x05 \x01(\v2\n" +
+	".trip.TripR\x04trip\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1f\n" +
+	"\voccurred_at\x18\a \x01(\tR\n" +
+	"occurredAt\x12\x1b\n" +
+	"\tserved_by\x18\b \x01(\tR\bservedBy\"U\n" +
+	"\x13WatchMatchesRequest\x12\x1f\n" +
+	"\vstation_ids\x18\x01 \x03(\tR\n" +
+	"stationIds\x12\x1d\n" +
+	"\n" +
+	"local_only\x18\x02 \x01(\bR\tlocalOnly*\x93\x01\n" +
+	"\x0eMatchEventType\x12 \n" +
+	"\x1cMATCH_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18MATCH_EVENT_TYPE_CREATED\x10\x01\x12\x1d\n" +
+	"\x19MATCH_EVENT_TYPE_REJECTED\x10\x02\x12\"\n" +
+	"\x1eMATCH_EVENT_TYPE_RIDER_EXPIRED\x10\x032\xd5\x01\n" +
 	"\x0fMatchingService\x128\n" +
-	"\x05Match\x12\x16.matching.MatchRequest\x1a\x17.matching.MatchResponseB\x1aZ\x18lastmile/gen/go/matchingb\x06proto3"
+	"\x05Match\x12\x16.matching.MatchRequest\x1a\x17.matching.MatchResponse\x12A\n" +
+	"\bGetOwner\x12\x19.matching.GetOwnerRequest\x1a\x1a.matching.GetOwnerResponse\x12E\n" +
+	"\fWatchMatches\x12\x1d.matching.WatchMatchesRequest\x1a\x14.matching.MatchEvent0\x01B\x1aZ\x18lastmile/gen/go/matchingb\x06proto3"
 
 var (
 	file_api_matching_proto_rawDescOnce sync.Once
@@ -145,21 +557,33 @@ func file_api_matching_proto_rawDescGZIP() []byte {
 	return file_api_matching_proto_rawDescData
 }
 
-var file_api_matching_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_matching_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_matching_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_api_matching_proto_goTypes = []any{
-	(*MatchRequest)(nil),  // 0: matching.MatchRequest
-	(*MatchResponse)(nil), // 1: matching.MatchResponse
-	(*trip.Trip)(nil),     // 2: trip.Trip
+	(MatchEventType)(0),         // 0: matching.MatchEventType
+	(*MatchRequest)(nil),        // 1: matching.MatchRequest
+	(*MatchResponse)(nil),       // 2: matching.MatchResponse
+	(*GetOwnerRequest)(nil),     // 3: matching.GetOwnerRequest
+	(*GetOwnerResponse)(nil),    // 4: matching.GetOwnerResponse
+	(*MatchEvent)(nil),          // 5: matching.MatchEvent
+	(*WatchMatchesRequest)(nil), // 6: matching.WatchMatchesRequest
+	(*trip.Trip)(nil),           // 7: trip.Trip
 }
 var file_api_matching_proto_depIdxs = []int32{
-	2, // 0: matching.MatchResponse.trips:type_name -> trip.Trip
-	0, // 1: matching.MatchingService.Match:input_type -> matching.MatchRequest
-	1, // 2: matching.MatchingService.Match:output_type -> matching.MatchResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	7, // 0: matching.MatchResponse.trips:type_name -> trip.Trip
+	0, // 1: matching.MatchEvent.type:type_name -> matching.MatchEventType
+	7, // 2: matching.MatchEvent.trip:type_name -> trip.Trip
+	1, // 3: matching.MatchingService.Match:input_type -> matching.MatchRequest
+	3, // 4: matching.MatchingService.GetOwner:input_type -> matching.GetOwnerRequest
+	6, // 5: matching.MatchingService.WatchMatches:input_type -> matching.WatchMatchesRequest
+	2, // 6: matching.MatchingService.Match:output_type -> matching.MatchResponse
+	4, // 7: matching.MatchingService.GetOwner:output_type -> matching.GetOwnerResponse
+	5, // 8: matching.MatchingService.WatchMatches:output_type -> matching.MatchEvent
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_api_matching_proto_init() }
@@ -172,13 +596,14 @@ func file_api_matching_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_matching_proto_rawDesc), len(file_api_matching_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_matching_proto_goTypes,
 		DependencyIndexes: file_api_matching_proto_depIdxs,
+		EnumInfos:         file_api_matching_proto_enumTypes,
 		MessageInfos:      file_api_matching_proto_msgTypes,
 	}.Build()
 	File_api_matching_proto = out.File
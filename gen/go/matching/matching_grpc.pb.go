@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MatchingService_Match_FullMethodName = "/matching.MatchingService/Match"
+	MatchingService_Match_FullMethodName        = "/matching.MatchingService/Match"
+	MatchingService_GetOwner_FullMethodName     = "/matching.MatchingService/GetOwner"
+	MatchingService_WatchMatches_FullMethodName = "/matching.MatchingService/WatchMatches"
 )
 
 // MatchingServiceClient is the client API for MatchingService service.
@@ -27,6 +29,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MatchingServiceClient interface {
 	Match(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*MatchResponse, error)
+	GetOwner(ctx context.Context, in *GetOwnerRequest, opts ...grpc.CallOption) (*GetOwnerResponse, error)
+	WatchMatches(ctx context.Context, in *WatchMatchesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MatchEvent], error)
 }
 
 type matchingServiceClient struct {
@@ -47,11 +51,42 @@ func (c *matchingServiceClient) Match(ctx context.Context, in *MatchRequest, opt
 	return out, nil
 }
 
+func (c *matchingServiceClient) GetOwner(ctx context.Context, in *GetOwnerRequest, opts ...grpc.CallOption) (*GetOwnerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOwnerResponse)
+	err := c.cc.Invoke(ctx, MatchingService_GetOwner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchingServiceClient) WatchMatches(ctx context.Context, in *WatchMatchesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MatchingService_ServiceDesc.Streams[0], MatchingService_WatchMatches_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchMatchesRequest, MatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchingService_WatchMatchesClient = grpc.ServerStreamingClient[MatchEvent]
+
 // MatchingServiceServer is the server API for MatchingService service.
 // All implementations must embed UnimplementedMatchingServiceServer
 // for forward compatibility.
 type MatchingServiceServer interface {
 	Match(context.Context, *MatchRequest) (*MatchResponse, error)
+	GetOwner(context.Context, *GetOwnerRequest) (*GetOwnerResponse, error)
+	WatchMatches(*WatchMatchesRequest, grpc.ServerStreamingServer[MatchEvent]) error
 	mustEmbedUnimplementedMatchingServiceServer()
 }
 
@@ -65,6 +100,12 @@ type UnimplementedMatchingServiceServer struct{}
 func (UnimplementedMatchingServiceServer) Match(context.Context, *MatchRequest) (*MatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Match not implemented")
 }
+func (UnimplementedMatchingServiceServer) GetOwner(context.Context, *GetOwnerRequest) (*GetOwnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOwner not implemented")
+}
+func (UnimplementedMatchingServiceServer) WatchMatches(*WatchMatchesRequest, grpc.ServerStreamingServer[MatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMatches not implemented")
+}
 func (UnimplementedMatchingServiceServer) mustEmbedUnimplementedMatchingServiceServer() {}
 func (UnimplementedMatchingServiceServer) testEmbeddedByValue()                         {}
 
@@ -104,6 +145,35 @@ func _MatchingService_Match_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MatchingService_GetOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingServiceServer).GetOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchingService_GetOwner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingServiceServer).GetOwner(ctx, req.(*GetOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchingService_WatchMatches_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchMatchesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchingServiceServer).WatchMatches(m, &grpc.GenericServerStream[WatchMatchesRequest, MatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchingService_WatchMatchesServer = grpc.ServerStreamingServer[MatchEvent]
+
 // MatchingService_ServiceDesc is the grpc.ServiceDesc for MatchingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +185,17 @@ var MatchingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Match",
 			Handler:    _MatchingService_Match_Handler,
 		},
+		{
+			MethodName: "GetOwner",
+			Handler:    _MatchingService_GetOwner_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchMatches",
+			Handler:       _MatchingService_WatchMatches_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/matching.proto",
 }
@@ -28,6 +28,8 @@ type Rider struct {
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
 	ArrivalTime   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=arrival_time,json=arrivalTime,proto3" json:"arrival_time,omitempty"`
+	StationId     string                 `protobuf:"bytes,5,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // e.g., "waiting", "matched", "picked_up"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,6 +92,20 @@ func (x *Rider) GetArrivalTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Rider) GetStationId() string {
+	if x != nil {
+		return x.StationId
+	}
+	return ""
+}
+
+func (x *Rider) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 type Ride struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -334,16 +350,222 @@ func (x *TrackRideResponse) GetRide() *Ride {
 	return nil
 }
 
+type ListRidersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StationId     string                 `protobuf:"bytes,1,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRidersRequest) Reset() {
+	*x = ListRidersRequest{}
+	mi := &file_api_rider_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRidersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRidersRequest) ProtoMessage() {}
+
+func (x *ListRidersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_rider_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRidersRequest.ProtoReflect.Descriptor instead.
+func (*ListRidersRequest) Descriptor() ([]byte, []int) {
+	return file_api_rider_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListRidersRequest) GetStationId() string {
+	if x != nil {
+		return x.StationId
+	}
+	return ""
+}
+
+func (x *ListRidersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListRidersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Riders        []*Rider               `protobuf:"bytes,1,rep,name=riders,proto3" json:"riders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRidersResponse) Reset() {
+	*x = ListRidersResponse{}
+	mi := &file_api_rider_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRidersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRidersResponse) ProtoMessage() {}
+
+func (x *ListRidersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_rider_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRidersResponse.ProtoReflect.Descriptor instead.
+func (*ListRidersResponse) Descriptor() ([]byte, []int) {
+	return file_api_rider_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListRidersResponse) GetRiders() []*Rider {
+	if x != nil {
+		return x.Riders
+	}
+	return nil
+}
+
+type UpdateRiderStatusRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// expected_status, when set, makes the update conditional: it is applied
+	// only while the rider is in that status and fails with FailedPrecondition
+	// otherwise, e.g. to claim a rider who is still waiting.
+	ExpectedStatus string `protobuf:"bytes,3,opt,name=expected_status,json=expectedStatus,proto3" json:"expected_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateRiderStatusRequest) Reset() {
+	*x = UpdateRiderStatusRequest{}
+	mi := &file_api_rider_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRiderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRiderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateRiderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_rider_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRiderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRiderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_rider_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateRiderStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateRiderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateRiderStatusRequest) GetExpectedStatus() string {
+	if x != nil {
+		return x.ExpectedStatus
+	}
+	return ""
+}
+
+type UpdateRiderStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rider         *Rider                 `protobuf:"bytes,1,opt,name=rider,proto3" json:"rider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRiderStatusResponse) Reset() {
+	*x = UpdateRiderStatusResponse{}
+	mi := &file_api_rider_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRiderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRiderStatusResponse) ProtoMessage() {}
+
+func (x *UpdateRiderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_rider_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRiderStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRiderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_rider_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateRiderStatusResponse) GetRider() *Rider {
+	if x != nil {
+		return x.Rider
+	}
+	return nil
+}
+
 var File_api_rider_proto protoreflect.FileDescriptor
 
 const file_api_rider_proto_rawDesc = "" +
 	"\n" +
-	"\x0fapi/rider.proto\x12\x05rider\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8c\x01\n" +
+	"\x0fapi/rider.proto\x12\x05rider\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc3\x01\n" +
 	"\x05Rider\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
 	"\vdestination\x18\x03 \x01(\tR\vdestination\x12=\n" +
-	"\farrival_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\varrivalTime\"f\n" +
+	"\farrival_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\varrivalTime\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x05 \x01(\tR\tstationId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\"f\n" +
 	"\x04Ride\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\brider_id\x18\x02 \x01(\tR\ariderId\x12\x1b\n" +
@@ -356,10 +578,25 @@ const file_api_rider_proto_rawDesc = "" +
 	"\x10TrackRideRequest\x12\x17\n" +
 	"\aride_id\x18\x01 \x01(\tR\x06rideId\"4\n" +
 	"\x11TrackRideResponse\x12\x1f\n" +
-	"\x04ride\x18\x01 \x01(\v2\v.rider.RideR\x04ride2\x9a\x01\n" +
+	"\x04ride\x18\x01 \x01(\v2\v.rider.RideR\x04ride\"J\n" +
+	"\x11ListRidersRequest\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x01 \x01(\tR\tstationId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\":\n" +
+	"\x12ListRidersResponse\x12$\n" +
+	"\x06riders\x18\x01 \x03(\v2\f.rider.RiderR\x06riders\"k\n" +
+	"\x18UpdateRiderStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12'\n" +
+	"\x0fexpected_status\x18\x03 \x01(\tR\x0eexpectedStatus\"?\n" +
+	"\x19UpdateRiderStatusResponse\x12\"\n" +
+	"\x05rider\x18\x01 \x01(\v2\f.rider.RiderR\x05rider2\xb5\x02\n" +
 	"\fRiderService\x12J\n" +
 	"\rRegisterRider\x12\x1b.rider.RegisterRiderRequest\x1a\x1c.rider.RegisterRiderResponse\x12>\n" +
-	"\tTrackRide\x12\x17.rider.TrackRideRequest\x1a\x18.rider.TrackRideResponseB\x17Z\x15lastmile/gen/go/riderb\x06proto3"
+	"\tTrackRide\x12\x17.rider.TrackRideRequest\x1a\x18.rider.TrackRideResponse\x12A\n" +
+	"\n" +
+	"ListRiders\x12\x18.rider.ListRidersRequest\x1a\x19.rider.ListRidersResponse\x12V\n" +
+	"\x11UpdateRiderStatus\x12\x1f.rider.UpdateRiderStatusRequest\x1a .rider.UpdateRiderStatusResponseB\x17Z\x15lastmile/gen/go/riderb\x06proto3"
 
 var (
 	file_api_rider_proto_rawDescOnce sync.Once
@@ -373,29 +610,39 @@ func file_api_rider_proto_rawDescGZIP() []byte {
 	return file_api_rider_proto_rawDescData
 }
 
-var file_api_rider_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_rider_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_api_rider_proto_goTypes = []any{
-	(*Rider)(nil),                 // 0: rider.Rider
-	(*Ride)(nil),                  // 1: rider.Ride
-	(*RegisterRiderRequest)(nil),  // 2: rider.RegisterRiderRequest
-	(*RegisterRiderResponse)(nil), // 3: rider.RegisterRiderResponse
-	(*TrackRideRequest)(nil),      // 4: rider.TrackRideRequest
-	(*TrackRideResponse)(nil),     // 5: rider.TrackRideResponse
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(*Rider)(nil),                     // 0: rider.Rider
+	(*Ride)(nil),                      // 1: rider.Ride
+	(*RegisterRiderRequest)(nil),      // 2: rider.RegisterRiderRequest
+	(*RegisterRiderResponse)(nil),     // 3: rider.RegisterRiderResponse
+	(*TrackRideRequest)(nil),          // 4: rider.TrackRideRequest
+	(*TrackRideResponse)(nil),         // 5: rider.TrackRideResponse
+	(*ListRidersRequest)(nil),         // 6: rider.ListRidersRequest
+	(*ListRidersResponse)(nil),        // 7: rider.ListRidersResponse
+	(*UpdateRiderStatusRequest)(nil),  // 8: rider.UpdateRiderStatusRequest
+	(*UpdateRiderStatusResponse)(nil), // 9: rider.UpdateRiderStatusResponse
+	(*timestamppb.Timestamp)(nil),     // 10: google.protobuf.Timestamp
 }
 var file_api_rider_proto_depIdxs = []int32{
-	6, // 0: rider.Rider.arrival_time:type_name -> google.protobuf.Timestamp
-	0, // 1: rider.RegisterRiderRequest.rider:type_name -> rider.Rider
-	1, // 2: rider.TrackRideResponse.ride:type_name -> rider.Ride
-	2, // 3: rider.RiderService.RegisterRider:input_type -> rider.RegisterRiderRequest
-	4, // 4: rider.RiderService.TrackRide:input_type -> rider.TrackRideRequest
-	3, // 5: rider.RiderService.RegisterRider:output_type -> rider.RegisterRiderResponse
-	5, // 6: rider.RiderService.TrackRide:output_type -> rider.TrackRideResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	10, // 0: rider.Rider.arrival_time:type_name -> google.protobuf.Timestamp
+	0,  // 1: rider.RegisterRiderRequest.rider:type_name -> rider.Rider
+	1,  // 2: rider.TrackRideResponse.ride:type_name -> rider.Ride
+	0,  // 3: rider.ListRidersResponse.riders:type_name -> rider.Rider
+	0,  // 4: rider.UpdateRiderStatusResponse.rider:type_name -> rider.Rider
+	2,  // 5: rider.RiderService.RegisterRider:input_type -> rider.RegisterRiderRequest
+	4,  // 6: rider.RiderService.TrackRide:input_type -> rider.TrackRideRequest
+	6,  // 7: rider.RiderService.ListRiders:input_type -> rider.ListRidersRequest
+	8,  // 8: rider.RiderService.UpdateRiderStatus:input_type -> rider.UpdateRiderStatusRequest
+	3,  // 9: rider.RiderService.RegisterRider:output_type -> rider.RegisterRiderResponse
+	5,  // 10: rider.RiderService.TrackRide:output_type -> rider.TrackRideResponse
+	7,  // 11: rider.RiderService.ListRiders:output_type -> rider.ListRidersResponse
+	9,  // 12: rider.RiderService.UpdateRiderStatus:output_type -> rider.UpdateRiderStatusResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_api_rider_proto_init() }
@@ -409,7 +656,7 @@ func file_api_rider_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_rider_proto_rawDesc), len(file_api_rider_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
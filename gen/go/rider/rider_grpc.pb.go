@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RiderService_RegisterRider_FullMethodName = "/rider.RiderService/RegisterRider"
-	RiderService_TrackRide_FullMethodName     = "/rider.RiderService/TrackRide"
+	RiderService_RegisterRider_FullMethodName     = "/rider.RiderService/RegisterRider"
+	RiderService_TrackRide_FullMethodName         = "/rider.RiderService/TrackRide"
+	RiderService_ListRiders_FullMethodName        = "/rider.RiderService/ListRiders"
+	RiderService_UpdateRiderStatus_FullMethodName = "/rider.RiderService/UpdateRiderStatus"
 )
 
 // RiderServiceClient is the client API for RiderService service.
@@ -29,6 +31,8 @@ const (
 type RiderServiceClient interface {
 	RegisterRider(ctx context.Context, in *RegisterRiderRequest, opts ...grpc.CallOption) (*RegisterRiderResponse, error)
 	TrackRide(ctx context.Context, in *TrackRideRequest, opts ...grpc.CallOption) (*TrackRideResponse, error)
+	ListRiders(ctx context.Context, in *ListRidersRequest, opts ...grpc.CallOption) (*ListRidersResponse, error)
+	UpdateRiderStatus(ctx context.Context, in *UpdateRiderStatusRequest, opts ...grpc.CallOption) (*UpdateRiderStatusResponse, error)
 }
 
 type riderServiceClient struct {
@@ -59,12 +63,34 @@ func (c *riderServiceClient) TrackRide(ctx context.Context, in *TrackRideRequest
 	return out, nil
 }
 
+func (c *riderServiceClient) ListRiders(ctx context.Context, in *ListRidersRequest, opts ...grpc.CallOption) (*ListRidersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRidersResponse)
+	err := c.cc.Invoke(ctx, RiderService_ListRiders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riderServiceClient) UpdateRiderStatus(ctx context.Context, in *UpdateRiderStatusRequest, opts ...grpc.CallOption) (*UpdateRiderStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateRiderStatusResponse)
+	err := c.cc.Invoke(ctx, RiderService_UpdateRiderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RiderServiceServer is the server API for RiderService service.
 // All implementations must embed UnimplementedRiderServiceServer
 // for forward compatibility.
 type RiderServiceServer interface {
 	RegisterRider(context.Context, *RegisterRiderRequest) (*RegisterRiderResponse, error)
 	TrackRide(context.Context, *TrackRideRequest) (*TrackRideResponse, error)
+	ListRiders(context.Context, *ListRidersRequest) (*ListRidersResponse, error)
+	UpdateRiderStatus(context.Context, *UpdateRiderStatusRequest) (*UpdateRiderStatusResponse, error)
 	mustEmbedUnimplementedRiderServiceServer()
 }
 
@@ -81,6 +107,12 @@ func (UnimplementedRiderServiceServer) RegisterRider(context.Context, *RegisterR
 func (UnimplementedRiderServiceServer) TrackRide(context.Context, *TrackRideRequest) (*TrackRideResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TrackRide not implemented")
 }
+func (UnimplementedRiderServiceServer) ListRiders(context.Context, *ListRidersRequest) (*ListRidersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRiders not implemented")
+}
+func (UnimplementedRiderServiceServer) UpdateRiderStatus(context.Context, *UpdateRiderStatusRequest) (*UpdateRiderStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRiderStatus not implemented")
+}
 func (UnimplementedRiderServiceServer) mustEmbedUnimplementedRiderServiceServer() {}
 func (UnimplementedRiderServiceServer) testEmbeddedByValue()                      {}
 
@@ -138,6 +170,42 @@ func _RiderService_TrackRide_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RiderService_ListRiders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRidersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiderServiceServer).ListRiders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiderService_ListRiders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiderServiceServer).ListRiders(ctx, req.(*ListRidersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiderService_UpdateRiderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRiderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiderServiceServer).UpdateRiderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiderService_UpdateRiderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiderServiceServer).UpdateRiderStatus(ctx, req.(*UpdateRiderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RiderService_ServiceDesc is the grpc.ServiceDesc for RiderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +221,14 @@ var RiderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TrackRide",
 			Handler:    _RiderService_TrackRide_Handler,
 		},
+		{
+			MethodName: "ListRiders",
+			Handler:    _RiderService_ListRiders_Handler,
+		},
+		{
+			MethodName: "UpdateRiderStatus",
+			Handler:    _RiderService_UpdateRiderStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/rider.proto",
@@ -21,18 +21,76 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type Station struct {
+type GeoPoint struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	NearbyAreas   []string               `protobuf:"bytes,3,rep,name=nearby_areas,json=nearbyAreas,proto3" json:"nearby_areas,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *GeoPoint) Reset() {
+	*x = GeoPoint{}
+	mi := &file_api_station_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoPoint) ProtoMessage() {}
+
+func (x *GeoPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_station_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoPoint.ProtoReflect.Descriptor instead.
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return file_api_station_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GeoPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeoPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type Station struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NearbyAreas []string               `protobuf:"bytes,3,rep,name=nearby_areas,json=nearbyAreas,proto3" json:"nearby_areas,omitempty"`
+	Latitude    float64                `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude   float64                `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// Geofence around the station. A polygon takes precedence over the radius;
+	// with neither set, consumers fall back to their default radius.
+	GeofenceRadiusMeters float64     `protobuf:"fixed64,6,opt,name=geofence_radius_meters,json=geofenceRadiusMeters,proto3" json:"geofence_radius_meters,omitempty"`
+	Geofence             []*GeoPoint `protobuf:"bytes,7,rep,name=geofence,proto3" json:"geofence,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
 func (x *Station) Reset() {
 	*x = Station{}
-	mi := &file_api_station_proto_msgTypes[0]
+	mi := &file_api_station_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -44,7 +102,7 @@ func (x *Station) String() string {
 func (*Station) ProtoMessage() {}
 
 func (x *Station) ProtoReflect() protoreflect.Message {
-	mi := &file_api_station_proto_msgTypes[0]
+	mi := &file_api_station_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,7 +115,7 @@ func (x *Station) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Station.ProtoReflect.Descriptor instead.
 func (*Station) Descriptor() ([]byte, []int) {
-	return file_api_station_proto_rawDescGZIP(), []int{0}
+	return file_api_station_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Station) GetId() string {
@@ -81,6 +139,126 @@ func (x *Station) GetNearbyAreas() []string {
 	return nil
 }
 
+func (x *Station) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Station) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Station) GetGeofenceRadiusMeters() float64 {
+	if x != nil {
+		return x.GeofenceRadiusMeters
+	}
+	return 0
+}
+
+func (x *Station) GetGeofence() []*GeoPoint {
+	if x != nil {
+		return x.Geofence
+	}
+	return nil
+}
+
+type PickupPoint struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StationId            string                 `protobuf:"bytes,3,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	Latitude             float64                `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float64                `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	GeofenceRadiusMeters float64                `protobuf:"fixed64,6,opt,name=geofence_radius_meters,json=geofenceRadiusMeters,proto3" json:"geofence_radius_meters,omitempty"`
+	Geofence             []*GeoPoint            `protobuf:"bytes,7,rep,name=geofence,proto3" json:"geofence,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PickupPoint) Reset() {
+	*x = PickupPoint{}
+	mi := &file_api_station_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PickupPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PickupPoint) ProtoMessage() {}
+
+func (x *PickupPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_station_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PickupPoint.ProtoReflect.Descriptor instead.
+func (*PickupPoint) Descriptor() ([]byte, []int) {
+	return file_api_station_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PickupPoint) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PickupPoint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PickupPoint) GetStationId() string {
+	if x != nil {
+		return x.StationId
+	}
+	return ""
+}
+
+func (x *PickupPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *PickupPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *PickupPoint) GetGeofenceRadiusMeters() float64 {
+	if x != nil {
+		return x.GeofenceRadiusMeters
+	}
+	return 0
+}
+
+func (x *PickupPoint) GetGeofence() []*GeoPoint {
+	if x != nil {
+		return x.Geofence
+	}
+	return nil
+}
+
 type AddStationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Station       *Station               `protobuf:"bytes,1,opt,name=station,proto3" json:"station,omitempty"`
@@ -90,7 +268,7 @@ type AddStationRequest struct {
 
 func (x *AddStationRequest) Reset() {
 	*x = AddStationRequest{}
-	mi := &file_api_station_proto_msgTypes[1]
+	mi := &file_api_station_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -102,7 +280,7 @@ func (x *AddStationRequest) String() string {
 func (*AddStationRequest) ProtoMessage() {}
 
 func (x *AddStationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_station_proto_msgTypes[1]
+	mi := &file_api_station_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -115,7 +293,7 @@ func (x *AddStationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddStationRequest.ProtoReflect.Descriptor instead.
 func (*AddStationRequest) Descriptor() ([]byte, []int) {
-	return file_api_station_proto_rawDescGZIP(), []int{1}
+	return file_api_station_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AddStationRequest) GetStation() *Station {
@@ -134,7 +312,7 @@ type AddStationResponse struct {
 
 func (x *AddStationResponse) Reset() {
 	*x = AddStationResponse{}
-	mi := &file_api_station_proto_msgTypes[2]
+	mi := &file_api_station_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -146,7 +324,7 @@ func (x *AddStationResponse) String() string {
 func (*AddStationResponse) ProtoMessage() {}
 
 func (x *AddStationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_station_proto_msgTypes[2]
+	mi := &file_api_station_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +337,7 @@ func (x *AddStationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddStationResponse.ProtoReflect.Descriptor instead.
 func (*AddStationResponse) Descriptor() ([]byte, []int) {
-	return file_api_station_proto_rawDescGZIP(), []int{2}
+	return file_api_station_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *AddStationResponse) GetId() string {
@@ -178,7 +356,7 @@ type GetStationRequest struct {
 
 func (x *GetStationRequest) Reset() {
 	*x = GetStationRequest{}
-	mi := &file_api_station_proto_msgTypes[3]
+	mi := &file_api_station_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -190,7 +368,7 @@ func (x *GetStationRequest) String() string {
 func (*GetStationRequest) ProtoMessage() {}
 
 func (x *GetStationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_station_proto_msgTypes[3]
+	mi := &file_api_station_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -203,7 +381,7 @@ func (x *GetStationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStationRequest.ProtoReflect.Descriptor instead.
 func (*GetStationRequest) Descriptor() ([]byte, []int) {
-	return file_api_station_proto_rawDescGZIP(), []int{3}
+	return file_api_station_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetStationRequest) GetId() string {
@@ -222,7 +400,7 @@ type GetStationResponse struct {
 
 func (x *GetStationResponse) Reset() {
 	*x = GetStationResponse{}
-	mi := &file_api_station_proto_msgTypes[4]
+	mi := &file_api_station_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -234,7 +412,7 @@ func (x *GetStationResponse) String() string {
 func (*GetStationResponse) ProtoMessage() {}
 
 func (x *GetStationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_station_proto_msgTypes[4]
+	mi := &file_api_station_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,7 +425,7 @@ func (x *GetStationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStationResponse.ProtoReflect.Descriptor instead.
 func (*GetStationResponse) Descriptor() ([]byte, []int) {
-	return file_api_station_proto_rawDescGZIP(), []int{4}
+	return file_api_station_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetStationResponse) GetStation() *Station {
@@ -257,15 +435,207 @@ func (x *GetStationResponse) GetStation() *Station {
 	return nil
 }
 
+type AddPickupPointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PickupPoint   *PickupPoint           `protobuf:"bytes,1,opt,name=pickup_point,json=pickupPoint,proto3" json:"pickup_point,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPickupPointRequest) Reset() {
+	*x = AddPickupPointRequest{}
+	mi := &file_api_station_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPickupPointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPickupPointRequest) ProtoMessage() {}
+
+func (x *AddPickupPointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_station_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPickupPointRequest.ProtoReflect.Descriptor instead.
+func (*AddPickupPointRequest) Descriptor() ([]byte, []int) {
+	return file_api_station_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddPickupPointRequest) GetPickupPoint() *PickupPoint {
+	if x != nil {
+		return x.PickupPoint
+	}
+	return nil
+}
+
+type AddPickupPointResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPickupPointResponse) Reset() {
+	*x = AddPickupPointResponse{}
+	mi := &file_api_station_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPickupPointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPickupPointResponse) ProtoMessage() {}
+
+func (x *AddPickupPointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_station_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPickupPointResponse.ProtoReflect.Descriptor instead.
+func (*AddPickupPointResponse) Descriptor() ([]byte, []int) {
+	return file_api_station_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AddPickupPointResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListStationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStationsRequest) Reset() {
+	*x = ListStationsRequest{}
+	mi := &file_api_station_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStationsRequest) ProtoMessage() {}
+
+func (x *ListStationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_station_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStationsRequest.ProtoReflect.Descriptor instead.
+func (*ListStationsRequest) Descriptor() ([]byte, []int) {
+	return file_api_station_proto_rawDescGZIP(), []int{9}
+}
+
+type ListStationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stations      []*Station             `protobuf:"bytes,1,rep,name=stations,proto3" json:"stations,omitempty"`
+	PickupPoints  []*PickupPoint         `protobuf:"bytes,2,rep,name=pickup_points,json=pickupPoints,proto3" json:"pickup_points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStationsResponse) Reset() {
+	*x = ListStationsResponse{}
+	mi := &file_api_station_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStationsResponse) ProtoMessage() {}
+
+func (x *ListStationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_station_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStationsResponse.ProtoReflect.Descriptor instead.
+func (*ListStationsResponse) Descriptor() ([]byte, []int) {
+	return file_api_station_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListStationsResponse) GetStations() []*Station {
+	if x != nil {
+		return x.Stations
+	}
+	return nil
+}
+
+func (x *ListStationsResponse) GetPickupPoints() []*PickupPoint {
+	if x != nil {
+		return x.PickupPoints
+	}
+	return nil
+}
+
 var File_api_station_proto protoreflect.FileDescriptor
 
 const file_api_station_proto_rawDesc = "" +
 	"\n" +
-	"\x11api/station.proto\x12\astation\"P\n" +
+	"\x11api/station.proto\x12\astation\"D\n" +
+	"\bGeoPoint\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xef\x01\n" +
 	"\aStation\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
-	"\fnearby_areas\x18\x03 \x03(\tR\vnearbyAreas\"?\n" +
+	"\fnearby_areas\x18\x03 \x03(\tR\vnearbyAreas\x12\x1a\n" +
+	"\blatitude\x18\x04 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x05 \x01(\x01R\tlongitude\x124\n" +
+	"\x16geofence_radius_meters\x18\x06 \x01(\x01R\x14geofenceRadiusMeters\x12-\n" +
+	"\bgeofence\x18\a \x03(\v2\x11.station.GeoPointR\bgeofence\"\xef\x01\n" +
+	"\vPickupPoint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x03 \x01(\tR\tstationId\x12\x1a\n" +
+	"\blatitude\x18\x04 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x05 \x01(\x01R\tlongitude\x124\n" +
+	"\x16geofence_radius_meters\x18\x06 \x01(\x01R\x14geofenceRadiusMeters\x12-\n" +
+	"\bgeofence\x18\a \x03(\v2\x11.station.GeoPointR\bgeofence\"?\n" +
 	"\x11AddStationRequest\x12*\n" +
 	"\astation\x18\x01 \x01(\v2\x10.station.StationR\astation\"$\n" +
 	"\x12AddStationResponse\x12\x0e\n" +
@@ -273,12 +643,22 @@ const file_api_station_proto_rawDesc = "" +
 	"\x11GetStationRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"@\n" +
 	"\x12GetStationResponse\x12*\n" +
-	"\astation\x18\x01 \x01(\v2\x10.station.StationR\astation2\x9e\x01\n" +
+	"\astation\x18\x01 \x01(\v2\x10.station.StationR\astation\"P\n" +
+	"\x15AddPickupPointRequest\x127\n" +
+	"\fpickup_point\x18\x01 \x01(\v2\x14.station.PickupPointR\vpickupPoint\"(\n" +
+	"\x16AddPickupPointResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13ListStationsRequest\"\x7f\n" +
+	"\x14ListStationsResponse\x12,\n" +
+	"\bstations\x18\x01 \x03(\v2\x10.station.StationR\bstations\x129\n" +
+	"\rpickup_points\x18\x02 \x03(\v2\x14.station.PickupPointR\fpickupPoints2\xbe\x02\n" +
 	"\x0eStationService\x12E\n" +
 	"\n" +
 	"AddStation\x12\x1a.station.AddStationRequest\x1a\x1b.station.AddStationResponse\x12E\n" +
 	"\n" +
-	"GetStation\x12\x1a.station.GetStationRequest\x1a\x1b.station.GetStationResponseB\x19Z\x17lastmile/gen/go/stationb\x06proto3"
+	"GetStation\x12\x1a.station.GetStationRequest\x1a\x1b.station.GetStationResponse\x12Q\n" +
+	"\x0eAddPickupPoint\x12\x1e.station.AddPickupPointRequest\x1a\x1f.station.AddPickupPointResponse\x12K\n" +
+	"\fListStations\x12\x1c.station.ListStationsRequest\x1a\x1d.station.ListStationsResponseB\x19Z\x17lastmile/gen/go/stationb\x06proto3"
 
 var (
 	file_api_station_proto_rawDescOnce sync.Once
@@ -292,26 +672,41 @@ func file_api_station_proto_rawDescGZIP() []byte {
 	return file_api_station_proto_rawDescData
 }
 
-var file_api_station_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_station_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_station_proto_goTypes = []any{
-	(*Station)(nil),            // 0: station.Station
-	(*AddStationRequest)(nil),  // 1: station.AddStationRequest
-	(*AddStationResponse)(nil), // 2: station.AddStationResponse
-	(*GetStationRequest)(nil),  // 3: station.GetStationRequest
-	(*GetStationResponse)(nil), // 4: station.GetStationResponse
+	(*GeoPoint)(nil),               // 0: station.GeoPoint
+	(*Station)(nil),                // 1: station.Station
+	(*PickupPoint)(nil),            // 2: station.PickupPoint
+	(*AddStationRequest)(nil),      // 3: station.AddStationRequest
+	(*AddStationResponse)(nil),     // 4: station.AddStationResponse
+	(*GetStationRequest)(nil),      // 5: station.GetStationRequest
+	(*GetStationResponse)(nil),     // 6: station.GetStationResponse
+	(*AddPickupPointRequest)(nil),  // 7: station.AddPickupPointRequest
+	(*AddPickupPointResponse)(nil), // 8: station.AddPickupPointResponse
+	(*ListStationsRequest)(nil),    // 9: station.ListStationsRequest
+	(*ListStationsResponse)(nil),   // 10: station.ListStationsResponse
 }
 var file_api_station_proto_depIdxs = []int32{
-	0, // 0: station.AddStationRequest.station:type_name -> station.Station
-	0, // 1: station.GetStationResponse.station:type_name -> station.Station
-	1, // 2: station.StationService.AddStation:input_type -> station.AddStationRequest
-	3, // 3: station.StationService.GetStation:input_type -> station.GetStationRequest
-	2, // 4: station.StationService.AddStation:output_type -> station.AddStationResponse
-	4, // 5: station.StationService.GetStation:output_type -> station.GetStationResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: station.Station.geofence:type_name -> station.GeoPoint
+	0,  // 1: station.PickupPoint.geofence:type_name -> station.GeoPoint
+	1,  // 2: station.AddStationRequest.station:type_name -> station.Station
+	1,  // 3: station.GetStationResponse.station:type_name -> station.Station
+	2,  // 4: station.AddPickupPointRequest.pickup_point:type_name -> station.PickupPoint
+	1,  // 5: station.ListStationsResponse.stations:type_name -> station.Station
+	2,  // 6: station.ListStationsResponse.pickup_points:type_name -> station.PickupPoint
+	3,  // 7: station.StationService.AddStation:input_type -> station.AddStationRequest
+	5,  // 8: station.StationService.GetStation:input_type -> station.GetStationRequest
+	7,  // 9: station.StationService.AddPickupPoint:input_type -> station.AddPickupPointRequest
+	9,  // 10: station.StationService.ListStations:input_type -> station.ListStationsRequest
+	4,  // 11: station.StationService.AddStation:output_type -> station.AddStationResponse
+	6,  // 12: station.StationService.GetStation:output_type -> station.GetStationResponse
+	8,  // 13: station.StationService.AddPickupPoint:output_type -> station.AddPickupPointResponse
+	10, // 14: station.StationService.ListStations:output_type -> station.ListStationsResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_api_station_proto_init() }
@@ -325,7 +720,7 @@ func file_api_station_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_station_proto_rawDesc), len(file_api_station_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
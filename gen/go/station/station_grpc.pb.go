@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StationService_AddStation_FullMethodName = "/station.StationService/AddStation"
-	StationService_GetStation_FullMethodName = "/station.StationService/GetStation"
+	StationService_AddStation_FullMethodName     = "/station.StationService/AddStation"
+	StationService_GetStation_FullMethodName     = "/station.StationService/GetStation"
+	StationService_AddPickupPoint_FullMethodName = "/station.StationService/AddPickupPoint"
+	StationService_ListStations_FullMethodName   = "/station.StationService/ListStations"
 )
 
 // StationServiceClient is the client API for StationService service.
@@ -29,6 +31,8 @@ const (
 type StationServiceClient interface {
 	AddStation(ctx context.Context, in *AddStationRequest, opts ...grpc.CallOption) (*AddStationResponse, error)
 	GetStation(ctx context.Context, in *GetStationRequest, opts ...grpc.CallOption) (*GetStationResponse, error)
+	AddPickupPoint(ctx context.Context, in *AddPickupPointRequest, opts ...grpc.CallOption) (*AddPickupPointResponse, error)
+	ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (*ListStationsResponse, error)
 }
 
 type stationServiceClient struct {
@@ -59,12 +63,34 @@ func (c *stationServiceClient) GetStation(ctx context.Context, in *GetStationReq
 	return out, nil
 }
 
+func (c *stationServiceClient) AddPickupPoint(ctx context.Context, in *AddPickupPointRequest, opts ...grpc.CallOption) (*AddPickupPointResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddPickupPointResponse)
+	err := c.cc.Invoke(ctx, StationService_AddPickupPoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stationServiceClient) ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (*ListStationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStationsResponse)
+	err := c.cc.Invoke(ctx, StationService_ListStations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StationServiceServer is the server API for StationService service.
 // All implementations must embed UnimplementedStationServiceServer
 // for forward compatibility.
 type StationServiceServer interface {
 	AddStation(context.Context, *AddStationRequest) (*AddStationResponse, error)
 	GetStation(context.Context, *GetStationRequest) (*GetStationResponse, error)
+	AddPickupPoint(context.Context, *AddPickupPointRequest) (*AddPickupPointResponse, error)
+	ListStations(context.Context, *ListStationsRequest) (*ListStationsResponse, error)
 	mustEmbedUnimplementedStationServiceServer()
 }
 
@@ -81,6 +107,12 @@ func (UnimplementedStationServiceServer) AddStation(context.Context, *AddStation
 func (UnimplementedStationServiceServer) GetStation(context.Context, *GetStationRequest) (*GetStationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStation not implemented")
 }
+func (UnimplementedStationServiceServer) AddPickupPoint(context.Context, *AddPickupPointRequest) (*AddPickupPointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPickupPoint not implemented")
+}
+func (UnimplementedStationServiceServer) ListStations(context.Context, *ListStationsRequest) (*ListStationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStations not implemented")
+}
 func (UnimplementedStationServiceServer) mustEmbedUnimplementedStationServiceServer() {}
 func (UnimplementedStationServiceServer) testEmbeddedByValue()                        {}
 
@@ -138,6 +170,42 @@ func _StationService_GetStation_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StationService_AddPickupPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPickupPointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StationServiceServer).AddPickupPoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StationService_AddPickupPoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StationServiceServer).AddPickupPoint(ctx, req.(*AddPickupPointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StationService_ListStations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StationServiceServer).ListStations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StationService_ListStations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StationServiceServer).ListStations(ctx, req.(*ListStationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StationService_ServiceDesc is the grpc.ServiceDesc for StationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +221,14 @@ var StationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStation",
 			Handler:    _StationService_GetStation_Handler,
 		},
+		{
+			MethodName: "AddPickupPoint",
+			Handler:    _StationService_AddPickupPoint_Handler,
+		},
+		{
+			MethodName: "ListStations",
+			Handler:    _StationService_ListStations_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/station.proto",
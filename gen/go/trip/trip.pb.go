@@ -209,6 +209,94 @@ func (x *GetTripResponse) GetTrip() *Trip {
 	return nil
 }
 
+type CreateTripRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trip          *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTripRequest) Reset() {
+	*x = CreateTripRequest{}
+	mi := &file_api_trip_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTripRequest) ProtoMessage() {}
+
+func (x *CreateTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_trip_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTripRequest.ProtoReflect.Descriptor instead.
+func (*CreateTripRequest) Descriptor() ([]byte, []int) {
+	return file_api_trip_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTripRequest) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type CreateTripResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trip          *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTripResponse) Reset() {
+	*x = CreateTripResponse{}
+	mi := &file_api_trip_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTripResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTripResponse) ProtoMessage() {}
+
+func (x *CreateTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_trip_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTripResponse.ProtoReflect.Descriptor instead.
+func (*CreateTripResponse) Descriptor() ([]byte, []int) {
+	return file_api_trip_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateTripResponse) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
 type UpdateTripRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -219,7 +307,7 @@ type UpdateTripRequest struct {
 
 func (x *UpdateTripRequest) Reset() {
 	*x = UpdateTripRequest{}
-	mi := &file_api_trip_proto_msgTypes[3]
+	mi := &file_api_trip_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -231,7 +319,7 @@ func (x *UpdateTripRequest) String() string {
 func (*UpdateTripRequest) ProtoMessage() {}
 
 func (x *UpdateTripRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_trip_proto_msgTypes[3]
+	mi := &file_api_trip_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +332,7 @@ func (x *UpdateTripRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTripRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTripRequest) Descriptor() ([]byte, []int) {
-	return file_api_trip_proto_rawDescGZIP(), []int{3}
+	return file_api_trip_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpdateTripRequest) GetId() string {
@@ -270,7 +358,7 @@ type UpdateTripResponse struct {
 
 func (x *UpdateTripResponse) Reset() {
 	*x = UpdateTripResponse{}
-	mi := &file_api_trip_proto_msgTypes[4]
+	mi := &file_api_trip_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -282,7 +370,7 @@ func (x *UpdateTripResponse) String() string {
 func (*UpdateTripResponse) ProtoMessage() {}
 
 func (x *UpdateTripResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_trip_proto_msgTypes[4]
+	mi := &file_api_trip_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -295,7 +383,7 @@ func (x *UpdateTripResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTripResponse.ProtoReflect.Descriptor instead.
 func (*UpdateTripResponse) Descriptor() ([]byte, []int) {
-	return file_api_trip_proto_rawDescGZIP(), []int{4}
+	return file_api_trip_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateTripResponse) GetTrip() *Trip {
@@ -326,14 +414,22 @@ const file_api_trip_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
 	"\x0fGetTripResponse\x12\x1e\n" +
 	"\x04trip\x18\x01 \x01(\v2\n" +
+	".trip.TripR\x04trip\"3\n" +
+	"\x11CreateTripRequest\x12\x1e\n" +
+	"\x04trip\x18\x01 \x01(\v2\n" +
+	".trip.TripR\x04trip\"4\n" +
+	"\x12CreateTripResponse\x12\x1e\n" +
+	"\x04trip\x18\x01 \x01(\v2\n" +
 	".trip.TripR\x04trip\";\n" +
 	"\x11UpdateTripRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\"4\n" +
 	"\x12UpdateTripResponse\x12\x1e\n" +
 	"\x04trip\x18\x01 \x01(\v2\n" +
-	".trip.TripR\x04trip2\x86\x01\n" +
-	"\vTripService\x126\n" +
+	".trip.TripR\x04trip2\xc7\x01\n" +
+	"\vTripService\x12?\n" +
+	"\n" +
+	"CreateTrip\x12\x17.trip.CreateTripRequest\x1a\x18.trip.CreateTripResponse\x126\n" +
 	"\aGetTrip\x12\x14.trip.GetTripRequest\x1a\x15.trip.GetTripResponse\x12?\n" +
 	"\n" +
 	"UpdateTrip\x12\x17.trip.UpdateTripRequest\x1a\x18.trip.UpdateTripResponseB\x16Z\x14lastmile/gen/go/tripb\x06proto3"
@@ -350,26 +446,32 @@ func file_api_trip_proto_rawDescGZIP() []byte {
 	return file_api_trip_proto_rawDescData
 }
 
-var file_api_trip_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_trip_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_api_trip_proto_goTypes = []any{
 	(*Trip)(nil),               // 0: trip.Trip
 	(*GetTripRequest)(nil),     // 1: trip.GetTripRequest
 	(*GetTripResponse)(nil),    // 2: trip.GetTripResponse
-	(*UpdateTripRequest)(nil),  // 3: trip.UpdateTripRequest
-	(*UpdateTripResponse)(nil), // 4: trip.UpdateTripResponse
+	(*CreateTripRequest)(nil),  // 3: trip.CreateTripRequest
+	(*CreateTripResponse)(nil), // 4: trip.CreateTripResponse
+	(*UpdateTripRequest)(nil),  // 5: trip.UpdateTripRequest
+	(*UpdateTripResponse)(nil), // 6: trip.UpdateTripResponse
 }
 var file_api_trip_proto_depIdxs = []int32{
 	0, // 0: trip.GetTripResponse.trip:type_name -> trip.Trip
-	0, // 1: trip.UpdateTripResponse.trip:type_name -> trip.Trip
-	1, // 2: trip.TripService.GetTrip:input_type -> trip.GetTripRequest
-	3, // 3: trip.TripService.UpdateTrip:input_type -> trip.UpdateTripRequest
-	2, // 4: trip.TripService.GetTrip:output_type -> trip.GetTripResponse
-	4, // 5: trip.TripService.UpdateTrip:output_type -> trip.UpdateTripResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0, // 1: trip.CreateTripRequest.trip:type_name -> trip.Trip
+	0, // 2: trip.CreateTripResponse.trip:type_name -> trip.Trip
+	0, // 3: trip.UpdateTripResponse.trip:type_name -> trip.Trip
+	3, // 4: trip.TripService.CreateTrip:input_type -> trip.CreateTripRequest
+	1, // 5: trip.TripService.GetTrip:input_type -> trip.GetTripRequest
+	5, // 6: trip.TripService.UpdateTrip:input_type -> trip.UpdateTripRequest
+	4, // 7: trip.TripService.CreateTrip:output_type -> trip.CreateTripResponse
+	2, // 8: trip.TripService.GetTrip:output_type -> trip.GetTripResponse
+	6, // 9: trip.TripService.UpdateTrip:output_type -> trip.UpdateTripResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_api_trip_proto_init() }
@@ -383,7 +485,7 @@ func file_api_trip_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_trip_proto_rawDesc), len(file_api_trip_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"lastmile/internal/pkg/assign"
+)
+
+const (
+	// matchModeGreedy offers each rider to the nearest drivers as soon as they book.
+	matchModeGreedy = "greedy"
+	// matchModeBatch collects riders per station and assigns them all at once.
+	matchModeBatch = "batch"
+
+	defaultBatchWindow = 15 * time.Second
+	// batchRiderWaitWeight is what each minute a rider would stand at the
+	// pickup waiting for their driver adds to a pairing's cost, on top of the
+	// arrival gap. It makes a driver who is early cheaper than one who is
+	// equally late.
+	batchRiderWaitWeight = 1.0
+)
+
+type batchMatchRequest struct {
+	StationID string `json:"stationId"`
+}
+
+type batchMatchResponse struct {
+	StationID string   `json:"stationId"`
+	Trips     []Trip   `json:"trips"`
+	Unmatched []string `json:"unmatchedRiderIds"`
+}
+
+// SetMatchMode switches between greedy and batch matching. In batch mode
+// riders booking at a station are held for window and then assigned together.
+func (g *Gateway) SetMatchMode(mode string, window time.Duration) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = matchModeGreedy
+	}
+	if mode != matchModeGreedy && mode != matchModeBatch {
+		return fmt.Errorf("unknown match mode '%s'", mode)
+	}
+	if window <= 0 {
+		window = defaultBatchWindow
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.matchMode = mode
+	g.batchWindow = window
+	return nil
+}
+
+func (g *Gateway) batchModeLocked() bool {
+	return g.matchMode == matchModeBatch
+}
+
+// scheduleBatchLocked arms a one-shot timer that assigns everyone waiting at
+// the station once the batch window closes. Already armed stations are left alone.
+func (g *Gateway) scheduleBatchLocked(stationID string) {
+	if _, armed := g.batchTimers[stationID]; armed {
+		return
+	}
+	window := g.batchWindow
+	if window <= 0 {
+		window = defaultBatchWindow
+	}
+	g.batchTimers[stationID] = time.AfterFunc(window, func() {
+		if _, err := g.runStationBatch(stationID); err != nil {
+			g.logger.Warn("batch match failed", "stationId", stationID, "err", err)
+		}
+	})
+}
+
+// runStationBatch assigns every waiting rider at the station to the approaching
+// drivers in one pass. Seats, destination compatibility and the arrival window
+// constrain the assignment; the gap between driver and rider arrival plus the
+// weighted time riders would wait for their driver is the cost being
+// minimised. Riders left over stay queued for the next batch.
+func (g *Gateway) runStationBatch(stationID string) (batchMatchResponse, error) {
+	g.mu.Lock()
+	if timer, ok := g.batchTimers[stationID]; ok {
+		timer.Stop()
+		delete(g.batchTimers, stationID)
+	}
+	station, ok := g.stationByID(stationID)
+	if !ok {
+		g.mu.Unlock()
+		return batchMatchResponse{}, fmt.Errorf("unknown station '%s'", stationID)
+	}
+
+	window := g.matchWindowLocked(0)
+	now := time.Now()
+	g.expireStaleRidersLocked(now)
+
+	riders := make([]*Rider, 0)
+	for i := range g.riders {
+		if g.riders[i].StationID == stationID && g.riders[i].Status == "waiting" {
+			riders = append(riders, &g.riders[i])
+		}
+	}
+	drivers := make([]*Driver, 0)
+	for i := range g.drivers {
+		if routeContains(g.drivers[i].Route.TargetStationIDs, stationID) {
+			drivers = append(drivers, &g.drivers[i])
+		}
+	}
+
+	supply := make([]assign.Driver, len(drivers))
+	for i, d := range drivers {
+		supply[i] = assign.Driver{ID: d.ID, Seats: g.seatsForDriverLocked(d)}
+	}
+	demand := make([]assign.Rider, len(riders))
+	for j, r := range riders {
+		demand[j] = assign.Rider{ID: r.ID}
+	}
+
+	pairs := assign.Solve(supply, demand, func(i, j int) (float64, bool) {
+		driver, rider := drivers[i], riders[j]
+		if !compatibleDestination(driver.Route.Destination, rider.Destination) {
+			return 0, false
+		}
+		if !g.driverCanServeLocked(driver, station, rider.Pickup, rider.ArrivalTime, window, now) {
+			return 0, false
+		}
+		arrival := g.projectedArrivalLocked(driver, station, now)
+		return arrivalGapMinutes(arrival, rider.ArrivalTime) + batchRiderWaitWeight*riderWaitMinutes(arrival, rider.ArrivalTime, now), true
+	})
+
+	seated := make(map[string]bool, len(pairs))
+	for _, p := range pairs {
+		seated[p.RiderID] = true
+	}
+	unmatched := make([]string, 0)
+	for _, r := range riders {
+		if !seated[r.ID] {
+			unmatched = append(unmatched, r.ID)
+			g.deferRiderLocked(r.ID, station, r.Pickup)
+		}
+	}
+	stationCopy := copyStation(station)
+	g.mu.Unlock()
+
+	trips := make([]Trip, 0, len(pairs))
+	for _, p := range pairs {
+		trip, err := g.createTripForRider(p.DriverID, stationID, p.RiderID)
+		if err != nil {
+			g.logger.Warn("batch trip creation failed", "driverId", p.DriverID, "riderId", p.RiderID, "err", err)
+			g.mu.Lock()
+			if rider, findErr := g.findRiderByID(p.RiderID); findErr == nil {
+				g.deferRiderLocked(rider.ID, stationCopy, rider.Pickup)
+			}
+			g.mu.Unlock()
+			unmatched = append(unmatched, p.RiderID)
+			continue
+		}
+		trips = append(trips, trip)
+	}
+	for _, trip := range trips {
+		g.queueRiderApproval(trip)
+	}
+
+	g.logger.Info("batch match completed",
+		"stationId", stationID,
+		"riders", len(riders),
+		"drivers", len(drivers),
+		"matched", len(trips),
+		"unmatched", len(unmatched))
+
+	return batchMatchResponse{StationID: stationID, Trips: trips, Unmatched: unmatched}, nil
+}
+
+func (g *Gateway) seatsForDriverLocked(driver *Driver) int {
+	if plan, ok := g.driverPlans[driver.ID]; ok {
+		return plan.SeatsAvailable
+	}
+	return driver.SeatsAvailable
+}
+
+func arrivalGapMinutes(driverArrival, riderArrival time.Time) float64 {
+	if riderArrival.IsZero() {
+		return 0
+	}
+	return math.Abs(driverArrival.Sub(riderArrival).Minutes())
+}
+
+// riderWaitMinutes is how long a rider ready at riderArrival, or now if that
+// has passed, would wait for a driver arriving at driverArrival.
+func riderWaitMinutes(driverArrival, riderArrival, now time.Time) float64 {
+	ready := now
+	if riderArrival.After(now) {
+		ready = riderArrival
+	}
+	return math.Max(0, driverArrival.Sub(ready).Minutes())
+}
+
+// BatchMatchHandler runs a batch assignment for a station immediately and
+// returns every trip it created.
+func (g *Gateway) BatchMatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload batchMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if payload.StationID == "" {
+		http.Error(w, "stationId is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := g.runStationBatch(payload.StationID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -7,10 +7,16 @@ import (
 	"time"
 
 	driverpb "lastmile/gen/go/driver"
-	locationpb "lastmile/gen/go/location"
 )
 
 func (g *Gateway) configureDriverRoute(payload driverRouteRequest) (driverRouteResponse, error) {
+	return g.replaceDriverRoute(payload, nil)
+}
+
+// replaceDriverRoute configures the driver's route. When check is set it runs
+// under g.mu right before the new plan replaces the old one, and an error
+// from it leaves the driver's route untouched.
+func (g *Gateway) replaceDriverRoute(payload driverRouteRequest, check func() error) (driverRouteResponse, error) {
 	driverID := strings.TrimSpace(payload.DriverID)
 	if driverID == "" {
 		return driverRouteResponse{}, fmt.Errorf("driverId is required")
@@ -23,6 +29,13 @@ func (g *Gateway) configureDriverRoute(payload driverRouteRequest) (driverRouteR
 	if seats <= 0 {
 		seats = 1
 	}
+	vehicle, err := g.resolveDriverVehicle(driverID, strings.TrimSpace(payload.VehicleID))
+	if err != nil {
+		return driverRouteResponse{}, err
+	}
+	if vehicle != nil && vehicle.MaxSeats > 0 && seats > vehicle.MaxSeats {
+		seats = vehicle.MaxSeats
+	}
 	targets := g.stationIDsForPickupIDs(normalized)
 	if len(targets) == 0 {
 		return driverRouteResponse{}, fmt.Errorf("unable to infer metro stations for selected pickups")
@@ -43,10 +56,23 @@ func (g *Gateway) configureDriverRoute(payload driverRouteRequest) (driverRouteR
 	}
 
 	g.mu.Lock()
+	if check != nil {
+		if err := check(); err != nil {
+			g.mu.Unlock()
+			return driverRouteResponse{}, err
+		}
+	}
 	if existing, ok := g.driverPlans[driverID]; ok && existing.simCancel != nil {
 		existing.simCancel()
 	}
 	g.driverPlans[driverID] = plan
+	// A new route has to be started before the driver takes riders again.
+	switch g.driverStatuses[driverID] {
+	case "":
+		g.driverStatuses[driverID] = driverStatusOffline
+	case driverStatusAvailable:
+		g.setDriverStatusLocked(driverID, driverStatusOffline, "route changed")
+	}
 	g.mu.Unlock()
 
 	if g.store != nil {
@@ -65,6 +91,7 @@ func (g *Gateway) configureDriverRoute(payload driverRouteRequest) (driverRouteR
 		SeatsAvailable: seats,
 		TargetStations: targets,
 		Destination:    destination,
+		Vehicle:        vehicle,
 	}, nil
 }
 
@@ -124,16 +151,21 @@ func (g *Gateway) ensureDriverProfile(payload driverRouteRequest, targets []stri
 		g.logger.Warn("register driver failed", "driverId", payload.DriverID, "err", err)
 	}
 
-	_, err = g.driverClient.RegisterRoute(ctx, &driverpb.RegisterRouteRequest{
+	g.registerDriverRoute(ctx, payload.DriverID, targets, destination, seats)
+}
+
+func (g *Gateway) registerDriverRoute(ctx context.Context, driverID string, targets []string, destination string, seats int) {
+	_, err := g.driverClient.RegisterRoute(ctx, &driverpb.RegisterRouteRequest{
 		Route: &driverpb.Route{
-			DriverId:         payload.DriverID,
+			DriverId:         driverID,
 			TargetStationIds: targets,
 			AvailableSeats:   int32(seats),
+			SeatsTotal:       int32(seats),
 			Destination:      destination,
 		},
 	})
 	if err != nil {
-		g.logger.Warn("register route failed", "driverId", payload.DriverID, "err", err)
+		g.logger.Warn("register route failed", "driverId", driverID, "err", err)
 	}
 }
 
@@ -154,6 +186,10 @@ func (g *Gateway) startDriverTrip(payload startTripRequest) (driverRouteResponse
 	plan.SeatsAvailable = plan.SeatsTotal
 	plan.CurrentIndex = 0
 	plan.Simulated = payload.Simulate
+	if g.driverStatuses[driverID] != driverStatusAvailable {
+		g.setDriverStatusLocked(driverID, driverStatusAvailable, "trip started")
+	}
+	g.syncDriverStatusLocked(driverID, "trip started")
 	pickups := g.pickupPointsForIDs(plan.PickupIDs)
 	targets := append([]string{}, plan.TargetStations...)
 	destination := plan.Destination
@@ -199,70 +235,16 @@ func (g *Gateway) startDriverTrip(payload startTripRequest) (driverRouteResponse
 	return planResp, nil
 }
 
-func (g *Gateway) runSimulatedTrip(ctx context.Context, driverID string, waypoints []PickupPoint) {
-	if g.locationClient == nil {
-		return
-	}
-	for _, wp := range waypoints {
-		if g.simulatedHop(ctx, driverID, wp.Latitude, wp.Longitude) {
-			return
-		}
-		passed := g.recordDriverLocationProgress(driverID, wp.Latitude, wp.Longitude)
-		if passed != nil {
-			g.handlePickupCheckpoint(driverID, passed)
-		}
-		g.maybeCompleteTrips(driverID, wp.Latitude, wp.Longitude)
-		if station, ok := g.stationByID(wp.StationID); ok {
-			if g.simulatedHop(ctx, driverID, station.Latitude, station.Longitude) {
-				return
-			}
-			g.maybeCompleteTrips(driverID, station.Latitude, station.Longitude)
-		}
-	}
-}
-
-func (g *Gateway) simulatedHop(ctx context.Context, driverID string, lat, lon float64) bool {
-	select {
-	case <-ctx.Done():
-		return true
-	default:
-	}
-	_ = g.pushLocationUpdate(ctx, driverID, lat, lon)
-	g.publishDriverLocation(driverID, lat, lon)
-	select {
-	case <-ctx.Done():
-		return true
-	case <-time.After(3 * time.Second):
-	}
-	return false
-}
-
-func (g *Gateway) pushLocationUpdate(ctx context.Context, driverID string, lat, lon float64) error {
-	if g.locationClient == nil {
-		return nil
-	}
-	stream, err := g.locationClient.UpdateLocation(ctx)
-	if err != nil {
-		g.logger.Warn("simulate location stream failed", "driverId", driverID, "err", err)
-		return err
-	}
-	if err := stream.Send(&locationpb.UpdateLocationRequest{Location: &locationpb.Location{DriverId: driverID, Latitude: lat, Longitude: lon}}); err != nil {
-		g.logger.Warn("simulate location send failed", "driverId", driverID, "err", err)
-		return err
-	}
-	if _, err := stream.CloseAndRecv(); err != nil {
-		g.logger.Warn("simulate location close failed", "driverId", driverID, "err", err)
-		return err
-	}
-	return nil
-}
-
 func (g *Gateway) recordDriverLocationProgress(driverID string, lat, lon float64) *PickupPoint {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	plan, ok := g.driverPlans[driverID]
-	if !ok || plan.CurrentIndex >= len(plan.PickupIDs) {
+	if !ok {
+		return nil
+	}
+	g.noteScheduledDepartureLocked(plan, lat, lon)
+	if plan.CurrentIndex >= len(plan.PickupIDs) {
 		return nil
 	}
 	nextID := plan.PickupIDs[plan.CurrentIndex]
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	driverpb "lastmile/gen/go/driver"
+	"lastmile/internal/pkg/driverstatus"
+)
+
+// Driver statuses as kept by DriverService. The gateway mirrors each
+// driver's status and moves it along as trips are matched, picked up and
+// completed; the driver service decides which changes are allowed.
+const (
+	driverStatusOffline   = "offline"
+	driverStatusAvailable = "available"
+	driverStatusEnRoute   = "en_route_to_pickup"
+	driverStatusFull      = "full"
+
+	// driverStatusPushBacklog is how many unsent changes may queue up before
+	// the gateway warns that DriverService is falling behind. Changes are
+	// never dropped.
+	driverStatusPushBacklog = 256
+	driverStatusPushTimeout = 3 * time.Second
+)
+
+// driverStatusChange is one status the gateway asks DriverService to apply.
+// Previous is the mirrored status it replaced, restored if the change fails.
+type driverStatusChange struct {
+	DriverID string
+	Status   string
+	Previous string
+	Reason   string
+}
+
+// DriverStatusUpdate is the reply to a status change made over HTTP.
+type DriverStatusUpdate struct {
+	DriverID  string `json:"driverId"`
+	Status    string `json:"status"`
+	Previous  string `json:"previous"`
+	Reason    string `json:"reason,omitempty"`
+	ChangedAt string `json:"changedAt"`
+}
+
+type driverStatusRequest struct {
+	DriverID string `json:"driverId"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason"`
+}
+
+// noteDriverStatusLocked records a status reported by DriverService.
+func (g *Gateway) noteDriverStatusLocked(driverID string, s driverpb.DriverStatus) {
+	if name := driverstatus.Name(s); name != "" {
+		g.driverStatuses[driverID] = name
+	}
+}
+
+// wantedDriverStatusLocked derives a started driver's status from their
+// seats and trips. It returns "" for drivers who have not started a trip,
+// whose status the gateway leaves alone.
+func (g *Gateway) wantedDriverStatusLocked(driverID string) string {
+	plan, ok := g.driverPlans[driverID]
+	if !ok || !plan.Active {
+		return ""
+	}
+	if plan.SeatsAvailable <= 0 {
+		return driverStatusFull
+	}
+	for i := range g.trips {
+		trip := &g.trips[i]
+		if trip.DriverID != driverID {
+			continue
+		}
+		switch trip.Status {
+		case "awaiting_rider", "pending", "awaiting_pickup":
+			return driverStatusEnRoute
+		}
+	}
+	return driverStatusAvailable
+}
+
+// syncDriverStatusLocked moves the driver to the status their trips call
+// for. A driver who went offline stays offline until they start a trip.
+func (g *Gateway) syncDriverStatusLocked(driverID, reason string) {
+	current := g.driverStatuses[driverID]
+	if current == driverStatusOffline {
+		return
+	}
+	if want := g.wantedDriverStatusLocked(driverID); want != "" && want != current {
+		g.setDriverStatusLocked(driverID, want, reason)
+	}
+}
+
+// setDriverStatusLocked updates the mirror straight away and queues the
+// change for DriverService, which applies queued changes in order.
+func (g *Gateway) setDriverStatusLocked(driverID, status, reason string) {
+	previous := g.driverStatuses[driverID]
+	g.driverStatuses[driverID] = status
+	if g.driverClient == nil {
+		return
+	}
+	g.statusPushOnce.Do(func() {
+		g.statusWake = make(chan struct{}, 1)
+		go g.pushDriverStatuses()
+	})
+	g.statusQueue = append(g.statusQueue, driverStatusChange{DriverID: driverID, Status: status, Previous: previous, Reason: reason})
+	if len(g.statusQueue) == driverStatusPushBacklog {
+		g.logger.Warn("driver status changes backing up", "queued", len(g.statusQueue))
+	}
+	select {
+	case g.statusWake <- struct{}{}:
+	default:
+	}
+}
+
+// pushDriverStatuses sends queued changes to DriverService in order. A
+// change it refuses, or that cannot be delivered, is undone in the mirror
+// unless a later change has already replaced it.
+func (g *Gateway) pushDriverStatuses() {
+	for range g.statusWake {
+		g.mu.Lock()
+		changes := g.statusQueue
+		g.statusQueue = nil
+		g.mu.Unlock()
+
+		for _, change := range changes {
+			s, _ := driverstatus.Parse(change.Status)
+			ctx, cancel := context.WithTimeout(context.Background(), driverStatusPushTimeout)
+			_, err := g.driverClient.SetDriverStatus(ctx, &driverpb.SetDriverStatusRequest{DriverId: change.DriverID, Status: s, Reason: change.Reason})
+			cancel()
+			if err == nil {
+				continue
+			}
+			g.logger.Warn("set driver status failed, restoring previous status", "driverId", change.DriverID, "status", change.Status, "previous", change.Previous, "err", err)
+			g.mu.Lock()
+			if g.driverStatuses[change.DriverID] == change.Status {
+				if change.Previous == "" {
+					delete(g.driverStatuses, change.DriverID)
+				} else {
+					g.driverStatuses[change.DriverID] = change.Previous
+				}
+			}
+			g.mu.Unlock()
+		}
+	}
+}
+
+// AttachDriverStatusFeed keeps the gateway's driver statuses in step with
+// DriverService.WatchDriverStatus until ctx is cancelled, reconnecting when
+// the stream drops.
+func (g *Gateway) AttachDriverStatusFeed(ctx context.Context, client driverpb.DriverServiceClient) {
+	if client == nil {
+		return
+	}
+	go func() {
+		backoff := time.Second
+		for {
+			stream, err := client.WatchDriverStatus(ctx, &driverpb.WatchDriverStatusRequest{})
+			if err == nil {
+				backoff = time.Second
+				for {
+					ev, recvErr := stream.Recv()
+					if recvErr != nil {
+						err = recvErr
+						break
+					}
+					g.mu.Lock()
+					g.noteDriverStatusLocked(ev.DriverId, ev.Status)
+					g.mu.Unlock()
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			g.logger.Warn("driver status feed disconnected", "err", err, "retryIn", backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+// DriverStatusHandler lets a driver go online or offline by hand. POST
+// {driverId, status, reason}; a change the driver service refuses, such as
+// going offline with riders still to pick up, is a 409.
+func (g *Gateway) DriverStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var payload driverStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	driverID := strings.TrimSpace(payload.DriverID)
+	want, ok := driverstatus.Parse(payload.Status)
+	if driverID == "" || !ok {
+		http.Error(w, "driverId and a valid status are required", http.StatusBadRequest)
+		return
+	}
+	if g.driverClient == nil {
+		http.Error(w, "driver service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), driverStatusPushTimeout)
+	defer cancel()
+	resp, err := g.driverClient.SetDriverStatus(ctx, &driverpb.SetDriverStatusRequest{DriverId: driverID, Status: want, Reason: payload.Reason})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		case codes.NotFound:
+			http.Error(w, status.Convert(err).Message(), http.StatusNotFound)
+		case codes.FailedPrecondition:
+			http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+		default:
+			g.logger.Error("set driver status failed", "driverId", driverID, "err", err)
+			http.Error(w, "failed to set driver status", http.StatusBadGateway)
+		}
+		return
+	}
+
+	change := resp.GetChange()
+	g.mu.Lock()
+	g.noteDriverStatusLocked(driverID, change.GetStatus())
+	g.mu.Unlock()
+	writeJSON(w, http.StatusOK, DriverStatusUpdate{
+		DriverID:  driverID,
+		Status:    driverstatus.Name(change.GetStatus()),
+		Previous:  driverstatus.Name(change.GetPrevious()),
+		Reason:    change.GetReason(),
+		ChangedAt: change.GetChangedAt(),
+	})
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	driverpb "lastmile/gen/go/driver"
+	"lastmile/internal/pkg/driverstatus"
+)
+
+// statusDriverClient records the statuses the gateway sets, refusing every
+// change with err or changes to the refuse status. Other calls are not
+// expected.
+type statusDriverClient struct {
+	driverpb.DriverServiceClient
+	mu     sync.Mutex
+	sets   []string
+	err    error
+	refuse string
+}
+
+func (c *statusDriverClient) SetDriverStatus(_ context.Context, in *driverpb.SetDriverStatusRequest, _ ...grpc.CallOption) (*driverpb.SetDriverStatusResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	if name := driverstatus.Name(in.Status); name == c.refuse {
+		return nil, status.Errorf(codes.FailedPrecondition, "invalid status transition: to %s", name)
+	}
+	c.sets = append(c.sets, driverstatus.Name(in.Status))
+	return &driverpb.SetDriverStatusResponse{Change: &driverpb.DriverStatusEvent{DriverId: in.DriverId, Status: in.Status, Reason: in.Reason}}, nil
+}
+
+func (c *statusDriverClient) pushed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.sets...)
+}
+
+func TestDriverStatusFollowsSeatsAndTrips(t *testing.T) {
+	client := &statusDriverClient{}
+	gw := NewGateway(nil, client, nil, nil)
+	station, _ := gw.stationByID("station-ecity")
+	pickup := PickupPoint{ID: "pickup-status", StationID: station.ID, Latitude: station.Latitude, Longitude: station.Longitude}
+	gw.pickupPoints = append(gw.pickupPoints, pickup)
+	gw.drivers = []Driver{{ID: "driver-status", SeatsAvailable: 1, Route: Route{TargetStationIDs: []string{station.ID}}}}
+	gw.driverPlans["driver-status"] = &driverPlan{DriverID: "driver-status", PickupIDs: []string{pickup.ID}, TargetStations: []string{station.ID}, SeatsTotal: 1, SeatsAvailable: 1}
+	gw.driverStatuses["driver-status"] = driverStatusOffline
+	gw.riders = []Rider{{ID: "rider-status", StationID: station.ID, Status: "waiting", ArrivalTime: time.Now(), PickupPointID: pickup.ID, Pickup: &pickup}}
+
+	gw.mu.Lock()
+	reason := gw.driverIneligibleReasonLocked(&gw.drivers[0], station, &pickup, time.Now(), time.Hour, time.Now())
+	gw.mu.Unlock()
+	if reason != "driver not available" {
+		t.Fatalf("expected an offline driver to be skipped, got %q", reason)
+	}
+
+	if _, err := gw.startDriverTrip(startTripRequest{DriverID: "driver-status"}); err != nil {
+		t.Fatalf("start trip: %v", err)
+	}
+	if got := gw.driverStatuses["driver-status"]; got != driverStatusAvailable {
+		t.Fatalf("expected the driver available once started, got %q", got)
+	}
+
+	trip, err := gw.matchTrip("driver-status", station.ID, "rider-status", 0)
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	if got := gw.driverStatuses["driver-status"]; got != driverStatusFull {
+		t.Fatalf("expected the driver full after filling the last seat, got %q", got)
+	}
+	gw.mu.Lock()
+	reason = gw.driverIneligibleReasonLocked(&gw.drivers[0], station, &pickup, time.Now(), time.Hour, time.Now())
+	gw.mu.Unlock()
+	if reason != "no seats available" {
+		t.Fatalf("expected a full driver to be skipped, got %q", reason)
+	}
+
+	if _, err := gw.completeTrip(trip.ID); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if got := gw.driverStatuses["driver-status"]; got != driverStatusAvailable {
+		t.Fatalf("expected the driver available after the drop-off, got %q", got)
+	}
+
+	want := []string{driverStatusAvailable, driverStatusFull, driverStatusAvailable}
+	deadline := time.Now().Add(time.Second)
+	for len(client.pushed()) < len(want) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := client.pushed(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected the driver service to see %v in order, got %v", want, got)
+	}
+}
+
+func TestDriverWithRidersToCollectIsEnRoute(t *testing.T) {
+	gw, stationID := newPooledGateway(t)
+	gw.driverPlans["driver-pool"].Active = true
+	gw.driverStatuses["driver-pool"] = driverStatusAvailable
+
+	if _, err := gw.matchTrip("driver-pool", stationID, "rider-first", 0); err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	if got := gw.driverStatuses["driver-pool"]; got != driverStatusEnRoute {
+		t.Fatalf("expected the driver en route to the pickup, got %q", got)
+	}
+
+	pickup := &PickupPoint{ID: "pickup-first", StationID: stationID}
+	gw.trips[0].Status = "pending"
+	gw.handlePickupCheckpoint("driver-pool", pickup)
+	if got := gw.driverStatuses["driver-pool"]; got != driverStatusAvailable {
+		t.Fatalf("expected the driver available with seats left after the pickup, got %q", got)
+	}
+}
+
+func TestDriverStatusHandlerReportsRefusedChanges(t *testing.T) {
+	client := &statusDriverClient{err: status.Error(codes.FailedPrecondition, "invalid status transition: full to offline")}
+	gw := NewGateway(nil, client, nil, nil)
+
+	rec := httptest.NewRecorder()
+	gw.DriverStatusHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/status", strings.NewReader(`{"driverId":"driver-1","status":"offline"}`)))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	gw.DriverStatusHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/status", strings.NewReader(`{"driverId":"driver-1","status":"busy"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown status, got %d", rec.Code)
+	}
+
+	client.err = nil
+	rec = httptest.NewRecorder()
+	gw.DriverStatusHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/status", strings.NewReader(`{"driverId":"driver-1","status":"available","reason":"shift started"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := gw.driverStatuses["driver-1"]; got != driverStatusAvailable {
+		t.Fatalf("expected the gateway to record the new status, got %q", got)
+	}
+}
+
+func TestRefusedStatusPushRestoresPreviousStatus(t *testing.T) {
+	client := &statusDriverClient{refuse: driverStatusFull}
+	gw := NewGateway(nil, client, nil, nil)
+	gw.driverStatuses["driver-1"] = driverStatusOffline
+	gw.driverStatuses["driver-2"] = driverStatusAvailable
+
+	gw.mu.Lock()
+	gw.setDriverStatusLocked("driver-1", driverStatusFull, "test")
+	gw.setDriverStatusLocked("driver-2", driverStatusFull, "test")
+	gw.setDriverStatusLocked("driver-2", driverStatusAvailable, "test")
+	gw.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		gw.mu.Lock()
+		got := gw.driverStatuses["driver-1"]
+		queued := len(gw.statusQueue)
+		gw.mu.Unlock()
+		if got == driverStatusOffline && queued == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the refused change to be undone, driver-1 is %q with %d queued", got, queued)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for len(client.pushed()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	if got := gw.driverStatuses["driver-2"]; got != driverStatusAvailable {
+		t.Fatalf("expected driver-2 to keep its last status, got %q", got)
+	}
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"time"
+
+	"lastmile/internal/pkg/eta"
+)
+
+// driverStop is one stop still ahead of a driver: a pickup point or the
+// target station.
+type driverStop struct {
+	id    string
+	point eta.Point
+}
+
+// driverETAs is the result of re-estimating one driver: minutes to their next
+// stop and, per open trip, minutes to that trip's pickup or, once on board,
+// to its station.
+type driverETAs struct {
+	Next  int
+	Trips map[string]int
+}
+
+// etaPrediction is the pickup time a rider was given when matched, kept so
+// the estimate can be compared with the actual arrival.
+type etaPrediction struct {
+	driverID    string
+	pickupID    string
+	predictedAt time.Time
+	arrival     time.Time
+}
+
+func awaitingPickup(status string) bool {
+	return status == "awaiting_rider" || status == "pending" || status == "awaiting_pickup"
+}
+
+func hasPosition(driver *Driver) bool {
+	return driver != nil && (driver.Latitude != 0 || driver.Longitude != 0)
+}
+
+// remainingStopsLocked lists the driver's stops in the order they will be
+// made: the plan's pickups not yet reached, pickups of matched riders that are
+// not on the plan, then the target station.
+func (g *Gateway) remainingStopsLocked(driver *Driver) []driverStop {
+	var stops []driverStop
+	seen := make(map[string]bool)
+	add := func(id string, lat, lon float64) {
+		if id == "" || seen[id] || (lat == 0 && lon == 0) {
+			return
+		}
+		seen[id] = true
+		stops = append(stops, driverStop{id: id, point: eta.Point{Latitude: lat, Longitude: lon}})
+	}
+
+	stationID := ""
+	if len(driver.Route.TargetStationIDs) > 0 {
+		stationID = driver.Route.TargetStationIDs[0]
+	}
+	if plan, ok := g.driverPlans[driver.ID]; ok {
+		for i := plan.CurrentIndex; i < len(plan.PickupIDs); i++ {
+			if pickup, ok := g.pickupByID(plan.PickupIDs[i]); ok {
+				add(pickup.ID, pickup.Latitude, pickup.Longitude)
+			}
+		}
+		if len(plan.TargetStations) > 0 {
+			stationID = plan.TargetStations[0]
+		}
+	}
+	for i := range g.trips {
+		trip := &g.trips[i]
+		if trip.DriverID != driver.ID {
+			continue
+		}
+		if awaitingPickup(trip.Status) && trip.PickupPoint != nil {
+			add(trip.PickupPoint.ID, trip.PickupPoint.Latitude, trip.PickupPoint.Longitude)
+		}
+		if stationID == "" && !legFinished(trip.Status) {
+			stationID = trip.StationID
+		}
+	}
+	if station, ok := g.stationByID(stationID); ok {
+		add(station.ID, station.Latitude, station.Longitude)
+	}
+	return stops
+}
+
+// etaToLocked estimates how long the driver needs to reach the stop: along
+// their route when the stop is on it, directly otherwise. ok is false when
+// the driver's position is unknown.
+func (g *Gateway) etaToLocked(driver *Driver, id string, target eta.Point) (time.Duration, bool) {
+	if !hasPosition(driver) || (target.Latitude == 0 && target.Longitude == 0) {
+		return 0, false
+	}
+	from := eta.Point{Latitude: driver.Latitude, Longitude: driver.Longitude}
+	stops := g.remainingStopsLocked(driver)
+	for i, stop := range stops {
+		if stop.id == id {
+			return g.eta.Route(driver.ID, from, pointsOf(stops[:i+1]))[i], true
+		}
+	}
+	return g.eta.Route(driver.ID, from, []eta.Point{target})[0], true
+}
+
+// refreshETAsLocked re-estimates the driver's remaining stops and writes the
+// results to the driver and their open trips.
+func (g *Gateway) refreshETAsLocked(driver *Driver) driverETAs {
+	result := driverETAs{Trips: make(map[string]int)}
+	if !hasPosition(driver) {
+		return result
+	}
+	stops := g.remainingStopsLocked(driver)
+	from := eta.Point{Latitude: driver.Latitude, Longitude: driver.Longitude}
+	durations := g.eta.Route(driver.ID, from, pointsOf(stops))
+	byStop := make(map[string]time.Duration, len(stops))
+	for i, stop := range stops {
+		byStop[stop.id] = durations[i]
+	}
+	if len(durations) > 0 {
+		result.Next = eta.Minutes(durations[0])
+	}
+	driver.ETAMinutes = result.Next
+
+	for i := range g.trips {
+		trip := &g.trips[i]
+		if trip.DriverID != driver.ID || legFinished(trip.Status) {
+			continue
+		}
+		target := trip.StationID
+		if awaitingPickup(trip.Status) && trip.PickupPoint != nil {
+			target = trip.PickupPoint.ID
+		}
+		d, ok := byStop[target]
+		if !ok {
+			continue
+		}
+		trip.ETAMinutes = eta.Minutes(d)
+		result.Trips[trip.ID] = trip.ETAMinutes
+	}
+	return result
+}
+
+// projectedArrivalLocked estimates when the driver reaches the station.
+// Drivers without a usable position fall back to their advertised ETA.
+func (g *Gateway) projectedArrivalLocked(driver *Driver, station *Station, now time.Time) time.Time {
+	if driver == nil {
+		return now
+	}
+	if station != nil {
+		if d, ok := g.etaToLocked(driver, station.ID, eta.Point{Latitude: station.Latitude, Longitude: station.Longitude}); ok {
+			return now.Add(d)
+		}
+	}
+	return now.Add(time.Duration(driver.ETAMinutes) * time.Minute)
+}
+
+// predictPickupLocked sets the trip's ETA to its pickup and remembers the
+// promised arrival for measuring the estimator.
+func (g *Gateway) predictPickupLocked(driver *Driver, trip *Trip, now time.Time) {
+	if trip.PickupPoint == nil {
+		return
+	}
+	d, ok := g.etaToLocked(driver, trip.PickupPoint.ID, eta.Point{Latitude: trip.PickupPoint.Latitude, Longitude: trip.PickupPoint.Longitude})
+	if !ok {
+		return
+	}
+	trip.ETAMinutes = eta.Minutes(d)
+	g.etaPredictions[trip.ID] = etaPrediction{
+		driverID:    driver.ID,
+		pickupID:    trip.PickupPoint.ID,
+		predictedAt: now,
+		arrival:     now.Add(d),
+	}
+}
+
+// recordPickupArrivalLocked logs how far the pickup estimate given at match
+// time was from the actual arrival.
+func (g *Gateway) recordPickupArrivalLocked(tripID string, at time.Time) {
+	prediction, ok := g.etaPredictions[tripID]
+	if !ok {
+		return
+	}
+	delete(g.etaPredictions, tripID)
+	g.logger.Info("eta accuracy",
+		"tripId", tripID,
+		"driverId", prediction.driverID,
+		"pickupId", prediction.pickupID,
+		"predictedMinutes", prediction.arrival.Sub(prediction.predictedAt).Minutes(),
+		"actualMinutes", at.Sub(prediction.predictedAt).Minutes(),
+		"errorSeconds", at.Sub(prediction.arrival).Seconds())
+}
+
+func pointsOf(stops []driverStop) []eta.Point {
+	points := make([]eta.Point, len(stops))
+	for i, stop := range stops {
+		points[i] = stop.point
+	}
+	return points
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+// newETAGateway has one driver heading for two pickups at the same station,
+// with one rider waiting at the second pickup and one already on board.
+func newETAGateway(t *testing.T) (*Gateway, []PickupPoint) {
+	t.Helper()
+	gw := NewGateway(nil, nil, nil, nil)
+	station, _ := gw.stationByID("station-ecity")
+	pickups := []PickupPoint{
+		{ID: "pickup-eta-first", StationID: station.ID, Latitude: station.Latitude - 0.03, Longitude: station.Longitude},
+		{ID: "pickup-eta-second", StationID: station.ID, Latitude: station.Latitude - 0.015, Longitude: station.Longitude},
+	}
+	gw.pickupPoints = append(gw.pickupPoints, pickups...)
+	gw.drivers = []Driver{{ID: "driver-eta", Name: "Eta", SeatsAvailable: 2, Route: Route{TargetStationIDs: []string{station.ID}}}}
+	gw.driverPlans["driver-eta"] = &driverPlan{DriverID: "driver-eta", PickupIDs: []string{pickups[0].ID, pickups[1].ID}, TargetStations: []string{station.ID}, SeatsTotal: 3, SeatsAvailable: 2, Active: true}
+	second := pickups[1]
+	gw.trips = []Trip{
+		{ID: "trip-waiting", DriverID: "driver-eta", RiderID: "rider-waiting", StationID: station.ID, PickupPoint: &second, PickupPointID: second.ID, Status: "pending"},
+		{ID: "trip-onboard", DriverID: "driver-eta", RiderID: "rider-onboard", StationID: station.ID, Status: "in_progress"},
+	}
+	return gw, pickups
+}
+
+func TestDriverLocationRefreshesETAs(t *testing.T) {
+	gw, pickups := newETAGateway(t)
+	start := pickups[0]
+
+	gw.publishDriverLocation("driver-eta", start.Latitude-0.02, start.Longitude)
+
+	driver, _ := gw.findDriver("driver-eta", "")
+	waiting, onboard := gw.trips[0].ETAMinutes, gw.trips[1].ETAMinutes
+	if driver.ETAMinutes <= 0 {
+		t.Fatalf("expected an ETA to the next pickup, got %d", driver.ETAMinutes)
+	}
+	if waiting <= driver.ETAMinutes {
+		t.Fatalf("second pickup (%d min) should come after the first (%d min)", waiting, driver.ETAMinutes)
+	}
+	if onboard < waiting {
+		t.Fatalf("station (%d min) should come after the last pickup (%d min)", onboard, waiting)
+	}
+
+	resp, err := gw.driverRequests("driver-eta")
+	if err != nil {
+		t.Fatalf("driver requests: %v", err)
+	}
+	if resp.Driver.ETAMinutes != driver.ETAMinutes {
+		t.Fatalf("expected the driver summary to carry the ETA, got %d", resp.Driver.ETAMinutes)
+	}
+
+	// Faster driving shortens the estimate.
+	before := gw.trips[0].ETAMinutes
+	now := time.Now()
+	gw.eta.Observe("driver-eta", start.Latitude-0.02, start.Longitude, 20, now)
+	gw.publishDriverLocation("driver-eta", start.Latitude-0.02, start.Longitude)
+	if gw.trips[0].ETAMinutes >= before {
+		t.Fatalf("expected a faster driver to arrive sooner, got %d then %d", before, gw.trips[0].ETAMinutes)
+	}
+}
+
+func TestPickupArrivalSettlesPrediction(t *testing.T) {
+	gw, pickups := newETAGateway(t)
+	gw.drivers[0].Latitude, gw.drivers[0].Longitude = pickups[0].Latitude-0.02, pickups[0].Longitude
+
+	gw.mu.Lock()
+	gw.predictPickupLocked(&gw.drivers[0], &gw.trips[0], time.Now())
+	gw.mu.Unlock()
+	if gw.trips[0].ETAMinutes <= 0 {
+		t.Fatalf("expected a pickup ETA at match time, got %d", gw.trips[0].ETAMinutes)
+	}
+	if _, ok := gw.etaPredictions["trip-waiting"]; !ok {
+		t.Fatal("expected the promised pickup time to be recorded")
+	}
+
+	second := pickups[1]
+	gw.handlePickupCheckpoint("driver-eta", &second)
+	if _, ok := gw.etaPredictions["trip-waiting"]; ok {
+		t.Fatal("expected the prediction to be settled at pickup")
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// serveEvents relays events to the client until it goes away. WebSocket
+// clients get one JSON message per event; plain HTTP clients get a
+// Server-Sent Events stream with each event named by name.
+func serveEvents[T any](g *Gateway, w http.ResponseWriter, r *http.Request, events <-chan T, name func(T) string) {
+	if websocket.IsWebSocketUpgrade(r) {
+		serveEventsWebSocket(g, w, r, events)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case ev := <-events:
+			payload, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name(ev), payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func serveEventsWebSocket[T any](g *Gateway, w http.ResponseWriter, r *http.Request, events <-chan T) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		g.logger.Error("websocket upgrade failed", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// Reading is only needed to notice the client going away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case ev := <-events:
+			if err := conn.WriteJSON(ev); err != nil {
+				g.logger.Info("event stream write failed", "path", r.URL.Path, "err", err)
+				return
+			}
+		case <-closed:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -19,8 +19,12 @@ import (
 	gatewaypb "lastmile/gen/go/gateway"
 	locationpb "lastmile/gen/go/location"
 	userpb "lastmile/gen/go/user"
+	"lastmile/internal/pkg/eta"
+	"lastmile/internal/pkg/matchpolicy"
 
 	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Route mirrors the mobile Route type.
@@ -51,6 +55,16 @@ type Driver struct {
 	Route          Route   `json:"route"`
 	Latitude       float64 `json:"latitude,omitempty"`
 	Longitude      float64 `json:"longitude,omitempty"`
+	// Presence is online, idle, stale or offline; empty until the driver is
+	// first heard from.
+	Presence   string     `json:"presence,omitempty"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+	// Vehicle is the car the driver is driving, if they registered one.
+	Vehicle *Vehicle `json:"vehicle,omitempty"`
+	// Rating averages the stars riders gave the driver.
+	Rating *RatingSummary `json:"rating,omitempty"`
+	// positioned is set once the location service has reported a position.
+	positioned bool
 }
 
 // Rider mirrors the mobile Rider type.
@@ -63,6 +77,8 @@ type Rider struct {
 	Status        string       `json:"status"`
 	PickupPointID string       `json:"pickupPointId,omitempty"`
 	Pickup        *PickupPoint `json:"pickup,omitempty"`
+	// Rating averages the stars drivers gave the rider.
+	Rating *RatingSummary `json:"rating,omitempty"`
 }
 
 // Trip mirrors the mobile Trip type.
@@ -79,6 +95,8 @@ type Trip struct {
 	CreatedAt     time.Time    `json:"createdAt,omitempty"`
 	CompletedAt   time.Time    `json:"completedAt,omitempty"`
 	RoomID        string       `json:"roomId,omitempty"`
+	JourneyID     string       `json:"journeyId,omitempty"`
+	Vehicle       *TripVehicle `json:"vehicle,omitempty"`
 }
 
 type pendingTripContext struct {
@@ -100,6 +118,15 @@ type driverPlan struct {
 	StartedAt      time.Time
 	Simulated      bool
 	simCancel      context.CancelFunc
+	// replay is the trace replay driving the simulation, if any.
+	replay *traceReplay
+	// scheduleID is the route template that started the plan, if any. Such
+	// a plan expires at startBy unless the driver has moved by then.
+	scheduleID         string
+	startBy            time.Time
+	startLat, startLon float64
+	hasStart           bool
+	moved              bool
 }
 
 type Station struct {
@@ -117,6 +144,10 @@ type BackendMetrics struct {
 	SeatsOpen      int     `json:"seatsOpen"`
 	AvgWaitMinutes float64 `json:"avgWaitMinutes"`
 	Version        string  `json:"version"`
+	// MatchWindowMinutes is the arrival-time tolerance between drivers and riders.
+	MatchWindowMinutes float64 `json:"matchWindowMinutes"`
+	// RidersDeferred counts riders queued until a driver falls inside the window.
+	RidersDeferred int `json:"ridersDeferred"`
 }
 
 type BackendSnapshot struct {
@@ -130,8 +161,9 @@ type BackendSnapshot struct {
 }
 
 type matchRequest struct {
-	DriverID  string `json:"driverId"`
-	StationID string `json:"stationId"`
+	DriverID      string `json:"driverId"`
+	StationID     string `json:"stationId"`
+	WindowMinutes int    `json:"windowMinutes,omitempty"`
 }
 
 type driverSummary struct {
@@ -139,6 +171,7 @@ type driverSummary struct {
 	Name           string `json:"name"`
 	SeatsAvailable int    `json:"seatsAvailable"`
 	NextStop       string `json:"nextStop"`
+	ETAMinutes     int    `json:"etaMinutes"`
 }
 
 type riderRequestView struct {
@@ -150,6 +183,9 @@ type riderRequestView struct {
 	Pickup         *PickupPoint `json:"pickup,omitempty"`
 	Status         string       `json:"status"`
 	DistanceMeters float64      `json:"distanceMeters"`
+	// ETAMinutes is how long the driver needs to reach the rider's pickup.
+	ETAMinutes int  `json:"etaMinutes,omitempty"`
+	InWindow   bool `json:"inWindow"`
 }
 
 type driverRequestsResponse struct {
@@ -164,6 +200,11 @@ type driverAttempt struct {
 	DistanceMeters float64 `json:"distanceMeters"`
 	Accepted       bool    `json:"accepted"`
 	Reason         string  `json:"reason,omitempty"`
+	// Policy names the MatchPolicy that ranked this driver; Score and
+	// ScoreBreakdown explain the ranking.
+	Policy         string               `json:"policy,omitempty"`
+	Score          float64              `json:"score"`
+	ScoreBreakdown []matchpolicy.Factor `json:"scoreBreakdown,omitempty"`
 }
 
 type bookRideRequest struct {
@@ -184,7 +225,10 @@ type bookRideResponse struct {
 	Pickup               *PickupPoint    `json:"pickup"`
 	RequestedDestination string          `json:"requestedDestination"`
 	Attempts             []driverAttempt `json:"attempts"`
-	Trip                 *Trip           `json:"trip,omitempty"`
+	// Skipped lists drivers on the station's route that were not offered the
+	// ride, each with the reason.
+	Skipped []driverAttempt `json:"skipped,omitempty"`
+	Trip    *Trip           `json:"trip,omitempty"`
 }
 
 type driverRouteRequest struct {
@@ -194,6 +238,9 @@ type driverRouteRequest struct {
 	PickupPointIDs []string `json:"pickupPointIds"`
 	Seats          int      `json:"seats"`
 	Destination    string   `json:"destination"`
+	// VehicleID switches the driver to that linked vehicle; without it the
+	// vehicle they drove last is kept.
+	VehicleID string `json:"vehicleId,omitempty"`
 }
 
 type driverRouteResponse struct {
@@ -203,6 +250,7 @@ type driverRouteResponse struct {
 	SeatsAvailable int           `json:"seatsAvailable"`
 	TargetStations []string      `json:"targetStations"`
 	Destination    string        `json:"destination"`
+	Vehicle        *Vehicle      `json:"vehicle,omitempty"`
 }
 
 type startTripRequest struct {
@@ -220,6 +268,7 @@ type Gateway struct {
 	mu             sync.Mutex
 	logger         *slog.Logger
 	drivers        []Driver
+	driverIndex    driverIndex
 	riders         []Rider
 	trips          []Trip
 	stations       []Station
@@ -232,6 +281,35 @@ type Gateway struct {
 	store          *Persistence
 	pendingTrips   map[string]*pendingTripContext
 	pushTokens     map[string]string
+	matchWindow    time.Duration
+	deferredRiders map[string]*deferredRider
+	matchMode      string
+	batchWindow    time.Duration
+	batchTimers    map[string]*time.Timer
+	policies       *matchpolicy.Selector
+	feed           *matchFeed
+	journeys       map[string]*Journey
+	activeJourneys map[string]string
+	eta            *eta.Estimator
+	etaPredictions map[string]etaPrediction
+	presence       map[string]*driverPresence
+	presenceCfg    PresenceConfig
+	presenceFeed   *presenceFeed
+	simCfg         SimulatorConfig
+	simToken       string
+	driverStatuses map[string]string
+	statusQueue    []driverStatusChange
+	statusWake     chan struct{}
+	statusPushOnce sync.Once
+	routeTemplates map[string]*RouteTemplate
+	templateWrites []routeTemplateWrite
+	templateWake   chan struct{}
+	templateOnce   sync.Once
+	scheduleCfg    ScheduleConfig
+	driverVehicles map[string]*Vehicle
+	ratings        map[string]Rating
+	ratingTotals   map[string]*ratingTotal
+	ratingRule     matchpolicy.RatingRule
 }
 
 func NewGateway(logger *slog.Logger, driverClient driverpb.DriverServiceClient, locClient locationpb.LocationServiceClient, userClient userpb.UserServiceClient) *Gateway {
@@ -304,6 +382,27 @@ func NewGateway(logger *slog.Logger, driverClient driverpb.DriverServiceClient,
 		userClient:     userClient,
 		pendingTrips:   make(map[string]*pendingTripContext),
 		pushTokens:     make(map[string]string),
+		matchWindow:    defaultMatchWindow,
+		deferredRiders: make(map[string]*deferredRider),
+		matchMode:      matchModeGreedy,
+		batchWindow:    defaultBatchWindow,
+		batchTimers:    make(map[string]*time.Timer),
+		feed:           newMatchFeed(),
+		journeys:       make(map[string]*Journey),
+		activeJourneys: make(map[string]string),
+		eta:            eta.New(eta.Config{}),
+		etaPredictions: make(map[string]etaPrediction),
+		presence:       make(map[string]*driverPresence),
+		presenceCfg:    PresenceConfig{}.withDefaults(),
+		presenceFeed:   newPresenceFeed(),
+		simCfg:         SimulatorConfig{}.withDefaults(),
+		driverStatuses: make(map[string]string),
+		routeTemplates: make(map[string]*RouteTemplate),
+		scheduleCfg:    ScheduleConfig{}.withDefaults(),
+		driverVehicles: make(map[string]*Vehicle),
+		ratings:        make(map[string]Rating),
+		ratingTotals:   make(map[string]*ratingTotal),
+		ratingRule:     DefaultRatingRule,
 	}
 }
 
@@ -348,6 +447,18 @@ func (g *Gateway) snapshot() BackendSnapshot {
 				driverIDs = append(driverIDs, d.Id)
 			}
 
+			vehicleByID := make(map[string]*driverpb.Vehicle)
+			if hasActiveVehicle(resp.Drivers) {
+				vehResp, err := g.driverClient.ListVehicles(context.Background(), &driverpb.ListVehiclesRequest{})
+				if err != nil {
+					g.logger.Error("failed to list vehicles", "err", err)
+				} else {
+					for _, v := range vehResp.Vehicles {
+						vehicleByID[v.Id] = v
+					}
+				}
+			}
+
 			locMap := make(map[string]*locationpb.Location)
 			if g.locationClient != nil && len(driverIDs) > 0 {
 				locResp, err := g.locationClient.GetDriverLocations(context.Background(), &locationpb.GetDriverLocationsRequest{DriverIds: driverIDs})
@@ -388,8 +499,19 @@ func (g *Gateway) snapshot() BackendSnapshot {
 					route.Destination = "Unknown"
 				}
 
+				// The gateway's own changes may still be on their way to the
+				// driver service, so a status it already holds wins.
+				if _, ok := g.driverStatuses[d.Id]; !ok {
+					g.noteDriverStatusLocked(d.Id, d.Status)
+				}
+
+				if v, ok := vehicleByID[d.ActiveVehicleId]; ok {
+					g.driverVehicles[d.Id] = vehicleFromProto(v)
+				}
+
 				lat, lon := 0.0, 0.0
-				if loc, ok := locMap[d.Id]; ok {
+				loc, positioned := locMap[d.Id]
+				if positioned {
 					lat, lon = loc.Latitude, loc.Longitude
 				}
 
@@ -398,19 +520,31 @@ func (g *Gateway) snapshot() BackendSnapshot {
 					Name:           d.Name,
 					CarDetails:     d.CarDetails,
 					SeatsAvailable: seats,
-					ETAMinutes:     5,
-					Status:         "active",
+					Status:         g.driverStatuses[d.Id],
 					Route:          route,
 					Latitude:       lat,
 					Longitude:      lon,
+					Vehicle:        g.driverVehicles[d.Id],
+					positioned:     positioned,
 				})
+				g.refreshETAsLocked(&g.drivers[len(g.drivers)-1])
 			}
 		}
 	}
 
+	now := time.Now()
+	for i := range g.drivers {
+		g.applyPresenceLocked(&g.drivers[i], now)
+		g.drivers[i].Rating = g.ratingSummaryLocked(ratingRoleDriver, g.drivers[i].ID)
+	}
+	riders := append([]Rider{}, g.riders...)
+	for i := range riders {
+		riders[i].Rating = g.ratingSummaryLocked(ratingRoleRider, riders[i].ID)
+	}
+
 	return BackendSnapshot{
 		Drivers:       append([]Driver{}, g.drivers...),
-		Riders:        append([]Rider{}, g.riders...),
+		Riders:        riders,
 		Trips:         append([]Trip{}, g.trips...),
 		Stations:      append([]Station{}, g.stations...),
 		Metrics:       metrics,
@@ -482,19 +616,27 @@ func (g *Gateway) metrics() BackendMetrics {
 	}
 
 	return BackendMetrics{
-		PendingMatches: pending,
-		RidersWaiting:  waiting,
-		SeatsOpen:      seats,
-		AvgWaitMinutes: avgWait,
-		Version:        "go-gateway",
+		PendingMatches:     pending,
+		RidersWaiting:      waiting,
+		SeatsOpen:          seats,
+		AvgWaitMinutes:     avgWait,
+		Version:            "go-gateway",
+		MatchWindowMinutes: g.matchWindowLocked(0).Minutes(),
+		RidersDeferred:     len(g.deferredRiders),
 	}
 }
 
-func (g *Gateway) createTrip(driverID, stationID string) (Trip, error) {
-	return g.createTripForRider(driverID, stationID, "")
+// createTrip matches the driver with the best waiting rider at the station.
+// A zero window uses the gateway's configured match window.
+func (g *Gateway) createTrip(driverID, stationID string, window time.Duration) (Trip, error) {
+	return g.matchTrip(driverID, stationID, "", window)
 }
 
 func (g *Gateway) createTripForRider(driverID, stationID, riderID string) (Trip, error) {
+	return g.matchTrip(driverID, stationID, riderID, 0)
+}
+
+func (g *Gateway) matchTrip(driverID, stationID, riderID string, window time.Duration) (Trip, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
@@ -503,6 +645,10 @@ func (g *Gateway) createTripForRider(driverID, stationID, riderID string) (Trip,
 		return Trip{}, err
 	}
 
+	window = g.matchWindowLocked(window)
+	station, _ := g.stationByID(stationID)
+	driverArrival := g.projectedArrivalLocked(driver, station, time.Now())
+
 	var rider *Rider
 	if riderID != "" {
 		rider, err = g.findRiderByID(riderID)
@@ -512,8 +658,11 @@ func (g *Gateway) createTripForRider(driverID, stationID, riderID string) (Trip,
 		if rider.StationID != stationID {
 			return Trip{}, fmt.Errorf("rider '%s' is not waiting at station '%s'", rider.ID, stationID)
 		}
+		if !withinArrivalWindow(driverArrival, rider.ArrivalTime, window) {
+			return Trip{}, fmt.Errorf("rider '%s' arrives outside the %s match window", rider.ID, window)
+		}
 	} else {
-		rider, err = g.findRider(stationID, driver.Route.Destination)
+		rider, err = g.findRider(stationID, driver.Route.Destination, driverArrival, window)
 		if err != nil {
 			return Trip{}, err
 		}
@@ -556,23 +705,30 @@ func (g *Gateway) createTripForRider(driverID, stationID, riderID string) (Trip,
 		ETAMinutes:    driver.ETAMinutes,
 		Status:        "awaiting_rider",
 		CreatedAt:     now,
+		Vehicle:       g.tripVehicleLocked(driver.ID),
 	}
 
+	g.joinJourneyLocked(&trip)
 	g.trips = append([]Trip{trip}, g.trips...)
+	g.predictPickupLocked(driver, &g.trips[0], now)
+	trip = g.trips[0]
 	rider.Status = "matched"
+	delete(g.deferredRiders, rider.ID)
+	g.syncDriverStatusLocked(driver.ID, "rider matched")
 
 	g.logger.Info("match created",
 		"tripId", trip.ID,
 		"driverId", driverID,
 		"riderId", rider.ID,
 		"stationId", stationID)
+	g.publishMatchEvent(matchEventCreated, stationID, driver.ID, rider.ID, &trip, "")
 
 	return trip, nil
 }
 
 // TriggerMatchProto creates a trip and returns the proto representation.
-func (g *Gateway) TriggerMatchProto(driverID, stationID string) (*gatewaypb.GatewayTrip, error) {
-	trip, err := g.createTrip(driverID, stationID)
+func (g *Gateway) TriggerMatchProto(driverID, stationID string, windowMinutes int) (*gatewaypb.GatewayTrip, error) {
+	trip, err := g.createTrip(driverID, stationID, time.Duration(windowMinutes)*time.Minute)
 	if err != nil {
 		return nil, err
 	}
@@ -588,6 +744,7 @@ func (g *Gateway) driverRequests(driverID string) (driverRequestsResponse, error
 	if err != nil {
 		return driverRequestsResponse{}, err
 	}
+	g.refreshETAsLocked(driver)
 
 	stationName := ""
 	if len(driver.Route.TargetStationIDs) > 0 {
@@ -599,6 +756,8 @@ func (g *Gateway) driverRequests(driverID string) (driverRequestsResponse, error
 	}
 
 	requests := make([]riderRequestView, 0)
+	window := g.matchWindowLocked(0)
+	now := time.Now()
 
 	for i := range g.riders {
 		rider := g.riders[i]
@@ -619,6 +778,14 @@ func (g *Gateway) driverRequests(driverID string) (driverRequestsResponse, error
 			}
 		}
 		distance := driverDistanceToPickup(driver, pickup, station)
+		target, targetID := eta.Point{Latitude: station.Latitude, Longitude: station.Longitude}, station.ID
+		if pickup != nil {
+			target, targetID = eta.Point{Latitude: pickup.Latitude, Longitude: pickup.Longitude}, pickup.ID
+		}
+		etaMinutes := 0
+		if d, ok := g.etaToLocked(driver, targetID, target); ok {
+			etaMinutes = eta.Minutes(d)
+		}
 		requests = append(requests, riderRequestView{
 			ID:             rider.ID,
 			Name:           rider.Name,
@@ -628,6 +795,8 @@ func (g *Gateway) driverRequests(driverID string) (driverRequestsResponse, error
 			Pickup:         pickup,
 			Status:         rider.Status,
 			DistanceMeters: distance,
+			ETAMinutes:     etaMinutes,
+			InWindow:       withinArrivalWindow(g.projectedArrivalLocked(driver, station, now), rider.ArrivalTime, window),
 		})
 	}
 
@@ -641,6 +810,7 @@ func (g *Gateway) driverRequests(driverID string) (driverRequestsResponse, error
 			Name:           driver.Name,
 			SeatsAvailable: driver.SeatsAvailable,
 			NextStop:       stationName,
+			ETAMinutes:     driver.ETAMinutes,
 		},
 		Requests:    requests,
 		GeneratedAt: time.Now().UTC(),
@@ -673,9 +843,40 @@ func (g *Gateway) bookRide(payload bookRideRequest) (bookRideResponse, error) {
 		name = "Guest Rider"
 	}
 
+	// The nearby search runs before taking the lock for the booking itself;
+	// batch mode ranks drivers later, so it skips the search.
+	g.mu.Lock()
+	batch := g.batchModeLocked()
+	g.mu.Unlock()
+	var near *nearbyDrivers
+	if !batch {
+		near = g.findNearbyDrivers(station, pickup)
+	}
+
 	g.mu.Lock()
 	rider := g.upsertRiderLocked(payload.RiderID, name, station, requestedDestination, pickup)
-	candidates := g.driverCandidatesLocked(station, pickup)
+	if g.batchModeLocked() {
+		g.scheduleBatchLocked(station.ID)
+		riderSnapshot := copyRider(rider)
+		window := g.batchWindow
+		g.mu.Unlock()
+		if g.store != nil {
+			go g.store.RecordRiderRequest(riderSnapshot, copyPickupPoint(pickup), "waiting")
+		}
+		return bookRideResponse{
+			Status:               "batched",
+			Message:              fmt.Sprintf("Matching riders at %s in %s", station.Name, window),
+			Rider:                riderSnapshot,
+			Station:              *station,
+			Pickup:               copyPickupPoint(pickup),
+			RequestedDestination: requestedDestination,
+			Attempts:             []driverAttempt{},
+		}, nil
+	}
+	candidates, skipped := g.rankDriversLocked(station, pickup, rider.ArrivalTime, near)
+	if len(candidates) == 0 {
+		g.deferRiderLocked(rider.ID, station, pickup)
+	}
 	g.mu.Unlock()
 
 	riderSnapshot := copyRider(rider)
@@ -709,6 +910,12 @@ func (g *Gateway) bookRide(payload bookRideRequest) (bookRideResponse, error) {
 		go g.hub.EnqueueRiderRequest(riderSnapshot, stationCopy, pickupCopy, attempts)
 	}
 
+	if trip == nil && g.hub == nil && len(attempts) > 0 {
+		g.mu.Lock()
+		g.deferRiderLocked(riderSnapshot.ID, station, pickup)
+		g.mu.Unlock()
+	}
+
 	status := "queued"
 	message := fmt.Sprintf("No drivers available near %s yet", station.Name)
 	if trip != nil && acceptedIndex >= 0 {
@@ -726,6 +933,7 @@ func (g *Gateway) bookRide(payload bookRideRequest) (bookRideResponse, error) {
 		Pickup:               pickupCopy,
 		RequestedDestination: requestedDestination,
 		Attempts:             attempts,
+		Skipped:              skipped,
 		Trip:                 trip,
 	}, nil
 }
@@ -733,7 +941,11 @@ func (g *Gateway) bookRide(payload bookRideRequest) (bookRideResponse, error) {
 func toProtoDrivers(drivers []Driver) []*gatewaypb.GatewayDriver {
 	out := make([]*gatewaypb.GatewayDriver, 0, len(drivers))
 	for _, d := range drivers {
-		out = append(out, &gatewaypb.GatewayDriver{
+		lastSeen := ""
+		if d.LastSeenAt != nil {
+			lastSeen = d.LastSeenAt.UTC().Format(time.RFC3339)
+		}
+		pd := &gatewaypb.GatewayDriver{
 			Id:             d.ID,
 			Name:           d.Name,
 			CarDetails:     d.CarDetails,
@@ -746,9 +958,16 @@ func toProtoDrivers(drivers []Driver) []*gatewaypb.GatewayDriver {
 				Destination:      d.Route.Destination,
 				PickupPoints:     toProtoPickupPoints(d.Route.PickupPoints),
 			},
-			Latitude:  d.Latitude,
-			Longitude: d.Longitude,
-		})
+			Latitude:   d.Latitude,
+			Longitude:  d.Longitude,
+			Presence:   d.Presence,
+			LastSeenAt: lastSeen,
+		}
+		if d.Rating != nil {
+			pd.RatingAverage = d.Rating.Average
+			pd.RatingCount = int32(d.Rating.Count)
+		}
+		out = append(out, pd)
 	}
 	return out
 }
@@ -756,14 +975,19 @@ func toProtoDrivers(drivers []Driver) []*gatewaypb.GatewayDriver {
 func toProtoRiders(riders []Rider) []*gatewaypb.GatewayRider {
 	out := make([]*gatewaypb.GatewayRider, 0, len(riders))
 	for _, r := range riders {
-		out = append(out, &gatewaypb.GatewayRider{
+		pr := &gatewaypb.GatewayRider{
 			Id:          r.ID,
 			Name:        r.Name,
 			Destination: r.Destination,
 			ArrivalTime: r.ArrivalTime.Format(time.RFC3339),
 			StationId:   r.StationID,
 			Status:      r.Status,
-		})
+		}
+		if r.Rating != nil {
+			pr.RatingAverage = r.Rating.Average
+			pr.RatingCount = int32(r.Rating.Count)
+		}
+		out = append(out, pr)
 	}
 	return out
 }
@@ -777,7 +1001,7 @@ func toProtoTrips(trips []Trip) []*gatewaypb.GatewayTrip {
 }
 
 func toProtoTrip(t Trip) *gatewaypb.GatewayTrip {
-	return &gatewaypb.GatewayTrip{
+	out := &gatewaypb.GatewayTrip{
 		Id:            t.ID,
 		DriverId:      t.DriverID,
 		RiderId:       t.RiderID,
@@ -789,6 +1013,11 @@ func toProtoTrip(t Trip) *gatewaypb.GatewayTrip {
 		PickupPointId: t.PickupPointID,
 		Pickup:        toProtoPickupPoint(t.PickupPoint),
 	}
+	if t.Vehicle != nil {
+		out.VehiclePlate = t.Vehicle.Plate
+		out.VehicleColour = t.Vehicle.Colour
+	}
+	return out
 }
 
 func toProtoPickupPoints(points []PickupPoint) []*gatewaypb.GatewayPickupPoint {
@@ -830,11 +1059,13 @@ func toProtoStations(stations []Station) []*gatewaypb.GatewayStation {
 
 func toProtoMetrics(m BackendMetrics) *gatewaypb.BackendMetrics {
 	return &gatewaypb.BackendMetrics{
-		PendingMatches: int32(m.PendingMatches),
-		RidersWaiting:  int32(m.RidersWaiting),
-		SeatsOpen:      int32(m.SeatsOpen),
-		AvgWaitMinutes: m.AvgWaitMinutes,
-		Version:        "go-gateway",
+		PendingMatches:     int32(m.PendingMatches),
+		RidersWaiting:      int32(m.RidersWaiting),
+		SeatsOpen:          int32(m.SeatsOpen),
+		AvgWaitMinutes:     m.AvgWaitMinutes,
+		Version:            "go-gateway",
+		MatchWindowMinutes: m.MatchWindowMinutes,
+		RidersDeferred:     int32(m.RidersDeferred),
 	}
 }
 
@@ -857,17 +1088,27 @@ func (g *Gateway) findDriver(driverID, stationID string) (*Driver, error) {
 	return nil, fmt.Errorf("driver '%s' not found", driverID)
 }
 
-func (g *Gateway) findRider(stationID, destination string) (*Rider, error) {
+// findRider returns the first rider at the station heading to destination whose
+// arrival falls within window of the driver's projected arrival.
+func (g *Gateway) findRider(stationID, destination string, driverArrival time.Time, window time.Duration) (*Rider, error) {
+	outsideWindow := false
 	for i := range g.riders {
 		rider := &g.riders[i]
 		if rider.StationID != stationID || rider.Status == "picked_up" {
 			continue
 		}
 		if destination == "" || rider.Destination == "" || strings.EqualFold(rider.Destination, destination) {
+			if !withinArrivalWindow(driverArrival, rider.ArrivalTime, window) {
+				outsideWindow = true
+				continue
+			}
 			return rider, nil
 		}
 	}
 
+	if outsideWindow {
+		return nil, fmt.Errorf("no riders at station arrive within the %s match window", window)
+	}
 	return nil, errors.New("no riders available for station with matching destination")
 }
 
@@ -1047,40 +1288,147 @@ func (g *Gateway) upsertRiderLocked(riderID, name string, station *Station, dest
 	return &g.riders[0]
 }
 
-func (g *Gateway) driverCandidatesLocked(station *Station, pickup *PickupPoint) []driverAttempt {
+// driverCandidatesLocked lists drivers that can serve a rider at pickup, best
+// first according to the station's MatchPolicy. Drivers projected to reach the
+// station outside the match window of riderArrival are skipped so the rider
+// stays queued for them.
+func (g *Gateway) driverCandidatesLocked(station *Station, pickup *PickupPoint, riderArrival time.Time, near *nearbyDrivers) []driverAttempt {
+	offered, _ := g.rankDriversLocked(station, pickup, riderArrival, near)
+	return offered
+}
+
+// rankDriversLocked scores every eligible driver with the station's policy and
+// also reports the drivers routed to the station that were passed over. near
+// is the result of findNearbyDrivers, or nil to consider every driver.
+func (g *Gateway) rankDriversLocked(station *Station, pickup *PickupPoint, riderArrival time.Time, near *nearbyDrivers) (offered, skipped []driverAttempt) {
 	if pickup == nil {
-		return nil
+		return nil, nil
 	}
-	attempts := make([]driverAttempt, 0, len(g.drivers))
-	for i := range g.drivers {
-		driver := &g.drivers[i]
-		if station != nil && !routeContains(driver.Route.TargetStationIDs, station.ID) {
+	window := g.matchWindowLocked(0)
+	now := time.Now()
+	policy := g.ratingRule.Apply(g.policies.For(station.ID))
+
+	pool := g.candidateDriversLocked(near)
+	eligible := make([]*Driver, 0, len(pool))
+	candidates := make([]matchpolicy.Candidate, 0, len(pool))
+	for _, driver := range pool {
+		if reason := g.driverIneligibleReasonLocked(driver, station, pickup, riderArrival, window, now); reason != "" {
+			if routeContains(driver.Route.TargetStationIDs, station.ID) {
+				skipped = append(skipped, driverAttempt{
+					DriverID:       driver.ID,
+					DriverName:     driver.Name,
+					DistanceMeters: driverDistanceToPickup(driver, pickup, station),
+					Reason:         reason,
+					Policy:         policy.Name(),
+				})
+			}
 			continue
 		}
-		if plan, ok := g.driverPlans[driver.ID]; ok {
-			if !plan.Active {
-				continue
-			}
+		eligible = append(eligible, driver)
+		candidates = append(candidates, g.policyCandidateLocked(driver, station, pickup, riderArrival, now))
+	}
+
+	byID := make(map[string]*Driver, len(eligible))
+	for _, d := range eligible {
+		byID[d.ID] = d
+	}
+	ranked := matchpolicy.Rank(policy, candidates)
+	offered = make([]driverAttempt, 0, len(ranked))
+	for _, r := range ranked {
+		offered = append(offered, driverAttempt{
+			DriverID:       r.DriverID,
+			DriverName:     byID[r.DriverID].Name,
+			DistanceMeters: r.DistanceMeters,
+			Policy:         policy.Name(),
+			Score:          r.Score.Total,
+			ScoreBreakdown: r.Score.Factors,
+		})
+	}
+	return offered, skipped
+}
+
+// policyCandidateLocked gathers what a MatchPolicy needs to score driver for a
+// rider at pickup.
+func (g *Gateway) policyCandidateLocked(driver *Driver, station *Station, pickup *PickupPoint, riderArrival time.Time, now time.Time) matchpolicy.Candidate {
+	seatsTotal, seatsAvailable := driver.SeatsAvailable, driver.SeatsAvailable
+	if plan, ok := g.driverPlans[driver.ID]; ok {
+		seatsTotal, seatsAvailable = plan.SeatsTotal, plan.SeatsAvailable
+	}
+	served := 0
+	for _, trip := range g.trips {
+		if trip.DriverID == driver.ID {
+			served++
+		}
+	}
+	var wait time.Duration
+	if !riderArrival.IsZero() {
+		wait = now.Sub(riderArrival)
+	}
+	var rating float64
+	var ratings int
+	if summary := g.ratingSummaryLocked(ratingRoleDriver, driver.ID); summary != nil {
+		rating, ratings = summary.Average, summary.Count
+	}
+	return matchpolicy.Candidate{
+		DriverID:       driver.ID,
+		DistanceMeters: driverDistanceToPickup(driver, pickup, station),
+		ArrivalGap:     time.Duration(arrivalGapMinutes(g.projectedArrivalLocked(driver, station, now), riderArrival) * float64(time.Minute)),
+		RiderWait:      wait,
+		SeatsTotal:     seatsTotal,
+		SeatsAvailable: seatsAvailable,
+		TripsServed:    served,
+		Rating:         rating,
+		Ratings:        ratings,
+	}
+}
+
+// driverCanServeLocked reports whether driver can take a rider waiting at
+// pickup: routed to the station, neither offline nor full, on a plan that has
+// not yet passed the pickup, with a free seat, and arriving within window of
+// the rider.
+func (g *Gateway) driverCanServeLocked(driver *Driver, station *Station, pickup *PickupPoint, riderArrival time.Time, window time.Duration, now time.Time) bool {
+	return g.driverIneligibleReasonLocked(driver, station, pickup, riderArrival, window, now) == ""
+}
+
+// driverIneligibleReasonLocked explains why driver cannot serve the rider, or
+// returns "" when it can.
+func (g *Gateway) driverIneligibleReasonLocked(driver *Driver, station *Station, pickup *PickupPoint, riderArrival time.Time, window time.Duration, now time.Time) string {
+	if station != nil && !routeContains(driver.Route.TargetStationIDs, station.ID) {
+		return "not routed to station"
+	}
+	// Drivers not heard from since the gateway started are not penalised.
+	if state, ok := g.driverPresenceLocked(driver.ID, now); ok && (state == presenceStale || state == presenceOffline) {
+		return "driver " + state
+	}
+	switch g.driverStatuses[driver.ID] {
+	case driverStatusOffline:
+		return "driver not available"
+	case driverStatusFull:
+		return "no seats available"
+	}
+	if g.driverRatedOutLocked(driver.ID) {
+		return "rating too low"
+	}
+	if plan, ok := g.driverPlans[driver.ID]; ok {
+		if pickup != nil {
 			idx := indexOf(plan.PickupIDs, pickup.ID)
-			if idx == -1 || idx < plan.CurrentIndex {
-				continue
+			if idx == -1 {
+				return "pickup not on route"
 			}
-			if plan.SeatsAvailable <= 0 {
-				continue
+			if idx < plan.CurrentIndex {
+				return "already passed pickup"
 			}
-		} else if driver.SeatsAvailable <= 0 {
-			continue
 		}
-		attempts = append(attempts, driverAttempt{
-			DriverID:       driver.ID,
-			DriverName:     driver.Name,
-			DistanceMeters: driverDistanceToPickup(driver, pickup, station),
-		})
+		if plan.SeatsAvailable <= 0 {
+			return "no seats available"
+		}
+	} else if driver.SeatsAvailable <= 0 {
+		return "no seats available"
 	}
-	sort.Slice(attempts, func(i, j int) bool {
-		return attempts[i].DistanceMeters < attempts[j].DistanceMeters
-	})
-	return attempts
+	if !withinArrivalWindow(g.projectedArrivalLocked(driver, station, now), riderArrival, window) {
+		return "outside match window"
+	}
+	return ""
 }
 
 func indexOf(items []string, target string) int {
@@ -1160,7 +1508,7 @@ func (g *Gateway) MatchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	trip, err := g.createTrip(payload.DriverID, payload.StationID)
+	trip, err := g.createTrip(payload.DriverID, payload.StationID, time.Duration(payload.WindowMinutes)*time.Minute)
 	if err != nil {
 		g.logger.Warn("match creation failed", "err", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -1260,6 +1608,9 @@ func (g *Gateway) completeTrip(tripID string) (*Trip, error) {
 
 	completed.Status = "completed"
 	completed.CompletedAt = time.Now().UTC()
+	completed.ETAMinutes = 0
+	delete(g.etaPredictions, tripID)
+	g.updateLegLocked(tripID, "completed")
 
 	if driver, err := g.findDriver(completed.DriverID, ""); err == nil {
 		driver.SeatsAvailable++
@@ -1270,6 +1621,7 @@ func (g *Gateway) completeTrip(tripID string) (*Trip, error) {
 			plan.SeatsAvailable++
 		}
 	}
+	g.syncDriverStatusLocked(completed.DriverID, "trip completed")
 
 	if g.store != nil {
 		g.store.RecordTrip(*completed)
@@ -1278,6 +1630,7 @@ func (g *Gateway) completeTrip(tripID string) (*Trip, error) {
 		})
 		g.store.UpdateRiderRequestStatus(completed.RiderID, "completed", completed.DriverID, tripID)
 	}
+	go g.sendRatingPrompts(g.ratingPromptsLocked(*completed))
 	return completed, nil
 }
 
@@ -1419,10 +1772,14 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
+// LocationStreamHandler relays driver positions over a WebSocket. Filter with
+// driverId, driverIds=a,b, stationIds=a,b and bbox=minLon,minLat,maxLon,maxLat;
+// a position is sent when it matches any of them, and without filters every
+// driver is included.
 func (g *Gateway) LocationStreamHandler(w http.ResponseWriter, r *http.Request) {
-	driverID := r.URL.Query().Get("driverId")
-	if driverID == "" {
-		http.Error(w, "driverId required", http.StatusBadRequest)
+	req, err := locationSubscription(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -1438,7 +1795,7 @@ func (g *Gateway) LocationStreamHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	stream, err := g.locationClient.SubscribeLocationUpdates(r.Context(), &locationpb.SubscribeLocationRequest{DriverId: driverID})
+	stream, err := g.locationClient.SubscribeLocationUpdates(r.Context(), req)
 	if err != nil {
 		g.logger.Error("failed to subscribe to location updates", "err", err)
 		return
@@ -1448,16 +1805,46 @@ func (g *Gateway) LocationStreamHandler(w http.ResponseWriter, r *http.Request)
 		update, err := stream.Recv()
 		if err != nil {
 			g.logger.Info("location stream ended", "err", err)
+			if status.Code(err) == codes.ResourceExhausted {
+				// Tell the client it fell behind so it reconnects rather than
+				// showing stale positions.
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, status.Convert(err).Message()))
+			}
 			break
 		}
 
-		if err := conn.WriteJSON(update); err != nil {
+		if err := conn.WriteJSON(toLocationMessage(update)); err != nil {
 			g.logger.Info("websocket write failed", "err", err)
 			break
 		}
 	}
 }
 
+// locationMessage is a driver position as sent over /location/stream.
+type locationMessage struct {
+	DriverID       string  `json:"driverId"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	RecordedAt     string  `json:"recordedAt,omitempty"`
+	SpeedMps       float64 `json:"speedMps"`
+	HeadingDegrees float64 `json:"headingDegrees"`
+	AccuracyMeters float64 `json:"accuracyMeters,omitempty"`
+	LeftArea       bool    `json:"leftArea,omitempty"`
+}
+
+func toLocationMessage(u *locationpb.LocationUpdate) locationMessage {
+	return locationMessage{
+		DriverID:       u.GetDriverId(),
+		Latitude:       u.GetLatitude(),
+		Longitude:      u.GetLongitude(),
+		RecordedAt:     u.GetRecordedAt(),
+		SpeedMps:       u.GetSpeedMps(),
+		HeadingDegrees: u.GetHeadingDegrees(),
+		AccuracyMeters: u.GetAccuracyMeters(),
+		LeftArea:       u.GetLeftArea(),
+	}
+}
+
 func (g *Gateway) GetUserHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -1566,6 +1953,7 @@ func (g *Gateway) finalizePendingTrip(tripID string) (*Trip, error) {
 		if g.trips[i].ID == tripID {
 			g.trips[i].Status = "pending"
 			tripPtr = &g.trips[i]
+			g.updateLegLocked(tripID, "pending")
 			break
 		}
 	}
@@ -1598,6 +1986,8 @@ func (g *Gateway) cancelPendingTrip(tripID, reason string) error {
 			}
 		}
 	}
+	g.dropLegLocked(tripID)
+	delete(g.etaPredictions, tripID)
 	for i := range g.trips {
 		if g.trips[i].ID == tripID {
 			g.trips = append(g.trips[:i], g.trips[i+1:]...)
@@ -1612,8 +2002,10 @@ func (g *Gateway) cancelPendingTrip(tripID, reason string) error {
 			plan.SeatsAvailable++
 		}
 	}
+	g.syncDriverStatusLocked(ctx.Trip.DriverID, "rider declined")
 	g.mu.Unlock()
 
+	g.publishMatchEvent(matchEventRejected, ctx.Trip.StationID, ctx.Trip.DriverID, ctx.Trip.RiderID, &ctx.Trip, reason)
 	if g.store != nil {
 		g.store.RecordTripEvent(tripID, "rider_declined", map[string]any{"reason": reason})
 	}
@@ -1646,8 +2038,16 @@ func (g *Gateway) CancelTrip(tripID, reason string) error {
 }
 
 func (g *Gateway) publishDriverLocation(driverID string, lat, lon float64) {
+	g.mu.Lock()
+	g.noteJourneyPositionLocked(driverID, lat, lon)
+	var etas driverETAs
+	if driver, err := g.findDriver(driverID, ""); err == nil {
+		driver.Latitude, driver.Longitude = lat, lon
+		etas = g.refreshETAsLocked(driver)
+	}
+	g.mu.Unlock()
 	if g.hub != nil {
-		g.hub.BroadcastLocation(driverID, lat, lon)
+		g.hub.BroadcastLocation(driverID, lat, lon, etas)
 	}
 }
 
@@ -1656,26 +2056,33 @@ func (g *Gateway) handlePickupCheckpoint(driverID string, pickup *PickupPoint) {
 		return
 	}
 
-	if g.hub != nil {
-		g.hub.PickupArrived(driverID, pickup.ID)
-	}
-
-	var tripCopy *Trip
+	// Several pooled riders can share a pickup point; every one of their legs
+	// starts here.
+	var picked []Trip
 	g.mu.Lock()
 	for i := range g.trips {
 		trip := &g.trips[i]
 		if trip.DriverID == driverID && trip.PickupPointID == pickup.ID && trip.Status == "pending" {
 			trip.Status = "in_progress"
 			trip.CreatedAt = time.Now().UTC()
-			tripCopy = copyTrip(trip)
-			break
+			g.recordPickupArrivalLocked(trip.ID, trip.CreatedAt)
+			g.updateLegLocked(trip.ID, "in_progress")
+			picked = append(picked, *copyTrip(trip))
 		}
 	}
+	if len(picked) > 0 {
+		g.syncDriverStatusLocked(driverID, "riders picked up")
+	}
 	g.mu.Unlock()
 
-	if tripCopy != nil && g.store != nil {
-		g.store.RecordTrip(*tripCopy)
-		g.store.RecordTripEvent(tripCopy.ID, "pickup_reached", map[string]any{"pickupId": pickup.ID})
+	if g.hub != nil {
+		g.hub.PickupArrived(driverID, pickup.ID)
+	}
+	if g.store != nil {
+		for _, trip := range picked {
+			g.store.RecordTrip(trip)
+			g.store.RecordTripEvent(trip.ID, "pickup_reached", map[string]any{"pickupId": pickup.ID})
+		}
 	}
 }
 
@@ -1694,6 +2101,7 @@ func (g *Gateway) maybeCompleteTrips(driverID string, lat, lon float64) {
 		if haversineMeters(lat, lon, station.Latitude, station.Longitude) <= 150 {
 			trip.Status = "completed"
 			trip.CompletedAt = time.Now().UTC()
+			g.updateLegLocked(trip.ID, "completed")
 			if driver, err := g.findDriver(driverID, ""); err == nil {
 				driver.SeatsAvailable++
 			}
@@ -1703,7 +2111,17 @@ func (g *Gateway) maybeCompleteTrips(driverID string, lat, lon float64) {
 			completed = append(completed, *trip)
 		}
 	}
+	var prompts []RatingPrompt
+	for _, trip := range completed {
+		prompts = append(prompts, g.ratingPromptsLocked(trip)...)
+	}
+	if len(completed) > 0 {
+		g.syncDriverStatusLocked(driverID, "riders dropped off")
+	}
 	g.mu.Unlock()
+	if len(prompts) > 0 {
+		go g.sendRatingPrompts(prompts)
+	}
 
 	for _, trip := range completed {
 		if g.store != nil {
@@ -1872,14 +2290,20 @@ func (g *Gateway) UpdateLocationHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req struct {
-		DriverID  string  `json:"driverId"`
-		Latitude  float64 `json:"latitude"`
-		Longitude float64 `json:"longitude"`
+		DriverID       string  `json:"driverId"`
+		Latitude       float64 `json:"latitude"`
+		Longitude      float64 `json:"longitude"`
+		RecordedAt     string  `json:"recordedAt"`
+		SpeedMps       float64 `json:"speedMps"`
+		HeadingDegrees float64 `json:"headingDegrees"`
+		AccuracyMeters float64 `json:"accuracyMeters"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid payload", http.StatusBadRequest)
 		return
 	}
+	// Any sample is a heartbeat, even one the location service drops.
+	g.touchPresence(req.DriverID, nil)
 
 	stream, err := g.locationClient.UpdateLocation(r.Context())
 	if err != nil {
@@ -1890,9 +2314,13 @@ func (g *Gateway) UpdateLocationHandler(w http.ResponseWriter, r *http.Request)
 
 	err = stream.Send(&locationpb.UpdateLocationRequest{
 		Location: &locationpb.Location{
-			DriverId:  req.DriverID,
-			Latitude:  req.Latitude,
-			Longitude: req.Longitude,
+			DriverId:       req.DriverID,
+			Latitude:       req.Latitude,
+			Longitude:      req.Longitude,
+			RecordedAt:     req.RecordedAt,
+			SpeedMps:       req.SpeedMps,
+			HeadingDegrees: req.HeadingDegrees,
+			AccuracyMeters: req.AccuracyMeters,
 		},
 	})
 	if err != nil {
@@ -1901,30 +2329,51 @@ func (g *Gateway) UpdateLocationHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	passed := g.recordDriverLocationProgress(req.DriverID, req.Latitude, req.Longitude)
-
 	// Close and receive response
-	_, err = stream.CloseAndRecv()
+	resp, err := stream.CloseAndRecv()
 	if err != nil {
 		g.logger.Error("failed to close location update stream", "err", err)
 		// Don't fail the request if we sent the update successfully
+	} else if resp.Dropped > 0 {
+		// A late or inaccurate sample must not move the driver backwards on
+		// their route or on anyone's map.
+		g.logger.Info("location sample dropped by location service", "driverId", req.DriverID, "recordedAt", req.RecordedAt)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	} else if resp.Quarantined > 0 {
+		g.logger.Warn("location sample quarantined by location service", "driverId", req.DriverID, "lat", req.Latitude, "long", req.Longitude)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	} else if resp.Location != nil {
+		// Progress, maps and ETAs follow the smoothed position, not the raw fix.
+		req.Latitude, req.Longitude = resp.Location.Latitude, resp.Location.Longitude
 	}
 
+	recordedAt, err := time.Parse(time.RFC3339Nano, req.RecordedAt)
+	if err != nil {
+		recordedAt = time.Now()
+	}
+	g.eta.Observe(req.DriverID, req.Latitude, req.Longitude, req.SpeedMps, recordedAt)
+
+	passed := g.recordDriverLocationProgress(req.DriverID, req.Latitude, req.Longitude)
 	g.publishDriverLocation(req.DriverID, req.Latitude, req.Longitude)
 	if passed != nil {
 		g.handlePickupCheckpoint(req.DriverID, passed)
 	}
+	g.reevaluateDeferredRiders(req.DriverID, req.Latitude, req.Longitude)
 	w.WriteHeader(http.StatusOK)
 }
 
+// TripPickupHandler marks one rider's leg as picked up. The leg is addressed
+// by tripId, or by journeyId and riderId.
 func (g *Gateway) TripPickupHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	tripID := r.URL.Query().Get("tripId")
+	tripID := g.legTripIDFromRequest(r)
 	if tripID == "" {
-		http.Error(w, "tripId required", http.StatusBadRequest)
+		http.Error(w, "tripId or journeyId and riderId required", http.StatusBadRequest)
 		return
 	}
 
@@ -1936,74 +2385,79 @@ func (g *Gateway) TripPickupHandler(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 	}
-	g.mu.Unlock()
-
 	if targetTrip == nil {
+		g.mu.Unlock()
 		http.Error(w, "trip not found", http.StatusNotFound)
 		return
 	}
+	awaiting := targetTrip.Status == "pending" || targetTrip.Status == "awaiting_pickup"
+	targetTrip.Status = "in_progress"
+	targetTrip.CreatedAt = time.Now().UTC()
+	g.recordPickupArrivalLocked(tripID, targetTrip.CreatedAt)
+	journey := copyJourney(g.updateLegLocked(tripID, "in_progress"))
+	driverID := targetTrip.DriverID
+	destination := targetTrip.Destination
+	pickup, hasPickup := g.pickupByID(targetTrip.PickupPointID)
+	plan, hasPlan := g.driverPlans[driverID]
+	replaying := hasPlan && plan.replay != nil && plan.replay.active()
+	g.syncDriverStatusLocked(driverID, "rider picked up")
+	g.mu.Unlock()
 
-	if targetTrip.Status == "pending" || targetTrip.Status == "awaiting_pickup" {
-		// Advance to in_progress
-		if g.hub != nil {
-			g.hub.PickupArrived(targetTrip.DriverID, targetTrip.PickupPointID)
-		}
+	if g.hub != nil {
+		g.hub.LegPickedUp(tripID)
+	}
 
+	if awaiting && hasPickup {
 		// Warp Driver to Pickup Location
-		g.mu.Lock()
-		pickup, ok := g.pickupByID(targetTrip.PickupPointID)
-		driverID := targetTrip.DriverID
-		plan, hasPlan := g.driverPlans[driverID]
-		g.mu.Unlock()
-
-		if ok {
-			g.publishDriverLocation(driverID, pickup.Latitude, pickup.Longitude)
-
-			// Resume Simulation from Pickup -> Dropoff (Destination)
-			if hasPlan && plan.Simulated {
-				g.mu.Lock()
-				// Cancel existing sim
-				if plan.simCancel != nil {
-					plan.simCancel()
+		g.publishDriverLocation(driverID, pickup.Latitude, pickup.Longitude)
+
+		// Resume the simulation towards the riders still waiting, then on
+		// to the destination. A trace replay keeps following its recording.
+		if hasPlan && plan.Simulated && !replaying {
+			var waypoints []PickupPoint
+			if journey != nil {
+				for _, leg := range journey.Legs {
+					if leg.Status == "pending" && leg.Pickup != nil {
+						// Only stop at the pickup; the station comes last.
+						wp := *leg.Pickup
+						wp.StationID = ""
+						waypoints = append(waypoints, wp)
+					}
 				}
+			}
+			if destCoords := getDestinationCoords(destination); destCoords != nil {
+				waypoints = append(waypoints, *destCoords)
+			}
 
-				// Setup new sim to Destination
-				destCoords := getDestinationCoords(targetTrip.Destination)
-				if destCoords != nil {
-					simCtx, cancel := context.WithCancel(context.Background())
-					plan.simCancel = cancel
-					// We simulate TO destination.
-					// We treat destination as a single waypoint.
-					waypoints := []PickupPoint{*destCoords}
-					g.mu.Unlock()
-					go g.runSimulatedTrip(simCtx, driverID, waypoints)
-				} else {
-					g.mu.Unlock()
-					// Fallback: If no coords, maybe just stop sim?
-					// Or try to simulate to target stations?
-					// For now, stop sim is safer than random movement.
-				}
+			g.mu.Lock()
+			if plan.simCancel != nil {
+				plan.simCancel()
+				plan.simCancel = nil
 			}
+			// Without anywhere known to head for, stopping the simulation is
+			// safer than random movement.
+			if len(waypoints) > 0 {
+				simCtx, cancel := context.WithCancel(context.Background())
+				plan.simCancel = cancel
+				go g.runSimulatedTrip(simCtx, driverID, waypoints)
+			}
+			g.mu.Unlock()
 		}
 	}
 
-	// Also update local state
-	g.mu.Lock()
-	targetTrip.Status = "in_progress"
-	targetTrip.CreatedAt = time.Now().UTC()
-	g.mu.Unlock()
-
-	writeJSON(w, http.StatusOK, map[string]string{"status": "picked_up"})
+	writeJSON(w, http.StatusOK, map[string]string{"status": "picked_up", "tripId": tripID})
 }
 
+// TripDropoffHandler completes one rider's leg; the journey completes once
+// its last leg is dropped off.
 func (g *Gateway) TripDropoffHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	tripID := r.URL.Query().Get("tripId")
+	tripID := g.legTripIDFromRequest(r)
 	if tripID == "" {
-		http.Error(w, "tripId required", http.StatusBadRequest)
+		http.Error(w, "tripId or journeyId and riderId required", http.StatusBadRequest)
 		return
 	}
 
@@ -2020,16 +2474,39 @@ func (g *Gateway) TripDropoffHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, completed)
 }
 
+// SimulateTripHandler drives the trip's driver through the simulator. A POST
+// with trace=gpx|csv (trace in the body) or trace=history replays a recorded
+// drive at speed times real time; action=pause|resume|seek&offset= controls
+// the replay, and a GET reports its progress. A plain POST moves the driver
+// between the remaining pickups.
 func (g *Gateway) SimulateTripHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	tripID := r.URL.Query().Get("tripId")
+	query := r.URL.Query()
+	tripID := query.Get("tripId")
 	if tripID == "" {
 		http.Error(w, "tripId required", http.StatusBadRequest)
 		return
 	}
+	if r.Method == http.MethodGet {
+		rp := g.tripReplay(tripID)
+		if rp == nil {
+			http.Error(w, "no trace replay for trip", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, rp.status())
+		return
+	}
+	if action := query.Get("action"); action != "" {
+		g.controlTraceReplay(w, r, tripID, action)
+		return
+	}
+	if source := query.Get("trace"); source != "" {
+		g.startTraceReplay(w, r, tripID, source)
+		return
+	}
 
 	g.mu.Lock()
 	var targetTrip *Trip
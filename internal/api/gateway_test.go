@@ -240,3 +240,174 @@ func TestDriverAcceptHandler(t *testing.T) {
 		t.Fatalf("expected pickup %s, got %s", pickup.ID, trip.PickupPointID)
 	}
 }
+
+func TestBookRideDefersRiderOutsideMatchWindow(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	gw.SetMatchWindow(8 * time.Minute)
+	pickup := gw.pickupPoints[0]
+	station, _ := gw.stationByID(pickup.StationID)
+	gw.drivers = []Driver{
+		{
+			ID:             "driver-far",
+			Name:           "Ravi",
+			SeatsAvailable: 2,
+			Route: Route{
+				ID:               "route-far",
+				TargetStationIDs: []string{pickup.StationID},
+				Destination:      pickup.Name,
+			},
+			// Roughly 15 km south of the station: well outside an 8 minute window.
+			Latitude:  station.Latitude - 0.135,
+			Longitude: station.Longitude,
+		},
+	}
+	gw.riders = nil
+
+	result, err := gw.bookRide(bookRideRequest{Command: "book", Name: "Ananya", PickupPointID: pickup.ID})
+	if err != nil {
+		t.Fatalf("book ride failed: %v", err)
+	}
+	if result.Status != "queued" || len(result.Attempts) != 0 {
+		t.Fatalf("expected rider to stay queued, got %s (attempts=%+v)", result.Status, result.Attempts)
+	}
+	if metrics := gw.snapshot().Metrics; metrics.RidersDeferred != 1 || metrics.MatchWindowMinutes != 8 {
+		t.Fatalf("unexpected window metrics: %+v", metrics)
+	}
+
+	// The driver approaches the station; the deferred rider is matched.
+	gw.reevaluateDeferredRiders("driver-far", station.Latitude, station.Longitude)
+
+	rider, err := gw.findRiderByID(result.Rider.ID)
+	if err != nil {
+		t.Fatalf("rider lookup failed: %v", err)
+	}
+	if rider.Status != "matched" {
+		t.Fatalf("expected deferred rider to be matched once in window, got %s", rider.Status)
+	}
+	if metrics := gw.snapshot().Metrics; metrics.RidersDeferred != 0 {
+		t.Fatalf("expected no deferred riders, got %d", metrics.RidersDeferred)
+	}
+}
+
+func TestWithinArrivalWindow(t *testing.T) {
+	now := time.Now()
+	if !withinArrivalWindow(now, now.Add(4*time.Minute), 5*time.Minute) {
+		t.Fatalf("expected rider 4 minutes out to be within a 5 minute window")
+	}
+	if withinArrivalWindow(now, now.Add(40*time.Minute), 5*time.Minute) {
+		t.Fatalf("expected rider 40 minutes out to be outside a 5 minute window")
+	}
+	if !withinArrivalWindow(now, time.Time{}, time.Minute) {
+		t.Fatalf("riders without an arrival time should always match")
+	}
+}
+
+func TestRunStationBatchAssignsRidersTogether(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	if err := gw.SetMatchMode("batch", time.Hour); err != nil {
+		t.Fatalf("set match mode: %v", err)
+	}
+	gw.SetMatchWindow(15 * time.Minute)
+	station, _ := gw.stationByID("station-ecity")
+	now := time.Now()
+	gw.drivers = []Driver{
+		{ID: "driver-near", Name: "Near", SeatsAvailable: 1, Route: Route{TargetStationIDs: []string{station.ID}}, Latitude: station.Latitude, Longitude: station.Longitude},
+		// About 5 km out, so roughly 12 minutes away.
+		{ID: "driver-far", Name: "Far", SeatsAvailable: 1, Route: Route{TargetStationIDs: []string{station.ID}}, Latitude: station.Latitude - 0.045, Longitude: station.Longitude},
+	}
+	gw.riders = []Rider{
+		{ID: "rider-later", Name: "Later", StationID: station.ID, Status: "waiting", ArrivalTime: now.Add(12 * time.Minute)},
+		{ID: "rider-now", Name: "Now", StationID: station.ID, Status: "waiting", ArrivalTime: now.Add(time.Minute)},
+		{ID: "rider-elsewhere", Name: "Elsewhere", StationID: "station-hsr", Status: "waiting", ArrivalTime: now},
+	}
+
+	booked, err := gw.bookRide(bookRideRequest{Command: "book", Name: "Queued", PickupPointID: "pickup-" + station.ID})
+	if err != nil {
+		t.Fatalf("book ride: %v", err)
+	}
+	if booked.Status != "batched" {
+		t.Fatalf("expected batched status, got %s", booked.Status)
+	}
+
+	result, err := gw.runStationBatch(station.ID)
+	if err != nil {
+		t.Fatalf("batch failed: %v", err)
+	}
+	got := map[string]string{}
+	for _, trip := range result.Trips {
+		got[trip.RiderID] = trip.DriverID
+	}
+	if got["rider-now"] != "driver-near" || got["rider-later"] != "driver-far" {
+		t.Fatalf("expected arrival-aligned assignment, got %+v", got)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != booked.Rider.ID {
+		t.Fatalf("expected the extra rider to stay queued, got %+v", result.Unmatched)
+	}
+}
+
+func TestRunStationBatchWeighsRiderWait(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	gw.SetMatchWindow(15 * time.Minute)
+	station, _ := gw.stationByID("station-ecity")
+	route := Route{TargetStationIDs: []string{station.ID}}
+	gw.drivers = []Driver{
+		// Four minutes after the rider: the smaller gap, but the rider waits.
+		{ID: "driver-late", Name: "Late", SeatsAvailable: 1, Route: route, ETAMinutes: 14},
+		// Five minutes before the rider, who then boards straight away.
+		{ID: "driver-early", Name: "Early", SeatsAvailable: 1, Route: route, ETAMinutes: 5},
+	}
+	gw.riders = []Rider{
+		{ID: "rider-1", Name: "Rider", StationID: station.ID, Status: "waiting", ArrivalTime: time.Now().Add(10 * time.Minute)},
+	}
+
+	result, err := gw.runStationBatch(station.ID)
+	if err != nil {
+		t.Fatalf("batch failed: %v", err)
+	}
+	if len(result.Trips) != 1 || result.Trips[0].DriverID != "driver-early" {
+		t.Fatalf("expected the early driver so the rider does not wait, got %+v", result.Trips)
+	}
+}
+
+func TestRankDriversUsesStationPolicy(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	pickup := gw.pickupPoints[0]
+	station, _ := gw.stationByID(pickup.StationID)
+	route := Route{TargetStationIDs: []string{station.ID}}
+	gw.drivers = []Driver{
+		{ID: "driver-busy", Name: "Busy", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+		{ID: "driver-idle", Name: "Idle", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude - 0.01, Longitude: pickup.Longitude},
+		{ID: "driver-full", Name: "Full", SeatsAvailable: 0, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+	}
+	gw.trips = []Trip{{ID: "trip-a", DriverID: "driver-busy"}, {ID: "trip-b", DriverID: "driver-busy"}}
+
+	gw.mu.Lock()
+	offered, skipped := gw.rankDriversLocked(station, &pickup, time.Now(), nil)
+	gw.mu.Unlock()
+	if len(offered) != 2 || offered[0].DriverID != "driver-busy" || offered[0].Policy != "nearest" {
+		t.Fatalf("expected nearest driver first by default, got %+v", offered)
+	}
+	if len(skipped) != 1 || skipped[0].DriverID != "driver-full" || skipped[0].Reason != "no seats available" {
+		t.Fatalf("expected full driver to be skipped with a reason, got %+v", skipped)
+	}
+
+	if err := gw.SetMatchPolicy("nearest", map[string]string{station.ID: "fairness"}); err != nil {
+		t.Fatalf("set policy: %v", err)
+	}
+	gw.mu.Lock()
+	offered, _ = gw.rankDriversLocked(station, &pickup, time.Now(), nil)
+	gw.mu.Unlock()
+	if offered[0].DriverID != "driver-idle" || offered[0].Policy != "fairness" {
+		t.Fatalf("expected fairness to favour the idle driver, got %+v", offered)
+	}
+	if len(offered[0].ScoreBreakdown) == 0 || offered[0].ScoreBreakdown[0].Name != "fairness" {
+		t.Fatalf("expected a fairness score breakdown, got %+v", offered[0].ScoreBreakdown)
+	}
+	if offered[0].Score <= offered[1].Score {
+		t.Fatalf("expected offers ordered by score, got %v then %v", offered[0].Score, offered[1].Score)
+	}
+
+	if err := gw.SetMatchPolicy("cheapest", nil); err == nil {
+		t.Fatalf("expected unknown policy to be rejected")
+	}
+}
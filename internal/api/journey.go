@@ -0,0 +1,301 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Journey is a driver's pooled trip: one run to the station carrying every
+// rider matched onto it, each as a leg of the journey.
+type Journey struct {
+	ID              string    `json:"id"`
+	DriverID        string    `json:"driverId"`
+	StationID       string    `json:"stationId,omitempty"`
+	Status          string    `json:"status"`
+	Legs            []TripLeg `json:"legs"`
+	DriverLatitude  float64   `json:"driverLatitude,omitempty"`
+	DriverLongitude float64   `json:"driverLongitude,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	CompletedAt     time.Time `json:"completedAt,omitempty"`
+}
+
+// TripLeg is one rider's part of a journey. Legs are identified by the id of
+// the rider's Trip and ordered by where their pickup sits on the driver's route.
+type TripLeg struct {
+	TripID           string       `json:"tripId"`
+	RiderID          string       `json:"riderId"`
+	PickupPointID    string       `json:"pickupPointId,omitempty"`
+	Pickup           *PickupPoint `json:"pickup,omitempty"`
+	DropoffStationID string       `json:"dropoffStationId,omitempty"`
+	Destination      string       `json:"destination,omitempty"`
+	Status           string       `json:"status"`
+	MatchedAt        time.Time    `json:"matchedAt"`
+	PickedUpAt       time.Time    `json:"pickedUpAt,omitempty"`
+	DroppedOffAt     time.Time    `json:"droppedOffAt,omitempty"`
+}
+
+// riderLegView is what a rider may see of a pooled journey: their own leg and
+// where the driver is, but nothing about the other riders on board.
+type riderLegView struct {
+	JourneyID       string    `json:"journeyId"`
+	DriverID        string    `json:"driverId"`
+	Leg             TripLeg   `json:"leg"`
+	DriverLatitude  float64   `json:"driverLatitude,omitempty"`
+	DriverLongitude float64   `json:"driverLongitude,omitempty"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+func legFinished(status string) bool {
+	return status == "completed" || status == "cancelled"
+}
+
+// joinJourneyLocked adds the trip as a leg of its driver's active journey,
+// starting a new journey when the driver has none, and stamps the trip with
+// the journey id.
+func (g *Gateway) joinJourneyLocked(trip *Trip) *Journey {
+	now := time.Now().UTC()
+	journey := g.journeys[g.activeJourneys[trip.DriverID]]
+	if journey == nil {
+		journey = &Journey{
+			ID:        fmt.Sprintf("journey-%d", now.UnixNano()),
+			DriverID:  trip.DriverID,
+			StationID: trip.StationID,
+			Status:    "active",
+			CreatedAt: now,
+		}
+		g.journeys[journey.ID] = journey
+		g.activeJourneys[trip.DriverID] = journey.ID
+	}
+
+	journey.Legs = append(journey.Legs, TripLeg{
+		TripID:           trip.ID,
+		RiderID:          trip.RiderID,
+		PickupPointID:    trip.PickupPointID,
+		Pickup:           copyPickupPoint(trip.PickupPoint),
+		DropoffStationID: trip.StationID,
+		Destination:      trip.Destination,
+		Status:           trip.Status,
+		MatchedAt:        now,
+	})
+	g.orderLegsLocked(journey)
+	journey.UpdatedAt = now
+
+	trip.JourneyID = journey.ID
+	trip.RoomID = journey.ID
+	return journey
+}
+
+// orderLegsLocked sorts legs by the position of their pickup on the driver's
+// planned route; pickups off the route keep their match order at the end.
+func (g *Gateway) orderLegsLocked(journey *Journey) {
+	position := make(map[string]int)
+	if plan, ok := g.driverPlans[journey.DriverID]; ok {
+		for i, id := range plan.PickupIDs {
+			position[id] = i
+		}
+	}
+	sort.SliceStable(journey.Legs, func(i, j int) bool {
+		pi, iok := position[journey.Legs[i].PickupPointID]
+		pj, jok := position[journey.Legs[j].PickupPointID]
+		if iok != jok {
+			return iok
+		}
+		return iok && pi < pj
+	})
+}
+
+// updateLegLocked moves a leg to status and closes the journey once every leg
+// has finished. It returns the journey the leg belongs to, if any.
+func (g *Gateway) updateLegLocked(tripID, status string) *Journey {
+	journey, leg := g.legLocked(tripID)
+	if leg == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	leg.Status = status
+	switch status {
+	case "in_progress":
+		if leg.PickedUpAt.IsZero() {
+			leg.PickedUpAt = now
+		}
+	case "completed":
+		leg.DroppedOffAt = now
+	}
+	journey.UpdatedAt = now
+	g.closeJourneyIfDoneLocked(journey)
+	return journey
+}
+
+// dropLegLocked removes a leg that never started, e.g. when the rider declined.
+func (g *Gateway) dropLegLocked(tripID string) {
+	journey, leg := g.legLocked(tripID)
+	if leg == nil {
+		return
+	}
+	for i := range journey.Legs {
+		if journey.Legs[i].TripID == tripID {
+			journey.Legs = append(journey.Legs[:i], journey.Legs[i+1:]...)
+			break
+		}
+	}
+	journey.UpdatedAt = time.Now().UTC()
+	if len(journey.Legs) == 0 {
+		delete(g.journeys, journey.ID)
+		if g.activeJourneys[journey.DriverID] == journey.ID {
+			delete(g.activeJourneys, journey.DriverID)
+		}
+		return
+	}
+	g.closeJourneyIfDoneLocked(journey)
+}
+
+func (g *Gateway) closeJourneyIfDoneLocked(journey *Journey) {
+	for _, leg := range journey.Legs {
+		if !legFinished(leg.Status) {
+			return
+		}
+	}
+	journey.Status = "completed"
+	journey.CompletedAt = time.Now().UTC()
+	if g.activeJourneys[journey.DriverID] == journey.ID {
+		delete(g.activeJourneys, journey.DriverID)
+	}
+}
+
+func (g *Gateway) legLocked(tripID string) (*Journey, *TripLeg) {
+	for i := range g.trips {
+		if g.trips[i].ID != tripID {
+			continue
+		}
+		journey := g.journeys[g.trips[i].JourneyID]
+		if journey == nil {
+			return nil, nil
+		}
+		for j := range journey.Legs {
+			if journey.Legs[j].TripID == tripID {
+				return journey, &journey.Legs[j]
+			}
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+func (g *Gateway) noteJourneyPositionLocked(driverID string, lat, lon float64) {
+	journey := g.journeys[g.activeJourneys[driverID]]
+	if journey == nil {
+		return
+	}
+	journey.DriverLatitude = lat
+	journey.DriverLongitude = lon
+	journey.UpdatedAt = time.Now().UTC()
+}
+
+// journeySnapshot returns a copy of the journey, safe to hand to the hub.
+func (g *Gateway) journeySnapshot(journeyID string) *Journey {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return copyJourney(g.journeys[journeyID])
+}
+
+func copyJourney(j *Journey) *Journey {
+	if j == nil {
+		return nil
+	}
+	cp := *j
+	cp.Legs = make([]TripLeg, len(j.Legs))
+	for i, leg := range j.Legs {
+		leg.Pickup = copyPickupPoint(leg.Pickup)
+		cp.Legs[i] = leg
+	}
+	return &cp
+}
+
+// riderLegLocked builds the rider's view of the journey their trip belongs to.
+func (g *Gateway) riderLegLocked(tripID string) (riderLegView, bool) {
+	journey, leg := g.legLocked(tripID)
+	if leg == nil {
+		return riderLegView{}, false
+	}
+	view := riderLegView{
+		JourneyID:       journey.ID,
+		DriverID:        journey.DriverID,
+		Leg:             *leg,
+		DriverLatitude:  journey.DriverLatitude,
+		DriverLongitude: journey.DriverLongitude,
+		UpdatedAt:       journey.UpdatedAt,
+	}
+	view.Leg.Pickup = copyPickupPoint(leg.Pickup)
+	return view, true
+}
+
+// legTripIDFromRequest resolves the leg a pickup or drop-off request refers
+// to, either directly by tripId or by journeyId and riderId.
+func (g *Gateway) legTripIDFromRequest(r *http.Request) string {
+	query := r.URL.Query()
+	if tripID := query.Get("tripId"); tripID != "" {
+		return tripID
+	}
+	journeyID, riderID := query.Get("journeyId"), query.Get("riderId")
+	if journeyID == "" || riderID == "" {
+		return ""
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if journey := g.journeys[journeyID]; journey != nil {
+		for _, leg := range journey.Legs {
+			if leg.RiderID == riderID {
+				return leg.TripID
+			}
+		}
+	}
+	return ""
+}
+
+// DriverItineraryHandler returns the driver's active journey with every leg
+// in pickup order.
+func (g *Gateway) DriverItineraryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	driverID := r.URL.Query().Get("driverId")
+	if driverID == "" {
+		http.Error(w, "driverId required", http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	journey := copyJourney(g.journeys[g.activeJourneys[driverID]])
+	g.mu.Unlock()
+	if journey == nil {
+		http.Error(w, "no active journey", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, journey)
+}
+
+// TripLegHandler returns a rider's own leg and the driver's last position.
+func (g *Gateway) TripLegHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	tripID := r.URL.Query().Get("tripId")
+	if tripID == "" {
+		http.Error(w, "tripId required", http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	view, ok := g.riderLegLocked(tripID)
+	g.mu.Unlock()
+	if !ok {
+		http.Error(w, "trip not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, view)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newPooledGateway(t *testing.T) (*Gateway, string) {
+	t.Helper()
+	gw := NewGateway(nil, nil, nil, nil)
+	station, _ := gw.stationByID("station-ecity")
+	now := time.Now()
+	gw.drivers = []Driver{
+		{ID: "driver-pool", Name: "Pool", SeatsAvailable: 3, Route: Route{TargetStationIDs: []string{station.ID}}, Latitude: station.Latitude, Longitude: station.Longitude},
+	}
+	gw.driverPlans["driver-pool"] = &driverPlan{DriverID: "driver-pool", PickupIDs: []string{"pickup-first", "pickup-second"}, SeatsTotal: 3, SeatsAvailable: 3}
+	gw.riders = []Rider{
+		{ID: "rider-second", Name: "Second", StationID: station.ID, Status: "waiting", ArrivalTime: now, PickupPointID: "pickup-second", Pickup: &PickupPoint{ID: "pickup-second", StationID: station.ID}},
+		{ID: "rider-first", Name: "First", StationID: station.ID, Status: "waiting", ArrivalTime: now, PickupPointID: "pickup-first", Pickup: &PickupPoint{ID: "pickup-first", StationID: station.ID}},
+	}
+	return gw, station.ID
+}
+
+func TestPooledTripsShareOneJourney(t *testing.T) {
+	gw, stationID := newPooledGateway(t)
+
+	var trips []Trip
+	for _, riderID := range []string{"rider-second", "rider-first"} {
+		trip, err := gw.matchTrip("driver-pool", stationID, riderID, 0)
+		if err != nil {
+			t.Fatalf("match %s: %v", riderID, err)
+		}
+		trips = append(trips, trip)
+	}
+	if trips[0].JourneyID == "" || trips[0].JourneyID != trips[1].JourneyID {
+		t.Fatalf("expected both riders on one journey, got %q and %q", trips[0].JourneyID, trips[1].JourneyID)
+	}
+	if trips[0].RoomID != trips[0].JourneyID {
+		t.Fatalf("expected the room to be the journey, got %q", trips[0].RoomID)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/drivers/itinerary?driverId=driver-pool", nil)
+	rr := httptest.NewRecorder()
+	gw.DriverItineraryHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var journey Journey
+	if err := json.NewDecoder(rr.Body).Decode(&journey); err != nil {
+		t.Fatalf("decode itinerary: %v", err)
+	}
+	if len(journey.Legs) != 2 || journey.Legs[0].RiderID != "rider-first" || journey.Legs[1].RiderID != "rider-second" {
+		t.Fatalf("expected legs in route order, got %+v", journey.Legs)
+	}
+}
+
+func TestPooledLegsPickUpAndDropOffIndependently(t *testing.T) {
+	gw, stationID := newPooledGateway(t)
+	first, err := gw.matchTrip("driver-pool", stationID, "rider-first", 0)
+	if err != nil {
+		t.Fatalf("match first: %v", err)
+	}
+	second, err := gw.matchTrip("driver-pool", stationID, "rider-second", 0)
+	if err != nil {
+		t.Fatalf("match second: %v", err)
+	}
+	for _, trip := range []Trip{first, second} {
+		gw.pendingTrips[trip.ID] = &pendingTripContext{Trip: trip}
+		if _, err := gw.finalizePendingTrip(trip.ID); err != nil {
+			t.Fatalf("finalize %s: %v", trip.ID, err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/trips/pickup?journeyId="+first.JourneyID+"&riderId=rider-first", nil)
+	rr := httptest.NewRecorder()
+	gw.TripPickupHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	journey := gw.journeySnapshot(first.JourneyID)
+	statuses := map[string]string{}
+	for _, leg := range journey.Legs {
+		statuses[leg.RiderID] = leg.Status
+	}
+	if statuses["rider-first"] != "in_progress" || statuses["rider-second"] != "pending" {
+		t.Fatalf("expected only the first leg picked up, got %+v", statuses)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/trips/dropoff?tripId="+first.ID, nil)
+	rr = httptest.NewRecorder()
+	gw.TripDropoffHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if journey := gw.journeySnapshot(first.JourneyID); journey.Status != "active" {
+		t.Fatalf("expected journey to stay active with a rider left, got %s", journey.Status)
+	}
+
+	if _, err := gw.completeTrip(second.ID); err != nil {
+		t.Fatalf("complete second: %v", err)
+	}
+	journey = gw.journeySnapshot(first.JourneyID)
+	if journey.Status != "completed" || journey.CompletedAt.IsZero() {
+		t.Fatalf("expected journey completed after the last leg, got %+v", journey)
+	}
+	if _, ok := gw.activeJourneys["driver-pool"]; ok {
+		t.Fatal("expected the driver to have no active journey")
+	}
+}
+
+func TestTripLegHandlerShowsOnlyOwnLeg(t *testing.T) {
+	gw, stationID := newPooledGateway(t)
+	first, err := gw.matchTrip("driver-pool", stationID, "rider-first", 0)
+	if err != nil {
+		t.Fatalf("match first: %v", err)
+	}
+	if _, err := gw.matchTrip("driver-pool", stationID, "rider-second", 0); err != nil {
+		t.Fatalf("match second: %v", err)
+	}
+	gw.publishDriverLocation("driver-pool", 12.85, 77.66)
+
+	req := httptest.NewRequest(http.MethodGet, "/trips/leg?tripId="+first.ID, nil)
+	rr := httptest.NewRecorder()
+	gw.TripLegHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode leg: %v", err)
+	}
+	if _, ok := raw["legs"]; ok {
+		t.Fatal("rider view must not include the other legs")
+	}
+	var view riderLegView
+	if err := json.Unmarshal(rr.Body.Bytes(), &view); err != nil {
+		t.Fatalf("decode leg: %v", err)
+	}
+	if view.Leg.RiderID != "rider-first" || view.DriverLatitude != 12.85 || view.DriverLongitude != 77.66 {
+		t.Fatalf("unexpected rider view %+v", view)
+	}
+}
+
+func TestCancelledLegLeavesJourney(t *testing.T) {
+	gw, stationID := newPooledGateway(t)
+	trip, err := gw.matchTrip("driver-pool", stationID, "rider-first", 0)
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	gw.pendingTrips[trip.ID] = &pendingTripContext{Trip: trip}
+	if err := gw.CancelTrip(trip.ID, "rider_declined"); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if gw.journeySnapshot(trip.JourneyID) != nil {
+		t.Fatal("expected the empty journey to be removed")
+	}
+	if _, ok := gw.activeJourneys["driver-pool"]; ok {
+		t.Fatal("expected the driver to have no active journey")
+	}
+}
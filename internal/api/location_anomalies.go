@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+const anomaliesFetchTimeout = 5 * time.Second
+
+// DriverAnomalies counts a driver's location samples that the location
+// service dropped or quarantined, for spotting faulty devices.
+type DriverAnomalies struct {
+	DriverID    string `json:"driverId"`
+	Teleports   int64  `json:"teleports"`
+	Reanchors   int64  `json:"reanchors"`
+	OutOfOrder  int64  `json:"outOfOrder"`
+	LowAccuracy int64  `json:"lowAccuracy"`
+	Invalid     int64  `json:"invalid"`
+	LastKind    string `json:"lastKind"`
+	LastAt      string `json:"lastAt"`
+}
+
+// LocationAnomaliesHandler lists per-driver sample anomalies, for every
+// driver with any or only for driverId when given.
+func (g *Gateway) LocationAnomaliesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if g.locationClient == nil {
+		http.Error(w, "location service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	req := &locationpb.GetLocationAnomaliesRequest{}
+	if driverID := r.URL.Query().Get("driverId"); driverID != "" {
+		req.DriverIds = []string{driverID}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), anomaliesFetchTimeout)
+	defer cancel()
+	resp, err := g.locationClient.GetLocationAnomalies(ctx, req)
+	if err != nil {
+		g.logger.Error("get location anomalies failed", "err", err)
+		http.Error(w, "failed to load anomalies", http.StatusBadGateway)
+		return
+	}
+	drivers := make([]DriverAnomalies, 0, len(resp.Drivers))
+	for _, d := range resp.Drivers {
+		drivers = append(drivers, DriverAnomalies{
+			DriverID:    d.DriverId,
+			Teleports:   d.Teleports,
+			Reanchors:   d.Reanchors,
+			OutOfOrder:  d.OutOfOrder,
+			LowAccuracy: d.LowAccuracy,
+			Invalid:     d.Invalid,
+			LastKind:    d.LastKind,
+			LastAt:      d.LastAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, drivers)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+type anomaliesLocationClient struct {
+	locationpb.LocationServiceClient
+	req *locationpb.GetLocationAnomaliesRequest
+}
+
+func (c *anomaliesLocationClient) GetLocationAnomalies(_ context.Context, req *locationpb.GetLocationAnomaliesRequest, _ ...grpc.CallOption) (*locationpb.GetLocationAnomaliesResponse, error) {
+	c.req = req
+	return &locationpb.GetLocationAnomaliesResponse{Drivers: []*locationpb.DriverAnomalies{
+		{DriverId: "driver-1", Teleports: 2, LowAccuracy: 1, LastKind: "teleport", LastAt: "2026-01-01T09:00:00Z"},
+	}}, nil
+}
+
+func TestLocationAnomaliesHandler(t *testing.T) {
+	client := &anomaliesLocationClient{}
+	gw := NewGateway(nil, nil, client, nil)
+
+	rec := httptest.NewRecorder()
+	gw.LocationAnomaliesHandler(rec, httptest.NewRequest(http.MethodGet, "/location/anomalies?driverId=driver-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(client.req.DriverIds) != 1 || client.req.DriverIds[0] != "driver-1" {
+		t.Fatalf("expected the driver filter to be forwarded, got %v", client.req.DriverIds)
+	}
+	var drivers []DriverAnomalies
+	if err := json.Unmarshal(rec.Body.Bytes(), &drivers); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(drivers) != 1 || drivers[0].Teleports != 2 || drivers[0].LastKind != "teleport" {
+		t.Fatalf("unexpected anomalies %+v", drivers)
+	}
+
+	rec = httptest.NewRecorder()
+	NewGateway(nil, nil, nil, nil).LocationAnomaliesHandler(rec, httptest.NewRequest(http.MethodGet, "/location/anomalies", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a location service, got %d", rec.Code)
+	}
+}
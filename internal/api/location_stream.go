@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+// locationSubscription builds the location service subscription for a
+// /location/stream query.
+func locationSubscription(query url.Values) (*locationpb.SubscribeLocationRequest, error) {
+	req := &locationpb.SubscribeLocationRequest{
+		DriverId:   query.Get("driverId"),
+		DriverIds:  splitIDs(query.Get("driverIds")),
+		StationIds: splitIDs(query.Get("stationIds")),
+	}
+	if raw := query.Get("bbox"); raw != "" {
+		parts := strings.Split(raw, ",")
+		if len(parts) != 4 {
+			return nil, errors.New("bbox must be minLon,minLat,maxLon,maxLat")
+		}
+		var coords [4]float64
+		for i, part := range parts {
+			v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, errors.New("bbox must be minLon,minLat,maxLon,maxLat")
+			}
+			coords[i] = v
+		}
+		if coords[0] > coords[2] || coords[1] > coords[3] {
+			return nil, errors.New("bbox min must not exceed max")
+		}
+		req.Bounds = &locationpb.BoundingBox{
+			MinLongitude: coords[0],
+			MinLatitude:  coords[1],
+			MaxLongitude: coords[2],
+			MaxLatitude:  coords[3],
+		}
+	}
+	return req, nil
+}
+
+func splitIDs(raw string) []string {
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
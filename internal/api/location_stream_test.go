@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLocationSubscriptionParsesFilters(t *testing.T) {
+	query, _ := url.ParseQuery("driverId=driver-1&driverIds=driver-2,%20driver-3&stationIds=station-ecity&bbox=77.5,12.8,77.8,13.1")
+	req, err := locationSubscription(query)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if req.DriverId != "driver-1" || len(req.DriverIds) != 2 || req.DriverIds[1] != "driver-3" {
+		t.Fatalf("unexpected drivers %q %v", req.DriverId, req.DriverIds)
+	}
+	if len(req.StationIds) != 1 || req.StationIds[0] != "station-ecity" {
+		t.Fatalf("unexpected stations %v", req.StationIds)
+	}
+	b := req.Bounds
+	if b == nil || b.MinLongitude != 77.5 || b.MinLatitude != 12.8 || b.MaxLongitude != 77.8 || b.MaxLatitude != 13.1 {
+		t.Fatalf("expected bbox in minLon,minLat,maxLon,maxLat order, got %+v", b)
+	}
+
+	req, err = locationSubscription(url.Values{})
+	if err != nil || req.DriverId != "" || req.Bounds != nil || len(req.DriverIds)+len(req.StationIds) != 0 {
+		t.Fatalf("expected an unfiltered subscription, got %+v, %v", req, err)
+	}
+
+	for _, bad := range []string{"1,2,3", "a,b,c,d", "77.8,12.8,77.5,13.1"} {
+		if _, err := locationSubscription(url.Values{"bbox": {bad}}); err == nil {
+			t.Fatalf("expected bbox %q to be rejected", bad)
+		}
+	}
+}
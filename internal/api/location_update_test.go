@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+// sampleLocationClient accepts, drops or quarantines every sample sent
+// through UpdateLocation and remembers the last one.
+type sampleLocationClient struct {
+	locationpb.LocationServiceClient
+	drop       bool
+	quarantine bool
+	sent       *locationpb.Location
+}
+
+func (c *sampleLocationClient) UpdateLocation(context.Context, ...grpc.CallOption) (locationpb.LocationService_UpdateLocationClient, error) {
+	return &sampleStream{client: c}, nil
+}
+
+type sampleStream struct {
+	locationpb.LocationService_UpdateLocationClient
+	client *sampleLocationClient
+}
+
+func (s *sampleStream) Send(req *locationpb.UpdateLocationRequest) error {
+	s.client.sent = req.Location
+	return nil
+}
+
+func (s *sampleStream) CloseAndRecv() (*locationpb.UpdateLocationResponse, error) {
+	if s.client.drop {
+		return &locationpb.UpdateLocationResponse{Success: true, Dropped: 1}, nil
+	}
+	if s.client.quarantine {
+		return &locationpb.UpdateLocationResponse{Success: true, Quarantined: 1}, nil
+	}
+	return &locationpb.UpdateLocationResponse{Success: true, Accepted: 1}, nil
+}
+
+func TestUpdateLocationHandlerIgnoresDroppedAndQuarantinedSamples(t *testing.T) {
+	pickup := defaultPickupPoints()[0]
+	client := &sampleLocationClient{drop: true}
+	gw := NewGateway(nil, nil, client, nil)
+	gw.driverPlans["driver-1"] = &driverPlan{DriverID: "driver-1", PickupIDs: []string{pickup.ID}, Active: true}
+
+	body := fmt.Sprintf(`{"driverId":"driver-1","latitude":%v,"longitude":%v,"recordedAt":"2026-01-01T09:00:00Z","speedMps":4.2,"headingDegrees":180,"accuracyMeters":12}`,
+		pickup.Latitude, pickup.Longitude)
+	rec := httptest.NewRecorder()
+	gw.UpdateLocationHandler(rec, httptest.NewRequest(http.MethodPost, "/location/update", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for a dropped sample, got %d", rec.Code)
+	}
+	if client.sent.RecordedAt != "2026-01-01T09:00:00Z" || client.sent.SpeedMps != 4.2 || client.sent.HeadingDegrees != 180 || client.sent.AccuracyMeters != 12 {
+		t.Fatalf("expected motion fields to be forwarded, got %+v", client.sent)
+	}
+	if gw.driverPlans["driver-1"].CurrentIndex != 0 {
+		t.Fatalf("dropped sample must not advance the route")
+	}
+
+	client.drop, client.quarantine = false, true
+	rec = httptest.NewRecorder()
+	gw.UpdateLocationHandler(rec, httptest.NewRequest(http.MethodPost, "/location/update", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted || gw.driverPlans["driver-1"].CurrentIndex != 0 {
+		t.Fatalf("quarantined sample must be acknowledged without advancing the route, got %d", rec.Code)
+	}
+
+	client.quarantine = false
+	rec = httptest.NewRecorder()
+	gw.UpdateLocationHandler(rec, httptest.NewRequest(http.MethodPost, "/location/update", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gw.driverPlans["driver-1"].CurrentIndex != 1 {
+		t.Fatalf("accepted sample at the pickup should advance the route")
+	}
+}
+
+func TestLocationMessageUsesGatewayFieldNames(t *testing.T) {
+	data, err := json.Marshal(toLocationMessage(&locationpb.LocationUpdate{
+		DriverId: "driver-1", Latitude: 12.84, Longitude: 77.66, RecordedAt: "2026-01-01T09:00:00Z", SpeedMps: 3, HeadingDegrees: 45, AccuracyMeters: 5,
+	}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"driverId":"driver-1","latitude":12.84,"longitude":77.66,"recordedAt":"2026-01-01T09:00:00Z","speedMps":3,"headingDegrees":45,"accuracyMeters":5}`
+	if string(data) != want {
+		t.Fatalf("unexpected payload\n got %s\nwant %s", data, want)
+	}
+}
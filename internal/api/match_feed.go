@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	matchingpb "lastmile/gen/go/matching"
+	tripb "lastmile/gen/go/trip"
+)
+
+const (
+	matchEventCreated      = "match_created"
+	matchEventRejected     = "match_rejected"
+	matchEventRiderExpired = "rider_expired"
+
+	// defaultRiderExpiry is how long past their arrival a waiting rider is
+	// kept before the gateway gives up on matching them.
+	defaultRiderExpiry = 30 * time.Minute
+)
+
+// matchEvent is one entry of the match feed. It carries the same events as
+// MatchingService.WatchMatches, whether they were produced by the gateway's
+// own matching or relayed from the matching service.
+type matchEvent struct {
+	Type       string    `json:"type"`
+	StationID  string    `json:"stationId"`
+	DriverID   string    `json:"driverId,omitempty"`
+	RiderID    string    `json:"riderId,omitempty"`
+	Trip       *Trip     `json:"trip,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Source     string    `json:"source"`
+}
+
+// matchFeed fans match events out to HTTP and WebSocket watchers.
+type matchFeed struct {
+	mu   sync.Mutex
+	subs map[chan matchEvent]map[string]bool
+}
+
+func newMatchFeed() *matchFeed {
+	return &matchFeed{subs: make(map[chan matchEvent]map[string]bool)}
+}
+
+func (f *matchFeed) subscribe(stationIDs []string) (chan matchEvent, func()) {
+	var filter map[string]bool
+	if len(stationIDs) > 0 {
+		filter = make(map[string]bool, len(stationIDs))
+		for _, id := range stationIDs {
+			filter[id] = true
+		}
+	}
+	ch := make(chan matchEvent, 32)
+	f.mu.Lock()
+	f.subs[ch] = filter
+	f.mu.Unlock()
+	return ch, func() {
+		f.mu.Lock()
+		delete(f.subs, ch)
+		f.mu.Unlock()
+	}
+}
+
+func (f *matchFeed) publish(ev matchEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch, filter := range f.subs {
+		if filter != nil && !filter[ev.StationID] {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+			// Slow watchers miss events rather than stall matching.
+		}
+	}
+}
+
+func (g *Gateway) publishMatchEvent(typ, stationID, driverID, riderID string, trip *Trip, reason string) {
+	var tripCopy *Trip
+	if trip != nil {
+		t := *trip
+		tripCopy = &t
+	}
+	g.feed.publish(matchEvent{
+		Type:       typ,
+		StationID:  stationID,
+		DriverID:   driverID,
+		RiderID:    riderID,
+		Trip:       tripCopy,
+		Reason:     reason,
+		OccurredAt: time.Now().UTC(),
+		Source:     "gateway",
+	})
+}
+
+// expireStaleRidersLocked gives up on riders still waiting long after their
+// arrival time so they stop holding a place in the deferred queue.
+func (g *Gateway) expireStaleRidersLocked(now time.Time) {
+	for i := range g.riders {
+		rider := &g.riders[i]
+		if rider.Status != "waiting" || rider.ArrivalTime.IsZero() || now.Sub(rider.ArrivalTime) <= defaultRiderExpiry {
+			continue
+		}
+		rider.Status = "expired"
+		delete(g.deferredRiders, rider.ID)
+		g.logger.Info("rider expired", "riderId", rider.ID, "stationId", rider.StationID)
+		g.publishMatchEvent(matchEventRiderExpired, rider.StationID, "", rider.ID, nil, "no match before expiry")
+	}
+}
+
+// AttachMatchingFeed relays events from MatchingService.WatchMatches into the
+// gateway's match feed until ctx is cancelled, reconnecting when the stream drops.
+func (g *Gateway) AttachMatchingFeed(ctx context.Context, client matchingpb.MatchingServiceClient) {
+	if client == nil {
+		return
+	}
+	go func() {
+		backoff := time.Second
+		for {
+			stream, err := client.WatchMatches(ctx, &matchingpb.WatchMatchesRequest{})
+			if err == nil {
+				backoff = time.Second
+				for {
+					ev, recvErr := stream.Recv()
+					if recvErr != nil {
+						err = recvErr
+						break
+					}
+					g.feed.publish(fromProtoMatchEvent(ev))
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			g.logger.Warn("matching feed disconnected", "err", err, "retryIn", backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+func fromProtoMatchEvent(ev *matchingpb.MatchEvent) matchEvent {
+	out := matchEvent{
+		StationID: ev.GetStationId(),
+		DriverID:  ev.GetDriverId(),
+		RiderID:   ev.GetRiderId(),
+		Reason:    ev.GetReason(),
+		Source:    "matching",
+	}
+	switch ev.GetType() {
+	case matchingpb.MatchEventType_MATCH_EVENT_TYPE_CREATED:
+		out.Type = matchEventCreated
+	case matchingpb.MatchEventType_MATCH_EVENT_TYPE_REJECTED:
+		out.Type = matchEventRejected
+	case matchingpb.MatchEventType_MATCH_EVENT_TYPE_RIDER_EXPIRED:
+		out.Type = matchEventRiderExpired
+	}
+	if ts, err := time.Parse(time.RFC3339, ev.GetOccurredAt()); err == nil {
+		out.OccurredAt = ts
+	}
+	if ev.GetTrip() != nil {
+		trip := fromProtoTrip(ev.GetTrip())
+		out.Trip = &trip
+	}
+	return out
+}
+
+func fromProtoTrip(t *tripb.Trip) Trip {
+	trip := Trip{
+		ID:          t.GetId(),
+		DriverID:    t.GetDriverId(),
+		RiderID:     t.GetRiderId(),
+		StationID:   t.GetStationId(),
+		Destination: t.GetDestination(),
+		Status:      t.GetStatus(),
+	}
+	if ts, err := time.Parse(time.RFC3339, t.GetCreatedAt()); err == nil {
+		trip.CreatedAt = ts
+	}
+	return trip
+}
+
+// MatchWatchHandler streams match events. WebSocket clients get one JSON
+// message per event; plain HTTP clients get a Server-Sent Events stream.
+// Filter with ?stationIds=a,b; without it every station is included.
+func (g *Gateway) MatchWatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	events, unsubscribe := g.feed.subscribe(splitIDs(r.URL.Query().Get("stationIds")))
+	defer unsubscribe()
+	serveEvents(g, w, r, events, func(ev matchEvent) string { return ev.Type })
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	matchingpb "lastmile/gen/go/matching"
+	tripb "lastmile/gen/go/trip"
+)
+
+func seedFeedGateway(t *testing.T) (*Gateway, *Station) {
+	t.Helper()
+	gw := NewGateway(nil, nil, nil, nil)
+	station, _ := gw.stationByID("station-ecity")
+	gw.drivers = []Driver{
+		{ID: "driver-1", Name: "Ravi", SeatsAvailable: 2, Route: Route{TargetStationIDs: []string{station.ID}}, Latitude: station.Latitude, Longitude: station.Longitude},
+	}
+	gw.riders = []Rider{
+		{ID: "rider-1", Name: "Priya", StationID: station.ID, Status: "waiting", ArrivalTime: time.Now().Add(time.Minute)},
+	}
+	return gw, station
+}
+
+// waitForWatchers blocks until n feed subscriptions are registered.
+func waitForWatchers(t *testing.T, gw *Gateway, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		gw.feed.mu.Lock()
+		count := len(gw.feed.subs)
+		gw.feed.mu.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d match watchers", n)
+}
+
+func TestMatchWatchHandlerStreamsServerSentEvents(t *testing.T) {
+	gw, station := seedFeedGateway(t)
+	srv := httptest.NewServer(http.HandlerFunc(gw.MatchWatchHandler))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?stationIds="+station.ID, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("watch request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected event stream, got %s", ct)
+	}
+	waitForWatchers(t, gw, 1)
+
+	// An event for another station is filtered out.
+	gw.publishMatchEvent(matchEventRejected, "station-hsr", "driver-9", "", nil, "elsewhere")
+	trip, err := gw.createTripForRider("driver-1", station.ID, "rider-1")
+	if err != nil {
+		t.Fatalf("create trip: %v", err)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var eventName string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "event: ") {
+			eventName = strings.TrimPrefix(line, "event: ")
+			continue
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var ev matchEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		if eventName != matchEventCreated || ev.Type != matchEventCreated {
+			t.Fatalf("expected match_created first, got %s %+v", eventName, ev)
+		}
+		if ev.Trip == nil || ev.Trip.ID != trip.ID || ev.RiderID != "rider-1" || ev.Source != "gateway" {
+			t.Fatalf("unexpected event payload: %+v", ev)
+		}
+		return
+	}
+}
+
+func TestMatchWatchHandlerWebSocketCarriesRejectionsAndExpiry(t *testing.T) {
+	gw, station := seedFeedGateway(t)
+	gw.riders = append(gw.riders, Rider{ID: "rider-stale", StationID: station.ID, Status: "waiting", ArrivalTime: time.Now().Add(-time.Hour)})
+	srv := httptest.NewServer(http.HandlerFunc(gw.MatchWatchHandler))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial websocket: %v", err)
+	}
+	defer conn.Close()
+	waitForWatchers(t, gw, 1)
+
+	gw.reevaluateDeferredRiders("driver-1", station.Latitude, station.Longitude)
+	var expired matchEvent
+	if err := conn.ReadJSON(&expired); err != nil {
+		t.Fatalf("read expiry: %v", err)
+	}
+	if expired.Type != matchEventRiderExpired || expired.RiderID != "rider-stale" {
+		t.Fatalf("expected stale rider to expire, got %+v", expired)
+	}
+	if rider, _ := gw.findRiderByID("rider-stale"); rider.Status != "expired" {
+		t.Fatalf("expected rider status expired, got %s", rider.Status)
+	}
+
+	trip, err := gw.createTripForRider("driver-1", station.ID, "rider-1")
+	if err != nil {
+		t.Fatalf("create trip: %v", err)
+	}
+	// Hold the trip for rider approval without the auto-finalising fallback.
+	gw.mu.Lock()
+	gw.pendingTrips[trip.ID] = &pendingTripContext{Trip: trip}
+	gw.mu.Unlock()
+	var created matchEvent
+	if err := conn.ReadJSON(&created); err != nil || created.Type != matchEventCreated {
+		t.Fatalf("expected match_created, got %+v (%v)", created, err)
+	}
+	if err := gw.CancelTrip(trip.ID, "rider_declined"); err != nil {
+		t.Fatalf("cancel trip: %v", err)
+	}
+	var rejected matchEvent
+	if err := conn.ReadJSON(&rejected); err != nil {
+		t.Fatalf("read rejection: %v", err)
+	}
+	if rejected.Type != matchEventRejected || rejected.Reason != "rider_declined" || rejected.Trip == nil || rejected.Trip.ID != trip.ID {
+		t.Fatalf("unexpected rejection: %+v", rejected)
+	}
+}
+
+func TestFromProtoMatchEvent(t *testing.T) {
+	ev := fromProtoMatchEvent(&matchingpb.MatchEvent{
+		Type:       matchingpb.MatchEventType_MATCH_EVENT_TYPE_CREATED,
+		StationId:  "station-1",
+		DriverId:   "driver-1",
+		RiderId:    "rider-1",
+		Trip:       &tripb.Trip{Id: "trip-1", DriverId: "driver-1", RiderId: "rider-1", CreatedAt: "2025-01-02T03:04:05Z"},
+		OccurredAt: "2025-01-02T03:04:06Z",
+	})
+	if ev.Type != matchEventCreated || ev.Source != "matching" || ev.Trip == nil || ev.Trip.ID != "trip-1" {
+		t.Fatalf("unexpected conversion: %+v", ev)
+	}
+	if ev.OccurredAt.IsZero() || ev.Trip.CreatedAt.IsZero() {
+		t.Fatalf("expected timestamps to be parsed: %+v", ev)
+	}
+}
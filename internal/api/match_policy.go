@@ -0,0 +1,16 @@
+package api
+
+import "lastmile/internal/pkg/matchpolicy"
+
+// SetMatchPolicy chooses how candidate drivers are ranked for riders. The
+// default policy applies to every station without an entry in perStation.
+func (g *Gateway) SetMatchPolicy(defaultPolicy string, perStation map[string]string) error {
+	selector, err := matchpolicy.NewSelector(defaultPolicy, perStation)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.policies = selector
+	return nil
+}
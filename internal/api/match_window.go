@@ -0,0 +1,149 @@
+package api
+
+import "time"
+
+// defaultMatchWindow is how far apart a driver's projected arrival and a
+// rider's arrival at the same station may be for the two to be matched.
+const defaultMatchWindow = 10 * time.Minute
+
+type deferredRider struct {
+	station *Station
+	pickup  *PickupPoint
+}
+
+// SetMatchWindow overrides the arrival-time tolerance used for matching.
+// Non-positive values restore the default.
+func (g *Gateway) SetMatchWindow(window time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if window <= 0 {
+		window = defaultMatchWindow
+	}
+	g.matchWindow = window
+}
+
+func (g *Gateway) matchWindowLocked(override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	if g.matchWindow > 0 {
+		return g.matchWindow
+	}
+	return defaultMatchWindow
+}
+
+// withinArrivalWindow reports whether a driver arriving at driverArrival can
+// serve a rider arriving at riderArrival. Riders without an arrival time always match.
+func withinArrivalWindow(driverArrival, riderArrival time.Time, window time.Duration) bool {
+	if riderArrival.IsZero() {
+		return true
+	}
+	gap := driverArrival.Sub(riderArrival)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= window
+}
+
+func (g *Gateway) deferRiderLocked(riderID string, station *Station, pickup *PickupPoint) {
+	g.deferredRiders[riderID] = &deferredRider{station: copyStation(station), pickup: copyPickupPoint(pickup)}
+}
+
+// reevaluateDeferredRiders retries riders that were queued because no driver
+// would reach their station inside the window. It runs on every location update
+// from driverID so riders are offered as soon as the driver is close enough.
+func (g *Gateway) reevaluateDeferredRiders(driverID string, lat, lon float64) {
+	type offer struct {
+		rider    Rider
+		station  *Station
+		pickup   *PickupPoint
+		attempts []driverAttempt
+	}
+
+	g.mu.Lock()
+	g.expireStaleRidersLocked(time.Now())
+	driver, err := g.findDriver(driverID, "")
+	if err != nil || len(g.deferredRiders) == 0 {
+		g.mu.Unlock()
+		return
+	}
+	// Search around each pickup the driver serves once, outside the lock.
+	pickups := make(map[string]*deferredRider)
+	for _, ctx := range g.deferredRiders {
+		if ctx.station != nil && routeContains(driver.Route.TargetStationIDs, ctx.station.ID) {
+			pickups[deferredPickupKey(ctx)] = ctx
+		}
+	}
+	g.mu.Unlock()
+	near := make(map[string]*nearbyDrivers, len(pickups))
+	for key, ctx := range pickups {
+		near[key] = g.findNearbyDrivers(ctx.station, ctx.pickup)
+	}
+
+	g.mu.Lock()
+	driver, err = g.findDriver(driverID, "")
+	if err != nil {
+		g.mu.Unlock()
+		return
+	}
+	driver.Latitude, driver.Longitude = lat, lon
+
+	batch := g.batchModeLocked()
+	offers := make([]offer, 0)
+	for riderID, ctx := range g.deferredRiders {
+		rider, err := g.findRiderByID(riderID)
+		if err != nil || rider.Status != "waiting" {
+			delete(g.deferredRiders, riderID)
+			continue
+		}
+		if ctx.station == nil || !routeContains(driver.Route.TargetStationIDs, ctx.station.ID) {
+			continue
+		}
+		attempts := g.driverCandidatesLocked(ctx.station, ctx.pickup, rider.ArrivalTime, near[deferredPickupKey(ctx)])
+		if len(attempts) == 0 || indexOfAttempt(attempts, driverID) == -1 {
+			continue
+		}
+		if batch {
+			// Batch mode assigns the whole station together rather than rider by rider.
+			g.scheduleBatchLocked(ctx.station.ID)
+			continue
+		}
+		delete(g.deferredRiders, riderID)
+		offers = append(offers, offer{rider: copyRider(rider), station: ctx.station, pickup: ctx.pickup, attempts: attempts})
+	}
+	g.mu.Unlock()
+
+	for _, o := range offers {
+		g.logger.Info("deferred rider entered match window", "riderId", o.rider.ID, "driverId", driverID, "stationId", o.station.ID)
+		if g.hub != nil {
+			go g.hub.EnqueueRiderRequest(o.rider, o.station, o.pickup, o.attempts)
+			continue
+		}
+		trip, err := g.createTripForRider(driverID, o.station.ID, o.rider.ID)
+		if err != nil {
+			g.logger.Warn("deferred match failed", "riderId", o.rider.ID, "driverId", driverID, "err", err)
+			g.mu.Lock()
+			g.deferRiderLocked(o.rider.ID, o.station, o.pickup)
+			g.mu.Unlock()
+			continue
+		}
+		g.queueRiderApproval(trip)
+	}
+}
+
+// deferredPickupKey groups deferred riders waiting at the same pickup.
+func deferredPickupKey(ctx *deferredRider) string {
+	if ctx.pickup != nil {
+		return ctx.pickup.ID
+	}
+	return ctx.station.ID
+}
+
+func indexOfAttempt(attempts []driverAttempt, driverID string) int {
+	for i, attempt := range attempts {
+		if attempt.DriverID == driverID {
+			return i
+		}
+	}
+	return -1
+}
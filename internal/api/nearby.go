@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+const (
+	// nearbySearchRadius bounds how far from a pickup the gateway looks for
+	// drivers when the location index is available.
+	nearbySearchRadius = 10000.0
+	// nearbySearchLimit caps the drivers considered per search.
+	nearbySearchLimit   = 200
+	nearbySearchTimeout = 2 * time.Second
+)
+
+// nearbyDrivers is what the location service reported around a pickup.
+type nearbyDrivers struct {
+	// located are the drivers within the search radius, nearest first.
+	located []*locationpb.Location
+	// unpositioned are the drivers the location service has never had a
+	// position for. They are scored on what the gateway knows instead of
+	// being left out.
+	unpositioned []string
+}
+
+// driverIndex maps driver ids to their place in g.drivers. It is rebuilt
+// whenever g.drivers is replaced, so a booking only touches the drivers the
+// location service names.
+type driverIndex struct {
+	first *Driver
+	count int
+	byID  map[string]int
+	// unpositioned lists the drivers without a position when the index was
+	// built.
+	unpositioned []string
+}
+
+// driverIndexLocked returns the index for the current g.drivers, rebuilding
+// it if the slice has changed since it was built.
+func (g *Gateway) driverIndexLocked() *driverIndex {
+	idx := &g.driverIndex
+	if len(g.drivers) == idx.count && (idx.count == 0 || &g.drivers[0] == idx.first) {
+		return idx
+	}
+	*idx = driverIndex{count: len(g.drivers), byID: make(map[string]int, len(g.drivers))}
+	for i := range g.drivers {
+		idx.byID[g.drivers[i].ID] = i
+		if !g.drivers[i].positioned {
+			idx.unpositioned = append(idx.unpositioned, g.drivers[i].ID)
+		}
+	}
+	if idx.count > 0 {
+		idx.first = &g.drivers[0]
+	}
+	return idx
+}
+
+// driverByIDLocked looks a driver up through the index, or returns nil.
+func (g *Gateway) driverByIDLocked(id string) *Driver {
+	idx := g.driverIndexLocked()
+	i, ok := idx.byID[id]
+	if !ok || g.drivers[i].ID != id {
+		return nil
+	}
+	return &g.drivers[i]
+}
+
+// nearbyPoint is where candidates are searched from: the pickup when it has
+// coordinates, otherwise the station.
+func nearbyPoint(station *Station, pickup *PickupPoint) (lat, lon float64) {
+	lat, lon = station.Latitude, station.Longitude
+	if pickup != nil && pickup.Latitude != 0 && pickup.Longitude != 0 {
+		lat, lon = pickup.Latitude, pickup.Longitude
+	}
+	return lat, lon
+}
+
+// findNearbyDrivers asks the location service for the drivers around the
+// pickup, and which of the drivers the gateway has no position for are
+// still unknown to it. It must be called without g.mu held: only the index
+// is read under the lock and the call is made after releasing it. It returns
+// nil when the location service cannot be used and callers should scan
+// every driver instead.
+func (g *Gateway) findNearbyDrivers(station *Station, pickup *PickupPoint) *nearbyDrivers {
+	lat, lon := nearbyPoint(station, pickup)
+	if g.locationClient == nil || (lat == 0 && lon == 0) {
+		return nil
+	}
+	g.mu.Lock()
+	idx := g.driverIndexLocked()
+	fleet := idx.count
+	unpositioned := append([]string(nil), idx.unpositioned...)
+	g.mu.Unlock()
+	if fleet == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nearbySearchTimeout)
+	defer cancel()
+	resp, err := g.locationClient.FindDriversNear(ctx, &locationpb.FindDriversNearRequest{
+		Latitude:               lat,
+		Longitude:              lon,
+		RadiusMeters:           nearbySearchRadius,
+		Limit:                  nearbySearchLimit,
+		UnpositionedCandidates: unpositioned,
+	})
+	if err != nil {
+		g.logger.Warn("nearby driver search failed, scanning all drivers", "err", err)
+		return nil
+	}
+
+	near := &nearbyDrivers{
+		located:      make([]*locationpb.Location, 0, len(resp.Drivers)),
+		unpositioned: resp.UnpositionedDriverIds,
+	}
+	for _, d := range resp.Drivers {
+		near.located = append(near.located, d.GetLocation())
+	}
+	return near
+}
+
+// candidateDriversLocked is the pool rankDriversLocked scores. With a nearby
+// search it is the known drivers the location service placed near the pickup,
+// their positions refreshed and nearest first, followed by the drivers it
+// has no position for at all; drivers positioned outside the radius are left
+// out. Without one it is every driver.
+func (g *Gateway) candidateDriversLocked(near *nearbyDrivers) []*Driver {
+	if near == nil {
+		drivers := make([]*Driver, len(g.drivers))
+		for i := range g.drivers {
+			drivers[i] = &g.drivers[i]
+		}
+		return drivers
+	}
+
+	drivers := make([]*Driver, 0, len(near.located)+len(near.unpositioned))
+	for _, loc := range near.located {
+		driver := g.driverByIDLocked(loc.GetDriverId())
+		if driver == nil {
+			continue
+		}
+		driver.Latitude = loc.GetLatitude()
+		driver.Longitude = loc.GetLongitude()
+		driver.positioned = true
+		drivers = append(drivers, driver)
+	}
+	for _, id := range near.unpositioned {
+		if driver := g.driverByIDLocked(id); driver != nil && !driver.positioned {
+			drivers = append(drivers, driver)
+		}
+	}
+	return drivers
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+// nearbyLocationClient answers FindDriversNear from a fixed list, reporting
+// the candidates that are neither in it nor in positioned as unpositioned;
+// other methods are not used by these tests.
+type nearbyLocationClient struct {
+	locationpb.LocationServiceClient
+	drivers    []*locationpb.NearbyDriver
+	positioned []*locationpb.Location
+	err        error
+	calls      int
+	candidates []string
+}
+
+func (c *nearbyLocationClient) FindDriversNear(_ context.Context, req *locationpb.FindDriversNearRequest, _ ...grpc.CallOption) (*locationpb.FindDriversNearResponse, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.candidates = req.UnpositionedCandidates
+	known := make(map[string]bool)
+	for _, loc := range c.positioned {
+		known[loc.DriverId] = true
+	}
+	for _, d := range c.drivers {
+		known[d.Location.DriverId] = true
+	}
+	resp := &locationpb.FindDriversNearResponse{Drivers: c.drivers}
+	for _, id := range req.UnpositionedCandidates {
+		if !known[id] {
+			resp.UnpositionedDriverIds = append(resp.UnpositionedDriverIds, id)
+		}
+	}
+	return resp, nil
+}
+
+func TestRankDriversUsesLocationIndex(t *testing.T) {
+	pickup := defaultPickupPoints()[0]
+	client := &nearbyLocationClient{
+		drivers: []*locationpb.NearbyDriver{
+			{Location: &locationpb.Location{DriverId: "driver-near", Latitude: pickup.Latitude, Longitude: pickup.Longitude}},
+			{Location: &locationpb.Location{DriverId: "driver-unknown", Latitude: pickup.Latitude, Longitude: pickup.Longitude}},
+		},
+		// Positioned outside the search radius.
+		positioned: []*locationpb.Location{{DriverId: "driver-away", Latitude: pickup.Latitude + 0.5, Longitude: pickup.Longitude}},
+	}
+	gw := NewGateway(nil, nil, client, nil)
+	station, _ := gw.stationByID(pickup.StationID)
+	route := Route{TargetStationIDs: []string{station.ID}}
+	gw.drivers = []Driver{
+		// Stale position; the index says the driver is at the pickup now.
+		{ID: "driver-near", Name: "Near", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude - 0.05, Longitude: pickup.Longitude},
+		{ID: "driver-away", Name: "Away", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+		// Never reported a position, so the gateway's own one is scored.
+		{ID: "driver-silent", Name: "Silent", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude - 0.01, Longitude: pickup.Longitude},
+	}
+
+	near := gw.findNearbyDrivers(station, &pickup)
+	gw.mu.Lock()
+	offered, _ := gw.rankDriversLocked(station, &pickup, time.Now(), near)
+	gw.mu.Unlock()
+	if client.calls != 1 {
+		t.Fatalf("expected one index lookup, got %d", client.calls)
+	}
+	if len(offered) != 2 || offered[0].DriverID != "driver-near" || offered[1].DriverID != "driver-silent" {
+		t.Fatalf("expected the indexed driver and the one without a position, got %+v", offered)
+	}
+	if offered[0].DistanceMeters > 1 {
+		t.Fatalf("expected the indexed position to be used, got %.0f m", offered[0].DistanceMeters)
+	}
+
+	// Once a refresh has positions for the others, only the silent driver
+	// is sent as a candidate.
+	gw.mu.Lock()
+	gw.drivers = append([]Driver(nil), gw.drivers...)
+	gw.drivers[1].positioned = true
+	gw.mu.Unlock()
+	gw.findNearbyDrivers(station, &pickup)
+	if len(client.candidates) != 1 || client.candidates[0] != "driver-silent" {
+		t.Fatalf("expected only the driver without a position to be checked, got %v", client.candidates)
+	}
+}
+
+func TestRankDriversFallsBackWithoutIndex(t *testing.T) {
+	pickup := defaultPickupPoints()[0]
+	client := &nearbyLocationClient{err: errors.New("location service down")}
+	gw := NewGateway(nil, nil, client, nil)
+	station, _ := gw.stationByID(pickup.StationID)
+	route := Route{TargetStationIDs: []string{station.ID}}
+	gw.drivers = []Driver{
+		{ID: "driver-a", Name: "A", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+		{ID: "driver-b", Name: "B", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude - 0.01, Longitude: pickup.Longitude},
+	}
+
+	near := gw.findNearbyDrivers(station, &pickup)
+	if near != nil {
+		t.Fatalf("expected no nearby result while the location service is down")
+	}
+	gw.mu.Lock()
+	offered, _ := gw.rankDriversLocked(station, &pickup, time.Now(), near)
+	gw.mu.Unlock()
+	if len(offered) != 2 || offered[0].DriverID != "driver-a" {
+		t.Fatalf("expected every driver to be scanned, got %+v", offered)
+	}
+}
@@ -3,9 +3,11 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -207,6 +209,34 @@ func (p *Persistence) RecordTrip(trip Trip) {
 	}
 }
 
+// LoadTrip returns a recorded trip's parties, station and status, or nil
+// when no trip has the id.
+func (p *Persistence) LoadTrip(ctx context.Context, id string) (*Trip, error) {
+	if p == nil || p.pool == nil {
+		return nil, nil
+	}
+	var trip Trip
+	var destination *string
+	var completedAt *time.Time
+	err := p.pool.QueryRow(ctx, `
+		select id, driver_id, rider_id, station_id, status, destination, completed_at
+		from trips where id=$1
+	`, id).Scan(&trip.ID, &trip.DriverID, &trip.RiderID, &trip.StationID, &trip.Status, &destination, &completedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if destination != nil {
+		trip.Destination = *destination
+	}
+	if completedAt != nil {
+		trip.CompletedAt = *completedAt
+	}
+	return &trip, nil
+}
+
 func (p *Persistence) RecordTripEvent(tripID, eventType string, payload map[string]any) {
 	if p == nil || p.pool == nil {
 		return
@@ -223,6 +253,117 @@ func (p *Persistence) RecordTripEvent(tripID, eventType string, payload map[stri
 	}
 }
 
+func (p *Persistence) SaveRouteTemplate(t RouteTemplate) {
+	if p == nil || p.pool == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var lastRun *time.Time
+	if !t.LastRunAt.IsZero() {
+		lastRun = &t.LastRunAt
+	}
+	_, err := p.pool.Exec(ctx, `
+		insert into driver_route_templates (id, driver_id, name, pickup_ids, seats, destination, days, departure, timezone, created_at, last_run_at)
+		values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+		on conflict (id) do update set
+			name=excluded.name,
+			pickup_ids=excluded.pickup_ids,
+			seats=excluded.seats,
+			destination=excluded.destination,
+			days=excluded.days,
+			departure=excluded.departure,
+			timezone=excluded.timezone,
+			last_run_at=excluded.last_run_at
+	`, t.ID, t.DriverID, t.Name, t.PickupPointIDs, t.Seats, t.Destination, t.Days, t.Departure, t.Timezone, t.CreatedAt, lastRun)
+	if err != nil {
+		p.logger.Warn("save route template failed", "scheduleId", t.ID, "err", err)
+	}
+}
+
+func (p *Persistence) DeleteRouteTemplate(id string) {
+	if p == nil || p.pool == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if _, err := p.pool.Exec(ctx, `delete from driver_route_templates where id=$1`, id); err != nil {
+		p.logger.Warn("delete route template failed", "scheduleId", id, "err", err)
+	}
+}
+
+// LoadRouteTemplates returns every stored template; the caller works out
+// their next departures.
+func (p *Persistence) LoadRouteTemplates(ctx context.Context) ([]RouteTemplate, error) {
+	if p == nil || p.pool == nil {
+		return nil, nil
+	}
+	rows, err := p.pool.Query(ctx, `
+		select id, driver_id, name, pickup_ids, seats, destination, days, departure, timezone, created_at, last_run_at
+		from driver_route_templates
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []RouteTemplate
+	for rows.Next() {
+		var t RouteTemplate
+		var lastRun *time.Time
+		if err := rows.Scan(&t.ID, &t.DriverID, &t.Name, &t.PickupPointIDs, &t.Seats, &t.Destination, &t.Days, &t.Departure, &t.Timezone, &t.CreatedAt, &lastRun); err != nil {
+			return nil, err
+		}
+		if lastRun != nil {
+			t.LastRunAt = *lastRun
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// SaveRating stores a rating. A trip's rater is stored once; a second
+// rating of the same trip is ignored.
+func (p *Persistence) SaveRating(r Rating) error {
+	if p == nil || p.pool == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := p.pool.Exec(ctx, `
+		insert into trip_ratings (trip_id, rater_id, rater_role, subject_id, stars, tags, comment, created_at)
+		values ($1,$2,$3,$4,$5,$6,$7,$8)
+		on conflict (trip_id, rater_id) do nothing
+	`, r.TripID, r.RaterID, r.RaterRole, r.SubjectID, r.Stars, r.Tags, r.Comment, r.CreatedAt)
+	return err
+}
+
+// LoadRatings returns every stored rating.
+func (p *Persistence) LoadRatings(ctx context.Context) ([]Rating, error) {
+	if p == nil || p.pool == nil {
+		return nil, nil
+	}
+	rows, err := p.pool.Query(ctx, `
+		select trip_id, rater_id, rater_role, subject_id, stars, tags, comment, created_at
+		from trip_ratings
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rating
+	for rows.Next() {
+		var r Rating
+		if err := rows.Scan(&r.TripID, &r.RaterID, &r.RaterRole, &r.SubjectID, &r.Stars, &r.Tags, &r.Comment, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
 func pickupName(p *PickupPoint) string {
 	if p == nil {
 		return ""
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	presenceOnline  = "online"
+	presenceIdle    = "idle"
+	presenceStale   = "stale"
+	presenceOffline = "offline"
+
+	defaultPresenceIdleAfter    = time.Minute
+	defaultPresenceStaleAfter   = 5 * time.Minute
+	defaultPresenceOfflineAfter = 15 * time.Minute
+)
+
+// PresenceConfig sets how long after a driver was last seen they turn idle,
+// stale and offline. Zero values take the defaults.
+type PresenceConfig struct {
+	IdleAfter    time.Duration
+	StaleAfter   time.Duration
+	OfflineAfter time.Duration
+}
+
+func (c PresenceConfig) withDefaults() PresenceConfig {
+	if c.IdleAfter <= 0 {
+		c.IdleAfter = defaultPresenceIdleAfter
+	}
+	if c.StaleAfter <= c.IdleAfter {
+		c.StaleAfter = max(defaultPresenceStaleAfter, c.IdleAfter)
+	}
+	if c.OfflineAfter <= c.StaleAfter {
+		c.OfflineAfter = max(defaultPresenceOfflineAfter, c.StaleAfter)
+	}
+	return c
+}
+
+// driverPresence is when a driver was last heard from, by location update or
+// over their socket.
+type driverPresence struct {
+	lastSeen time.Time
+	// connected is set while the driver has a socket session; an open
+	// session counts as being seen continuously.
+	connected bool
+	state     string
+}
+
+// DriverPresence is a driver's presence as served to dashboards, and the
+// event pushed when it changes.
+type DriverPresence struct {
+	DriverID   string    `json:"driverId"`
+	State      string    `json:"state"`
+	Previous   string    `json:"previous,omitempty"`
+	Connected  bool      `json:"connected"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
+
+// SetPresenceConfig replaces the presence TTLs.
+func (g *Gateway) SetPresenceConfig(cfg PresenceConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.presenceCfg = cfg.withDefaults()
+}
+
+func (g *Gateway) presenceStateLocked(p *driverPresence, now time.Time) string {
+	if p.connected {
+		return presenceOnline
+	}
+	age := now.Sub(p.lastSeen)
+	switch {
+	case age <= g.presenceCfg.IdleAfter:
+		return presenceOnline
+	case age <= g.presenceCfg.StaleAfter:
+		return presenceIdle
+	case age <= g.presenceCfg.OfflineAfter:
+		return presenceStale
+	}
+	return presenceOffline
+}
+
+// driverPresenceLocked reports the driver's presence state. ok is false for
+// drivers not heard from since the gateway started.
+func (g *Gateway) driverPresenceLocked(driverID string, now time.Time) (state string, ok bool) {
+	p, ok := g.presence[driverID]
+	if !ok {
+		return "", false
+	}
+	return g.presenceStateLocked(p, now), true
+}
+
+// applyPresenceLocked copies the driver's presence onto the snapshot entry.
+func (g *Gateway) applyPresenceLocked(driver *Driver, now time.Time) {
+	p, ok := g.presence[driver.ID]
+	if !ok {
+		driver.Presence, driver.LastSeenAt = "", nil
+		return
+	}
+	lastSeen := p.lastSeen
+	driver.Presence = g.presenceStateLocked(p, now)
+	driver.LastSeenAt = &lastSeen
+}
+
+// observePresenceLocked records activity from the driver and returns the
+// change it caused, if any.
+func (g *Gateway) observePresenceLocked(driverID string, now time.Time, connected *bool) *DriverPresence {
+	if driverID == "" {
+		return nil
+	}
+	p, ok := g.presence[driverID]
+	if !ok {
+		p = &driverPresence{}
+		g.presence[driverID] = p
+	}
+	p.lastSeen = now
+	if connected != nil {
+		p.connected = *connected
+	}
+	return g.updatePresenceLocked(driverID, p, now)
+}
+
+func (g *Gateway) updatePresenceLocked(driverID string, p *driverPresence, now time.Time) *DriverPresence {
+	state := g.presenceStateLocked(p, now)
+	if state == p.state {
+		return nil
+	}
+	change := &DriverPresence{DriverID: driverID, State: state, Previous: p.state, Connected: p.connected, LastSeenAt: p.lastSeen}
+	p.state = state
+	return change
+}
+
+// touchPresence marks the driver as seen now. connected, when set, records
+// their socket session opening or closing.
+func (g *Gateway) touchPresence(driverID string, connected *bool) {
+	g.mu.Lock()
+	change := g.observePresenceLocked(driverID, time.Now(), connected)
+	g.mu.Unlock()
+	g.publishPresence(change)
+}
+
+// sweepPresence moves drivers whose TTLs ran out to their next state.
+func (g *Gateway) sweepPresence(now time.Time) {
+	g.mu.Lock()
+	var changes []*DriverPresence
+	for id, p := range g.presence {
+		if change := g.updatePresenceLocked(id, p, now); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	g.mu.Unlock()
+	for _, change := range changes {
+		g.publishPresence(change)
+	}
+}
+
+func (g *Gateway) publishPresence(change *DriverPresence) {
+	if change == nil {
+		return
+	}
+	g.logger.Info("driver presence changed", "driverId", change.DriverID, "state", change.State, "previous", change.Previous)
+	g.presenceFeed.publish(*change)
+}
+
+// WatchPresence re-evaluates driver presence every interval until ctx is
+// cancelled, so drivers that go quiet are marked idle, stale and offline.
+func (g *Gateway) WatchPresence(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				g.sweepPresence(now)
+			}
+		}
+	}()
+}
+
+// DriverPresenceHandler lists every known driver's presence.
+func (g *Gateway) DriverPresenceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	now := time.Now()
+	g.mu.Lock()
+	out := make([]DriverPresence, 0, len(g.presence))
+	for id, p := range g.presence {
+		out = append(out, DriverPresence{DriverID: id, State: g.presenceStateLocked(p, now), Connected: p.connected, LastSeenAt: p.lastSeen})
+	}
+	g.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].DriverID < out[j].DriverID })
+	writeJSON(w, http.StatusOK, out)
+}
+
+// DriverPresenceWatchHandler streams presence changes, over a WebSocket or
+// as Server-Sent Events.
+func (g *Gateway) DriverPresenceWatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	events, unsubscribe := g.presenceFeed.subscribe()
+	defer unsubscribe()
+	serveEvents(g, w, r, events, func(DriverPresence) string { return "driver_presence" })
+}
+
+// presenceFeed fans presence changes out to dashboard watchers.
+type presenceFeed struct {
+	mu   sync.Mutex
+	subs map[chan DriverPresence]struct{}
+}
+
+func newPresenceFeed() *presenceFeed {
+	return &presenceFeed{subs: make(map[chan DriverPresence]struct{})}
+}
+
+func (f *presenceFeed) subscribe() (chan DriverPresence, func()) {
+	ch := make(chan DriverPresence, 32)
+	f.mu.Lock()
+	f.subs[ch] = struct{}{}
+	f.mu.Unlock()
+	return ch, func() {
+		f.mu.Lock()
+		delete(f.subs, ch)
+		f.mu.Unlock()
+	}
+}
+
+func (f *presenceFeed) publish(ev DriverPresence) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Slow watchers miss changes; the list endpoint has the current state.
+		}
+	}
+}
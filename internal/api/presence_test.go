@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPresenceMovesThroughStatesAndPublishesChanges(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	gw.SetPresenceConfig(PresenceConfig{IdleAfter: time.Minute, StaleAfter: 5 * time.Minute, OfflineAfter: 15 * time.Minute})
+	events, unsubscribe := gw.presenceFeed.subscribe()
+	defer unsubscribe()
+
+	gw.touchPresence("driver-1", nil)
+	start := gw.presence["driver-1"].lastSeen
+	for _, step := range []struct {
+		after time.Duration
+		want  string
+	}{
+		{0, presenceOnline},
+		{2 * time.Minute, presenceIdle},
+		{6 * time.Minute, presenceStale},
+		{20 * time.Minute, presenceOffline},
+	} {
+		gw.sweepPresence(start.Add(step.after))
+		select {
+		case ev := <-events:
+			if ev.DriverID != "driver-1" || ev.State != step.want {
+				t.Fatalf("after %s expected %s, got %+v", step.after, step.want, ev)
+			}
+		default:
+			t.Fatalf("after %s expected a %s event", step.after, step.want)
+		}
+	}
+
+	connected := true
+	gw.touchPresence("driver-1", &connected)
+	gw.sweepPresence(time.Now().Add(time.Hour))
+	if ev := <-events; ev.State != presenceOnline || ev.Previous != presenceOffline || !ev.Connected {
+		t.Fatalf("expected the socket session to bring the driver back online, got %+v", ev)
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("a connected driver should stay online, got %+v", ev)
+	default:
+	}
+}
+
+func TestStaleDriversAreNotOffered(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	pickup := gw.pickupPoints[0]
+	station, _ := gw.stationByID(pickup.StationID)
+	route := Route{TargetStationIDs: []string{station.ID}}
+	gw.drivers = []Driver{
+		{ID: "driver-fresh", Name: "Fresh", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+		{ID: "driver-stale", Name: "Stale", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+		{ID: "driver-unseen", Name: "Unseen", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+	}
+	now := time.Now()
+	gw.presence["driver-fresh"] = &driverPresence{lastSeen: now}
+	gw.presence["driver-stale"] = &driverPresence{lastSeen: now.Add(-10 * time.Minute)}
+
+	gw.mu.Lock()
+	offered, skipped := gw.rankDriversLocked(station, &pickup, now, nil)
+	gw.mu.Unlock()
+	if len(offered) != 2 || indexOfAttempt(offered, "driver-stale") != -1 {
+		t.Fatalf("expected the stale driver to be left out, got %+v", offered)
+	}
+	if len(skipped) != 1 || skipped[0].Reason != "driver stale" {
+		t.Fatalf("expected the stale driver to be skipped with a reason, got %+v", skipped)
+	}
+
+	rec := httptest.NewRecorder()
+	gw.DriverPresenceHandler(rec, httptest.NewRequest(http.MethodGet, "/drivers/presence", nil))
+	var listed []DriverPresence
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(listed) != 2 || listed[0].DriverID != "driver-fresh" || listed[0].State != presenceOnline || listed[1].State != presenceStale {
+		t.Fatalf("unexpected presence list %+v", listed)
+	}
+
+	snapshot := gw.Snapshot()
+	for _, d := range snapshot.Drivers {
+		if d.ID == "driver-unseen" && (d.Presence != "" || d.LastSeenAt != nil) {
+			t.Fatalf("expected no presence for a driver never heard from, got %+v", d)
+		}
+		if d.ID == "driver-stale" && d.Presence != presenceStale {
+			t.Fatalf("expected the snapshot to carry presence, got %+v", d)
+		}
+	}
+}
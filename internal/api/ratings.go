@@ -0,0 +1,351 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"lastmile/internal/pkg/matchpolicy"
+)
+
+// After a trip is completed the driver rates the rider and the rider rates
+// the driver, 1 to 5 stars with optional tags and a comment. Each party
+// rates a trip once.
+const (
+	ratingRoleDriver = "driver"
+	ratingRoleRider  = "rider"
+
+	maxRatingTags        = 5
+	maxRatingTagLength   = 32
+	maxRatingComment     = 500
+	maxRatingsPerListing = 50
+)
+
+// DefaultRatingRule down-ranks drivers averaging under four stars once they
+// have three ratings; nobody is excluded unless configured.
+var DefaultRatingRule = matchpolicy.RatingRule{MinRatings: 3, DownRankBelow: 4, Weight: 1}
+
+// Tags offered in rating prompts. Raters may send their own as well.
+var (
+	driverRatingTags = []string{"punctual", "safe driving", "clean car", "friendly", "late", "rude"}
+	riderRatingTags  = []string{"on time", "polite", "kept driver waiting", "no show"}
+)
+
+// Rating is one party's verdict on the other after a trip.
+type Rating struct {
+	TripID    string    `json:"tripId"`
+	RaterID   string    `json:"raterId"`
+	RaterRole string    `json:"raterRole"`
+	SubjectID string    `json:"subjectId"`
+	Stars     int       `json:"stars"`
+	Tags      []string  `json:"tags,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RatingSummary is the average of the stars a driver or rider received.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// RatingPrompt asks one party of a completed trip to rate the other.
+type RatingPrompt struct {
+	TripID      string   `json:"tripId"`
+	RaterID     string   `json:"raterId"`
+	RaterRole   string   `json:"raterRole"`
+	SubjectID   string   `json:"subjectId"`
+	SubjectName string   `json:"subjectName"`
+	Tags        []string `json:"tags"`
+}
+
+type ratingRequest struct {
+	TripID  string   `json:"tripId"`
+	RaterID string   `json:"raterId"`
+	Stars   int      `json:"stars"`
+	Tags    []string `json:"tags"`
+	Comment string   `json:"comment"`
+}
+
+type ratingsResponse struct {
+	Summary *RatingSummary `json:"summary,omitempty"`
+	Ratings []Rating       `json:"ratings"`
+}
+
+type ratingTotal struct {
+	stars int
+	count int
+}
+
+// ratingSubjectKey keeps driver and rider totals apart.
+func ratingSubjectKey(role, id string) string {
+	return role + ":" + id
+}
+
+// subjectRole is the role of whoever a rater with raterRole rates.
+func subjectRole(raterRole string) string {
+	if raterRole == ratingRoleDriver {
+		return ratingRoleRider
+	}
+	return ratingRoleDriver
+}
+
+// SetRatingRule sets how drivers' ratings affect matching.
+func (g *Gateway) SetRatingRule(rule matchpolicy.RatingRule) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ratingRule = rule
+}
+
+// recordRatingLocked stores r and adds it to its subject's totals.
+func (g *Gateway) recordRatingLocked(r Rating) {
+	g.ratings[r.TripID+"/"+r.RaterID] = r
+	key := ratingSubjectKey(subjectRole(r.RaterRole), r.SubjectID)
+	total := g.ratingTotals[key]
+	if total == nil {
+		total = &ratingTotal{}
+		g.ratingTotals[key] = total
+	}
+	total.stars += r.Stars
+	total.count++
+}
+
+// ratingSummaryLocked returns the subject's average, or nil before their
+// first rating.
+func (g *Gateway) ratingSummaryLocked(role, id string) *RatingSummary {
+	total := g.ratingTotals[ratingSubjectKey(role, id)]
+	if total == nil || total.count == 0 {
+		return nil
+	}
+	avg := float64(total.stars) / float64(total.count)
+	return &RatingSummary{Average: math.Round(avg*100) / 100, Count: total.count}
+}
+
+// driverRatedOutLocked reports whether the rating rule keeps the driver
+// from being matched.
+func (g *Gateway) driverRatedOutLocked(driverID string) bool {
+	summary := g.ratingSummaryLocked(ratingRoleDriver, driverID)
+	if summary == nil {
+		return false
+	}
+	return g.ratingRule.Excludes(matchpolicy.Candidate{DriverID: driverID, Rating: summary.Average, Ratings: summary.Count})
+}
+
+// normaliseRatingTags lower-cases, trims and de-duplicates tags.
+func normaliseRatingTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || slices.Contains(out, tag) {
+			continue
+		}
+		if len(tag) > maxRatingTagLength {
+			return nil, fmt.Errorf("tags must be at most %d characters", maxRatingTagLength)
+		}
+		out = append(out, tag)
+	}
+	if len(out) > maxRatingTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxRatingTags)
+	}
+	return out, nil
+}
+
+// submitRating records the rater's rating of the other party of a
+// completed trip. The returned status is the HTTP status for a failure.
+func (g *Gateway) submitRating(req ratingRequest, now time.Time) (Rating, int, error) {
+	tripID, raterID := strings.TrimSpace(req.TripID), strings.TrimSpace(req.RaterID)
+	if tripID == "" || raterID == "" {
+		return Rating{}, http.StatusBadRequest, errors.New("tripId and raterId are required")
+	}
+	if req.Stars < 1 || req.Stars > 5 {
+		return Rating{}, http.StatusBadRequest, errors.New("stars must be between 1 and 5")
+	}
+	tags, err := normaliseRatingTags(req.Tags)
+	if err != nil {
+		return Rating{}, http.StatusBadRequest, err
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if len(comment) > maxRatingComment {
+		return Rating{}, http.StatusBadRequest, fmt.Errorf("comment must be at most %d characters", maxRatingComment)
+	}
+
+	trip, err := g.ratedTrip(tripID)
+	if err != nil {
+		g.logger.Error("load trip for rating failed", "tripId", tripID, "err", err)
+		return Rating{}, http.StatusServiceUnavailable, errors.New("trip lookup unavailable")
+	}
+	if trip == nil {
+		return Rating{}, http.StatusNotFound, fmt.Errorf("trip '%s' not found", tripID)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if trip.Status != "completed" {
+		return Rating{}, http.StatusConflict, errors.New("trips can only be rated once completed")
+	}
+	rating := Rating{TripID: tripID, RaterID: raterID, Stars: req.Stars, Tags: tags, Comment: comment, CreatedAt: now.UTC()}
+	switch raterID {
+	case trip.DriverID:
+		rating.RaterRole, rating.SubjectID = ratingRoleDriver, trip.RiderID
+	case trip.RiderID:
+		rating.RaterRole, rating.SubjectID = ratingRoleRider, trip.DriverID
+	default:
+		return Rating{}, http.StatusForbidden, errors.New("only the trip's driver and rider can rate it")
+	}
+	if _, ok := g.ratings[tripID+"/"+raterID]; ok {
+		return Rating{}, http.StatusConflict, errors.New("trip already rated")
+	}
+	g.recordRatingLocked(rating)
+	if g.store != nil {
+		go func() {
+			if err := g.store.SaveRating(rating); err != nil {
+				g.logger.Warn("save rating failed, kept in memory only", "tripId", rating.TripID, "raterId", rating.RaterID, "err", err)
+			}
+		}()
+	}
+	g.logger.Info("trip rated", "tripId", tripID, "raterId", raterID, "role", rating.RaterRole, "stars", rating.Stars)
+	return rating, 0, nil
+}
+
+// ratedTrip finds a trip among the gateway's own, then in the store for
+// trips it no longer holds, such as those from before a restart. It returns
+// nil when neither knows the trip.
+func (g *Gateway) ratedTrip(tripID string) (*Trip, error) {
+	g.mu.Lock()
+	for i := range g.trips {
+		if g.trips[i].ID == tripID {
+			trip := g.trips[i]
+			g.mu.Unlock()
+			return &trip, nil
+		}
+	}
+	g.mu.Unlock()
+	if g.store == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return g.store.LoadTrip(ctx, tripID)
+}
+
+// ratingsForLocked lists the ratings of one trip, or those a subject
+// received, newest first.
+func (g *Gateway) ratingsForLocked(tripID, role, subjectID string) []Rating {
+	out := make([]Rating, 0)
+	for _, r := range g.ratings {
+		switch {
+		case tripID != "" && r.TripID != tripID:
+			continue
+		case subjectID != "" && (r.SubjectID != subjectID || subjectRole(r.RaterRole) != role):
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	if len(out) > maxRatingsPerListing {
+		out = out[:maxRatingsPerListing]
+	}
+	return out
+}
+
+// ratingPromptsLocked builds the prompts for both parties of a completed trip.
+func (g *Gateway) ratingPromptsLocked(trip Trip) []RatingPrompt {
+	driverName := "Driver"
+	for i := range g.drivers {
+		if g.drivers[i].ID == trip.DriverID && g.drivers[i].Name != "" {
+			driverName = g.drivers[i].Name
+		}
+	}
+	riderName := "Rider"
+	if rider, err := g.findRiderByID(trip.RiderID); err == nil {
+		riderName = g.riderDisplayName(rider)
+	}
+	return []RatingPrompt{
+		{TripID: trip.ID, RaterID: trip.DriverID, RaterRole: ratingRoleDriver, SubjectID: trip.RiderID, SubjectName: riderName, Tags: riderRatingTags},
+		{TripID: trip.ID, RaterID: trip.RiderID, RaterRole: ratingRoleRider, SubjectID: trip.DriverID, SubjectName: driverName, Tags: driverRatingTags},
+	}
+}
+
+// sendRatingPrompts asks each rater through their live session, falling
+// back to a push notification for those who are not connected.
+func (g *Gateway) sendRatingPrompts(prompts []RatingPrompt) {
+	missed := prompts
+	if g.hub != nil {
+		missed = g.hub.RequestTripRatings(prompts)
+	}
+	for _, p := range missed {
+		g.sendPushNotification(p.RaterID, "How was your trip?", fmt.Sprintf("Rate your trip with %s", p.SubjectName), map[string]any{
+			"type":      "rating-request",
+			"tripId":    p.TripID,
+			"subjectId": p.SubjectID,
+		})
+	}
+}
+
+// LoadRatings restores stored ratings and their averages.
+func (g *Gateway) LoadRatings(ctx context.Context) error {
+	if g.store == nil {
+		return nil
+	}
+	ratings, err := g.store.LoadRatings(ctx)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, r := range ratings {
+		if _, ok := g.ratings[r.TripID+"/"+r.RaterID]; !ok {
+			g.recordRatingLocked(r)
+		}
+	}
+	g.logger.Info("ratings restored", "count", len(ratings))
+	return nil
+}
+
+// TripRatingsHandler takes and lists trip ratings. POST {tripId, raterId,
+// stars, tags, comment} once the trip is completed. GET ?tripId= lists a
+// trip's ratings; ?driverId= or ?riderId= lists the ratings they received
+// along with their average.
+func (g *Gateway) TripRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		tripID := strings.TrimSpace(q.Get("tripId"))
+		role, subjectID := ratingRoleDriver, strings.TrimSpace(q.Get("driverId"))
+		if subjectID == "" {
+			role, subjectID = ratingRoleRider, strings.TrimSpace(q.Get("riderId"))
+		}
+		if tripID == "" && subjectID == "" {
+			http.Error(w, "tripId, driverId or riderId is required", http.StatusBadRequest)
+			return
+		}
+		g.mu.Lock()
+		resp := ratingsResponse{Ratings: g.ratingsForLocked(tripID, role, subjectID)}
+		if subjectID != "" {
+			resp.Summary = g.ratingSummaryLocked(role, subjectID)
+		}
+		g.mu.Unlock()
+		writeJSON(w, http.StatusOK, resp)
+	case http.MethodPost:
+		var payload ratingRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		rating, code, err := g.submitRating(payload, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), code)
+			return
+		}
+		writeJSON(w, http.StatusOK, rating)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
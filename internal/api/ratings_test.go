@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lastmile/internal/pkg/matchpolicy"
+)
+
+func rate(gw *Gateway, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	gw.TripRatingsHandler(rec, httptest.NewRequest(http.MethodPost, "/trips/ratings", strings.NewReader(body)))
+	return rec
+}
+
+func TestTripRatingsAfterCompletion(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	gw.drivers = []Driver{{ID: "driver-rated", Name: "Ravi"}}
+	gw.trips = []Trip{{ID: "trip-rated", DriverID: "driver-rated", RiderID: "rider-priya", Status: "in_progress"}}
+
+	if rec := rate(gw, `{"tripId":"trip-rated","raterId":"rider-priya","stars":4}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 before the trip is completed, got %d", rec.Code)
+	}
+	if _, err := gw.completeTrip("trip-rated"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	for body, want := range map[string]int{
+		`{"tripId":"trip-rated","raterId":"rider-priya","stars":6}`:   http.StatusBadRequest,
+		`{"tripId":"trip-rated","raterId":"rider-rahul","stars":5}`:   http.StatusForbidden,
+		`{"tripId":"trip-missing","raterId":"rider-priya","stars":5}`: http.StatusNotFound,
+	} {
+		if rec := rate(gw, body); rec.Code != want {
+			t.Fatalf("expected %d for %s, got %d", want, body, rec.Code)
+		}
+	}
+
+	rec := rate(gw, `{"tripId":"trip-rated","raterId":"rider-priya","stars":2,"tags":["Late ","late","rude"],"comment":"took a long detour"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var rating Rating
+	if err := json.NewDecoder(rec.Body).Decode(&rating); err != nil || rating.SubjectID != "driver-rated" || rating.RaterRole != ratingRoleRider || strings.Join(rating.Tags, ",") != "late,rude" {
+		t.Fatalf("expected the rider's rating of the driver, got %+v (%v)", rating, err)
+	}
+	if rec := rate(gw, `{"tripId":"trip-rated","raterId":"rider-priya","stars":5}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 when rating twice, got %d", rec.Code)
+	}
+	if rec := rate(gw, `{"tripId":"trip-rated","raterId":"driver-rated","stars":5,"tags":["on time"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected the driver to rate the rider, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	snap := gw.Snapshot()
+	if got := snap.Drivers[0].Rating; got == nil || got.Average != 2 || got.Count != 1 {
+		t.Fatalf("expected the driver averaging 2 stars, got %+v", got)
+	}
+	for _, r := range snap.Riders {
+		if r.ID == "rider-priya" && (r.Rating == nil || r.Rating.Average != 5) {
+			t.Fatalf("expected the rider averaging 5 stars, got %+v", r.Rating)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	gw.TripRatingsHandler(rec, httptest.NewRequest(http.MethodGet, "/trips/ratings?driverId=driver-rated", nil))
+	var listed ratingsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&listed); err != nil || len(listed.Ratings) != 1 || listed.Summary == nil || listed.Summary.Count != 1 {
+		t.Fatalf("expected one rating of the driver, got %+v (%v)", listed, err)
+	}
+}
+
+func TestLowRatedDriversAreDownRankedOrExcluded(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	pickup := gw.pickupPoints[0]
+	station, _ := gw.stationByID(pickup.StationID)
+	route := Route{TargetStationIDs: []string{station.ID}}
+	gw.drivers = []Driver{
+		{ID: "driver-poor", Name: "Poor", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude, Longitude: pickup.Longitude},
+		{ID: "driver-good", Name: "Good", SeatsAvailable: 2, Route: route, Latitude: pickup.Latitude + 0.005, Longitude: pickup.Longitude},
+	}
+	for i, stars := range []int{3, 3, 3, 2} {
+		gw.recordRatingLocked(Rating{TripID: "trip-" + string(rune('a'+i)), RaterID: "rider", RaterRole: ratingRoleRider, SubjectID: "driver-poor", Stars: stars})
+	}
+
+	gw.mu.Lock()
+	offered, _ := gw.rankDriversLocked(station, &pickup, time.Now(), nil)
+	gw.mu.Unlock()
+	if len(offered) != 2 || offered[0].DriverID != "driver-good" {
+		t.Fatalf("expected the closer but poorly rated driver ranked last, got %+v", offered)
+	}
+	if f := offered[1].ScoreBreakdown; f[len(f)-1].Name != matchpolicy.FactorRating {
+		t.Fatalf("expected the rating penalty in the breakdown, got %+v", f)
+	}
+
+	gw.SetRatingRule(matchpolicy.RatingRule{MinRatings: 3, ExcludeBelow: 3})
+	gw.mu.Lock()
+	offered, skipped := gw.rankDriversLocked(station, &pickup, time.Now(), nil)
+	gw.mu.Unlock()
+	if len(offered) != 1 || offered[0].DriverID != "driver-good" {
+		t.Fatalf("expected only the well rated driver offered, got %+v", offered)
+	}
+	if len(skipped) != 1 || skipped[0].Reason != "rating too low" {
+		t.Fatalf("expected the poorly rated driver skipped for their rating, got %+v", skipped)
+	}
+}
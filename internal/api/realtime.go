@@ -60,18 +60,33 @@ type pendingApproval struct {
 	timer   *time.Timer
 }
 
+// tripRoom is the realtime room of one pooled journey, keyed by journey id.
+// Only the driver joins its socket room; riders are sent events about their
+// own leg directly so they never see the other riders on board.
 type tripRoom struct {
 	id         string
 	driverID   string
 	driverName string
-	riderID    string
-	riderName  string
-	pickup     *PickupPoint
 	station    *Station
 	status     string
+	legs       map[string]*roomLeg
 	updatedAt  time.Time
 	lastLat    float64
 	lastLon    float64
+	// etaMinutes is the driver's estimate to their next stop.
+	etaMinutes int
+}
+
+// roomLeg is one rider's leg within a tripRoom, keyed by the rider's trip id.
+type roomLeg struct {
+	tripID    string
+	riderID   string
+	riderName string
+	pickup    *PickupPoint
+	status    string
+	// etaMinutes is the estimate to this rider's pickup, or to the station
+	// once they are on board.
+	etaMinutes int
 }
 
 type sessionInit struct {
@@ -88,6 +103,7 @@ type driverResponsePayload struct {
 
 type tripStatusPayload struct {
 	TripID      string          `json:"tripId"`
+	JourneyID   string          `json:"journeyId,omitempty"`
 	Status      string          `json:"status"`
 	DriverID    string          `json:"driverId"`
 	RiderID     string          `json:"riderId"`
@@ -96,10 +112,12 @@ type tripStatusPayload struct {
 	LastLat     float64         `json:"latitude,omitempty"`
 	LastLon     float64         `json:"longitude,omitempty"`
 	RecordedAt  time.Time       `json:"recordedAt"`
+	ETAMinutes  int             `json:"etaMinutes,omitempty"`
 	Description string          `json:"description,omitempty"`
 	Trip        *Trip           `json:"trip,omitempty"`
 	Rider       *Rider          `json:"rider,omitempty"`
 	Attempts    []driverAttempt `json:"attempts,omitempty"`
+	Itinerary   *Journey        `json:"itinerary,omitempty"`
 }
 
 type riderApprovalPayload struct {
@@ -200,6 +218,7 @@ func (h *RealtimeHub) registerHandlers() {
 		if driverID == "" {
 			return
 		}
+		h.touchDriver(driverID, true)
 		h.handleDriverResponse(driverID, payload)
 	})
 
@@ -259,6 +278,7 @@ func (h *RealtimeHub) registerDriver(conn socketio.Conn, payload sessionInit) {
 		"role":   "driver",
 		"userId": payload.UserID,
 	})
+	h.touchDriver(payload.UserID, true)
 
 	go h.RefreshDriverQueue(payload.UserID)
 }
@@ -274,10 +294,13 @@ func (h *RealtimeHub) registerRider(conn socketio.Conn, payload sessionInit) {
 	h.mu.Lock()
 	h.riders[payload.UserID] = session
 
-	// Re-join active rooms
+	// Catch up on the rider's active legs
+	var legs []tripStatusPayload
 	for _, room := range h.rooms {
-		if room.riderID == payload.UserID && room.status != "completed" {
-			conn.Join(roomSocket(room.id))
+		for _, leg := range room.legs {
+			if leg.riderID == payload.UserID && !legFinished(leg.status) {
+				legs = append(legs, room.legPayload(leg))
+			}
 		}
 	}
 	h.mu.Unlock()
@@ -286,6 +309,9 @@ func (h *RealtimeHub) registerRider(conn socketio.Conn, payload sessionInit) {
 		"role":   "rider",
 		"userId": payload.UserID,
 	})
+	for _, leg := range legs {
+		conn.Emit("trip:status", leg)
+	}
 
 	go h.deliverPendingApprovals(payload.UserID)
 }
@@ -296,6 +322,7 @@ func (h *RealtimeHub) removeConn(conn socketio.Conn) {
 		if session.conn == conn {
 			delete(h.drivers, id)
 			h.mu.Unlock()
+			h.touchDriver(id, false)
 			return
 		}
 	}
@@ -323,6 +350,17 @@ func (h *RealtimeHub) removeConn(conn socketio.Conn) {
 	}
 }
 
+// touchDriver reports socket activity from the driver to the gateway's
+// presence tracking.
+func (h *RealtimeHub) touchDriver(driverID string, connected bool) {
+	h.mu.Lock()
+	gw := h.gateway
+	h.mu.Unlock()
+	if gw != nil {
+		gw.touchPresence(driverID, &connected)
+	}
+}
+
 func (h *RealtimeHub) driverIDForConn(conn socketio.Conn) string {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -366,6 +404,13 @@ func (h *RealtimeHub) handleDriverResponse(driverID string, payload driverRespon
 	queue.waiting = ""
 	h.pending[payload.RiderID] = queue
 	h.mu.Unlock()
+	if h.gateway != nil {
+		reason := payload.Reason
+		if reason == "" {
+			reason = "driver_declined"
+		}
+		h.gateway.publishMatchEvent(matchEventRejected, queue.rider.StationID, driverID, payload.RiderID, nil, reason)
+	}
 	go h.dispatchNext(queue)
 }
 
@@ -374,6 +419,9 @@ func (h *RealtimeHub) dispatchNext(queue *pendingQueue) {
 	if queue.index >= len(queue.attempts) {
 		delete(h.pending, queue.rider.ID)
 		h.mu.Unlock()
+		if h.gateway != nil {
+			h.gateway.publishMatchEvent(matchEventRejected, queue.rider.StationID, "", queue.rider.ID, nil, "no_drivers")
+		}
 		h.notifyRiderStatus(queue.rider.ID, tripStatusPayload{
 			RiderID:    queue.rider.ID,
 			Status:     "no_drivers",
@@ -424,6 +472,9 @@ func (h *RealtimeHub) handleDriverTimeout(riderID, driverID string) {
 	queue.timer = nil
 	h.pending[riderID] = queue
 	h.mu.Unlock()
+	if h.gateway != nil {
+		h.gateway.publishMatchEvent(matchEventRejected, queue.rider.StationID, driverID, riderID, nil, "driver_timeout")
+	}
 	go h.dispatchNext(queue)
 }
 
@@ -466,112 +517,238 @@ func (h *RealtimeHub) startTripRoom(driverID string, queue *pendingQueue) {
 		return
 	}
 
-	room := &tripRoom{
-		id:         trip.ID,
-		driverID:   driverID,
-		driverName: queue.attempts[queue.index].DriverName,
-		riderID:    trip.RiderID,
-		riderName:  queue.rider.Name,
-		pickup:     queue.pickup,
-		station:    queue.station,
-		status:     "awaiting_pickup",
-		updatedAt:  time.Now(),
-	}
+	rider := queue.rider
 	h.mu.Lock()
-	h.rooms[room.id] = room
+	room, leg := h.addLegLocked(trip, &rider, queue.pickup, queue.station, "awaiting_pickup")
+	room.driverName = queue.attempts[queue.index].DriverName
+	riderPayload := room.legPayload(leg)
+	driverPayload := room.driverPayload()
 	h.mu.Unlock()
 
+	riderPayload.Trip = &trip
+	riderPayload.Rider = &rider
+	driverPayload.RiderID = trip.RiderID
+	driverPayload.Pickup = queue.pickup
+	driverPayload.Trip = &trip
+	driverPayload.Rider = &rider
+	driverPayload.Itinerary = h.itinerary(room.id)
+
 	h.joinRoom(driverID, room.id)
-	h.joinRiderRoom(trip.RiderID, room.id)
+	h.emitToDriver(driverID, "trip:room-created", driverPayload)
+	h.notifyRiderStatus(trip.RiderID, riderPayload)
+}
+
+// addLegLocked records the trip as a leg of its journey's room, opening the
+// room when this is the journey's first leg.
+func (h *RealtimeHub) addLegLocked(trip Trip, rider *Rider, pickup *PickupPoint, station *Station, status string) (*tripRoom, *roomLeg) {
+	roomID := trip.JourneyID
+	if roomID == "" {
+		roomID = trip.ID
+	}
+	room, ok := h.rooms[roomID]
+	if !ok {
+		room = &tripRoom{
+			id:       roomID,
+			driverID: trip.DriverID,
+			station:  station,
+			legs:     make(map[string]*roomLeg),
+		}
+		h.rooms[roomID] = room
+	}
+	if room.station == nil {
+		room.station = station
+	}
+	leg, ok := room.legs[trip.ID]
+	if !ok {
+		leg = &roomLeg{tripID: trip.ID, riderID: trip.RiderID}
+		room.legs[trip.ID] = leg
+	}
+	if rider != nil {
+		leg.riderName = rider.Name
+	}
+	if pickup != nil {
+		leg.pickup = pickup
+	}
+	if trip.ETAMinutes > 0 {
+		leg.etaMinutes = trip.ETAMinutes
+	}
+	leg.status = status
+	room.refreshStatus()
+	return room, leg
+}
+
+// refreshStatus derives the room's status from its legs: completed once every
+// leg has finished, in progress while anyone is on board.
+func (room *tripRoom) refreshStatus() {
+	room.updatedAt = time.Now()
+	status := "completed"
+	for _, leg := range room.legs {
+		switch {
+		case leg.status == "in_progress":
+			room.status = "in_progress"
+			return
+		case !legFinished(leg.status):
+			status = "awaiting_pickup"
+		}
+	}
+	room.status = status
+}
 
-	payload := tripStatusPayload{
-		TripID:     trip.ID,
+func (room *tripRoom) legPayload(leg *roomLeg) tripStatusPayload {
+	return tripStatusPayload{
+		TripID:     leg.tripID,
+		JourneyID:  room.id,
+		Status:     leg.status,
+		DriverID:   room.driverID,
+		RiderID:    leg.riderID,
+		Pickup:     leg.pickup,
+		Station:    room.station,
+		LastLat:    room.lastLat,
+		LastLon:    room.lastLon,
+		RecordedAt: time.Now(),
+		ETAMinutes: leg.etaMinutes,
+	}
+}
+
+// driverPayload describes the whole journey; drivers see one room per
+// journey rather than one per rider.
+func (room *tripRoom) driverPayload() tripStatusPayload {
+	return tripStatusPayload{
+		TripID:     room.id,
+		JourneyID:  room.id,
 		Status:     room.status,
-		DriverID:   driverID,
-		RiderID:    trip.RiderID,
-		Pickup:     queue.pickup,
-		Station:    queue.station,
+		DriverID:   room.driverID,
+		Station:    room.station,
+		LastLat:    room.lastLat,
+		LastLon:    room.lastLon,
 		RecordedAt: time.Now(),
-		Trip:       &trip,
-		Rider:      &queue.rider,
+		ETAMinutes: room.etaMinutes,
 	}
-	h.emitToDriver(driverID, "trip:room-created", payload)
-	h.notifyRiderStatus(trip.RiderID, payload)
 }
 
-func (h *RealtimeHub) BroadcastLocation(driverID string, lat, lon float64) {
+func (h *RealtimeHub) itinerary(journeyID string) *Journey {
+	if h.gateway == nil {
+		return nil
+	}
+	return h.gateway.journeySnapshot(journeyID)
+}
+
+// BroadcastLocation pushes the driver's position and fresh ETAs to their
+// journey and to each rider still on it.
+func (h *RealtimeHub) BroadcastLocation(driverID string, lat, lon float64, etas driverETAs) {
 	h.mu.Lock()
-	rooms := make([]*tripRoom, 0, len(h.rooms))
+	var driverPayloads, riderPayloads []tripStatusPayload
 	for _, room := range h.rooms {
-		if room.driverID == driverID && room.status != "completed" {
-			room.lastLat = lat
-			room.lastLon = lon
-			room.updatedAt = time.Now()
-			rooms = append(rooms, room)
+		if room.driverID != driverID || room.status == "completed" {
+			continue
+		}
+		room.lastLat = lat
+		room.lastLon = lon
+		room.etaMinutes = etas.Next
+		room.updatedAt = time.Now()
+		driverPayloads = append(driverPayloads, room.driverPayload())
+		for _, leg := range room.legs {
+			if legFinished(leg.status) {
+				continue
+			}
+			if minutes, ok := etas.Trips[leg.tripID]; ok {
+				leg.etaMinutes = minutes
+			}
+			riderPayloads = append(riderPayloads, room.legPayload(leg))
 		}
 	}
 	h.mu.Unlock()
 
-	for _, room := range rooms {
-		h.server.BroadcastToRoom("/", roomSocket(room.id), "trip:location", tripStatusPayload{
-			TripID:     room.id,
-			Status:     room.status,
-			DriverID:   room.driverID,
-			RiderID:    room.riderID,
-			Pickup:     room.pickup,
-			Station:    room.station,
-			LastLat:    lat,
-			LastLon:    lon,
-			RecordedAt: time.Now(),
-		})
+	for _, payload := range driverPayloads {
+		h.server.BroadcastToRoom("/", roomSocket(payload.JourneyID), "trip:location", payload)
+	}
+	for _, payload := range riderPayloads {
+		h.emitToRider(payload.RiderID, "trip:location", payload)
 	}
 }
 
+// PickupArrived starts every leg the driver picks up at pickupID.
 func (h *RealtimeHub) PickupArrived(driverID string, pickupID string) {
+	h.updateLegs(func(room *tripRoom, leg *roomLeg) bool {
+		return room.driverID == driverID && leg.pickup != nil && leg.pickup.ID == pickupID &&
+			(leg.status == "awaiting_pickup" || leg.status == "pending")
+	}, "in_progress", "")
+}
+
+// LegPickedUp starts a single rider's leg.
+func (h *RealtimeHub) LegPickedUp(tripID string) {
+	h.updateLegs(func(_ *tripRoom, leg *roomLeg) bool {
+		return leg.tripID == tripID && !legFinished(leg.status)
+	}, "in_progress", "")
+}
+
+// updateLegs moves every matching leg to status, then tells each affected
+// rider about their own leg and each affected driver about the journey.
+func (h *RealtimeHub) updateLegs(match func(*tripRoom, *roomLeg) bool, status, description string) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
+	var riderPayloads []tripStatusPayload
+	touched := make(map[string]*tripRoom)
 	for _, room := range h.rooms {
-		if room.driverID != driverID || room.pickup == nil || room.pickup.ID != pickupID {
-			continue
-		}
-		if room.status == "awaiting_pickup" || room.status == "pending" {
-			room.status = "in_progress"
-			h.server.BroadcastToRoom("/", roomSocket(room.id), "trip:status", tripStatusPayload{
-				TripID:     room.id,
-				Status:     room.status,
-				DriverID:   room.driverID,
-				RiderID:    room.riderID,
-				Pickup:     room.pickup,
-				Station:    room.station,
-				RecordedAt: time.Now(),
-			})
+		for _, leg := range room.legs {
+			if !match(room, leg) {
+				continue
+			}
+			leg.status = status
+			payload := room.legPayload(leg)
+			payload.Description = description
+			riderPayloads = append(riderPayloads, payload)
+			touched[room.id] = room
 		}
 	}
+	driverPayloads := make([]tripStatusPayload, 0, len(touched))
+	for _, room := range touched {
+		room.refreshStatus()
+		payload := room.driverPayload()
+		payload.Description = description
+		driverPayloads = append(driverPayloads, payload)
+	}
+	h.mu.Unlock()
+
+	for _, payload := range riderPayloads {
+		h.emitToRider(payload.RiderID, "trip:status", payload)
+	}
+	for _, payload := range driverPayloads {
+		payload.Itinerary = h.itinerary(payload.JourneyID)
+		h.server.BroadcastToRoom("/", roomSocket(payload.JourneyID), "trip:status", payload)
+	}
 }
 
+// completeTripRoom drops off a single leg when given its trip id, or every
+// remaining leg of the journey when given the room id.
 func (h *RealtimeHub) completeTripRoom(tripID, reason string) {
 	h.mu.Lock()
-	room, ok := h.rooms[tripID]
-	if ok {
-		room.status = "completed"
+	var legIDs []string
+	if room, ok := h.rooms[tripID]; ok {
+		for _, leg := range room.legs {
+			if !legFinished(leg.status) {
+				legIDs = append(legIDs, leg.tripID)
+			}
+		}
+	} else {
+		legIDs = append(legIDs, tripID)
 	}
 	h.mu.Unlock()
 
-	if ok {
-		h.server.BroadcastToRoom("/", roomSocket(tripID), "trip:status", tripStatusPayload{
-			TripID:      tripID,
-			Status:      "completed",
-			DriverID:    room.driverID,
-			RiderID:     room.riderID,
-			RecordedAt:  time.Now(),
-			Description: reason,
-		})
-	}
 	if h.gateway != nil {
-		if _, err := h.gateway.completeTrip(tripID); err != nil {
-			h.logger.Warn("completeTripRoom failed", "tripId", tripID, "err", err)
+		for _, id := range legIDs {
+			if _, err := h.gateway.completeTrip(id); err != nil {
+				h.logger.Warn("completeTripRoom failed", "tripId", id, "err", err)
+			}
 		}
 	}
+
+	completed := make(map[string]bool, len(legIDs))
+	for _, id := range legIDs {
+		completed[id] = true
+	}
+	h.updateLegs(func(_ *tripRoom, leg *roomLeg) bool {
+		return completed[leg.tripID] && leg.status != "completed"
+	}, "completed", reason)
 }
 
 func (h *RealtimeHub) notifyRiderStatus(riderID string, payload tripStatusPayload) {
@@ -638,15 +815,6 @@ func (h *RealtimeHub) joinRoom(driverID, tripID string) {
 	}
 }
 
-func (h *RealtimeHub) joinRiderRoom(riderID, tripID string) {
-	h.mu.Lock()
-	session := h.riders[riderID]
-	h.mu.Unlock()
-	if session != nil {
-		session.conn.Join(roomSocket(tripID))
-	}
-}
-
 func (h *RealtimeHub) RefreshDriverQueue(driverID string) {
 	if h.gateway == nil {
 		return
@@ -724,12 +892,63 @@ func (h *RealtimeHub) popApproval(tripID string) *pendingApproval {
 }
 
 func (h *RealtimeHub) NotifyDriverTripCancelled(driverID, tripID, reason string) {
+	h.mu.Lock()
+	for id, room := range h.rooms {
+		if _, ok := room.legs[tripID]; !ok {
+			continue
+		}
+		delete(room.legs, tripID)
+		if len(room.legs) == 0 {
+			delete(h.rooms, id)
+		} else {
+			room.refreshStatus()
+		}
+		break
+	}
+	h.mu.Unlock()
 	h.emitToDriver(driverID, "driver:trip-cancelled", map[string]string{
 		"tripId": tripID,
 		"reason": reason,
 	})
 }
 
+// NotifyRouteActivated tells the driver a scheduled route has been started
+// for them and by when they need to set off.
+func (h *RealtimeHub) NotifyRouteActivated(driverID, scheduleID string, plan driverRouteResponse, startBy time.Time) {
+	h.emitToDriver(driverID, "driver:route-activated", map[string]any{
+		"scheduleId": scheduleID,
+		"plan":       plan,
+		"startBy":    startBy.UTC(),
+	})
+}
+
+// NotifyRouteExpired tells the driver their scheduled route was stopped
+// because they never set off.
+func (h *RealtimeHub) NotifyRouteExpired(driverID, scheduleID string) {
+	h.emitToDriver(driverID, "driver:route-expired", map[string]string{
+		"scheduleId": scheduleID,
+	})
+}
+
+// RequestTripRatings sends each prompt to its rater as trip:rating-request
+// and returns the prompts whose rater has no live session.
+func (h *RealtimeHub) RequestTripRatings(prompts []RatingPrompt) []RatingPrompt {
+	var missed []RatingPrompt
+	for _, p := range prompts {
+		if p.RaterRole == ratingRoleDriver {
+			if session := h.driverSession(p.RaterID); session != nil {
+				session.conn.Emit("trip:rating-request", p)
+				continue
+			}
+		} else if session := h.riderSession(p.RaterID); session != nil {
+			session.conn.Emit("trip:rating-request", p)
+			continue
+		}
+		missed = append(missed, p)
+	}
+	return missed
+}
+
 func (h *RealtimeHub) ClearApproval(tripID string) {
 	h.popApproval(tripID)
 }
@@ -738,41 +957,27 @@ func (h *RealtimeHub) CreateRoomForTrip(trip Trip, pickup *PickupPoint, station
 	if h == nil {
 		return
 	}
-	room := &tripRoom{
-		id:         trip.ID,
-		driverID:   trip.DriverID,
-		driverName: "",
-		riderID:    trip.RiderID,
-		riderName:  riderName(rider),
-		pickup:     pickup,
-		station:    station,
-		status:     trip.Status,
-		updatedAt:  time.Now(),
-	}
-
-	payload := tripStatusPayload{
-		TripID:     trip.ID,
-		Status:     trip.Status,
-		DriverID:   trip.DriverID,
-		RiderID:    trip.RiderID,
-		Pickup:     pickup,
-		Station:    station,
-		RecordedAt: time.Now(),
-		Trip:       &trip,
-		Rider:      rider,
-	}
 
 	h.mu.Lock()
-	h.rooms[trip.ID] = room
+	room, leg := h.addLegLocked(trip, rider, pickup, station, trip.Status)
+	riderPayload := room.legPayload(leg)
+	driverPayload := room.driverPayload()
 	h.mu.Unlock()
 
-	h.joinRoom(trip.DriverID, trip.ID)
+	riderPayload.Trip = &trip
+	riderPayload.Rider = rider
+	driverPayload.RiderID = trip.RiderID
+	driverPayload.Pickup = pickup
+	driverPayload.Trip = &trip
+	driverPayload.Rider = rider
+	driverPayload.Itinerary = h.itinerary(room.id)
+
+	h.joinRoom(trip.DriverID, room.id)
 	if rider != nil {
-		h.joinRiderRoom(rider.ID, trip.ID)
-		h.notifyRiderStatus(rider.ID, payload)
-		h.emitToRider(rider.ID, "trip:room-created", payload)
+		h.notifyRiderStatus(rider.ID, riderPayload)
+		h.emitToRider(rider.ID, "trip:room-created", riderPayload)
 	}
-	h.emitToDriver(trip.DriverID, "trip:room-created", payload)
+	h.emitToDriver(trip.DriverID, "trip:room-created", driverPayload)
 }
 
 func riderName(rider *Rider) string {
@@ -782,8 +987,8 @@ func riderName(rider *Rider) string {
 	return rider.Name
 }
 
-func roomSocket(tripID string) string {
-	return fmt.Sprintf("trip:%s", tripID)
+func roomSocket(journeyID string) string {
+	return fmt.Sprintf("trip:%s", journeyID)
 }
 
 func normalizeRole(role string) string {
@@ -816,5 +1021,6 @@ func (h *RealtimeHub) approvalPayload(ctx *pendingApproval) map[string]any {
 		"driverName": h.driverName(ctx.trip.DriverID),
 		"pickup":     ctx.pickup,
 		"station":    ctx.station,
+		"vehicle":    ctx.trip.Vehicle,
 	}
 }
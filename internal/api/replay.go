@@ -0,0 +1,360 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+const (
+	replayPlaying  = "playing"
+	replayPaused   = "paused"
+	replayFinished = "finished"
+	// replayStopped marks a replay cancelled by another simulation.
+	replayStopped = "stopped"
+
+	// replayDefaultInterval spaces trace points that carry no timestamps.
+	replayDefaultInterval = time.Second
+	// replayMaxGap caps the trace time between two points, so a driver parked
+	// in the recording does not stall the replay.
+	replayMaxGap   = time.Minute
+	maxReplaySpeed = 100
+	maxTraceBytes  = 10 << 20
+)
+
+// traceReplay plays a recorded drive back as a trip driver's positions.
+type traceReplay struct {
+	tripID   string
+	driverID string
+	source   string
+	points   []tracePoint
+	// offsets is each point's trace time since the first point.
+	offsets []time.Duration
+	speed   float64
+	// wake interrupts the player's wait after pause, resume or seek.
+	wake chan struct{}
+
+	mu    sync.Mutex
+	next  int
+	state string
+}
+
+// TraceReplayStatus reports a replay's progress.
+type TraceReplayStatus struct {
+	TripID          string  `json:"tripId"`
+	DriverID        string  `json:"driverId"`
+	Source          string  `json:"source"`
+	State           string  `json:"state"`
+	Speed           float64 `json:"speed"`
+	Played          int     `json:"played"`
+	Points          int     `json:"points"`
+	OffsetSeconds   float64 `json:"offsetSeconds"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+func newTraceReplay(tripID, driverID, source string, points []tracePoint, speed float64) *traceReplay {
+	offsets := make([]time.Duration, len(points))
+	for i := 1; i < len(points); i++ {
+		gap := replayDefaultInterval
+		if prev, at := points[i-1].at, points[i].at; !prev.IsZero() && !at.IsZero() {
+			gap = min(max(at.Sub(prev), 0), replayMaxGap)
+		}
+		offsets[i] = offsets[i-1] + gap
+	}
+	return &traceReplay{
+		tripID:   tripID,
+		driverID: driverID,
+		source:   source,
+		points:   points,
+		offsets:  offsets,
+		speed:    speed,
+		wake:     make(chan struct{}, 1),
+		state:    replayPlaying,
+	}
+}
+
+func (rp *traceReplay) status() TraceReplayStatus {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	st := TraceReplayStatus{
+		TripID:          rp.tripID,
+		DriverID:        rp.driverID,
+		Source:          rp.source,
+		State:           rp.state,
+		Speed:           rp.speed,
+		Played:          rp.next,
+		Points:          len(rp.points),
+		DurationSeconds: rp.offsets[len(rp.offsets)-1].Seconds(),
+	}
+	if rp.next > 0 {
+		st.OffsetSeconds = rp.offsets[rp.next-1].Seconds()
+	}
+	return st
+}
+
+// active reports whether the replay still drives the trip's driver.
+func (rp *traceReplay) active() bool {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	return rp.state == replayPlaying || rp.state == replayPaused
+}
+
+var errReplayOver = errors.New("replay is over")
+
+// control pauses, resumes or seeks the replay. Seeking moves playback to the
+// first point at or after offset in trace time.
+func (rp *traceReplay) control(action string, offset time.Duration) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if rp.state != replayPlaying && rp.state != replayPaused {
+		return errReplayOver
+	}
+	switch action {
+	case "pause":
+		rp.state = replayPaused
+	case "resume":
+		rp.state = replayPlaying
+	case "seek":
+		if offset < 0 || offset > rp.offsets[len(rp.offsets)-1] {
+			return fmt.Errorf("offset %s is outside the trace", offset)
+		}
+		rp.next = sort.Search(len(rp.offsets), func(i int) bool { return rp.offsets[i] >= offset })
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+	select {
+	case rp.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// step claims the next point to play and how long to wait after it. ok is
+// false while paused; done is set once the trace ran out.
+func (rp *traceReplay) step() (pt tracePoint, wait time.Duration, ok, done bool) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if rp.state == replayPaused {
+		return tracePoint{}, 0, false, false
+	}
+	if rp.next >= len(rp.points) {
+		rp.state = replayFinished
+		return tracePoint{}, 0, false, true
+	}
+	i := rp.next
+	rp.next++
+	if i+1 < len(rp.points) {
+		wait = time.Duration(float64(rp.offsets[i+1]-rp.offsets[i]) / rp.speed)
+	}
+	return rp.points[i], wait, true, false
+}
+
+// runTraceReplay pushes the trace's points through the simulator until it
+// runs out or ctx is cancelled.
+func (g *Gateway) runTraceReplay(ctx context.Context, rp *traceReplay) {
+	defer g.endTraceReplay(ctx, rp)
+	for {
+		pt, wait, ok, done := rp.step()
+		if done {
+			return
+		}
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-rp.wake:
+			}
+			continue
+		}
+		g.emitSimulatedPosition(ctx, rp.driverID, pt.Latitude, pt.Longitude, 0, 0)
+		g.advanceSimulatedDriver(rp.driverID, pt.Latitude, pt.Longitude)
+		select {
+		case <-ctx.Done():
+			return
+		case <-rp.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (g *Gateway) endTraceReplay(ctx context.Context, rp *traceReplay) {
+	rp.mu.Lock()
+	if rp.state != replayFinished {
+		rp.state = replayStopped
+	}
+	state, played := rp.state, rp.next
+	rp.mu.Unlock()
+	g.logger.Info("trace replay ended", "tripId", rp.tripID, "driverId", rp.driverID, "state", state, "played", played)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// A cancelled context means another simulation already took over.
+	if plan, ok := g.driverPlans[rp.driverID]; ok && plan.replay == rp && ctx.Err() == nil && plan.simCancel != nil {
+		plan.simCancel()
+		plan.simCancel = nil
+	}
+}
+
+// tripReplay returns the replay running for the trip, if any.
+func (g *Gateway) tripReplay(tripID string) *traceReplay {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := range g.trips {
+		if g.trips[i].ID != tripID {
+			continue
+		}
+		if plan, ok := g.driverPlans[g.trips[i].DriverID]; ok && plan.replay != nil && plan.replay.tripID == tripID {
+			return plan.replay
+		}
+		return nil
+	}
+	return nil
+}
+
+// startTraceReplay replaces the trip's simulation with a replay of a GPX or
+// CSV trace from the request body, or of a track from location history.
+func (g *Gateway) startTraceReplay(w http.ResponseWriter, r *http.Request, tripID, source string) {
+	speed := 1.0
+	if raw := r.URL.Query().Get("speed"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v <= 0 || v > maxReplaySpeed {
+			http.Error(w, fmt.Sprintf("speed must be a number in (0, %d]", maxReplaySpeed), http.StatusBadRequest)
+			return
+		}
+		speed = v
+	}
+
+	g.mu.Lock()
+	var trip *Trip
+	for i := range g.trips {
+		if g.trips[i].ID == tripID {
+			t := g.trips[i]
+			trip = &t
+			break
+		}
+	}
+	hasPlan := false
+	if trip != nil {
+		_, hasPlan = g.driverPlans[trip.DriverID]
+	}
+	g.mu.Unlock()
+	if trip == nil {
+		http.Error(w, "trip not found", http.StatusNotFound)
+		return
+	}
+	if !hasPlan {
+		http.Error(w, "driver plan not found", http.StatusNotFound)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxTraceBytes)
+	points, code, err := g.loadTrace(r, source, *trip)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	rp := newTraceReplay(tripID, trip.DriverID, source, points, speed)
+	g.mu.Lock()
+	plan, ok := g.driverPlans[trip.DriverID]
+	if !ok {
+		g.mu.Unlock()
+		http.Error(w, "driver plan not found", http.StatusNotFound)
+		return
+	}
+	if plan.simCancel != nil {
+		plan.simCancel()
+	}
+	simCtx, cancel := context.WithCancel(context.Background())
+	plan.simCancel = cancel
+	plan.Simulated = true
+	plan.replay = rp
+	g.mu.Unlock()
+
+	g.logger.Info("trace replay started", "tripId", tripID, "driverId", trip.DriverID, "source", source, "points", len(points), "speed", speed)
+	go g.runTraceReplay(simCtx, rp)
+	writeJSON(w, http.StatusOK, rp.status())
+}
+
+// loadTrace reads the trace to replay. The history source replays the
+// driver's recorded track between from and to, or the recorded path of
+// sourceTripId.
+func (g *Gateway) loadTrace(r *http.Request, source string, trip Trip) ([]tracePoint, int, error) {
+	if source != "history" {
+		points, err := parseTrace(source, r.Body)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return points, http.StatusOK, nil
+	}
+
+	if g.locationClient == nil {
+		return nil, http.StatusServiceUnavailable, errors.New("location service unavailable")
+	}
+	q := r.URL.Query()
+	var req *locationpb.GetDriverTrackRequest
+	if sourceTripID := q.Get("sourceTripId"); sourceTripID != "" {
+		g.mu.Lock()
+		for _, t := range g.trips {
+			if t.ID == sourceTripID {
+				req = tripTrackRequest(t)
+				break
+			}
+		}
+		g.mu.Unlock()
+		if req == nil {
+			return nil, http.StatusNotFound, errors.New("source trip not found")
+		}
+	} else {
+		req = &locationpb.GetDriverTrackRequest{DriverId: q.Get("driverId"), From: q.Get("from"), To: q.Get("to")}
+		if req.DriverId == "" {
+			req.DriverId = trip.DriverID
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), trackFetchTimeout)
+	defer cancel()
+	resp, err := g.locationClient.GetDriverTrack(ctx, req)
+	if err != nil {
+		g.logger.Error("get driver track failed", "driverId", req.DriverId, "err", err)
+		return nil, http.StatusBadGateway, errors.New("failed to load track")
+	}
+	points, err := traceFromTrack(resp.Points)
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	return points, http.StatusOK, nil
+}
+
+// controlTraceReplay applies pause, resume or seek to the trip's replay.
+func (g *Gateway) controlTraceReplay(w http.ResponseWriter, r *http.Request, tripID, action string) {
+	rp := g.tripReplay(tripID)
+	if rp == nil {
+		http.Error(w, "no trace replay for trip", http.StatusNotFound)
+		return
+	}
+	var offset time.Duration
+	if action == "seek" {
+		var err error
+		if offset, err = time.ParseDuration(r.URL.Query().Get("offset")); err != nil {
+			http.Error(w, "seek needs an offset such as 90s", http.StatusBadRequest)
+			return
+		}
+	}
+	if err := rp.control(action, offset); err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, errReplayOver) {
+			code = http.StatusConflict
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeJSON(w, http.StatusOK, rp.status())
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+// replayLocationClient serves a fixed recorded track and remembers every
+// position pushed through UpdateLocation, and the simulator tokens the
+// streams presented.
+type replayLocationClient struct {
+	locationpb.LocationServiceClient
+	track []*locationpb.TrackPoint
+
+	mu     sync.Mutex
+	sent   []*locationpb.Location
+	tokens []string
+}
+
+func (c *replayLocationClient) GetDriverTrack(_ context.Context, req *locationpb.GetDriverTrackRequest, _ ...grpc.CallOption) (*locationpb.GetDriverTrackResponse, error) {
+	return &locationpb.GetDriverTrackResponse{DriverId: req.DriverId, Points: c.track}, nil
+}
+
+func (c *replayLocationClient) UpdateLocation(ctx context.Context, _ ...grpc.CallOption) (locationpb.LocationService_UpdateLocationClient, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.mu.Lock()
+	c.tokens = append(c.tokens, md.Get(simulatorMetadataKey)...)
+	c.mu.Unlock()
+	return &replayStream{client: c}, nil
+}
+
+func (c *replayLocationClient) sentLatitudes() []float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]float64, 0, len(c.sent))
+	for _, loc := range c.sent {
+		out = append(out, loc.Latitude)
+	}
+	return out
+}
+
+type replayStream struct {
+	locationpb.LocationService_UpdateLocationClient
+	client *replayLocationClient
+}
+
+func (s *replayStream) Send(req *locationpb.UpdateLocationRequest) error {
+	s.client.mu.Lock()
+	s.client.sent = append(s.client.sent, req.Location)
+	s.client.mu.Unlock()
+	return nil
+}
+
+func (s *replayStream) CloseAndRecv() (*locationpb.UpdateLocationResponse, error) {
+	return &locationpb.UpdateLocationResponse{Success: true, Accepted: 1}, nil
+}
+
+func newReplayGateway(client *replayLocationClient) *Gateway {
+	gw := NewGateway(nil, nil, client, nil)
+	gw.trips = []Trip{{ID: "trip-replay", DriverID: "driver-replay", RiderID: "rider-1", Status: "pending"}}
+	gw.driverPlans["driver-replay"] = &driverPlan{DriverID: "driver-replay", Active: true}
+	return gw
+}
+
+func replayRequest(t *testing.T, gw *Gateway, method, query, body string) TraceReplayStatus {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	gw.SimulateTripHandler(rec, httptest.NewRequest(method, "/trips/simulate?tripId=trip-replay&"+query, strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s %s: expected 200, got %d: %s", method, query, rec.Code, rec.Body.String())
+	}
+	var st TraceReplayStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return st
+}
+
+func waitForReplay(t *testing.T, gw *Gateway, want func(TraceReplayStatus) bool) TraceReplayStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		st := replayRequest(t, gw, http.MethodGet, "", "")
+		if want(st) {
+			return st
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("replay did not reach the expected state, last status %+v", st)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSimulateTripReplaysCSVWithPauseAndSeek(t *testing.T) {
+	client := &replayLocationClient{}
+	gw := newReplayGateway(client)
+	trace := "lat,lon,time\n" +
+		"12.80,77.66,2026-01-01T09:00:00Z\n" +
+		"12.81,77.66,2026-01-01T09:00:10Z\n" +
+		"12.82,77.66,2026-01-01T09:00:20Z\n" +
+		"12.83,77.66,2026-01-01T09:00:30Z\n"
+
+	// At 10x the ten-second gaps take a second, leaving time to pause.
+	st := replayRequest(t, gw, http.MethodPost, "trace=csv&speed=10", trace)
+	if st.Points != 4 || st.DurationSeconds != 30 || st.Speed != 10 {
+		t.Fatalf("unexpected start status %+v", st)
+	}
+	waitForReplay(t, gw, func(st TraceReplayStatus) bool { return st.Played == 1 })
+	if st := replayRequest(t, gw, http.MethodPost, "action=pause", ""); st.State != replayPaused {
+		t.Fatalf("expected paused, got %+v", st)
+	}
+
+	st = replayRequest(t, gw, http.MethodPost, "action=seek&offset=30s", "")
+	if st.Played != 3 || st.State != replayPaused {
+		t.Fatalf("seek should move to the last point and stay paused, got %+v", st)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := client.sentLatitudes(); len(got) != 1 {
+		t.Fatalf("a paused replay must not push positions, sent %v", got)
+	}
+
+	replayRequest(t, gw, http.MethodPost, "action=resume", "")
+	waitForReplay(t, gw, func(st TraceReplayStatus) bool { return st.State == replayFinished })
+	if got := client.sentLatitudes(); len(got) != 2 || got[0] != 12.80 || got[1] != 12.83 {
+		t.Fatalf("expected the first point then the seek target, sent %v", got)
+	}
+
+	rec := httptest.NewRecorder()
+	gw.SimulateTripHandler(rec, httptest.NewRequest(http.MethodPost, "/trips/simulate?tripId=trip-replay&action=resume", nil))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 controlling a finished replay, got %d", rec.Code)
+	}
+}
+
+func TestSimulateTripReplaysRecordedTrack(t *testing.T) {
+	client := &replayLocationClient{track: []*locationpb.TrackPoint{
+		{Latitude: 12.84, Longitude: 77.66, RecordedAt: "2026-01-01T09:00:00Z"},
+		{Latitude: 12.85, Longitude: 77.66, RecordedAt: "2026-01-01T09:00:01Z"},
+	}}
+	gw := newReplayGateway(client)
+
+	st := replayRequest(t, gw, http.MethodPost, "trace=history&driverId=driver-field&speed=100", "")
+	if st.Source != "history" || st.Points != 2 {
+		t.Fatalf("unexpected start status %+v", st)
+	}
+	waitForReplay(t, gw, func(st TraceReplayStatus) bool { return st.State == replayFinished })
+	if got := client.sentLatitudes(); len(got) != 2 || got[1] != 12.85 {
+		t.Fatalf("expected the recorded track to be replayed, sent %v", got)
+	}
+	gw.mu.Lock()
+	simCancel := gw.driverPlans["driver-replay"].simCancel
+	gw.mu.Unlock()
+	if simCancel != nil {
+		t.Fatalf("a finished replay should release the driver's simulation")
+	}
+
+	rec := httptest.NewRecorder()
+	gw.SimulateTripHandler(rec, httptest.NewRequest(http.MethodPost, "/trips/simulate?tripId=trip-replay&trace=kml", strings.NewReader("<kml/>")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown trace format, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,459 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultScheduleInterval     = 30 * time.Second
+	defaultScheduleStartTimeout = 15 * time.Minute
+	// scheduleMoveMeters is how far from where a scheduled route found them
+	// a driver has to get to count as having set off.
+	scheduleMoveMeters = 200
+)
+
+// ScheduleConfig tunes the route template scheduler. Zero values take the
+// defaults.
+type ScheduleConfig struct {
+	// Interval is how often the scheduler looks for due templates.
+	Interval time.Duration
+	// StartTimeout is how long a scheduled plan waits for the driver to
+	// start moving before it expires. Departures missed by more than this,
+	// e.g. while the gateway was down, are skipped.
+	StartTimeout time.Duration
+	// Location is the timezone of templates that don't name one.
+	Location *time.Location
+}
+
+func (c ScheduleConfig) withDefaults() ScheduleConfig {
+	if c.Interval <= 0 {
+		c.Interval = defaultScheduleInterval
+	}
+	if c.StartTimeout <= 0 {
+		c.StartTimeout = defaultScheduleStartTimeout
+	}
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+	return c
+}
+
+// SetScheduleConfig replaces the scheduler settings. Templates already
+// stored keep their next departure.
+func (g *Gateway) SetScheduleConfig(cfg ScheduleConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.scheduleCfg = cfg.withDefaults()
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// RouteTemplate is a route a driver drives on a recurring schedule: on each
+// of Days at Departure the gateway configures and starts it for them.
+type RouteTemplate struct {
+	ID             string    `json:"id"`
+	DriverID       string    `json:"driverId"`
+	Name           string    `json:"name,omitempty"`
+	PickupPointIDs []string  `json:"pickupPointIds"`
+	Seats          int       `json:"seats"`
+	Destination    string    `json:"destination,omitempty"`
+	Days           []string  `json:"days"`
+	Departure      string    `json:"departure"`
+	Timezone       string    `json:"timezone,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	NextRunAt      time.Time `json:"nextRunAt"`
+	LastRunAt      time.Time `json:"lastRunAt,omitempty"`
+
+	days     map[time.Weekday]bool
+	hour     int
+	minute   int
+	location *time.Location
+}
+
+// compile checks the recurrence rule and normalises Days to short lower-case
+// names, e.g. "mon".
+func (t *RouteTemplate) compile(defaultLoc *time.Location) error {
+	if len(t.Days) == 0 {
+		return fmt.Errorf("at least one day is required")
+	}
+	t.days = make(map[time.Weekday]bool, len(t.Days))
+	for _, raw := range t.Days {
+		name := strings.ToLower(strings.TrimSpace(raw))
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		day, ok := weekdayNames[name]
+		if !ok {
+			return fmt.Errorf("unknown day %q", raw)
+		}
+		t.days[day] = true
+	}
+	t.Days = t.Days[:0]
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if t.days[day] {
+			t.Days = append(t.Days, strings.ToLower(day.String()[:3]))
+		}
+	}
+
+	at, err := time.Parse("15:04", strings.TrimSpace(t.Departure))
+	if err != nil {
+		return fmt.Errorf("departure must be HH:MM: %w", err)
+	}
+	t.hour, t.minute = at.Hour(), at.Minute()
+	t.Departure = at.Format("15:04")
+
+	t.location = defaultLoc
+	if t.Timezone != "" {
+		loc, err := time.LoadLocation(t.Timezone)
+		if err != nil {
+			return fmt.Errorf("unknown timezone %q", t.Timezone)
+		}
+		t.location = loc
+	}
+	return nil
+}
+
+// nextDeparture returns the first departure strictly after after.
+func (t *RouteTemplate) nextDeparture(after time.Time) time.Time {
+	local := after.In(t.location)
+	for i := 0; i <= 7; i++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+i, t.hour, t.minute, 0, 0, t.location)
+		if t.days[day.Weekday()] && day.After(after) {
+			return day
+		}
+	}
+	return time.Time{}
+}
+
+// addRouteTemplate validates and stores a template, replacing the one with
+// the same id.
+func (g *Gateway) addRouteTemplate(t RouteTemplate, now time.Time) (RouteTemplate, error) {
+	t.DriverID = strings.TrimSpace(t.DriverID)
+	if t.DriverID == "" {
+		return RouteTemplate{}, fmt.Errorf("driverId is required")
+	}
+	t.PickupPointIDs = g.normalizePickupIDs(t.PickupPointIDs)
+	if len(t.PickupPointIDs) == 0 {
+		return RouteTemplate{}, fmt.Errorf("select at least one pickup point")
+	}
+	if t.Seats <= 0 {
+		t.Seats = 1
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := t.compile(g.scheduleCfg.Location); err != nil {
+		return RouteTemplate{}, err
+	}
+	if t.ID == "" {
+		t.ID = fmt.Sprintf("schedule-%d", now.UnixNano())
+	}
+	if existing, ok := g.routeTemplates[t.ID]; ok {
+		if existing.DriverID != t.DriverID {
+			return RouteTemplate{}, fmt.Errorf("schedule '%s' belongs to another driver", t.ID)
+		}
+		t.CreatedAt, t.LastRunAt = existing.CreatedAt, existing.LastRunAt
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now.UTC()
+	}
+	t.NextRunAt = t.nextDeparture(now)
+	stored := t
+	g.routeTemplates[t.ID] = &stored
+	g.writeRouteTemplateLocked(routeTemplateWrite{template: stored})
+	return stored, nil
+}
+
+func (g *Gateway) removeRouteTemplate(id string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.routeTemplates[id]; !ok {
+		return false
+	}
+	delete(g.routeTemplates, id)
+	g.writeRouteTemplateLocked(routeTemplateWrite{deleteID: id})
+	return true
+}
+
+// routeTemplateWrite is a template to save, or the id of one to delete.
+type routeTemplateWrite struct {
+	template RouteTemplate
+	deleteID string
+}
+
+// writeRouteTemplateLocked queues a write to the store. Writes are applied
+// one at a time in the order they were queued, so an older copy of a
+// template never lands after a newer one or resurrects a deleted one.
+func (g *Gateway) writeRouteTemplateLocked(w routeTemplateWrite) {
+	if g.store == nil {
+		return
+	}
+	g.templateOnce.Do(func() {
+		g.templateWake = make(chan struct{}, 1)
+		go g.writeRouteTemplates()
+	})
+	g.templateWrites = append(g.templateWrites, w)
+	select {
+	case g.templateWake <- struct{}{}:
+	default:
+	}
+}
+
+func (g *Gateway) writeRouteTemplates() {
+	for range g.templateWake {
+		g.mu.Lock()
+		writes := g.templateWrites
+		g.templateWrites = nil
+		g.mu.Unlock()
+
+		for _, w := range writes {
+			if w.deleteID != "" {
+				g.store.DeleteRouteTemplate(w.deleteID)
+			} else {
+				g.store.SaveRouteTemplate(w.template)
+			}
+		}
+	}
+}
+
+func (g *Gateway) routeTemplatesFor(driverID string) []RouteTemplate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]RouteTemplate, 0)
+	for _, t := range g.routeTemplates {
+		if driverID == "" || t.DriverID == driverID {
+			out = append(out, *t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NextRunAt.Before(out[j].NextRunAt) })
+	return out
+}
+
+// LoadRouteTemplates restores the templates kept by the attached store.
+func (g *Gateway) LoadRouteTemplates(ctx context.Context) error {
+	if g.store == nil {
+		return nil
+	}
+	templates, err := g.store.LoadRouteTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, t := range templates {
+		if err := t.compile(g.scheduleCfg.Location); err != nil {
+			g.logger.Warn("skipping invalid route template", "scheduleId", t.ID, "err", err)
+			continue
+		}
+		t.NextRunAt = t.nextDeparture(now)
+		stored := t
+		g.routeTemplates[t.ID] = &stored
+	}
+	g.logger.Info("route templates restored", "count", len(templates))
+	return nil
+}
+
+// RunRouteScheduler activates due route templates and expires scheduled
+// plans the driver never set off on, until ctx is cancelled.
+func (g *Gateway) RunRouteScheduler(ctx context.Context) {
+	g.mu.Lock()
+	interval := g.scheduleCfg.Interval
+	g.mu.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				g.runRouteSchedule(now)
+			}
+		}
+	}()
+}
+
+// scheduledExpiry is a scheduled plan that timed out.
+type scheduledExpiry struct {
+	driverID   string
+	scheduleID string
+	plan       *driverPlan
+}
+
+func (g *Gateway) runRouteSchedule(now time.Time) {
+	g.mu.Lock()
+	var due []RouteTemplate
+	for _, t := range g.routeTemplates {
+		if t.NextRunAt.IsZero() || t.NextRunAt.After(now) {
+			continue
+		}
+		missed := now.Sub(t.NextRunAt) > g.scheduleCfg.StartTimeout
+		if missed {
+			g.logger.Warn("scheduled route missed", "scheduleId", t.ID, "driverId", t.DriverID, "departure", t.NextRunAt)
+		} else {
+			t.LastRunAt = now.UTC()
+			due = append(due, *t)
+		}
+		t.NextRunAt = t.nextDeparture(now)
+		g.writeRouteTemplateLocked(routeTemplateWrite{template: *t})
+	}
+	expired := g.expireIdleScheduledPlansLocked(now)
+	g.mu.Unlock()
+
+	for _, t := range due {
+		if err := g.activateRouteTemplate(t, now); err != nil {
+			g.logger.Warn("scheduled route not activated", "scheduleId", t.ID, "driverId", t.DriverID, "err", err)
+		}
+	}
+	for _, e := range expired {
+		if g.store != nil {
+			go g.store.UpdateDriverRouteStatus(e.driverID, e.plan, "expired")
+		}
+		if g.hub != nil {
+			g.hub.NotifyRouteExpired(e.driverID, e.scheduleID)
+			go g.hub.RefreshDriverQueue(e.driverID)
+		}
+		g.sendPushNotification(e.driverID, "Scheduled route expired", "Your scheduled route was stopped because you didn't set off.", map[string]any{"scheduleId": e.scheduleID})
+	}
+}
+
+// activateRouteTemplate configures and starts the template's route, unless
+// the driver is still busy with riders from an earlier one.
+func (g *Gateway) activateRouteTemplate(t RouteTemplate, now time.Time) error {
+	req := driverRouteRequest{DriverID: t.DriverID, PickupPointIDs: t.PickupPointIDs, Seats: t.Seats, Destination: t.Destination}
+	g.mu.Lock()
+	// Registering the route again must not wipe the driver's profile.
+	if driver, err := g.findDriver(t.DriverID, ""); err == nil {
+		req.Name, req.CarDetails = driver.Name, driver.CarDetails
+	}
+	startTimeout := g.scheduleCfg.StartTimeout
+	g.mu.Unlock()
+
+	// The driver may be matched while the lock is released above, so they
+	// are checked in the same critical section that replaces their route.
+	driverFree := func() error {
+		if plan, ok := g.driverPlans[t.DriverID]; ok && plan.Active && g.driverHasOpenTripsLocked(t.DriverID) {
+			return fmt.Errorf("driver still has riders on the current route")
+		}
+		return nil
+	}
+	if _, err := g.replaceDriverRoute(req, driverFree); err != nil {
+		return err
+	}
+	resp, err := g.startDriverTrip(startTripRequest{DriverID: t.DriverID})
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	if plan, ok := g.driverPlans[t.DriverID]; ok {
+		plan.scheduleID = t.ID
+		plan.startBy = now.Add(startTimeout)
+		if driver, err := g.findDriver(t.DriverID, ""); err == nil && (driver.Latitude != 0 || driver.Longitude != 0) {
+			plan.startLat, plan.startLon, plan.hasStart = driver.Latitude, driver.Longitude, true
+		}
+	}
+	g.mu.Unlock()
+
+	g.logger.Info("scheduled route activated", "scheduleId", t.ID, "driverId", t.DriverID, "startBy", now.Add(startTimeout))
+	if g.hub != nil {
+		g.hub.NotifyRouteActivated(t.DriverID, t.ID, resp, now.Add(startTimeout))
+	}
+	g.sendPushNotification(t.DriverID, "Your route has started", fmt.Sprintf("Heading to %s. Set off within %s to keep it open.", resp.Destination, startTimeout), map[string]any{"scheduleId": t.ID})
+	return nil
+}
+
+func (g *Gateway) driverHasOpenTripsLocked(driverID string) bool {
+	for i := range g.trips {
+		if g.trips[i].DriverID == driverID && g.trips[i].Status != "completed" {
+			return true
+		}
+	}
+	return false
+}
+
+// noteScheduledDepartureLocked marks a scheduled plan as under way once the
+// driver has moved away from where it found them. Without a known position
+// at activation, the first fix afterwards is the starting point.
+func (g *Gateway) noteScheduledDepartureLocked(plan *driverPlan, lat, lon float64) {
+	if plan.scheduleID == "" || plan.moved {
+		return
+	}
+	if !plan.hasStart {
+		plan.startLat, plan.startLon, plan.hasStart = lat, lon, true
+		return
+	}
+	if haversineMeters(plan.startLat, plan.startLon, lat, lon) >= scheduleMoveMeters {
+		plan.moved = true
+	}
+}
+
+// expireIdleScheduledPlansLocked stops scheduled plans whose driver has not
+// set off by the deadline. Plans with riders already matched are left for
+// the driver to finish.
+func (g *Gateway) expireIdleScheduledPlansLocked(now time.Time) []scheduledExpiry {
+	var out []scheduledExpiry
+	for driverID, plan := range g.driverPlans {
+		if plan.scheduleID == "" || !plan.Active || plan.moved || now.Before(plan.startBy) {
+			continue
+		}
+		if g.driverHasOpenTripsLocked(driverID) {
+			continue
+		}
+		plan.Active = false
+		if plan.simCancel != nil {
+			plan.simCancel()
+			plan.simCancel = nil
+		}
+		if g.driverStatuses[driverID] == driverStatusAvailable {
+			g.setDriverStatusLocked(driverID, driverStatusOffline, "scheduled route expired")
+		}
+		g.logger.Info("scheduled route expired", "scheduleId", plan.scheduleID, "driverId", driverID)
+		out = append(out, scheduledExpiry{driverID: driverID, scheduleID: plan.scheduleID, plan: plan})
+	}
+	return out
+}
+
+// DriverSchedulesHandler manages route templates. GET lists them, for one
+// driver with ?driverId=; POST stores one; DELETE ?id= removes one.
+func (g *Gateway) DriverSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, g.routeTemplatesFor(r.URL.Query().Get("driverId")))
+	case http.MethodPost:
+		var payload RouteTemplate
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		t, err := g.addRouteTemplate(payload, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, t)
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		if !g.removeRouteTemplate(id) {
+			http.Error(w, "schedule not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newScheduledGateway(t *testing.T) (*Gateway, RouteTemplate) {
+	t.Helper()
+	gw := NewGateway(nil, nil, nil, nil)
+	gw.SetScheduleConfig(ScheduleConfig{StartTimeout: 10 * time.Minute})
+	station, _ := gw.stationByID("station-ecity")
+	gw.drivers = []Driver{{ID: "driver-commute", Name: "Commuter", Latitude: station.Latitude, Longitude: station.Longitude}}
+	tmpl, err := gw.addRouteTemplate(RouteTemplate{
+		DriverID:       "driver-commute",
+		PickupPointIDs: []string{"pickup-station-ecity", "pickup-station-silkboard"},
+		Seats:          3,
+		Days:           []string{"Monday", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Departure:      "07:30",
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	return gw, tmpl
+}
+
+func TestRouteTemplateNextDeparture(t *testing.T) {
+	ist, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	tmpl := RouteTemplate{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Departure: "7:30", Timezone: "Asia/Kolkata"}
+	if err := tmpl.compile(time.UTC); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if tmpl.Departure != "07:30" {
+		t.Fatalf("expected the departure normalised to 07:30, got %q", tmpl.Departure)
+	}
+
+	friday := time.Date(2026, 10, 16, 8, 0, 0, 0, ist)
+	if got, want := tmpl.nextDeparture(friday), time.Date(2026, 10, 19, 7, 30, 0, 0, ist); !got.Equal(want) {
+		t.Fatalf("after Friday's departure expected Monday %s, got %s", want, got)
+	}
+	monday := time.Date(2026, 10, 19, 7, 0, 0, 0, ist)
+	if got, want := tmpl.nextDeparture(monday), time.Date(2026, 10, 19, 7, 30, 0, 0, ist); !got.Equal(want) {
+		t.Fatalf("before Monday's departure expected %s, got %s", want, got)
+	}
+
+	for _, bad := range []RouteTemplate{
+		{Days: []string{"someday"}, Departure: "07:30"},
+		{Days: []string{"mon"}, Departure: "7.30am"},
+		{Days: nil, Departure: "07:30"},
+		{Days: []string{"mon"}, Departure: "07:30", Timezone: "Mars/Olympus"},
+	} {
+		if err := bad.compile(time.UTC); err == nil {
+			t.Fatalf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestSchedulerStartsRouteAndExpiresItWhenDriverStaysPut(t *testing.T) {
+	gw, tmpl := newScheduledGateway(t)
+	departure := tmpl.NextRunAt
+
+	gw.runRouteSchedule(departure.Add(time.Minute))
+	plan := gw.driverPlans["driver-commute"]
+	if plan == nil || !plan.Active || plan.scheduleID != tmpl.ID {
+		t.Fatalf("expected the template's plan to be started, got %+v", plan)
+	}
+	if len(plan.PickupIDs) != 2 || plan.SeatsTotal != 3 {
+		t.Fatalf("expected the template's pickups and seats, got %v and %d", plan.PickupIDs, plan.SeatsTotal)
+	}
+	if got := gw.driverStatuses["driver-commute"]; got != driverStatusAvailable {
+		t.Fatalf("expected the driver available, got %q", got)
+	}
+	if next := gw.routeTemplatesFor("driver-commute")[0].NextRunAt; !next.After(departure) {
+		t.Fatalf("expected the next departure to move on from %s, got %s", departure, next)
+	}
+
+	// Reporting from where the route found them doesn't count as setting off.
+	station, _ := gw.stationByID("station-ecity")
+	gw.recordDriverLocationProgress("driver-commute", station.Latitude+0.0005, station.Longitude)
+
+	gw.runRouteSchedule(departure.Add(5 * time.Minute))
+	if !plan.Active {
+		t.Fatalf("expected the plan to wait until the start timeout")
+	}
+	gw.runRouteSchedule(departure.Add(12 * time.Minute))
+	if plan.Active {
+		t.Fatalf("expected the plan to expire once the driver never set off")
+	}
+	if got := gw.driverStatuses["driver-commute"]; got != driverStatusOffline {
+		t.Fatalf("expected the driver offline after expiry, got %q", got)
+	}
+}
+
+func TestSchedulerKeepsRouteOnceDriverSetsOff(t *testing.T) {
+	gw, tmpl := newScheduledGateway(t)
+	departure := tmpl.NextRunAt
+	gw.runRouteSchedule(departure)
+
+	station, _ := gw.stationByID("station-ecity")
+	gw.recordDriverLocationProgress("driver-commute", station.Latitude+0.01, station.Longitude)
+
+	gw.runRouteSchedule(departure.Add(30 * time.Minute))
+	if plan := gw.driverPlans["driver-commute"]; !plan.Active {
+		t.Fatalf("expected a driver who set off to keep their route")
+	}
+}
+
+func TestSchedulerLeavesRouteOfDriverWithRiders(t *testing.T) {
+	gw, tmpl := newScheduledGateway(t)
+	departure := tmpl.NextRunAt
+	gw.runRouteSchedule(departure)
+	plan := gw.driverPlans["driver-commute"]
+	gw.trips = append(gw.trips, Trip{ID: "trip-open", DriverID: "driver-commute", Status: "awaiting_pickup"})
+
+	next := gw.routeTemplatesFor("driver-commute")[0].NextRunAt
+	if err := gw.activateRouteTemplate(tmpl, next); err == nil {
+		t.Fatalf("expected a driver with riders to keep their route")
+	}
+	if got := gw.driverPlans["driver-commute"]; got != plan || !got.Active {
+		t.Fatalf("expected the current plan to be left in place, got %+v", got)
+	}
+}
+
+func TestSchedulerSkipsMissedDepartures(t *testing.T) {
+	gw, tmpl := newScheduledGateway(t)
+	gw.runRouteSchedule(tmpl.NextRunAt.Add(time.Hour))
+	if _, ok := gw.driverPlans["driver-commute"]; ok {
+		t.Fatalf("expected a departure missed by an hour not to start a route")
+	}
+	if next := gw.routeTemplatesFor("")[0].NextRunAt; !next.After(tmpl.NextRunAt.Add(time.Hour)) {
+		t.Fatalf("expected the template to wait for its next departure, got %s", next)
+	}
+}
+
+func TestDriverSchedulesHandler(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+
+	rec := httptest.NewRecorder()
+	gw.DriverSchedulesHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/schedules", strings.NewReader(`{"driverId":"driver-1","pickupPointIds":["pickup-station-ecity"],"days":["mon"],"departure":"25:00"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad departure, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.DriverSchedulesHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/schedules", strings.NewReader(`{"id":"commute","driverId":"driver-1","pickupPointIds":["pickup-station-ecity"],"days":["mon","fri"],"departure":"08:15"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := gw.routeTemplatesFor("driver-1"); len(got) != 1 || got[0].NextRunAt.IsZero() {
+		t.Fatalf("expected one scheduled template, got %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.DriverSchedulesHandler(rec, httptest.NewRequest(http.MethodDelete, "/drivers/schedules?id=commute", nil))
+	if rec.Code != http.StatusNoContent || len(gw.routeTemplatesFor("")) != 0 {
+		t.Fatalf("expected the template removed, got %d", rec.Code)
+	}
+}
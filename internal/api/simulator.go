@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	locationpb "lastmile/gen/go/location"
+	"lastmile/internal/pkg/drivepath"
+)
+
+const (
+	// defaultSimSpeedMPS is about 30 km/h of city driving.
+	defaultSimSpeedMPS       = 8.3
+	defaultSimSampleInterval = time.Second
+	defaultSimPickupDwell    = 30 * time.Second
+	simRouteTimeout          = 5 * time.Second
+)
+
+// SimulatorConfig tunes how the built-in simulator drives. Zero values take
+// the defaults.
+type SimulatorConfig struct {
+	SpeedMPS float64
+	// SampleInterval is how often the simulated driver reports a position.
+	SampleInterval time.Duration
+	// PickupDwell is how long the driver waits at each pickup.
+	PickupDwell time.Duration
+	// Router gives the path between waypoints; nil drives in straight lines.
+	Router drivepath.Router
+}
+
+func (c SimulatorConfig) withDefaults() SimulatorConfig {
+	if c.SpeedMPS <= 0 {
+		c.SpeedMPS = defaultSimSpeedMPS
+	}
+	if c.SampleInterval <= 0 {
+		c.SampleInterval = defaultSimSampleInterval
+	}
+	if c.PickupDwell <= 0 {
+		c.PickupDwell = defaultSimPickupDwell
+	}
+	if c.Router == nil {
+		c.Router = drivepath.StraightLine{}
+	}
+	return c
+}
+
+// SetSimulatorConfig replaces the simulator's speed, sample rate, pickup
+// dwell and router. Simulations already running keep their settings.
+func (g *Gateway) SetSimulatorConfig(cfg SimulatorConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.simCfg = cfg.withDefaults()
+}
+
+// simulatorMetadataKey carries the simulator token on simulated location
+// streams. It matches location.SimulatorMetadataKey.
+const simulatorMetadataKey = "x-lastmile-simulator-token"
+
+// SetSimulatorToken sets the token simulated location streams present so the
+// location service trusts them as simulated. It has to match the location
+// service's token; without one simulated drives are filtered like real ones.
+func (g *Gateway) SetSimulatorToken(token string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.simToken = token
+}
+
+// runSimulatedTrip drives from the driver's current position through the
+// waypoints, waiting at each pickup and continuing to a waypoint's station
+// when it has one. Every sample goes through the same checks as a real
+// driver's, so pickups and drop-offs fire as the driver passes them.
+func (g *Gateway) runSimulatedTrip(ctx context.Context, driverID string, waypoints []PickupPoint) {
+	if g.locationClient == nil || len(waypoints) == 0 {
+		return
+	}
+	g.mu.Lock()
+	cfg := g.simCfg
+	var from *drivepath.Point
+	if driver, err := g.findDriver(driverID, ""); err == nil && (driver.Latitude != 0 || driver.Longitude != 0) {
+		from = &drivepath.Point{Latitude: driver.Latitude, Longitude: driver.Longitude}
+	}
+	g.mu.Unlock()
+
+	if from == nil {
+		// Nowhere to drive from: appear at the first waypoint.
+		first := drivepath.Point{Latitude: waypoints[0].Latitude, Longitude: waypoints[0].Longitude}
+		if g.simulatedHop(ctx, cfg, driverID, drivepath.Sample{Point: first}, 0) {
+			return
+		}
+		from = &first
+	}
+	for _, wp := range waypoints {
+		to := drivepath.Point{Latitude: wp.Latitude, Longitude: wp.Longitude}
+		if g.simulatedDrive(ctx, cfg, driverID, *from, to) {
+			return
+		}
+		*from = to
+		if wp.ID != "" && g.simulatedDwell(ctx, cfg, driverID, to) {
+			return
+		}
+		if station, ok := g.stationByID(wp.StationID); ok {
+			to = drivepath.Point{Latitude: station.Latitude, Longitude: station.Longitude}
+			if g.simulatedDrive(ctx, cfg, driverID, *from, to) {
+				return
+			}
+			*from = to
+		}
+	}
+}
+
+// simulatedDrive moves the driver from one point to the next at the
+// configured speed. It returns true once ctx is cancelled.
+func (g *Gateway) simulatedDrive(ctx context.Context, cfg SimulatorConfig, driverID string, from, to drivepath.Point) bool {
+	routeCtx, cancel := context.WithTimeout(ctx, simRouteTimeout)
+	path, err := cfg.Router.Route(routeCtx, from, to)
+	cancel()
+	if err != nil {
+		g.logger.Warn("simulator route failed, driving in a straight line", "driverId", driverID, "err", err)
+		path = []drivepath.Point{from, to}
+	}
+	step := cfg.SpeedMPS * cfg.SampleInterval.Seconds()
+	for _, sample := range drivepath.Walk(path, step) {
+		if g.simulatedHop(ctx, cfg, driverID, sample, cfg.SpeedMPS) {
+			return true
+		}
+	}
+	return false
+}
+
+// simulatedDwell keeps reporting the driver standing at the stop for the
+// pickup dwell.
+func (g *Gateway) simulatedDwell(ctx context.Context, cfg SimulatorConfig, driverID string, at drivepath.Point) bool {
+	samples := int(math.Ceil(float64(cfg.PickupDwell) / float64(cfg.SampleInterval)))
+	for i := 0; i < samples; i++ {
+		if g.simulatedHop(ctx, cfg, driverID, drivepath.Sample{Point: at}, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// simulatedHop reports one simulated sample and waits for the next. It
+// returns true once ctx is cancelled.
+func (g *Gateway) simulatedHop(ctx context.Context, cfg SimulatorConfig, driverID string, sample drivepath.Sample, speedMPS float64) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+	}
+	g.emitSimulatedPosition(ctx, driverID, sample.Latitude, sample.Longitude, speedMPS, sample.Heading)
+	g.advanceSimulatedDriver(driverID, sample.Latitude, sample.Longitude)
+	select {
+	case <-ctx.Done():
+		return true
+	case <-time.After(cfg.SampleInterval):
+	}
+	return false
+}
+
+// emitSimulatedPosition reports a simulated position everywhere a real
+// driver's update would go.
+func (g *Gateway) emitSimulatedPosition(ctx context.Context, driverID string, lat, lon, speedMPS, heading float64) {
+	_ = g.pushLocationUpdate(ctx, &locationpb.Location{DriverId: driverID, Latitude: lat, Longitude: lon, SpeedMps: speedMPS, HeadingDegrees: heading, Simulated: true})
+	g.touchPresence(driverID, nil)
+	g.eta.Observe(driverID, lat, lon, speedMPS, time.Now())
+	g.publishDriverLocation(driverID, lat, lon)
+}
+
+// advanceSimulatedDriver runs the pickup checkpoint and trip completion
+// checks for a simulated position.
+func (g *Gateway) advanceSimulatedDriver(driverID string, lat, lon float64) {
+	if passed := g.recordDriverLocationProgress(driverID, lat, lon); passed != nil {
+		g.handlePickupCheckpoint(driverID, passed)
+	}
+	g.maybeCompleteTrips(driverID, lat, lon)
+	g.reevaluateDeferredRiders(driverID, lat, lon)
+}
+
+func (g *Gateway) pushLocationUpdate(ctx context.Context, loc *locationpb.Location) error {
+	if g.locationClient == nil {
+		return nil
+	}
+	g.mu.Lock()
+	token := g.simToken
+	g.mu.Unlock()
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, simulatorMetadataKey, token)
+	}
+	stream, err := g.locationClient.UpdateLocation(ctx)
+	if err != nil {
+		g.logger.Warn("simulate location stream failed", "driverId", loc.DriverId, "err", err)
+		return err
+	}
+	if err := stream.Send(&locationpb.UpdateLocationRequest{Location: loc}); err != nil {
+		g.logger.Warn("simulate location send failed", "driverId", loc.DriverId, "err", err)
+		return err
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		g.logger.Warn("simulate location close failed", "driverId", loc.DriverId, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lastmile/internal/pkg/drivepath"
+)
+
+func TestSimulatedTripDrivesThroughPickupToStation(t *testing.T) {
+	client := &replayLocationClient{}
+	gw := NewGateway(nil, nil, client, nil)
+	// 100 m per sample, sped up to a sample every millisecond.
+	gw.SetSimulatorConfig(SimulatorConfig{SpeedMPS: 100_000, SampleInterval: time.Millisecond, PickupDwell: 5 * time.Millisecond})
+	gw.SetSimulatorToken("sim-secret")
+	station, _ := gw.stationByID("station-ecity")
+	pickup := PickupPoint{ID: "pickup-sim", StationID: station.ID, Latitude: station.Latitude - 0.01, Longitude: station.Longitude}
+	gw.pickupPoints = append(gw.pickupPoints, pickup)
+	start := drivepath.Point{Latitude: station.Latitude - 0.02, Longitude: station.Longitude}
+	gw.drivers = []Driver{{ID: "driver-sim", Name: "Sim", SeatsAvailable: 2, Latitude: start.Latitude, Longitude: start.Longitude}}
+	gw.driverPlans["driver-sim"] = &driverPlan{DriverID: "driver-sim", PickupIDs: []string{pickup.ID}, SeatsTotal: 3, SeatsAvailable: 2, Active: true}
+	gw.trips = []Trip{{ID: "trip-sim", DriverID: "driver-sim", RiderID: "rider-sim", StationID: station.ID, PickupPoint: &pickup, PickupPointID: pickup.ID, Status: "pending"}}
+
+	gw.runSimulatedTrip(context.Background(), "driver-sim", []PickupPoint{pickup})
+
+	client.mu.Lock()
+	sent, tokens := client.sent, client.tokens
+	client.mu.Unlock()
+	if len(tokens) != len(sent) || tokens[0] != "sim-secret" {
+		t.Fatalf("expected every simulated stream to present the simulator token, got %d of %d", len(tokens), len(sent))
+	}
+	prev, atPickup := start, 0
+	for i, loc := range sent {
+		here := drivepath.Point{Latitude: loc.Latitude, Longitude: loc.Longitude}
+		if d := drivepath.Distance(prev, here); d > 101 {
+			t.Fatalf("sample %d jumped %.0f m", i, d)
+		}
+		if !loc.Simulated {
+			t.Fatalf("sample %d is not marked simulated", i)
+		}
+		if loc.Latitude == pickup.Latitude && loc.Longitude == pickup.Longitude {
+			atPickup++
+		}
+		prev = here
+	}
+	if atPickup < 6 {
+		t.Fatalf("expected the arrival and five dwell samples at the pickup, got %d", atPickup)
+	}
+	if prev != (drivepath.Point{Latitude: station.Latitude, Longitude: station.Longitude}) {
+		t.Fatalf("expected the drive to end at the station, ended at %+v", prev)
+	}
+	if sent[0].SpeedMps != 100_000 {
+		t.Fatalf("expected moving samples to carry the speed, got %v", sent[0].SpeedMps)
+	}
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	if gw.driverPlans["driver-sim"].CurrentIndex != 1 {
+		t.Fatalf("expected the pickup checkpoint to fire on the way")
+	}
+	if gw.trips[0].Status != "completed" {
+		t.Fatalf("expected the trip to complete near the station, got %s", gw.trips[0].Status)
+	}
+}
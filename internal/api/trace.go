@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+// tracePoint is one fix of a recorded drive. at is zero when the trace did
+// not carry timestamps.
+type tracePoint struct {
+	Latitude  float64
+	Longitude float64
+	at        time.Time
+}
+
+var errEmptyTrace = errors.New("trace has no points")
+
+// parseTrace reads a GPX or CSV trace.
+func parseTrace(format string, r io.Reader) ([]tracePoint, error) {
+	var (
+		points []tracePoint
+		err    error
+	)
+	switch format {
+	case "gpx":
+		points, err = parseGPXTrace(r)
+	case "csv":
+		points, err = parseCSVTrace(r)
+	default:
+		return nil, fmt.Errorf("unknown trace format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, errEmptyTrace
+	}
+	return points, nil
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+// parseGPXTrace reads track points, or route points when the file has no
+// tracks.
+func parseGPXTrace(r io.Reader) ([]tracePoint, error) {
+	var doc gpxFile
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid gpx: %w", err)
+	}
+	var raw []gpxPoint
+	for _, trk := range doc.Tracks {
+		for _, seg := range trk.Segments {
+			raw = append(raw, seg.Points...)
+		}
+	}
+	if len(raw) == 0 {
+		for _, rte := range doc.Routes {
+			raw = append(raw, rte.Points...)
+		}
+	}
+	points := make([]tracePoint, 0, len(raw))
+	for i, p := range raw {
+		pt := tracePoint{Latitude: p.Lat, Longitude: p.Lon}
+		if p.Time != "" {
+			at, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(p.Time))
+			if err != nil {
+				return nil, fmt.Errorf("point %d: invalid time %q", i+1, p.Time)
+			}
+			pt.at = at
+		}
+		if err := validTracePoint(pt); err != nil {
+			return nil, fmt.Errorf("point %d: %w", i+1, err)
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+// parseCSVTrace reads a CSV with a header row naming lat/latitude,
+// lon/lng/longitude and, optionally, time/timestamp/recordedAt columns.
+// Times are RFC3339 or Unix seconds.
+func parseCSVTrace(r io.Reader) ([]tracePoint, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	latCol, lonCol, timeCol := -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "lat", "latitude":
+			latCol = i
+		case "lon", "lng", "long", "longitude":
+			lonCol = i
+		case "time", "timestamp", "recordedat", "recorded_at":
+			timeCol = i
+		}
+	}
+	if latCol < 0 || lonCol < 0 {
+		return nil, errors.New("csv header needs latitude and longitude columns")
+	}
+
+	var points []tracePoint
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return points, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		var pt tracePoint
+		if pt.Latitude, err = strconv.ParseFloat(record[latCol], 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid latitude %q", line, record[latCol])
+		}
+		if pt.Longitude, err = strconv.ParseFloat(record[lonCol], 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid longitude %q", line, record[lonCol])
+		}
+		if timeCol >= 0 && record[timeCol] != "" {
+			if pt.at, err = parseTraceTime(record[timeCol]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid time %q", line, record[timeCol])
+			}
+		}
+		if err := validTracePoint(pt); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		points = append(points, pt)
+	}
+}
+
+func parseTraceTime(v string) (time.Time, error) {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*1e9)).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+func validTracePoint(p tracePoint) error {
+	if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+		return fmt.Errorf("invalid coordinates %v,%v", p.Latitude, p.Longitude)
+	}
+	return nil
+}
+
+// traceFromTrack turns a recorded track from the location service into a
+// trace.
+func traceFromTrack(points []*locationpb.TrackPoint) ([]tracePoint, error) {
+	if len(points) == 0 {
+		return nil, errEmptyTrace
+	}
+	out := make([]tracePoint, 0, len(points))
+	for _, p := range points {
+		pt := tracePoint{Latitude: p.Latitude, Longitude: p.Longitude}
+		if at, err := time.Parse(time.RFC3339Nano, p.RecordedAt); err == nil {
+			pt.at = at
+		}
+		out = append(out, pt)
+	}
+	return out, nil
+}
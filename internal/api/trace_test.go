@@ -0,0 +1,52 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTraceReadsGPXTrackPoints(t *testing.T) {
+	gpx := `<?xml version="1.0"?>
+<gpx version="1.1" creator="test">
+  <trk><trkseg>
+    <trkpt lat="12.8400" lon="77.6600"><time>2026-01-01T09:00:00Z</time></trkpt>
+    <trkpt lat="12.8410" lon="77.6605"><time>2026-01-01T09:00:05Z</time></trkpt>
+  </trkseg></trk>
+</gpx>`
+	points, err := parseTrace("gpx", strings.NewReader(gpx))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(points) != 2 || points[1].Latitude != 12.841 || points[1].Longitude != 77.6605 {
+		t.Fatalf("unexpected points %+v", points)
+	}
+	if got := points[1].at.Sub(points[0].at); got != 5*time.Second {
+		t.Fatalf("expected points 5s apart, got %s", got)
+	}
+}
+
+func TestParseTraceReadsCSVColumnsByName(t *testing.T) {
+	csv := "timestamp,lng,lat\n1767258000,77.66,12.84\n2026-01-01T09:00:10Z,77.661,12.842\n"
+	points, err := parseTrace("csv", strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(points) != 2 || points[0].Latitude != 12.84 || points[0].Longitude != 77.66 {
+		t.Fatalf("unexpected points %+v", points)
+	}
+	if got := points[1].at.Sub(points[0].at); got != 10*time.Second {
+		t.Fatalf("expected Unix and RFC3339 times to agree, got a %s gap", got)
+	}
+
+	for name, body := range map[string]string{
+		"no header columns": "x,y\n1,2\n",
+		"bad latitude":      "lat,lon\nnorth,77.66\n",
+		"out of range":      "lat,lon\n97,77.66\n",
+		"empty":             "lat,lon\n",
+	} {
+		if _, err := parseTrace("csv", strings.NewReader(body)); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
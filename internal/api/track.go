@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+const trackFetchTimeout = 5 * time.Second
+
+// TripTrack is a GeoJSON Feature holding the path a driver took during a trip.
+type TripTrack struct {
+	Type       string              `json:"type"`
+	Geometry   trackGeometry       `json:"geometry"`
+	Properties tripTrackProperties `json:"properties"`
+}
+
+type trackGeometry struct {
+	Type string `json:"type"`
+	// Coordinates are [longitude, latitude] pairs, as GeoJSON requires.
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+type tripTrackProperties struct {
+	TripID         string   `json:"tripId"`
+	DriverID       string   `json:"driverId"`
+	Status         string   `json:"status"`
+	DistanceMeters float64  `json:"distanceMeters"`
+	Timestamps     []string `json:"timestamps"`
+}
+
+// TripTrackHandler returns the driver's recorded path for a trip as GeoJSON,
+// from the trip's creation until it completed, or until now while it is open.
+func (g *Gateway) TripTrackHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	tripID := r.URL.Query().Get("tripId")
+	if tripID == "" {
+		http.Error(w, "tripId required", http.StatusBadRequest)
+		return
+	}
+	if g.locationClient == nil {
+		http.Error(w, "location service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	g.mu.Lock()
+	var trip *Trip
+	for i := range g.trips {
+		if g.trips[i].ID == tripID {
+			t := g.trips[i]
+			trip = &t
+			break
+		}
+	}
+	g.mu.Unlock()
+	if trip == nil {
+		http.Error(w, "trip not found", http.StatusNotFound)
+		return
+	}
+	if trip.DriverID == "" {
+		http.Error(w, "trip has no driver", http.StatusConflict)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), trackFetchTimeout)
+	defer cancel()
+	resp, err := g.locationClient.GetDriverTrack(ctx, tripTrackRequest(*trip))
+	if err != nil {
+		g.logger.Error("get driver track failed", "tripId", tripID, "driverId", trip.DriverID, "err", err)
+		http.Error(w, "failed to load track", http.StatusBadGateway)
+		return
+	}
+
+	track := TripTrack{
+		Type:     "Feature",
+		Geometry: trackGeometry{Type: "LineString", Coordinates: make([][2]float64, 0, len(resp.Points))},
+		Properties: tripTrackProperties{
+			TripID:         trip.ID,
+			DriverID:       trip.DriverID,
+			Status:         trip.Status,
+			DistanceMeters: resp.DistanceMeters,
+			Timestamps:     make([]string, 0, len(resp.Points)),
+		},
+	}
+	for _, p := range resp.Points {
+		track.Geometry.Coordinates = append(track.Geometry.Coordinates, [2]float64{p.Longitude, p.Latitude})
+		track.Properties.Timestamps = append(track.Properties.Timestamps, p.RecordedAt)
+	}
+	writeJSON(w, http.StatusOK, track)
+}
+
+// tripTrackRequest asks for the driver's points from the trip's creation until
+// it completed, or until now while it is open.
+func tripTrackRequest(trip Trip) *locationpb.GetDriverTrackRequest {
+	req := &locationpb.GetDriverTrackRequest{DriverId: trip.DriverID}
+	if !trip.CreatedAt.IsZero() {
+		req.From = trip.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if !trip.CompletedAt.IsZero() {
+		req.To = trip.CompletedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return req
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	locationpb "lastmile/gen/go/location"
+)
+
+// trackLocationClient answers GetDriverTrack with fixed points and records
+// the request it was given.
+type trackLocationClient struct {
+	locationpb.LocationServiceClient
+	points []*locationpb.TrackPoint
+	req    *locationpb.GetDriverTrackRequest
+}
+
+func (c *trackLocationClient) GetDriverTrack(_ context.Context, req *locationpb.GetDriverTrackRequest, _ ...grpc.CallOption) (*locationpb.GetDriverTrackResponse, error) {
+	c.req = req
+	return &locationpb.GetDriverTrackResponse{DriverId: req.DriverId, Points: c.points, DistanceMeters: 1113}, nil
+}
+
+func TestTripTrackHandlerReturnsGeoJSON(t *testing.T) {
+	client := &trackLocationClient{points: []*locationpb.TrackPoint{
+		{Latitude: 12.84, Longitude: 77.66, RecordedAt: "2026-01-01T09:01:00Z"},
+		{Latitude: 12.85, Longitude: 77.66, RecordedAt: "2026-01-01T09:02:00Z"},
+	}}
+	gw := NewGateway(nil, nil, client, nil)
+	created := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	gw.trips = []Trip{{ID: "trip-track", DriverID: "driver-1", RiderID: "rider-1", Status: "completed", CreatedAt: created, CompletedAt: created.Add(10 * time.Minute)}}
+
+	rec := httptest.NewRecorder()
+	gw.TripTrackHandler(rec, httptest.NewRequest(http.MethodGet, "/trips/track?tripId=trip-track", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if client.req.DriverId != "driver-1" || client.req.From != "2026-01-01T09:00:00Z" || client.req.To != "2026-01-01T09:10:00Z" {
+		t.Fatalf("expected the trip's driver and time range, got %+v", client.req)
+	}
+
+	var track TripTrack
+	if err := json.Unmarshal(rec.Body.Bytes(), &track); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if track.Type != "Feature" || track.Geometry.Type != "LineString" {
+		t.Fatalf("expected a LineString feature, got %s/%s", track.Type, track.Geometry.Type)
+	}
+	if len(track.Geometry.Coordinates) != 2 || track.Geometry.Coordinates[1] != [2]float64{77.66, 12.85} {
+		t.Fatalf("expected [lon, lat] coordinates, got %v", track.Geometry.Coordinates)
+	}
+	if track.Properties.DistanceMeters != 1113 || len(track.Properties.Timestamps) != 2 {
+		t.Fatalf("unexpected properties %+v", track.Properties)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.TripTrackHandler(rec, httptest.NewRequest(http.MethodGet, "/trips/track?tripId=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown trip, got %d", rec.Code)
+	}
+}
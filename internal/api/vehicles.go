@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	driverpb "lastmile/gen/go/driver"
+)
+
+const vehicleCallTimeout = 3 * time.Second
+
+// Vehicle is a car registered with DriverService.
+type Vehicle struct {
+	ID                   string `json:"id"`
+	Plate                string `json:"plate"`
+	Make                 string `json:"make,omitempty"`
+	Model                string `json:"model,omitempty"`
+	Colour               string `json:"colour,omitempty"`
+	MaxSeats             int    `json:"maxSeats"`
+	AirConditioned       bool   `json:"airConditioned"`
+	WheelchairAccessible bool   `json:"wheelchairAccessible"`
+	LuggageSpace         bool   `json:"luggageSpace"`
+}
+
+// TripVehicle is what a rider is told about the car coming for them.
+type TripVehicle struct {
+	Plate  string `json:"plate"`
+	Colour string `json:"colour,omitempty"`
+	Make   string `json:"make,omitempty"`
+	Model  string `json:"model,omitempty"`
+}
+
+type driverVehiclesResponse struct {
+	DriverID        string    `json:"driverId,omitempty"`
+	ActiveVehicleID string    `json:"activeVehicleId,omitempty"`
+	Vehicles        []Vehicle `json:"vehicles"`
+}
+
+type registerVehicleRequest struct {
+	Vehicle
+	DriverID string `json:"driverId"`
+}
+
+type selectVehicleRequest struct {
+	DriverID  string `json:"driverId"`
+	VehicleID string `json:"vehicleId"`
+}
+
+func vehicleFromProto(v *driverpb.Vehicle) *Vehicle {
+	if v == nil {
+		return nil
+	}
+	return &Vehicle{
+		ID:                   v.Id,
+		Plate:                v.Plate,
+		Make:                 v.Make,
+		Model:                v.Model,
+		Colour:               v.Colour,
+		MaxSeats:             int(v.MaxSeats),
+		AirConditioned:       v.AirConditioned,
+		WheelchairAccessible: v.WheelchairAccessible,
+		LuggageSpace:         v.LuggageSpace,
+	}
+}
+
+func (v Vehicle) toProto() *driverpb.Vehicle {
+	return &driverpb.Vehicle{
+		Id:                   v.ID,
+		Plate:                v.Plate,
+		Make:                 v.Make,
+		Model:                v.Model,
+		Colour:               v.Colour,
+		MaxSeats:             int32(v.MaxSeats),
+		AirConditioned:       v.AirConditioned,
+		WheelchairAccessible: v.WheelchairAccessible,
+		LuggageSpace:         v.LuggageSpace,
+	}
+}
+
+// tripVehicleLocked describes the driver's current vehicle for a trip, or
+// returns nil when the gateway does not know it.
+func (g *Gateway) tripVehicleLocked(driverID string) *TripVehicle {
+	v := g.driverVehicles[driverID]
+	if v == nil {
+		return nil
+	}
+	return &TripVehicle{Plate: v.Plate, Colour: v.Colour, Make: v.Make, Model: v.Model}
+}
+
+// resolveDriverVehicle returns the vehicle the driver will drive, switching
+// to vehicleID first when one is given. Without a driver service, or when
+// the driver has no vehicle, it returns the last one the gateway saw.
+func (g *Gateway) resolveDriverVehicle(driverID, vehicleID string) (*Vehicle, error) {
+	if g.driverClient == nil {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.driverVehicles[driverID], nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vehicleCallTimeout)
+	defer cancel()
+
+	var vehicle *Vehicle
+	if vehicleID != "" {
+		resp, err := g.driverClient.SelectVehicle(ctx, &driverpb.SelectVehicleRequest{DriverId: driverID, VehicleId: vehicleID})
+		if err != nil {
+			return nil, fmt.Errorf("select vehicle: %s", status.Convert(err).Message())
+		}
+		vehicle = vehicleFromProto(resp.Vehicle)
+	} else {
+		resp, err := g.driverClient.ListVehicles(ctx, &driverpb.ListVehiclesRequest{DriverId: driverID})
+		if err != nil {
+			g.logger.Warn("list vehicles failed", "driverId", driverID, "err", err)
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			return g.driverVehicles[driverID], nil
+		}
+		for _, v := range resp.Vehicles {
+			if v.Id == resp.ActiveVehicleId {
+				vehicle = vehicleFromProto(v)
+			}
+		}
+	}
+
+	// The route being configured replaces the plan this may cap, and is
+	// saved with the vehicle's seats, so nothing is persisted here.
+	g.mu.Lock()
+	g.noteDriverVehicleLocked(driverID, vehicle)
+	g.mu.Unlock()
+	return vehicle, nil
+}
+
+// noteDriverVehicleLocked records the driver's current vehicle and trims
+// their plan to its seats. Riders already on board keep their seats. It
+// reports whether the plan was trimmed.
+func (g *Gateway) noteDriverVehicleLocked(driverID string, vehicle *Vehicle) bool {
+	if vehicle == nil {
+		delete(g.driverVehicles, driverID)
+		return false
+	}
+	g.driverVehicles[driverID] = vehicle
+	plan, ok := g.driverPlans[driverID]
+	if !ok || vehicle.MaxSeats <= 0 || plan.SeatsTotal <= vehicle.MaxSeats {
+		return false
+	}
+	taken := plan.SeatsTotal - plan.SeatsAvailable
+	plan.SeatsTotal = vehicle.MaxSeats
+	plan.SeatsAvailable = max(vehicle.MaxSeats-taken, 0)
+	g.syncDriverStatusLocked(driverID, "vehicle changed")
+	return true
+}
+
+// saveCappedDriverRoute stores the driver's plan after a smaller vehicle
+// trimmed its seats, and registers the route again so the driver service
+// matches on the new seat count.
+func (g *Gateway) saveCappedDriverRoute(driverID string) {
+	g.mu.Lock()
+	plan, ok := g.driverPlans[driverID]
+	if !ok {
+		g.mu.Unlock()
+		return
+	}
+	pickups := g.pickupPointsForIDs(plan.PickupIDs)
+	targets := append([]string{}, plan.TargetStations...)
+	destination, seats := plan.Destination, plan.SeatsTotal
+	active := plan.Active
+	g.mu.Unlock()
+
+	if g.store != nil {
+		go func() {
+			g.store.SaveDriverRoute(driverID, plan, pickups)
+			// Saving rewrites the route as configured; a started one stays active.
+			if active {
+				g.store.UpdateDriverRouteStatus(driverID, plan, "active")
+			}
+		}()
+	}
+	if g.hub != nil {
+		go g.hub.RefreshDriverQueue(driverID)
+	}
+	if g.driverClient == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vehicleCallTimeout)
+	defer cancel()
+	g.registerDriverRoute(ctx, driverID, targets, destination, seats)
+}
+
+// writeVehicleError turns a DriverService error into an HTTP response.
+func (g *Gateway) writeVehicleError(w http.ResponseWriter, op string, err error) {
+	msg := status.Convert(err).Message()
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		http.Error(w, msg, http.StatusBadRequest)
+	case codes.NotFound:
+		http.Error(w, msg, http.StatusNotFound)
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		http.Error(w, msg, http.StatusConflict)
+	default:
+		g.logger.Error(op+" failed", "err", err)
+		http.Error(w, "failed to "+op, http.StatusBadGateway)
+	}
+}
+
+// DriverVehiclesHandler lists and registers vehicles. GET ?driverId= lists
+// the driver's vehicles and which one they are driving; without driverId
+// every vehicle is listed. POST a vehicle with a driverId to register it
+// and link it to that driver.
+func (g *Gateway) DriverVehiclesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if g.driverClient == nil {
+		http.Error(w, "driver service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), vehicleCallTimeout)
+	defer cancel()
+
+	if r.Method == http.MethodGet {
+		driverID := strings.TrimSpace(r.URL.Query().Get("driverId"))
+		resp, err := g.driverClient.ListVehicles(ctx, &driverpb.ListVehiclesRequest{DriverId: driverID})
+		if err != nil {
+			g.writeVehicleError(w, "list vehicles", err)
+			return
+		}
+		out := driverVehiclesResponse{DriverID: driverID, ActiveVehicleID: resp.ActiveVehicleId, Vehicles: make([]Vehicle, 0, len(resp.Vehicles))}
+		for _, v := range resp.Vehicles {
+			out.Vehicles = append(out.Vehicles, *vehicleFromProto(v))
+		}
+		writeJSON(w, http.StatusOK, out)
+		return
+	}
+
+	var payload registerVehicleRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	driverID := strings.TrimSpace(payload.DriverID)
+	if driverID == "" || strings.TrimSpace(payload.Plate) == "" || payload.MaxSeats < 1 {
+		http.Error(w, "driverId, plate and maxSeats are required", http.StatusBadRequest)
+		return
+	}
+	resp, err := g.driverClient.RegisterVehicle(ctx, &driverpb.RegisterVehicleRequest{DriverId: driverID, Vehicle: payload.Vehicle.toProto()})
+	if err != nil {
+		g.writeVehicleError(w, "register vehicle", err)
+		return
+	}
+	vehicle := payload.Vehicle
+	vehicle.ID = resp.Id
+	vehicle.Plate = strings.ToUpper(strings.TrimSpace(vehicle.Plate))
+	writeJSON(w, http.StatusOK, vehicle)
+}
+
+// DriverVehicleSelectHandler switches the vehicle a driver is driving.
+// POST {driverId, vehicleId}; the vehicle must be linked to the driver. A
+// smaller vehicle caps the seats on the driver's route.
+func (g *Gateway) DriverVehicleSelectHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var payload selectVehicleRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	driverID := strings.TrimSpace(payload.DriverID)
+	vehicleID := strings.TrimSpace(payload.VehicleID)
+	if driverID == "" || vehicleID == "" {
+		http.Error(w, "driverId and vehicleId are required", http.StatusBadRequest)
+		return
+	}
+	if g.driverClient == nil {
+		http.Error(w, "driver service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), vehicleCallTimeout)
+	defer cancel()
+	resp, err := g.driverClient.SelectVehicle(ctx, &driverpb.SelectVehicleRequest{DriverId: driverID, VehicleId: vehicleID})
+	if err != nil {
+		g.writeVehicleError(w, "select vehicle", err)
+		return
+	}
+	vehicle := vehicleFromProto(resp.Vehicle)
+	g.mu.Lock()
+	capped := g.noteDriverVehicleLocked(driverID, vehicle)
+	g.mu.Unlock()
+	if capped {
+		g.saveCappedDriverRoute(driverID)
+	}
+	writeJSON(w, http.StatusOK, vehicle)
+}
+
+func hasActiveVehicle(drivers []*driverpb.Driver) bool {
+	for _, d := range drivers {
+		if d.ActiveVehicleId != "" {
+			return true
+		}
+	}
+	return false
+}
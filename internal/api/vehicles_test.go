@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	driverpb "lastmile/gen/go/driver"
+)
+
+// vehicleDriverClient serves one driver's vehicles. Profile and status
+// calls succeed without doing anything.
+type vehicleDriverClient struct {
+	statusDriverClient
+	vehicles []*driverpb.Vehicle
+	active   string
+	routes   []*driverpb.Route
+}
+
+func (c *vehicleDriverClient) RegisterDriver(context.Context, *driverpb.RegisterDriverRequest, ...grpc.CallOption) (*driverpb.RegisterDriverResponse, error) {
+	return &driverpb.RegisterDriverResponse{}, nil
+}
+
+func (c *vehicleDriverClient) RegisterRoute(_ context.Context, in *driverpb.RegisterRouteRequest, _ ...grpc.CallOption) (*driverpb.RegisterRouteResponse, error) {
+	c.routes = append(c.routes, in.Route)
+	return &driverpb.RegisterRouteResponse{}, nil
+}
+
+func (c *vehicleDriverClient) ListVehicles(context.Context, *driverpb.ListVehiclesRequest, ...grpc.CallOption) (*driverpb.ListVehiclesResponse, error) {
+	return &driverpb.ListVehiclesResponse{Vehicles: c.vehicles, ActiveVehicleId: c.active}, nil
+}
+
+func (c *vehicleDriverClient) SelectVehicle(_ context.Context, in *driverpb.SelectVehicleRequest, _ ...grpc.CallOption) (*driverpb.SelectVehicleResponse, error) {
+	for _, v := range c.vehicles {
+		if v.Id == in.VehicleId {
+			c.active = v.Id
+			return &driverpb.SelectVehicleResponse{Vehicle: v}, nil
+		}
+	}
+	return nil, status.Error(codes.FailedPrecondition, "vehicle is not linked to the driver")
+}
+
+func (c *vehicleDriverClient) RegisterVehicle(_ context.Context, in *driverpb.RegisterVehicleRequest, _ ...grpc.CallOption) (*driverpb.RegisterVehicleResponse, error) {
+	v := in.Vehicle
+	v.Id = "vehicle-" + v.Plate
+	c.vehicles = append(c.vehicles, v)
+	return &driverpb.RegisterVehicleResponse{Id: v.Id}, nil
+}
+
+func TestDriverRouteSeatsAreCappedByVehicle(t *testing.T) {
+	client := &vehicleDriverClient{
+		vehicles: []*driverpb.Vehicle{
+			{Id: "van", Plate: "KA01VAN", Colour: "white", MaxSeats: 6},
+			{Id: "hatch", Plate: "KA01CAR", Colour: "red", Make: "Maruti", Model: "Swift", MaxSeats: 3},
+		},
+		active: "van",
+	}
+	gw := NewGateway(nil, client, nil, nil)
+	station, _ := gw.stationByID("station-ecity")
+	pickup := PickupPoint{ID: "pickup-vehicle", StationID: station.ID, Latitude: station.Latitude, Longitude: station.Longitude}
+	gw.pickupPoints = append(gw.pickupPoints, pickup)
+
+	res, err := gw.configureDriverRoute(driverRouteRequest{DriverID: "driver-car", PickupPointIDs: []string{pickup.ID}, Seats: 8})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	if res.SeatsTotal != 6 || res.Vehicle == nil || res.Vehicle.ID != "van" {
+		t.Fatalf("expected 6 seats in the van, got %d in %+v", res.SeatsTotal, res.Vehicle)
+	}
+
+	if _, err := gw.configureDriverRoute(driverRouteRequest{DriverID: "driver-car", PickupPointIDs: []string{pickup.ID}, Seats: 8, VehicleID: "bike"}); err == nil {
+		t.Fatalf("expected an unlinked vehicle to be refused")
+	}
+	res, err = gw.configureDriverRoute(driverRouteRequest{DriverID: "driver-car", PickupPointIDs: []string{pickup.ID}, Seats: 8, VehicleID: "hatch"})
+	if err != nil {
+		t.Fatalf("configure with the hatchback: %v", err)
+	}
+	if plan := gw.driverPlans["driver-car"]; plan.SeatsTotal != 3 || plan.SeatsAvailable != 3 {
+		t.Fatalf("expected the plan capped at 3 seats, got %d/%d", plan.SeatsAvailable, plan.SeatsTotal)
+	}
+
+	gw.drivers = []Driver{{ID: "driver-car", SeatsAvailable: 3, Route: Route{TargetStationIDs: []string{station.ID}}}}
+	gw.riders = []Rider{{ID: "rider-car", StationID: station.ID, Status: "waiting", ArrivalTime: time.Now(), PickupPointID: pickup.ID, Pickup: &pickup}}
+	if _, err := gw.startDriverTrip(startTripRequest{DriverID: "driver-car"}); err != nil {
+		t.Fatalf("start trip: %v", err)
+	}
+	trip, err := gw.matchTrip("driver-car", station.ID, "rider-car", 0)
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	if trip.Vehicle == nil || trip.Vehicle.Plate != "KA01CAR" || trip.Vehicle.Colour != "red" {
+		t.Fatalf("expected the rider to see the hatchback, got %+v", trip.Vehicle)
+	}
+	if pb := toProtoTrip(trip); pb.VehiclePlate != "KA01CAR" || pb.VehicleColour != "red" {
+		t.Fatalf("expected the plate and colour on the proto trip, got %q %q", pb.VehiclePlate, pb.VehicleColour)
+	}
+}
+
+func TestDriverVehiclesHandlers(t *testing.T) {
+	gw := NewGateway(nil, nil, nil, nil)
+	rec := httptest.NewRecorder()
+	gw.DriverVehiclesHandler(rec, httptest.NewRequest(http.MethodGet, "/drivers/vehicles?driverId=driver-1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a driver service, got %d", rec.Code)
+	}
+
+	client := &vehicleDriverClient{}
+	gw = NewGateway(nil, client, nil, nil)
+	gw.driverPlans["driver-1"] = &driverPlan{DriverID: "driver-1", SeatsTotal: 5, SeatsAvailable: 3}
+
+	rec = httptest.NewRecorder()
+	gw.DriverVehiclesHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/vehicles", strings.NewReader(`{"driverId":"driver-1","plate":"ka01ab1234","colour":"grey"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without maxSeats, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	gw.DriverVehiclesHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/vehicles", strings.NewReader(`{"driverId":"driver-1","plate":"ka01ab1234","colour":"grey","maxSeats":4}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var registered Vehicle
+	if err := json.NewDecoder(rec.Body).Decode(&registered); err != nil || registered.ID == "" || registered.Plate != "KA01AB1234" {
+		t.Fatalf("expected the registered vehicle back, got %+v (%v)", registered, err)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.DriverVehicleSelectHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/vehicles/select", strings.NewReader(`{"driverId":"driver-1","vehicleId":"unknown"}`)))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an unlinked vehicle, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	gw.DriverVehicleSelectHandler(rec, httptest.NewRequest(http.MethodPost, "/drivers/vehicles/select", strings.NewReader(`{"driverId":"driver-1","vehicleId":"`+registered.ID+`"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	// Two riders were on board the bigger car; the smaller one has room for two more.
+	if plan := gw.driverPlans["driver-1"]; plan.SeatsTotal != 4 || plan.SeatsAvailable != 2 {
+		t.Fatalf("expected the plan trimmed to 2 of 4 seats, got %d/%d", plan.SeatsAvailable, plan.SeatsTotal)
+	}
+	if n := len(client.routes); n != 1 || client.routes[0].AvailableSeats != 4 {
+		t.Fatalf("expected the trimmed route registered with 4 seats, got %v", client.routes)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.DriverVehiclesHandler(rec, httptest.NewRequest(http.MethodGet, "/drivers/vehicles?driverId=driver-1", nil))
+	var list driverVehiclesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil || len(list.Vehicles) != 1 || list.ActiveVehicleID != registered.ID {
+		t.Fatalf("expected the selected vehicle listed as active, got %+v (%v)", list, err)
+	}
+}
@@ -0,0 +1,374 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pb "lastmile/gen/go/driver"
+	"lastmile/internal/pkg/driverstatus"
+)
+
+// PostgresRepository keeps drivers in the drivers table and routes in the
+// driver_routes and driver_route_pickups tables the gateway also writes.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository connects to dsn and creates the drivers and vehicle
+// tables, or adds the drivers table's newer columns, if needed. The route
+// tables come from schema.sql.
+func NewPostgresRepository(ctx context.Context, dsn string) (*PostgresRepository, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = pool.Exec(ctx, `
+		create table if not exists drivers (
+			id text primary key,
+			name text not null default '',
+			car_details text not null default '',
+			created_at timestamptz not null default now()
+		);
+		alter table drivers add column if not exists status text not null default 'offline';
+		alter table drivers add column if not exists active_vehicle_id text;
+		create table if not exists vehicles (
+			id text primary key,
+			plate text not null,
+			plate_key text not null unique,
+			make text not null default '',
+			model text not null default '',
+			colour text not null default '',
+			max_seats integer not null default 1,
+			air_conditioned boolean not null default false,
+			wheelchair_accessible boolean not null default false,
+			luggage_space boolean not null default false,
+			created_at timestamptz not null default now()
+		);
+		create table if not exists driver_vehicles (
+			driver_id text not null references drivers(id) on delete cascade,
+			vehicle_id text not null references vehicles(id) on delete cascade,
+			linked_at timestamptz not null default now(),
+			primary key (driver_id, vehicle_id)
+		);
+	`)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return &PostgresRepository{pool: pool}, nil
+}
+
+// Close releases the connection pool.
+func (p *PostgresRepository) Close() {
+	p.pool.Close()
+}
+
+func (p *PostgresRepository) SaveDriver(ctx context.Context, driver *pb.Driver) error {
+	_, err := p.pool.Exec(ctx, `
+		insert into drivers (id, name, car_details)
+		values ($1, $2, $3)
+		on conflict (id) do update set
+			name = excluded.name,
+			car_details = excluded.car_details
+	`, driver.Id, driver.Name, driver.CarDetails)
+	return err
+}
+
+// driverColumns reads a driver from drivers d, with their linked vehicles
+// in the order they were linked.
+const driverColumns = `
+	d.id, d.name, d.car_details, d.status, coalesce(d.active_vehicle_id, ''),
+	coalesce((select array_agg(vehicle_id order by linked_at, vehicle_id)
+		from driver_vehicles dv where dv.driver_id = d.id), '{}'::text[])`
+
+func scanDriver(row pgx.Row) (*pb.Driver, error) {
+	d := &pb.Driver{}
+	var statusName string
+	if err := row.Scan(&d.Id, &d.Name, &d.CarDetails, &statusName, &d.ActiveVehicleId, &d.VehicleIds); err != nil {
+		return nil, err
+	}
+	d.Status = parseStoredStatus(statusName)
+	return d, nil
+}
+
+// parseStoredStatus reads a drivers.status value; anything unrecognised
+// counts as offline.
+func parseStoredStatus(name string) pb.DriverStatus {
+	if s, ok := driverstatus.Parse(name); ok {
+		return s
+	}
+	return pb.DriverStatus_DRIVER_STATUS_OFFLINE
+}
+
+func (p *PostgresRepository) Driver(ctx context.Context, id string) (*pb.Driver, error) {
+	d, err := scanDriver(p.pool.QueryRow(ctx, `select `+driverColumns+` from drivers d where d.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return d, err
+}
+
+func (p *PostgresRepository) Drivers(ctx context.Context) ([]*pb.Driver, error) {
+	rows, err := p.pool.Query(ctx, `select `+driverColumns+` from drivers d order by d.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*pb.Driver
+	for rows.Next() {
+		d, err := scanDriver(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// SetDriverStatus locks the driver's row while checking the transition, so
+// replicas changing the same driver at once apply one change after the other.
+func (p *PostgresRepository) SetDriverStatus(ctx context.Context, driverID string, status pb.DriverStatus) (pb.DriverStatus, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `select status from drivers where id = $1 for update`, driverID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED, ErrNotFound
+	}
+	if err != nil {
+		return pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED, err
+	}
+	previous := parseStoredStatus(current)
+	if err := CheckTransition(previous, status); err != nil {
+		return previous, err
+	}
+	if _, err := tx.Exec(ctx, `update drivers set status = $2 where id = $1`, driverID, driverstatus.Name(status)); err != nil {
+		return previous, err
+	}
+	return previous, tx.Commit(ctx)
+}
+
+// SaveRoute upserts the driver's driver_routes row. Only the seats, target
+// stations and destination are touched, so the gateway's trip state and
+// pickups on the same row survive. The stored capacity changes only when
+// the route sets SeatsTotal; seats available never exceed it.
+func (p *PostgresRepository) SaveRoute(ctx context.Context, route *pb.Route) (string, error) {
+	targets, err := json.Marshal(route.TargetStationIds)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	err = p.pool.QueryRow(ctx, `
+		insert into driver_routes (driver_id, seats_total, seats_available, metadata)
+		values ($1, coalesce(nullif($5::int, 0), $2), least($2, coalesce(nullif($5::int, 0), $2)),
+			jsonb_build_object('target_stops', $3::jsonb, 'destination', $4::text))
+		on conflict (driver_id) do update set
+			seats_total = coalesce(nullif($5::int, 0), driver_routes.seats_total),
+			seats_available = least(excluded.seats_available, coalesce(nullif($5::int, 0), driver_routes.seats_total)),
+			metadata = driver_routes.metadata || excluded.metadata
+		returning id::text
+	`, route.DriverId, route.AvailableSeats, string(targets), route.Destination, route.SeatsTotal).Scan(&id)
+	return id, err
+}
+
+// routeColumns reads a route from driver_routes r. Target stations come from
+// the route's metadata, or from its pickups' stations in order when the
+// metadata has none.
+const routeColumns = `
+	r.id::text, r.driver_id, r.seats_available, r.seats_total, coalesce(r.metadata->>'destination', ''),
+	coalesce(
+		case when jsonb_typeof(r.metadata->'target_stops') = 'array' and jsonb_array_length(r.metadata->'target_stops') > 0
+			then (select array_agg(value) from jsonb_array_elements_text(r.metadata->'target_stops'))
+		end,
+		(select array_agg(station_id order by first_sequence)
+			from (select station_id, min(sequence) as first_sequence
+				from driver_route_pickups p where p.route_id = r.id
+				group by station_id) stops),
+		'{}'::text[]
+	)`
+
+func scanRoute(row pgx.Row) (*pb.Route, error) {
+	r := &pb.Route{}
+	if err := row.Scan(&r.Id, &r.DriverId, &r.AvailableSeats, &r.SeatsTotal, &r.Destination, &r.TargetStationIds); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (p *PostgresRepository) RouteForDriver(ctx context.Context, driverID string) (*pb.Route, error) {
+	r, err := scanRoute(p.pool.QueryRow(ctx, `select `+routeColumns+` from driver_routes r where r.driver_id = $1`, driverID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return r, err
+}
+
+func (p *PostgresRepository) Routes(ctx context.Context) ([]*pb.Route, error) {
+	rows, err := p.pool.Query(ctx, `select `+routeColumns+` from driver_routes r order by r.driver_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*pb.Route
+	for rows.Next() {
+		r, err := scanRoute(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+const vehicleColumns = `id, plate, make, model, colour, max_seats, air_conditioned, wheelchair_accessible, luggage_space`
+
+func scanVehicle(row pgx.Row) (*pb.Vehicle, error) {
+	v := &pb.Vehicle{}
+	if err := row.Scan(&v.Id, &v.Plate, &v.Make, &v.Model, &v.Colour, &v.MaxSeats, &v.AirConditioned, &v.WheelchairAccessible, &v.LuggageSpace); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (p *PostgresRepository) SaveVehicle(ctx context.Context, vehicle *pb.Vehicle, driverID string) (string, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	key := plateKey(vehicle.Plate)
+	id := vehicle.Id
+	var owner string
+	err = tx.QueryRow(ctx, `select id from vehicles where plate_key = $1 for update`, key).Scan(&owner)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return "", err
+	case id == "":
+		id = owner
+	case id != owner:
+		return "", ErrDuplicatePlate
+	}
+	if id == "" {
+		id = uuid.New().String()
+	} else {
+		// Only an unlinked vehicle, or one driverID drives, may be changed.
+		var linkedElsewhere bool
+		err = tx.QueryRow(ctx, `
+			select exists (select 1 from driver_vehicles where vehicle_id = $1)
+				and not exists (select 1 from driver_vehicles where vehicle_id = $1 and driver_id = $2)
+		`, id, driverID).Scan(&linkedElsewhere)
+		if err != nil {
+			return "", err
+		}
+		if linkedElsewhere {
+			return "", ErrDuplicatePlate
+		}
+	}
+	_, err = tx.Exec(ctx, `
+		insert into vehicles (id, plate, plate_key, make, model, colour, max_seats, air_conditioned, wheelchair_accessible, luggage_space)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		on conflict (id) do update set
+			plate = excluded.plate,
+			plate_key = excluded.plate_key,
+			make = excluded.make,
+			model = excluded.model,
+			colour = excluded.colour,
+			max_seats = excluded.max_seats,
+			air_conditioned = excluded.air_conditioned,
+			wheelchair_accessible = excluded.wheelchair_accessible,
+			luggage_space = excluded.luggage_space
+	`, id, vehicle.Plate, key, vehicle.Make, vehicle.Model, vehicle.Colour, vehicle.MaxSeats, vehicle.AirConditioned, vehicle.WheelchairAccessible, vehicle.LuggageSpace)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		// Another replica registered the plate first.
+		return "", ErrDuplicatePlate
+	}
+	if err != nil {
+		return "", err
+	}
+	return id, tx.Commit(ctx)
+}
+
+func (p *PostgresRepository) Vehicle(ctx context.Context, id string) (*pb.Vehicle, error) {
+	v, err := scanVehicle(p.pool.QueryRow(ctx, `select `+vehicleColumns+` from vehicles where id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return v, err
+}
+
+func (p *PostgresRepository) Vehicles(ctx context.Context, driverID string) ([]*pb.Vehicle, error) {
+	rows, err := p.pool.Query(ctx, `
+		select `+vehicleColumns+` from vehicles v
+		where $1 = '' or exists (select 1 from driver_vehicles dv where dv.vehicle_id = v.id and dv.driver_id = $1)
+		order by plate
+	`, driverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*pb.Vehicle
+	for rows.Next() {
+		v, err := scanVehicle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func (p *PostgresRepository) LinkVehicle(ctx context.Context, driverID, vehicleID string) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var driverFound, vehicleFound bool
+	err = tx.QueryRow(ctx, `
+		select exists (select 1 from drivers where id = $1), exists (select 1 from vehicles where id = $2)
+	`, driverID, vehicleID).Scan(&driverFound, &vehicleFound)
+	if err != nil {
+		return err
+	}
+	if !driverFound || !vehicleFound {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `insert into driver_vehicles (driver_id, vehicle_id) values ($1, $2) on conflict do nothing`, driverID, vehicleID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `update drivers set active_vehicle_id = $2 where id = $1 and active_vehicle_id is null`, driverID, vehicleID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresRepository) SelectVehicle(ctx context.Context, driverID, vehicleID string) error {
+	tag, err := p.pool.Exec(ctx, `
+		update drivers set active_vehicle_id = $2
+		where id = $1 and exists (select 1 from driver_vehicles where driver_id = $1 and vehicle_id = $2)
+	`, driverID, vehicleID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+	if _, err := p.Driver(ctx, driverID); err != nil {
+		return err
+	}
+	return ErrVehicleNotLinked
+}
@@ -0,0 +1,53 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPostgresRepository connects to DRIVER_TEST_DSN, skipping the test
+// when it is unset. The route tables normally come from schema.sql, which
+// needs Supabase's auth schema, so the columns used here are created
+// directly.
+func newTestPostgresRepository(t *testing.T) *PostgresRepository {
+	t.Helper()
+	dsn := os.Getenv("DRIVER_TEST_DSN")
+	if dsn == "" {
+		t.Skip("DRIVER_TEST_DSN not set")
+	}
+	ctx := context.Background()
+	repo, err := NewPostgresRepository(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(repo.Close)
+	_, err = repo.pool.Exec(ctx, `
+		create table if not exists driver_routes (
+			id uuid primary key default gen_random_uuid(),
+			driver_id text not null unique,
+			seats_total integer not null default 1,
+			seats_available integer not null default 1,
+			metadata jsonb not null default '{}'::jsonb
+		);
+		create table if not exists driver_route_pickups (
+			route_id uuid references driver_routes(id) on delete cascade,
+			sequence integer not null,
+			station_id text not null,
+			primary key (route_id, sequence)
+		);
+	`)
+	require.NoError(t, err)
+	return repo
+}
+
+func TestPostgresSeatUpdatesKeepTheRouteCapacity(t *testing.T) {
+	repo := newTestPostgresRepository(t)
+	driverID := fmt.Sprintf("driver-capacity-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = repo.pool.Exec(context.Background(), `delete from driver_routes where driver_id = $1`, driverID)
+	})
+	testSeatUpdatesKeepTheRouteCapacity(t, repo, driverID)
+}
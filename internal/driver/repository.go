@@ -0,0 +1,298 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	pb "lastmile/gen/go/driver"
+)
+
+var (
+	// ErrNotFound is returned by a Repository for unknown drivers, routes and
+	// vehicles.
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicatePlate is returned when a vehicle's plate belongs to another
+	// vehicle, or to a vehicle another driver has linked.
+	ErrDuplicatePlate = errors.New("plate already registered")
+	// ErrVehicleNotLinked is returned when a driver selects a vehicle they
+	// have not linked.
+	ErrVehicleNotLinked = errors.New("vehicle not linked to driver")
+)
+
+// Repository stores drivers, with their vehicle details, and the route each
+// driver currently offers. Every replica sharing a repository serves the
+// same drivers.
+type Repository interface {
+	// SaveDriver stores the driver's details. New drivers start offline and
+	// an existing driver keeps their status and vehicles.
+	SaveDriver(ctx context.Context, driver *pb.Driver) error
+	Driver(ctx context.Context, id string) (*pb.Driver, error)
+	// Drivers returns every driver ordered by id.
+	Drivers(ctx context.Context) ([]*pb.Driver, error)
+	// SaveRoute stores the driver's route, replacing any earlier one; the
+	// route keeps the id of the one it replaces. A route without SeatsTotal
+	// keeps the earlier capacity, or takes its available seats as the
+	// capacity when new, and its available seats are capped at the capacity.
+	// It returns the route's id.
+	SaveRoute(ctx context.Context, route *pb.Route) (string, error)
+	RouteForDriver(ctx context.Context, driverID string) (*pb.Route, error)
+	// Routes returns every route ordered by driver id.
+	Routes(ctx context.Context) ([]*pb.Route, error)
+	// SetDriverStatus moves the driver to status if CheckTransition allows
+	// it, checking and writing in one step. It returns the status the driver
+	// had before, also when the transition is refused.
+	SetDriverStatus(ctx context.Context, driverID string, status pb.DriverStatus) (pb.DriverStatus, error)
+
+	// SaveVehicle stores a vehicle for driverID, who may be empty, and
+	// returns its id. A vehicle sent without an id but with a known plate
+	// updates that vehicle. An existing vehicle is only updated when no
+	// driver has linked it or driverID has; otherwise ErrDuplicatePlate.
+	SaveVehicle(ctx context.Context, vehicle *pb.Vehicle, driverID string) (string, error)
+	Vehicle(ctx context.Context, id string) (*pb.Vehicle, error)
+	// Vehicles returns the vehicles linked to driverID, or every vehicle when
+	// driverID is empty, ordered by plate.
+	Vehicles(ctx context.Context, driverID string) ([]*pb.Vehicle, error)
+	// LinkVehicle links a vehicle to a driver. The first vehicle a driver
+	// links becomes their active one; linking again changes nothing.
+	LinkVehicle(ctx context.Context, driverID, vehicleID string) error
+	// SelectVehicle makes one of the driver's linked vehicles their active one.
+	SelectVehicle(ctx context.Context, driverID, vehicleID string) error
+}
+
+// MemoryRepository is a Repository for a single process. It is safe for
+// concurrent use, and stores and returns copies so callers can keep using
+// their messages.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	drivers map[string]*pb.Driver
+	// routes is keyed by driver id; a driver has at most one route.
+	routes   map[string]*pb.Route
+	vehicles map[string]*pb.Vehicle
+	// plates maps plateKey to vehicle id.
+	plates map[string]string
+}
+
+// NewMemoryRepository returns an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		drivers:  make(map[string]*pb.Driver),
+		routes:   make(map[string]*pb.Route),
+		vehicles: make(map[string]*pb.Vehicle),
+		plates:   make(map[string]string),
+	}
+}
+
+func (m *MemoryRepository) SaveDriver(_ context.Context, driver *pb.Driver) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := proto.Clone(driver).(*pb.Driver)
+	d.Status = pb.DriverStatus_DRIVER_STATUS_OFFLINE
+	d.VehicleIds, d.ActiveVehicleId = nil, ""
+	if existing, ok := m.drivers[d.Id]; ok {
+		d.Status = existing.Status
+		d.VehicleIds, d.ActiveVehicleId = existing.VehicleIds, existing.ActiveVehicleId
+	}
+	m.drivers[d.Id] = d
+	return nil
+}
+
+func (m *MemoryRepository) Driver(_ context.Context, id string) (*pb.Driver, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.drivers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(d).(*pb.Driver), nil
+}
+
+func (m *MemoryRepository) Drivers(_ context.Context) ([]*pb.Driver, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*pb.Driver, 0, len(m.drivers))
+	for _, d := range m.drivers {
+		out = append(out, proto.Clone(d).(*pb.Driver))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Id < out[j].Id })
+	return out, nil
+}
+
+func (m *MemoryRepository) SaveRoute(_ context.Context, route *pb.Route) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := proto.Clone(route).(*pb.Route)
+	if existing, ok := m.routes[r.DriverId]; ok {
+		r.Id = existing.Id
+		if r.SeatsTotal == 0 {
+			r.SeatsTotal = existing.SeatsTotal
+		}
+	} else if r.Id == "" {
+		r.Id = uuid.New().String()
+	}
+	if r.SeatsTotal == 0 {
+		r.SeatsTotal = r.AvailableSeats
+	}
+	r.AvailableSeats = min(r.AvailableSeats, r.SeatsTotal)
+	m.routes[r.DriverId] = r
+	return r.Id, nil
+}
+
+func (m *MemoryRepository) RouteForDriver(_ context.Context, driverID string) (*pb.Route, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.routes[driverID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(r).(*pb.Route), nil
+}
+
+func (m *MemoryRepository) Routes(_ context.Context) ([]*pb.Route, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*pb.Route, 0, len(m.routes))
+	for _, r := range m.routes {
+		out = append(out, proto.Clone(r).(*pb.Route))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DriverId < out[j].DriverId })
+	return out, nil
+}
+
+func (m *MemoryRepository) SetDriverStatus(_ context.Context, driverID string, status pb.DriverStatus) (pb.DriverStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.drivers[driverID]
+	if !ok {
+		return pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED, ErrNotFound
+	}
+	previous := d.Status
+	if err := CheckTransition(previous, status); err != nil {
+		return previous, err
+	}
+	d.Status = status
+	return previous, nil
+}
+
+// plateKey is the form plates are compared in: upper case without spaces or
+// dashes.
+func plateKey(plate string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, plate)
+}
+
+func (m *MemoryRepository) SaveVehicle(_ context.Context, vehicle *pb.Vehicle, driverID string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v := proto.Clone(vehicle).(*pb.Vehicle)
+	key := plateKey(v.Plate)
+	if owner, ok := m.plates[key]; ok {
+		if v.Id == "" {
+			v.Id = owner
+		} else if owner != v.Id {
+			return "", ErrDuplicatePlate
+		}
+	}
+	if v.Id == "" {
+		v.Id = uuid.New().String()
+	}
+	if old, ok := m.vehicles[v.Id]; ok {
+		if m.linkedByOtherLocked(v.Id, driverID) {
+			return "", ErrDuplicatePlate
+		}
+		delete(m.plates, plateKey(old.Plate))
+	}
+	m.vehicles[v.Id] = v
+	m.plates[key] = v.Id
+	return v.Id, nil
+}
+
+// linkedByOtherLocked reports whether the vehicle is linked to drivers but
+// not to driverID.
+func (m *MemoryRepository) linkedByOtherLocked(vehicleID, driverID string) bool {
+	linked := false
+	for id, d := range m.drivers {
+		if slices.Contains(d.VehicleIds, vehicleID) {
+			if id == driverID {
+				return false
+			}
+			linked = true
+		}
+	}
+	return linked
+}
+
+func (m *MemoryRepository) Vehicle(_ context.Context, id string) (*pb.Vehicle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.vehicles[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(v).(*pb.Vehicle), nil
+}
+
+func (m *MemoryRepository) Vehicles(_ context.Context, driverID string) ([]*pb.Vehicle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ids []string
+	if driverID == "" {
+		for id := range m.vehicles {
+			ids = append(ids, id)
+		}
+	} else if d, ok := m.drivers[driverID]; ok {
+		ids = d.VehicleIds
+	}
+	out := make([]*pb.Vehicle, 0, len(ids))
+	for _, id := range ids {
+		if v, ok := m.vehicles[id]; ok {
+			out = append(out, proto.Clone(v).(*pb.Vehicle))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Plate < out[j].Plate })
+	return out, nil
+}
+
+func (m *MemoryRepository) LinkVehicle(_ context.Context, driverID, vehicleID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.drivers[driverID]
+	if !ok {
+		return ErrNotFound
+	}
+	if _, ok := m.vehicles[vehicleID]; !ok {
+		return ErrNotFound
+	}
+	if !slices.Contains(d.VehicleIds, vehicleID) {
+		d.VehicleIds = append(d.VehicleIds, vehicleID)
+	}
+	if d.ActiveVehicleId == "" {
+		d.ActiveVehicleId = vehicleID
+	}
+	return nil
+}
+
+func (m *MemoryRepository) SelectVehicle(_ context.Context, driverID, vehicleID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.drivers[driverID]
+	if !ok {
+		return ErrNotFound
+	}
+	if !slices.Contains(d.VehicleIds, vehicleID) {
+		return ErrVehicleNotLinked
+	}
+	d.ActiveVehicleId = vehicleID
+	return nil
+}
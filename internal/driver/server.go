@@ -2,6 +2,7 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/google/uuid"
@@ -15,29 +16,33 @@ import (
 // Server implements the DriverServiceServer interface.
 type Server struct {
 	pb.UnimplementedDriverServiceServer
-	drivers      map[string]*pb.Driver
-	routes       map[string]*pb.Route
-	driverRoutes map[string]string
-	logger       *slog.Logger
+	repo     Repository
+	statuses *statusBus
+	logger   *slog.Logger
 }
 
-// NewServer creates a new Server.
+// NewServer creates a new Server that keeps drivers in memory.
 // An optional logger can be provided; slog.Default() is used otherwise.
 func NewServer(logger ...*slog.Logger) *Server {
+	return NewServerWithRepository(NewMemoryRepository(), logger...)
+}
+
+// NewServerWithRepository creates a Server backed by repo.
+func NewServerWithRepository(repo Repository, logger ...*slog.Logger) *Server {
 	l := logging.New("driver")
 	if len(logger) > 0 && logger[0] != nil {
 		l = logger[0]
 	}
 
 	return &Server{
-		drivers:      make(map[string]*pb.Driver),
-		routes:       make(map[string]*pb.Route),
-		driverRoutes: make(map[string]string),
-		logger:       l,
+		repo:     repo,
+		statuses: newStatusBus(),
+		logger:   l,
 	}
 }
 
-// RegisterDriver registers a new driver.
+// RegisterDriver registers a new driver, or updates the details of an
+// existing one without touching their status.
 func (s *Server) RegisterDriver(ctx context.Context, req *pb.RegisterDriverRequest) (*pb.RegisterDriverResponse, error) {
 	if req.Driver == nil {
 		s.logger.Warn("register driver: missing driver payload")
@@ -49,7 +54,10 @@ func (s *Server) RegisterDriver(ctx context.Context, req *pb.RegisterDriverReque
 		id = uuid.New().String()
 	}
 	req.Driver.Id = id
-	s.drivers[id] = req.Driver
+	if err := s.repo.SaveDriver(ctx, req.Driver); err != nil {
+		s.logger.Error("register driver: save failed", "driverId", id, "err", err)
+		return nil, status.Errorf(codes.Unavailable, "save driver: %v", err)
+	}
 	s.logger.Info("driver registered", "driverId", id, "name", req.Driver.Name)
 
 	return &pb.RegisterDriverResponse{Id: id}, nil
@@ -68,29 +76,40 @@ func (s *Server) RegisterRoute(ctx context.Context, req *pb.RegisterRouteRequest
 		return nil, status.Errorf(codes.InvalidArgument, "driverId is required")
 	}
 
-	if existingID, ok := s.driverRoutes[req.Route.DriverId]; ok {
-		req.Route.Id = existingID
-	} else if req.Route.Id == "" {
-		req.Route.Id = uuid.New().String()
+	// A route never offers more seats than the vehicle being driven has.
+	vehicle, err := s.activeVehicle(ctx, req.Route.DriverId)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "get vehicle: %v", err)
+	}
+	if vehicle != nil && req.Route.AvailableSeats > vehicle.MaxSeats {
+		req.Route.AvailableSeats = vehicle.MaxSeats
+	}
+	if vehicle != nil && req.Route.SeatsTotal > vehicle.MaxSeats {
+		req.Route.SeatsTotal = vehicle.MaxSeats
 	}
 
-	s.routes[req.Route.Id] = req.Route
-	s.driverRoutes[req.Route.DriverId] = req.Route.Id
-	s.logger.Info("route registered", "routeId", req.Route.Id, "driverId", req.Route.DriverId, "targetStations", req.Route.TargetStationIds)
+	id, err := s.repo.SaveRoute(ctx, req.Route)
+	if err != nil {
+		s.logger.Error("register route: save failed", "driverId", req.Route.DriverId, "err", err)
+		return nil, status.Errorf(codes.Unavailable, "save route: %v", err)
+	}
+	req.Route.Id = id
+	s.logger.Info("route registered", "routeId", id, "driverId", req.Route.DriverId, "targetStations", req.Route.TargetStationIds)
 
-	return &pb.RegisterRouteResponse{Id: req.Route.Id}, nil
+	return &pb.RegisterRouteResponse{Id: id}, nil
 }
 
 // ListDrivers returns all registered drivers and their routes.
 func (s *Server) ListDrivers(ctx context.Context, req *pb.ListDriversRequest) (*pb.ListDriversResponse, error) {
-	drivers := make([]*pb.Driver, 0, len(s.drivers))
-	for _, d := range s.drivers {
-		drivers = append(drivers, d)
+	drivers, err := s.repo.Drivers(ctx)
+	if err != nil {
+		s.logger.Error("list drivers failed", "err", err)
+		return nil, status.Errorf(codes.Unavailable, "list drivers: %v", err)
 	}
-
-	routes := make([]*pb.Route, 0, len(s.routes))
-	for _, r := range s.routes {
-		routes = append(routes, r)
+	routes, err := s.repo.Routes(ctx)
+	if err != nil {
+		s.logger.Error("list routes failed", "err", err)
+		return nil, status.Errorf(codes.Unavailable, "list routes: %v", err)
 	}
 
 	return &pb.ListDriversResponse{
@@ -98,3 +117,28 @@ func (s *Server) ListDrivers(ctx context.Context, req *pb.ListDriversRequest) (*
 		Routes:  routes,
 	}, nil
 }
+
+// GetRoute returns a driver together with the route currently registered for them.
+func (s *Server) GetRoute(ctx context.Context, req *pb.GetRouteRequest) (*pb.GetRouteResponse, error) {
+	if req.DriverId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "driverId is required")
+	}
+
+	route, err := s.repo.RouteForDriver(ctx, req.DriverId)
+	if errors.Is(err, ErrNotFound) {
+		s.logger.Warn("route not found", "driverId", req.DriverId)
+		return nil, status.Errorf(codes.NotFound, "no route registered for driver '%s'", req.DriverId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "get route: %v", err)
+	}
+	driver, err := s.repo.Driver(ctx, req.DriverId)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, status.Errorf(codes.Unavailable, "get driver: %v", err)
+	}
+
+	return &pb.GetRouteResponse{
+		Driver: driver,
+		Route:  route,
+	}, nil
+}
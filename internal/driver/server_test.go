@@ -2,6 +2,8 @@ package driver
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,8 +26,8 @@ func TestRegisterDriver(t *testing.T) {
 	assert.NotEmpty(t, res.Id)
 
 	// Check if the driver was actually added
-	driver, ok := s.drivers[res.Id]
-	assert.True(t, ok)
+	driver, err := s.repo.Driver(context.Background(), res.Id)
+	require.NoError(t, err)
 	assert.Equal(t, "John Doe", driver.Name)
 }
 
@@ -44,7 +46,106 @@ func TestRegisterRoute(t *testing.T) {
 	assert.NotEmpty(t, res.Id)
 
 	// Check if the route was actually added
-	route, ok := s.routes[res.Id]
-	assert.True(t, ok)
+	route, err := s.repo.RouteForDriver(context.Background(), "driver-123")
+	require.NoError(t, err)
+	assert.Equal(t, res.Id, route.Id)
 	assert.Equal(t, "driver-123", route.DriverId)
 }
+
+func TestGetRoute(t *testing.T) {
+	s := NewServer()
+	_, err := s.RegisterRoute(context.Background(), &pb.RegisterRouteRequest{
+		Route: &pb.Route{DriverId: "driver-123", TargetStationIds: []string{"station-1"}, AvailableSeats: 2},
+	})
+	require.NoError(t, err)
+
+	res, err := s.GetRoute(context.Background(), &pb.GetRouteRequest{DriverId: "driver-123"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), res.Route.AvailableSeats)
+
+	_, err = s.GetRoute(context.Background(), &pb.GetRouteRequest{DriverId: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestRegisterRouteKeepsTheDriversRouteID(t *testing.T) {
+	s := NewServer()
+	first, err := s.RegisterRoute(context.Background(), &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: "driver-123", AvailableSeats: 2}})
+	require.NoError(t, err)
+	second, err := s.RegisterRoute(context.Background(), &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: "driver-123", AvailableSeats: 3, SeatsTotal: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, first.Id, second.Id)
+
+	res, err := s.ListDrivers(context.Background(), &pb.ListDriversRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Routes, 1)
+	assert.Equal(t, int32(3), res.Routes[0].AvailableSeats)
+	assert.Equal(t, int32(3), res.Routes[0].SeatsTotal)
+}
+
+func TestSeatUpdatesKeepTheRouteCapacity(t *testing.T) {
+	testSeatUpdatesKeepTheRouteCapacity(t, NewMemoryRepository(), "driver-capacity")
+}
+
+// testSeatUpdatesKeepTheRouteCapacity registers a four-seat route and then
+// updates its seats the way MatchingService does after a match, without a
+// capacity.
+func testSeatUpdatesKeepTheRouteCapacity(t *testing.T, repo Repository, driverID string) {
+	s := NewServerWithRepository(repo)
+	ctx := context.Background()
+	_, err := s.RegisterRoute(ctx, &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: driverID, AvailableSeats: 4, SeatsTotal: 4}})
+	require.NoError(t, err)
+
+	_, err = s.RegisterRoute(ctx, &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: driverID, AvailableSeats: 3}})
+	require.NoError(t, err)
+	route, err := repo.RouteForDriver(ctx, driverID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, route.SeatsTotal, "a match does not change the capacity")
+	assert.EqualValues(t, 3, route.AvailableSeats)
+
+	_, err = s.RegisterRoute(ctx, &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: driverID, AvailableSeats: 6}})
+	require.NoError(t, err)
+	route, err = repo.RouteForDriver(ctx, driverID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, route.AvailableSeats, "available seats never exceed the capacity")
+
+	_, err = s.RegisterRoute(ctx, &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: driverID, AvailableSeats: 2, SeatsTotal: 2}})
+	require.NoError(t, err)
+	route, err = repo.RouteForDriver(ctx, driverID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, route.SeatsTotal, "a new route may shrink the capacity")
+	assert.EqualValues(t, 2, route.AvailableSeats)
+}
+
+func TestServersSharingARepositoryListTheSameDrivers(t *testing.T) {
+	repo := NewMemoryRepository()
+	s := NewServerWithRepository(repo)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("driver-%02d", i)
+			_, err := s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: id, Name: id}})
+			assert.NoError(t, err)
+			_, err = s.RegisterRoute(ctx, &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: id, AvailableSeats: 2}})
+			assert.NoError(t, err)
+			_, err = s.ListDrivers(ctx, &pb.ListDriversRequest{})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	// A restarted replica on the same repository serves the same drivers.
+	before, err := s.ListDrivers(ctx, &pb.ListDriversRequest{})
+	require.NoError(t, err)
+	after, err := NewServerWithRepository(repo).ListDrivers(ctx, &pb.ListDriversRequest{})
+	require.NoError(t, err)
+	require.Len(t, after.Drivers, 20)
+	assert.Equal(t, "driver-00", after.Drivers[0].Id)
+	for i := range before.Drivers {
+		assert.Equal(t, before.Drivers[i].Id, after.Drivers[i].Id)
+		assert.Equal(t, before.Routes[i].Id, after.Routes[i].Id)
+	}
+}
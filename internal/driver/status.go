@@ -0,0 +1,174 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/driver"
+	"lastmile/internal/pkg/driverstatus"
+)
+
+// ErrInvalidTransition is returned, wrapped with the statuses involved, when a
+// driver cannot move from their current status to the requested one.
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// transitions lists the statuses each status may move to. A driver has to
+// come online before taking riders, and has to drop everyone off before going
+// offline.
+var transitions = map[pb.DriverStatus][]pb.DriverStatus{
+	pb.DriverStatus_DRIVER_STATUS_OFFLINE:            {pb.DriverStatus_DRIVER_STATUS_AVAILABLE},
+	pb.DriverStatus_DRIVER_STATUS_AVAILABLE:          {pb.DriverStatus_DRIVER_STATUS_OFFLINE, pb.DriverStatus_DRIVER_STATUS_EN_ROUTE_TO_PICKUP, pb.DriverStatus_DRIVER_STATUS_FULL},
+	pb.DriverStatus_DRIVER_STATUS_EN_ROUTE_TO_PICKUP: {pb.DriverStatus_DRIVER_STATUS_AVAILABLE, pb.DriverStatus_DRIVER_STATUS_FULL},
+	pb.DriverStatus_DRIVER_STATUS_FULL:               {pb.DriverStatus_DRIVER_STATUS_AVAILABLE, pb.DriverStatus_DRIVER_STATUS_EN_ROUTE_TO_PICKUP},
+}
+
+// CheckTransition reports whether a driver may move from one status to
+// another. Staying in the same status is always allowed.
+func CheckTransition(from, to pb.DriverStatus) error {
+	if _, ok := transitions[to]; !ok {
+		return fmt.Errorf("%w: unknown status %s", ErrInvalidTransition, to)
+	}
+	if from == to {
+		return nil
+	}
+	for _, next := range transitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, driverstatus.Name(from), driverstatus.Name(to))
+}
+
+// statusWatcherBuffer is how many events a watcher may have unsent before it
+// is disconnected.
+const statusWatcherBuffer = 32
+
+// statusBus fans status changes out to WatchDriverStatus streams. It only
+// carries changes made through this replica.
+type statusBus struct {
+	mu   sync.Mutex
+	subs map[*statusWatcher]bool
+}
+
+// statusWatcher is one WatchDriverStatus stream. A watcher that lets its
+// buffer fill is dropped and lagged closed, rather than have it skip a
+// transition silently.
+type statusWatcher struct {
+	filter map[string]bool
+	events chan *pb.DriverStatusEvent
+	lagged chan struct{}
+}
+
+func newStatusBus() *statusBus {
+	return &statusBus{subs: make(map[*statusWatcher]bool)}
+}
+
+// subscribe registers for changes to the given drivers, or every driver when
+// none are given. The returned func unsubscribes.
+func (b *statusBus) subscribe(driverIDs []string) (*statusWatcher, func()) {
+	w := &statusWatcher{
+		events: make(chan *pb.DriverStatusEvent, statusWatcherBuffer),
+		lagged: make(chan struct{}),
+	}
+	if len(driverIDs) > 0 {
+		w.filter = make(map[string]bool, len(driverIDs))
+		for _, id := range driverIDs {
+			w.filter[id] = true
+		}
+	}
+	b.mu.Lock()
+	b.subs[w] = true
+	b.mu.Unlock()
+	return w, func() {
+		b.mu.Lock()
+		delete(b.subs, w)
+		b.mu.Unlock()
+	}
+}
+
+func (b *statusBus) publish(ev *pb.DriverStatusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for w := range b.subs {
+		if w.filter != nil && !w.filter[ev.DriverId] {
+			continue
+		}
+		select {
+		case w.events <- ev:
+		default:
+			// Disconnect slow watchers rather than hold up status updates.
+			delete(b.subs, w)
+			close(w.lagged)
+		}
+	}
+}
+
+// SetDriverStatus moves a driver to a new status when the transition is
+// allowed, and emits a DriverStatusEvent when the status actually changes.
+func (s *Server) SetDriverStatus(ctx context.Context, req *pb.SetDriverStatusRequest) (*pb.SetDriverStatusResponse, error) {
+	if req.DriverId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "driverId is required")
+	}
+	if _, ok := transitions[req.Status]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status %s", req.Status)
+	}
+
+	previous, err := s.repo.SetDriverStatus(ctx, req.DriverId, req.Status)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "driver '%s' not found", req.DriverId)
+	case errors.Is(err, ErrInvalidTransition):
+		s.logger.Warn("driver status rejected", "driverId", req.DriverId, "from", driverstatus.Name(previous), "to", driverstatus.Name(req.Status))
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	case err != nil:
+		s.logger.Error("set driver status failed", "driverId", req.DriverId, "err", err)
+		return nil, status.Errorf(codes.Unavailable, "set driver status: %v", err)
+	}
+
+	ev := &pb.DriverStatusEvent{
+		DriverId:  req.DriverId,
+		Status:    req.Status,
+		Previous:  previous,
+		Reason:    req.Reason,
+		ChangedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if previous != req.Status {
+		s.logger.Info("driver status changed", "driverId", req.DriverId, "from", driverstatus.Name(previous), "to", driverstatus.Name(req.Status), "reason", req.Reason)
+		s.statuses.publish(ev)
+	}
+	return &pb.SetDriverStatusResponse{Change: ev}, nil
+}
+
+// WatchDriverStatus streams status changes for the requested drivers as
+// they happen. Only changes made through this replica are seen: the stream
+// is fed in memory, not from the repository, so with more than one driver
+// service replica a watcher misses changes the others apply. Run a single
+// replica while anything depends on the stream being complete. A watcher
+// that falls too far behind is disconnected with ResourceExhausted instead of
+// skipping changes.
+func (s *Server) WatchDriverStatus(req *pb.WatchDriverStatusRequest, stream grpc.ServerStreamingServer[pb.DriverStatusEvent]) error {
+	w, unsubscribe := s.statuses.subscribe(req.DriverIds)
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case ev := <-w.events:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-w.lagged:
+			s.logger.Warn("driver status watcher fell behind, disconnecting", "buffer", statusWatcherBuffer)
+			return status.Errorf(codes.ResourceExhausted, "watcher fell more than %d status changes behind; rewatch and re-read the drivers' statuses", statusWatcherBuffer)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
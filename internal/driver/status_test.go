@@ -0,0 +1,224 @@
+package driver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "lastmile/gen/go/driver"
+	"lastmile/internal/pkg/driverstatus"
+)
+
+const (
+	offline  = pb.DriverStatus_DRIVER_STATUS_OFFLINE
+	free     = pb.DriverStatus_DRIVER_STATUS_AVAILABLE
+	enRoute  = pb.DriverStatus_DRIVER_STATUS_EN_ROUTE_TO_PICKUP
+	full     = pb.DriverStatus_DRIVER_STATUS_FULL
+	noStatus = pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED
+)
+
+func serveDriver(t *testing.T, s *Server) pb.DriverServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	g := grpc.NewServer()
+	pb.RegisterDriverServiceServer(g, s)
+	go func() { _ = g.Serve(lis) }()
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///driver",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewDriverServiceClient(conn)
+}
+
+func TestCheckTransition(t *testing.T) {
+	cases := []struct {
+		from, to pb.DriverStatus
+		ok       bool
+	}{
+		{offline, free, true},
+		{offline, enRoute, false},
+		{offline, full, false},
+		{free, offline, true},
+		{free, enRoute, true},
+		{free, full, true},
+		{enRoute, free, true},
+		{enRoute, full, true},
+		{enRoute, offline, false},
+		{full, free, true},
+		{full, enRoute, true},
+		{full, offline, false},
+		{free, free, true},
+		{free, noStatus, false},
+	}
+	for _, tc := range cases {
+		err := CheckTransition(tc.from, tc.to)
+		if tc.ok {
+			assert.NoError(t, err, "%s -> %s", driverstatus.Name(tc.from), driverstatus.Name(tc.to))
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidTransition, "%s -> %s", driverstatus.Name(tc.from), driverstatus.Name(tc.to))
+		}
+	}
+}
+
+func TestNewDriversStartOfflineAndKeepTheirStatus(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+	_, err := s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: "driver-1", Name: "Ravi"}})
+	require.NoError(t, err)
+
+	d, err := s.repo.Driver(ctx, "driver-1")
+	require.NoError(t, err)
+	assert.Equal(t, offline, d.Status)
+
+	_, err = s.SetDriverStatus(ctx, &pb.SetDriverStatusRequest{DriverId: "driver-1", Status: free})
+	require.NoError(t, err)
+	// Registering again, as the gateway does on every route change, keeps the status.
+	_, err = s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: "driver-1", Name: "Ravi K", Status: offline}})
+	require.NoError(t, err)
+
+	res, err := s.ListDrivers(ctx, &pb.ListDriversRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Drivers, 1)
+	assert.Equal(t, "Ravi K", res.Drivers[0].Name)
+	assert.Equal(t, free, res.Drivers[0].Status)
+}
+
+func TestSetDriverStatusValidatesTransitions(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+	_, err := s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: "driver-1"}})
+	require.NoError(t, err)
+
+	_, err = s.SetDriverStatus(ctx, &pb.SetDriverStatusRequest{DriverId: "driver-1", Status: full})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "offline drivers must come online first")
+
+	res, err := s.SetDriverStatus(ctx, &pb.SetDriverStatusRequest{DriverId: "driver-1", Status: free, Reason: "shift started"})
+	require.NoError(t, err)
+	assert.Equal(t, offline, res.Change.Previous)
+	assert.Equal(t, free, res.Change.Status)
+	assert.NotEmpty(t, res.Change.ChangedAt)
+
+	_, err = s.SetDriverStatus(ctx, &pb.SetDriverStatusRequest{DriverId: "driver-2", Status: free})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.SetDriverStatus(ctx, &pb.SetDriverStatusRequest{DriverId: "driver-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchDriverStatusStreamsChanges(t *testing.T) {
+	s := NewServer()
+	client := serveDriver(t, s)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, id := range []string{"driver-1", "driver-2"} {
+		_, err := client.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: id}})
+		require.NoError(t, err)
+	}
+
+	stream, err := client.WatchDriverStatus(ctx, &pb.WatchDriverStatusRequest{DriverIds: []string{"driver-1"}})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		s.statuses.mu.Lock()
+		defer s.statuses.mu.Unlock()
+		return len(s.statuses.subs) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	for _, change := range []struct {
+		id     string
+		status pb.DriverStatus
+	}{
+		{"driver-2", free},    // not watched
+		{"driver-1", free},    // offline -> available
+		{"driver-1", free},    // no change, no event
+		{"driver-1", enRoute}, // available -> en route
+		{"driver-1", offline}, // refused
+		{"driver-1", full},    // en route -> full
+	} {
+		_, _ = client.SetDriverStatus(ctx, &pb.SetDriverStatusRequest{DriverId: change.id, Status: change.status, Reason: "test"})
+	}
+
+	var got [][2]pb.DriverStatus
+	for i := 0; i < 3; i++ {
+		ev, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "driver-1", ev.DriverId)
+		assert.Equal(t, "test", ev.Reason)
+		got = append(got, [2]pb.DriverStatus{ev.Previous, ev.Status})
+	}
+	assert.Equal(t, [][2]pb.DriverStatus{{offline, free}, {free, enRoute}, {enRoute, full}}, got)
+}
+
+// blockedStatusStream holds every Send until release is closed.
+type blockedStatusStream struct {
+	grpc.ServerStreamingServer[pb.DriverStatusEvent]
+	ctx     context.Context
+	release chan struct{}
+	sent    []*pb.DriverStatusEvent
+}
+
+func (s *blockedStatusStream) Context() context.Context { return s.ctx }
+
+func (s *blockedStatusStream) Send(ev *pb.DriverStatusEvent) error {
+	<-s.release
+	s.sent = append(s.sent, ev)
+	return nil
+}
+
+func TestWatchDriverStatusDisconnectsSlowWatchers(t *testing.T) {
+	s := NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &blockedStatusStream{ctx: ctx, release: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() { done <- s.WatchDriverStatus(&pb.WatchDriverStatusRequest{}, stream) }()
+	require.Eventually(t, func() bool {
+		s.statuses.mu.Lock()
+		defer s.statuses.mu.Unlock()
+		return len(s.statuses.subs) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	// One event is held in Send; the rest overflow the buffer.
+	total := statusWatcherBuffer + 5
+	for i := 0; i < total; i++ {
+		s.statuses.publish(&pb.DriverStatusEvent{DriverId: "driver-1", Reason: "test"})
+	}
+	close(stream.release)
+
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	case <-time.After(time.Second):
+		t.Fatal("slow watcher was not disconnected")
+	}
+	assert.Less(t, len(stream.sent), total)
+	s.statuses.mu.Lock()
+	assert.Empty(t, s.statuses.subs)
+	s.statuses.mu.Unlock()
+}
+
+func TestWatchDriverStatusKeepsWatchersThatKeepUp(t *testing.T) {
+	bus := newStatusBus()
+	w, unsubscribe := bus.subscribe(nil)
+	defer unsubscribe()
+	for i := 0; i < 3*statusWatcherBuffer; i++ {
+		bus.publish(&pb.DriverStatusEvent{DriverId: "driver-1", ChangedAt: time.Unix(int64(i), 0).UTC().Format(time.RFC3339)})
+		ev := <-w.events
+		require.Equal(t, time.Unix(int64(i), 0).UTC().Format(time.RFC3339), ev.ChangedAt)
+	}
+	select {
+	case <-w.lagged:
+		t.Fatal("a watcher that kept up was disconnected")
+	default:
+	}
+}
@@ -0,0 +1,139 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/driver"
+)
+
+// vehicleError maps repository errors from the vehicle calls to gRPC codes.
+func vehicleError(op string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s: driver or vehicle not found", op)
+	case errors.Is(err, ErrDuplicatePlate):
+		return status.Errorf(codes.AlreadyExists, "%s: %v", op, err)
+	case errors.Is(err, ErrVehicleNotLinked):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", op, err)
+	default:
+		return status.Errorf(codes.Unavailable, "%s: %v", op, err)
+	}
+}
+
+// RegisterVehicle registers a vehicle, or updates the one with the same id
+// or plate, and links it to driver_id when given. A vehicle another driver
+// has linked cannot be updated or claimed; that is AlreadyExists.
+func (s *Server) RegisterVehicle(ctx context.Context, req *pb.RegisterVehicleRequest) (*pb.RegisterVehicleResponse, error) {
+	v := req.GetVehicle()
+	if v == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "vehicle is required")
+	}
+	v.Plate = strings.ToUpper(strings.TrimSpace(v.Plate))
+	if plateKey(v.Plate) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "plate is required")
+	}
+	if v.MaxSeats < 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "maxSeats must be at least 1")
+	}
+
+	id, err := s.repo.SaveVehicle(ctx, v, req.DriverId)
+	if err != nil {
+		s.logger.Warn("register vehicle failed", "plate", v.Plate, "err", err)
+		return nil, vehicleError("register vehicle", err)
+	}
+	s.logger.Info("vehicle registered", "vehicleId", id, "plate", v.Plate, "maxSeats", v.MaxSeats)
+
+	if req.DriverId != "" {
+		if err := s.repo.LinkVehicle(ctx, req.DriverId, id); err != nil {
+			return nil, vehicleError("link vehicle", err)
+		}
+	}
+	return &pb.RegisterVehicleResponse{Id: id}, nil
+}
+
+// LinkVehicle links a registered vehicle to a driver. A driver's first
+// vehicle becomes the one they are driving.
+func (s *Server) LinkVehicle(ctx context.Context, req *pb.LinkVehicleRequest) (*pb.LinkVehicleResponse, error) {
+	if req.DriverId == "" || req.VehicleId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "driverId and vehicleId are required")
+	}
+	if err := s.repo.LinkVehicle(ctx, req.DriverId, req.VehicleId); err != nil {
+		return nil, vehicleError("link vehicle", err)
+	}
+	driver, err := s.repo.Driver(ctx, req.DriverId)
+	if err != nil {
+		return nil, vehicleError("get driver", err)
+	}
+	s.logger.Info("vehicle linked", "driverId", req.DriverId, "vehicleId", req.VehicleId)
+	return &pb.LinkVehicleResponse{Driver: driver}, nil
+}
+
+// SelectVehicle switches the driver to another of their linked vehicles.
+// Their registered route keeps at most as many seats as the vehicle has.
+func (s *Server) SelectVehicle(ctx context.Context, req *pb.SelectVehicleRequest) (*pb.SelectVehicleResponse, error) {
+	if req.DriverId == "" || req.VehicleId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "driverId and vehicleId are required")
+	}
+	if err := s.repo.SelectVehicle(ctx, req.DriverId, req.VehicleId); err != nil {
+		return nil, vehicleError("select vehicle", err)
+	}
+	driver, err := s.repo.Driver(ctx, req.DriverId)
+	if err != nil {
+		return nil, vehicleError("get driver", err)
+	}
+	vehicle, err := s.repo.Vehicle(ctx, req.VehicleId)
+	if err != nil {
+		return nil, vehicleError("get vehicle", err)
+	}
+	if route, err := s.repo.RouteForDriver(ctx, req.DriverId); err == nil && route.SeatsTotal > vehicle.MaxSeats {
+		// Riders on board keep their seats in the smaller car.
+		taken := route.SeatsTotal - route.AvailableSeats
+		route.SeatsTotal = vehicle.MaxSeats
+		route.AvailableSeats = max(vehicle.MaxSeats-taken, 0)
+		if _, err := s.repo.SaveRoute(ctx, route); err != nil {
+			s.logger.Warn("cap route seats failed", "driverId", req.DriverId, "err", err)
+		}
+	}
+	s.logger.Info("vehicle selected", "driverId", req.DriverId, "vehicleId", req.VehicleId, "plate", vehicle.Plate)
+	return &pb.SelectVehicleResponse{Driver: driver, Vehicle: vehicle}, nil
+}
+
+// ListVehicles lists the driver's linked vehicles and which one they are
+// driving, or every vehicle when no driver is given.
+func (s *Server) ListVehicles(ctx context.Context, req *pb.ListVehiclesRequest) (*pb.ListVehiclesResponse, error) {
+	vehicles, err := s.repo.Vehicles(ctx, req.DriverId)
+	if err != nil {
+		return nil, vehicleError("list vehicles", err)
+	}
+	resp := &pb.ListVehiclesResponse{Vehicles: vehicles}
+	if req.DriverId != "" {
+		driver, err := s.repo.Driver(ctx, req.DriverId)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, vehicleError("get driver", err)
+		}
+		resp.ActiveVehicleId = driver.GetActiveVehicleId()
+	}
+	return resp, nil
+}
+
+// activeVehicle returns the vehicle the driver is driving, or nil when they
+// have none.
+func (s *Server) activeVehicle(ctx context.Context, driverID string) (*pb.Vehicle, error) {
+	driver, err := s.repo.Driver(ctx, driverID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil || driver.ActiveVehicleId == "" {
+		return nil, err
+	}
+	vehicle, err := s.repo.Vehicle(ctx, driver.ActiveVehicleId)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return vehicle, err
+}
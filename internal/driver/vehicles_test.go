@@ -0,0 +1,104 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/driver"
+)
+
+func TestRegisterVehicleLinksAndRejectsDuplicatePlates(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+	_, err := s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: "driver-1"}})
+	require.NoError(t, err)
+
+	_, err = s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{Vehicle: &pb.Vehicle{Plate: " "}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{Vehicle: &pb.Vehicle{Plate: "KA01AB1234"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "a vehicle needs at least one seat")
+
+	res, err := s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{
+		DriverId: "driver-1",
+		Vehicle:  &pb.Vehicle{Plate: "ka 01 ab 1234", Make: "Maruti", Model: "Ertiga", Colour: "white", MaxSeats: 6, AirConditioned: true},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Id)
+
+	// A second vehicle with the same plate, however it is spelled, is refused.
+	_, err = s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{Vehicle: &pb.Vehicle{Id: "other", Plate: "KA-01-AB-1234", MaxSeats: 4}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	list, err := s.ListVehicles(ctx, &pb.ListVehiclesRequest{DriverId: "driver-1"})
+	require.NoError(t, err)
+	require.Len(t, list.Vehicles, 1)
+	assert.Equal(t, "KA 01 AB 1234", list.Vehicles[0].Plate)
+	assert.Equal(t, res.Id, list.ActiveVehicleId, "a driver's first vehicle is the one they drive")
+}
+
+func TestRegisterVehicleCannotTakeAnotherDriversVehicle(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+	for _, id := range []string{"driver-1", "driver-2"} {
+		_, err := s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: id}})
+		require.NoError(t, err)
+	}
+	res, err := s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{DriverId: "driver-1", Vehicle: &pb.Vehicle{Plate: "KA01AB1234", Make: "Maruti", MaxSeats: 6}})
+	require.NoError(t, err)
+
+	_, err = s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{DriverId: "driver-2", Vehicle: &pb.Vehicle{Plate: "ka 01 ab 1234", Make: "Tata", MaxSeats: 2}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err), "another driver's plate is refused")
+	_, err = s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{DriverId: "driver-2", Vehicle: &pb.Vehicle{Id: res.Id, Plate: "KA01AB1234", MaxSeats: 2}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err), "another driver's vehicle id is refused")
+
+	v, err := s.repo.Vehicle(ctx, res.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Maruti", v.Make)
+	assert.EqualValues(t, 6, v.MaxSeats)
+	list, err := s.ListVehicles(ctx, &pb.ListVehiclesRequest{DriverId: "driver-2"})
+	require.NoError(t, err)
+	assert.Empty(t, list.Vehicles)
+
+	// The driver who has it linked can still update it.
+	_, err = s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{DriverId: "driver-1", Vehicle: &pb.Vehicle{Plate: "KA01AB1234", Make: "Maruti", Colour: "grey", MaxSeats: 6}})
+	require.NoError(t, err)
+}
+
+func TestSelectVehicleCapsRouteSeats(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+	_, err := s.RegisterDriver(ctx, &pb.RegisterDriverRequest{Driver: &pb.Driver{Id: "driver-1"}})
+	require.NoError(t, err)
+	van, err := s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{DriverId: "driver-1", Vehicle: &pb.Vehicle{Plate: "KA01VAN", MaxSeats: 6}})
+	require.NoError(t, err)
+	hatch, err := s.RegisterVehicle(ctx, &pb.RegisterVehicleRequest{Vehicle: &pb.Vehicle{Plate: "KA01CAR", MaxSeats: 3}})
+	require.NoError(t, err)
+
+	_, err = s.RegisterRoute(ctx, &pb.RegisterRouteRequest{Route: &pb.Route{DriverId: "driver-1", AvailableSeats: 8}})
+	require.NoError(t, err)
+	route, err := s.repo.RouteForDriver(ctx, "driver-1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, route.AvailableSeats, "seats are capped by the van")
+
+	_, err = s.SelectVehicle(ctx, &pb.SelectVehicleRequest{DriverId: "driver-1", VehicleId: hatch.Id})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "only linked vehicles can be selected")
+
+	_, err = s.LinkVehicle(ctx, &pb.LinkVehicleRequest{DriverId: "driver-1", VehicleId: hatch.Id})
+	require.NoError(t, err)
+	sel, err := s.SelectVehicle(ctx, &pb.SelectVehicleRequest{DriverId: "driver-1", VehicleId: hatch.Id})
+	require.NoError(t, err)
+	assert.Equal(t, hatch.Id, sel.Driver.ActiveVehicleId)
+	assert.ElementsMatch(t, []string{van.Id, hatch.Id}, sel.Driver.VehicleIds)
+
+	route, err = s.repo.RouteForDriver(ctx, "driver-1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, route.AvailableSeats, "switching to a smaller car caps the route")
+
+	_, err = s.SelectVehicle(ctx, &pb.SelectVehicleRequest{DriverId: "driver-9", VehicleId: hatch.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -40,7 +40,7 @@ func (s *Server) TriggerMatch(ctx context.Context, req *gatewaypb.TriggerMatchRe
 		return nil, status.Errorf(codes.InvalidArgument, "driver_id and station_id are required")
 	}
 
-	trip, err := s.gateway.TriggerMatchProto(req.GetDriverId(), req.GetStationId())
+	trip, err := s.gateway.TriggerMatchProto(req.GetDriverId(), req.GetStationId(), int(req.GetArrivalWindowMinutes()))
 	if err != nil {
 		s.logger.Warn("TriggerMatch failed", "driverId", req.GetDriverId(), "stationId", req.GetStationId(), "err", err)
 		return nil, status.Error(codes.FailedPrecondition, err.Error())
@@ -0,0 +1,151 @@
+package location
+
+import (
+	"context"
+	"crypto/subtle"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "lastmile/gen/go/location"
+	"lastmile/internal/pkg/gpsfilter"
+)
+
+// SimulatorMetadataKey carries the simulator token on the gateway's
+// simulator streams. Only samples on a stream presenting the token configured
+// with SetSimulatorToken are trusted as simulated.
+const SimulatorMetadataKey = "x-lastmile-simulator-token"
+
+// anomalyKind names why a sample was not taken as truth.
+type anomalyKind string
+
+const (
+	anomalyInvalid     anomalyKind = "invalid"
+	anomalyLowAccuracy anomalyKind = "low_accuracy"
+	anomalyOutOfOrder  anomalyKind = "out_of_order"
+	anomalyTeleport    anomalyKind = "teleport"
+	anomalyReanchor    anomalyKind = "reanchor"
+)
+
+// driverAnomalies counts a driver's rejected samples for ops.
+type driverAnomalies struct {
+	counts   map[anomalyKind]int64
+	lastKind anomalyKind
+	lastAt   time.Time
+}
+
+// SetGPSFilter replaces the smoothing and teleport detection settings.
+// Call it before serving.
+func (s *Server) SetGPSFilter(cfg gpsfilter.Config) {
+	s.mu.Lock()
+	s.filter = gpsfilter.New(cfg)
+	s.mu.Unlock()
+}
+
+// SetSimulatorToken sets the token the gateway's simulator presents. Without
+// one no stream is trusted to carry simulated drives. Call it before serving.
+func (s *Server) SetSimulatorToken(token string) {
+	s.mu.Lock()
+	s.simulatorToken = token
+	s.mu.Unlock()
+}
+
+// fromSimulator reports whether the stream was opened by the gateway's
+// simulator, which alone may report simulated drives.
+func (s *Server) fromSimulator(ctx context.Context) bool {
+	s.mu.RLock()
+	token := s.simulatorToken
+	s.mu.RUnlock()
+	if token == "" {
+		return false
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, presented := range md.Get(SimulatorMetadataKey) {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSampleLocked smooths the sample in place and reports whether it must
+// be quarantined as an impossible jump. Samples from the gateway's simulator
+// pass untouched, since a replay may start far from the driver's last fix.
+// Other samples flagged as simulated keep their raw position but are still
+// checked for jumps.
+func (s *Server) filterSampleLocked(loc *pb.Location, at time.Time, fromSimulator bool) (quarantine bool) {
+	if loc.Simulated && fromSimulator {
+		s.filter.Forget(loc.DriverId)
+		return false
+	}
+	result := s.filter.Apply(loc.DriverId, gpsfilter.Fix{
+		Latitude:       loc.Latitude,
+		Longitude:      loc.Longitude,
+		AccuracyMeters: loc.AccuracyMeters,
+		SpeedMPS:       loc.SpeedMps,
+		At:             at,
+	})
+	if result.Flagged {
+		s.noteAnomalyLocked(loc.DriverId, anomalyTeleport, at)
+		s.logger.Warn("location sample quarantined",
+			"driverId", loc.DriverId,
+			"lat", loc.Latitude,
+			"long", loc.Longitude,
+			"jumpMeters", result.JumpMeters,
+			"impliedSpeed", result.ImpliedSpeedMPS)
+		return true
+	}
+	if result.Reanchored {
+		s.noteAnomalyLocked(loc.DriverId, anomalyReanchor, at)
+		s.logger.Info("location track re-anchored after consistent jumps", "driverId", loc.DriverId)
+	}
+	if !loc.Simulated {
+		loc.Latitude, loc.Longitude = result.Latitude, result.Longitude
+	}
+	return false
+}
+
+func (s *Server) noteAnomalyLocked(driverID string, kind anomalyKind, at time.Time) {
+	a, ok := s.anomalies[driverID]
+	if !ok {
+		a = &driverAnomalies{counts: make(map[anomalyKind]int64)}
+		s.anomalies[driverID] = a
+	}
+	a.counts[kind]++
+	a.lastKind = kind
+	a.lastAt = at
+}
+
+// GetLocationAnomalies reports how many of each driver's samples were
+// dropped or quarantined.
+func (s *Server) GetLocationAnomalies(ctx context.Context, req *pb.GetLocationAnomaliesRequest) (*pb.GetLocationAnomaliesResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := req.DriverIds
+	if len(ids) == 0 {
+		for id := range s.anomalies {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	}
+	resp := &pb.GetLocationAnomaliesResponse{}
+	for _, id := range ids {
+		a, ok := s.anomalies[id]
+		if !ok {
+			continue
+		}
+		resp.Drivers = append(resp.Drivers, &pb.DriverAnomalies{
+			DriverId:    id,
+			Teleports:   a.counts[anomalyTeleport],
+			Reanchors:   a.counts[anomalyReanchor],
+			OutOfOrder:  a.counts[anomalyOutOfOrder],
+			LowAccuracy: a.counts[anomalyLowAccuracy],
+			Invalid:     a.counts[anomalyInvalid],
+			LastKind:    string(a.lastKind),
+			LastAt:      a.lastAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
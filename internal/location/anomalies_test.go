@@ -0,0 +1,89 @@
+package location
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	pb "lastmile/gen/go/location"
+)
+
+func TestUpdateLocationQuarantinesTeleports(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewLocationServiceClient(conn)
+
+	base := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	stream, err := client.UpdateLocation(ctx)
+	require.NoError(t, err)
+	for _, loc := range []*pb.Location{
+		{DriverId: "driver-teleport", Latitude: 40.4168, Longitude: -3.7038, RecordedAt: base.Format(time.RFC3339), AccuracyMeters: 5},
+		{DriverId: "driver-teleport", Latitude: 40.4172, Longitude: -3.7038, RecordedAt: base.Add(10 * time.Second).Format(time.RFC3339), AccuracyMeters: 20},
+		// Forty kilometres in ten seconds.
+		{DriverId: "driver-teleport", Latitude: 40.78, Longitude: -3.7038, RecordedAt: base.Add(20 * time.Second).Format(time.RFC3339), AccuracyMeters: 5},
+	} {
+		require.NoError(t, stream.Send(&pb.UpdateLocationRequest{Location: loc}))
+	}
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, resp.Accepted)
+	assert.EqualValues(t, 1, resp.Quarantined)
+	require.NotNil(t, resp.Location)
+	assert.Greater(t, resp.Location.Latitude, 40.4168)
+	assert.Less(t, resp.Location.Latitude, 40.4172, "the noisier fix only moves the estimate part of the way")
+
+	locs, err := client.GetDriverLocations(ctx, &pb.GetDriverLocationsRequest{DriverIds: []string{"driver-teleport"}})
+	require.NoError(t, err)
+	require.Len(t, locs.Locations, 1)
+	assert.Equal(t, resp.Location.Latitude, locs.Locations[0].Latitude, "quarantined samples must not replace the last position")
+
+	anomalies, err := client.GetLocationAnomalies(ctx, &pb.GetLocationAnomaliesRequest{DriverIds: []string{"driver-teleport"}})
+	require.NoError(t, err)
+	require.Len(t, anomalies.Drivers, 1)
+	assert.EqualValues(t, 1, anomalies.Drivers[0].Teleports)
+	assert.Equal(t, string(anomalyTeleport), anomalies.Drivers[0].LastKind)
+}
+
+func TestSimulatedSamplesSkipTheFilter(t *testing.T) {
+	s := NewServer()
+	now := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assert.False(t, s.filterSampleLocked(&pb.Location{DriverId: "driver-1", Latitude: 12.84, Longitude: 77.66, AccuracyMeters: 5}, now, false))
+	assert.True(t, s.filterSampleLocked(&pb.Location{DriverId: "driver-1", Latitude: 13.5, Longitude: 77.66, AccuracyMeters: 5}, now.Add(time.Second), false))
+	// A client claiming to be simulated is still checked for jumps.
+	assert.True(t, s.filterSampleLocked(&pb.Location{DriverId: "driver-1", Latitude: 13.5, Longitude: 77.66, Simulated: true}, now.Add(2*time.Second), false))
+	assert.EqualValues(t, 2, s.anomalies["driver-1"].counts[anomalyTeleport])
+	// The gateway's simulator is trusted.
+	assert.False(t, s.filterSampleLocked(&pb.Location{DriverId: "driver-1", Latitude: 13.5, Longitude: 77.66, Simulated: true}, now.Add(3*time.Second), true))
+
+	// An untrusted simulated sample that is not a jump keeps its raw position.
+	loc := &pb.Location{DriverId: "driver-2", Latitude: 12.84, Longitude: 77.66, AccuracyMeters: 5}
+	require.False(t, s.filterSampleLocked(loc, now, false))
+	loc = &pb.Location{DriverId: "driver-2", Latitude: 12.8403, Longitude: 77.66, AccuracyMeters: 50, Simulated: true}
+	require.False(t, s.filterSampleLocked(loc, now.Add(10*time.Second), false))
+	assert.Equal(t, 12.8403, loc.Latitude)
+}
+
+func TestOnlyTheSimulatorTokenMarksAStreamSimulated(t *testing.T) {
+	s := NewServer()
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(SimulatorMetadataKey, token))
+	}
+	assert.False(t, s.fromSimulator(withToken("")), "no stream is trusted without a configured token")
+
+	s.SetSimulatorToken("sim-secret")
+	assert.True(t, s.fromSimulator(withToken("sim-secret")))
+	assert.False(t, s.fromSimulator(withToken("guess")))
+	assert.False(t, s.fromSimulator(context.Background()))
+}
@@ -0,0 +1,329 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	stationpb "lastmile/gen/go/station"
+)
+
+const (
+	fenceStation = "station"
+	fencePickup  = "pickup"
+
+	// defaultStationRadius is how close a driver must come to a station to
+	// enter its zone when neither the station nor the config sets a radius.
+	defaultStationRadius = 800.0 // ~0.5mi
+	// defaultPickupRadius matches the distance at which the gateway counts a
+	// pickup point as reached.
+	defaultPickupRadius = 120.0
+	// exitRadiusRatio puts the exit boundary of a circular fence further out
+	// than its entry radius so GPS jitter at the edge does not register as
+	// repeated exits and entries.
+	exitRadiusRatio = 1.25
+	// polygonExitSlack is the same allowance for polygon fences, in metres
+	// outside the polygon.
+	polygonExitSlack = 200.0
+	// defaultDwellAfter is how long a driver must stay inside a fence before
+	// a dwell event.
+	defaultDwellAfter = 2 * time.Minute
+)
+
+type coord struct {
+	lat float64
+	lon float64
+}
+
+// Fence is a station or pickup-point zone. A non-empty polygon takes
+// precedence over the radius around center.
+type Fence struct {
+	ID        string
+	Kind      string
+	StationID string
+	Center    coord
+	Radius    float64
+	Polygon   []coord
+}
+
+func (f Fence) inside(lat, lon float64) bool {
+	if len(f.Polygon) >= 3 {
+		return pointInPolygon(lat, lon, f.Polygon)
+	}
+	return haversineMeters(lat, lon, f.Center.lat, f.Center.lon) <= f.Radius
+}
+
+// outside reports whether a driver has moved far enough out to leave the fence.
+func (f Fence) outside(lat, lon float64) bool {
+	if len(f.Polygon) >= 3 {
+		return !pointInPolygon(lat, lon, f.Polygon) && distanceToPolygonMeters(lat, lon, f.Polygon) > polygonExitSlack
+	}
+	return haversineMeters(lat, lon, f.Center.lat, f.Center.lon) > f.Radius*exitRadiusRatio
+}
+
+// GeofenceEventType is the kind of transition a driver made.
+type GeofenceEventType int
+
+const (
+	GeofenceEnter GeofenceEventType = iota + 1
+	GeofenceDwell
+	GeofenceExit
+)
+
+func (t GeofenceEventType) String() string {
+	switch t {
+	case GeofenceEnter:
+		return "enter"
+	case GeofenceDwell:
+		return "dwell"
+	case GeofenceExit:
+		return "exit"
+	}
+	return "unknown"
+}
+
+// GeofenceEvent is one driver transition across a fence.
+type GeofenceEvent struct {
+	Type      GeofenceEventType
+	DriverID  string
+	Fence     Fence
+	Latitude  float64
+	Longitude float64
+	At        time.Time
+	// Dwell is how long the driver had been inside, set on dwell and exit.
+	Dwell time.Duration
+}
+
+// GeofenceConfig tunes the engine. Zero values take the defaults.
+type GeofenceConfig struct {
+	// StationRadii overrides the radius of individual stations, by id.
+	StationRadii  map[string]float64
+	StationRadius float64
+	PickupRadius  float64
+	DwellAfter    time.Duration
+}
+
+type presence struct {
+	enteredAt time.Time
+	dwelled   bool
+}
+
+// Geofencer tracks which fences each driver is inside and turns location
+// pings into enter, dwell and exit events.
+type Geofencer struct {
+	cfg GeofenceConfig
+
+	mu     sync.Mutex
+	fences map[string]Fence
+	inside map[string]map[string]*presence
+}
+
+// NewGeofencer creates an engine with no fences; load them with SetFences or Load.
+func NewGeofencer(cfg GeofenceConfig) *Geofencer {
+	if cfg.StationRadius <= 0 {
+		cfg.StationRadius = defaultStationRadius
+	}
+	if cfg.PickupRadius <= 0 {
+		cfg.PickupRadius = defaultPickupRadius
+	}
+	if cfg.DwellAfter <= 0 {
+		cfg.DwellAfter = defaultDwellAfter
+	}
+	return &Geofencer{
+		cfg:    cfg,
+		fences: make(map[string]Fence),
+		inside: make(map[string]map[string]*presence),
+	}
+}
+
+// SetFences replaces the fence set. Drivers inside a fence that no longer
+// exists are forgotten without an exit event.
+func (g *Geofencer) SetFences(fences []Fence) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.fences = make(map[string]Fence, len(fences))
+	for _, f := range fences {
+		g.fences[f.ID] = f
+	}
+	for driverID, in := range g.inside {
+		for id := range in {
+			if _, ok := g.fences[id]; !ok {
+				delete(in, id)
+			}
+		}
+		if len(in) == 0 {
+			delete(g.inside, driverID)
+		}
+	}
+}
+
+// Fences returns the number of fences loaded.
+func (g *Geofencer) Fences() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.fences)
+}
+
+// InStation reports whether the position is within the station's zone,
+// counting the exit margin so a driver at the edge does not flicker in and out.
+func (g *Geofencer) InStation(stationID string, lat, lon float64) bool {
+	g.mu.Lock()
+	fence, ok := g.fences[stationID]
+	g.mu.Unlock()
+	return ok && fence.Kind == fenceStation && !fence.outside(lat, lon)
+}
+
+// Observe records a driver position and returns the transitions it caused,
+// exits before entries.
+func (g *Geofencer) Observe(driverID string, lat, lon float64, at time.Time) []GeofenceEvent {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	in := g.inside[driverID]
+	var exits, others []GeofenceEvent
+	for id, fence := range g.fences {
+		p, wasInside := in[id]
+		event := GeofenceEvent{DriverID: driverID, Fence: fence, Latitude: lat, Longitude: lon, At: at}
+		switch {
+		case wasInside && fence.outside(lat, lon):
+			delete(in, id)
+			event.Type = GeofenceExit
+			event.Dwell = at.Sub(p.enteredAt)
+			exits = append(exits, event)
+		case wasInside:
+			if !p.dwelled && at.Sub(p.enteredAt) >= g.cfg.DwellAfter {
+				p.dwelled = true
+				event.Type = GeofenceDwell
+				event.Dwell = at.Sub(p.enteredAt)
+				others = append(others, event)
+			}
+		case fence.inside(lat, lon):
+			if in == nil {
+				in = make(map[string]*presence)
+				g.inside[driverID] = in
+			}
+			in[id] = &presence{enteredAt: at}
+			event.Type = GeofenceEnter
+			others = append(others, event)
+		}
+	}
+	if len(in) == 0 {
+		delete(g.inside, driverID)
+	}
+	return append(exits, others...)
+}
+
+// Load replaces the fences with the stations and pickup points listed by
+// StationService.
+func (g *Geofencer) Load(ctx context.Context, stations stationpb.StationServiceClient) error {
+	resp, err := stations.ListStations(ctx, &stationpb.ListStationsRequest{})
+	if err != nil {
+		return err
+	}
+	fences := make([]Fence, 0, len(resp.Stations)+len(resp.PickupPoints))
+	for _, st := range resp.Stations {
+		radius := st.GeofenceRadiusMeters
+		if override, ok := g.cfg.StationRadii[st.Id]; ok {
+			radius = override
+		}
+		if radius <= 0 {
+			radius = g.cfg.StationRadius
+		}
+		fences = append(fences, Fence{
+			ID:        st.Id,
+			Kind:      fenceStation,
+			StationID: st.Id,
+			Center:    coord{lat: st.Latitude, lon: st.Longitude},
+			Radius:    radius,
+			Polygon:   toCoords(st.Geofence),
+		})
+	}
+	for _, p := range resp.PickupPoints {
+		radius := p.GeofenceRadiusMeters
+		if radius <= 0 {
+			radius = g.cfg.PickupRadius
+		}
+		fences = append(fences, Fence{
+			ID:        p.Id,
+			Kind:      fencePickup,
+			StationID: p.StationId,
+			Center:    coord{lat: p.Latitude, lon: p.Longitude},
+			Radius:    radius,
+			Polygon:   toCoords(p.Geofence),
+		})
+	}
+	g.SetFences(fences)
+	return nil
+}
+
+func toCoords(points []*stationpb.GeoPoint) []coord {
+	if len(points) == 0 {
+		return nil
+	}
+	out := make([]coord, len(points))
+	for i, p := range points {
+		out[i] = coord{lat: p.Latitude, lon: p.Longitude}
+	}
+	return out
+}
+
+// pointInPolygon uses ray casting, treating coordinates as planar; fences
+// are small enough for that to hold.
+func pointInPolygon(lat, lon float64, poly []coord) bool {
+	in := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.lat > lat) != (b.lat > lat) && lon < (b.lon-a.lon)*(lat-a.lat)/(b.lat-a.lat)+a.lon {
+			in = !in
+		}
+	}
+	return in
+}
+
+// distanceToPolygonMeters is the distance from the point to the nearest edge,
+// on a local equirectangular projection.
+func distanceToPolygonMeters(lat, lon float64, poly []coord) float64 {
+	const metersPerDegree = 111320.0
+	scale := math.Cos(lat * math.Pi / 180)
+	project := func(c coord) (float64, float64) {
+		return (c.lon - lon) * metersPerDegree * scale, (c.lat - lat) * metersPerDegree
+	}
+	best := math.Inf(1)
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		ax, ay := project(poly[j])
+		bx, by := project(poly[i])
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+		}
+		best = math.Min(best, math.Hypot(ax+t*dx, ay+t*dy))
+	}
+	return best
+}
+
+// ParseStationRadii parses per-station radius overrides of the form
+// "station-a=600,station-b=1200", in metres.
+func ParseStationRadii(spec string) (map[string]float64, error) {
+	radii := make(map[string]float64)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid station radius %q, want station=metres", pair)
+		}
+		radius, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || radius <= 0 {
+			return nil, fmt.Errorf("invalid radius for station %q: %q", id, value)
+		}
+		radii[strings.TrimSpace(id)] = radius
+	}
+	return radii, nil
+}
@@ -0,0 +1,135 @@
+package location
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "lastmile/gen/go/location"
+	stationpb "lastmile/gen/go/station"
+	"lastmile/internal/station"
+)
+
+func eventTypes(events []GeofenceEvent) []string {
+	out := make([]string, len(events))
+	for i, ev := range events {
+		out[i] = ev.Type.String() + ":" + ev.Fence.ID
+	}
+	return out
+}
+
+func TestGeofencerEnterDwellExit(t *testing.T) {
+	g := NewGeofencer(GeofenceConfig{DwellAfter: time.Minute})
+	g.SetFences([]Fence{ecityFence})
+	start := time.Now()
+	center := ecityFence.Center
+
+	assert.Empty(t, g.Observe("driver-1", center.lat+0.05, center.lon, start))
+	assert.Equal(t, []string{"enter:station-ecity"}, eventTypes(g.Observe("driver-1", center.lat+0.005, center.lon, start)))
+	assert.Empty(t, g.Observe("driver-1", center.lat, center.lon, start.Add(30*time.Second)))
+
+	dwell := g.Observe("driver-1", center.lat, center.lon, start.Add(90*time.Second))
+	require.Equal(t, []string{"dwell:station-ecity"}, eventTypes(dwell))
+	assert.Equal(t, 90*time.Second, dwell[0].Dwell)
+	assert.Empty(t, g.Observe("driver-1", center.lat, center.lon, start.Add(2*time.Minute)), "dwell fires once per visit")
+
+	// ~890 m is past the entry radius but inside the exit radius.
+	assert.Empty(t, g.Observe("driver-1", center.lat+0.008, center.lon, start.Add(3*time.Minute)))
+	exit := g.Observe("driver-1", center.lat+0.02, center.lon, start.Add(4*time.Minute))
+	require.Equal(t, []string{"exit:station-ecity"}, eventTypes(exit))
+	assert.Equal(t, 4*time.Minute, exit[0].Dwell)
+}
+
+func TestGeofencerPolygon(t *testing.T) {
+	g := NewGeofencer(GeofenceConfig{})
+	g.SetFences([]Fence{{
+		ID:        "pickup-gate",
+		Kind:      fencePickup,
+		StationID: "station-ecity",
+		Polygon:   []coord{{12.840, 77.650}, {12.840, 77.660}, {12.850, 77.660}, {12.850, 77.650}},
+	}})
+
+	assert.Empty(t, g.Observe("driver-1", 12.835, 77.655, time.Now()))
+	assert.Equal(t, []string{"enter:pickup-gate"}, eventTypes(g.Observe("driver-1", 12.845, 77.655, time.Now())))
+	// ~110 m outside the edge is within the exit slack.
+	assert.Empty(t, g.Observe("driver-1", 12.839, 77.655, time.Now()))
+	assert.Equal(t, []string{"exit:pickup-gate"}, eventTypes(g.Observe("driver-1", 12.830, 77.655, time.Now())))
+}
+
+func serveStations(t *testing.T) stationpb.StationServiceClient {
+	t.Helper()
+	l := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	stations := station.NewServer()
+	stations.Seed(station.DefaultCatalog())
+	stationpb.RegisterStationServiceServer(srv, stations)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return stationpb.NewStationServiceClient(conn)
+}
+
+func TestGeofencerLoadsStationsWithRadiusOverrides(t *testing.T) {
+	radii, err := ParseStationRadii("station-ecity=300")
+	require.NoError(t, err)
+	g := NewGeofencer(GeofenceConfig{StationRadii: radii})
+	require.NoError(t, g.Load(context.Background(), serveStations(t)))
+	assert.Equal(t, 18, g.Fences())
+
+	// 500 m from Electronic City: inside its catalog radius but not the override.
+	assert.NotContains(t, eventTypes(g.Observe("driver-1", 12.8456+0.0045, 77.66, time.Now())), "enter:station-ecity")
+	events := g.Observe("driver-2", 12.8519+0.0045, 77.6546, time.Now())
+	assert.Contains(t, eventTypes(events), "enter:station-konappana")
+}
+
+func TestParseStationRadiiRejectsBadInput(t *testing.T) {
+	_, err := ParseStationRadii("station-ecity")
+	assert.Error(t, err)
+	_, err = ParseStationRadii("station-ecity=-5")
+	assert.Error(t, err)
+}
+
+func TestWatchGeofenceEventsFiltersByStation(t *testing.T) {
+	s := NewServer()
+	s.WatchStations(context.Background(), serveStations(t), 0)
+
+	l := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	pb.RegisterLocationServiceServer(srv, s)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewLocationServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := client.WatchGeofenceEvents(ctx, &pb.WatchGeofenceEventsRequest{StationIds: []string{"station-hsr"}})
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+
+	// Entering Electronic City is filtered out; HSR Layout comes through.
+	s.trackGeofences(ctx, &pb.Location{DriverId: "driver-1", Latitude: 12.8456, Longitude: 77.66})
+	s.trackGeofences(ctx, &pb.Location{DriverId: "driver-1", Latitude: 12.9121, Longitude: 77.6387})
+
+	ev, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.GeofenceEventType_GEOFENCE_EVENT_TYPE_ENTER, ev.Type)
+	assert.Equal(t, "station-hsr", ev.StationId)
+	assert.Equal(t, "driver-1", ev.DriverId)
+}
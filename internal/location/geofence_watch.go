@@ -0,0 +1,128 @@
+package location
+
+import (
+	"context"
+	"time"
+
+	pb "lastmile/gen/go/location"
+	stationpb "lastmile/gen/go/station"
+)
+
+// geofenceFilter narrows a WatchGeofenceEvents stream; nil sets match everything.
+type geofenceFilter struct {
+	drivers  map[string]bool
+	stations map[string]bool
+}
+
+func newGeofenceFilter(req *pb.WatchGeofenceEventsRequest) geofenceFilter {
+	set := func(ids []string) map[string]bool {
+		if len(ids) == 0 {
+			return nil
+		}
+		out := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			out[id] = true
+		}
+		return out
+	}
+	return geofenceFilter{drivers: set(req.DriverIds), stations: set(req.StationIds)}
+}
+
+func (f geofenceFilter) matches(ev *pb.GeofenceEvent) bool {
+	return (f.drivers == nil || f.drivers[ev.DriverId]) && (f.stations == nil || f.stations[ev.StationId])
+}
+
+// SetGeofenceConfig replaces the geofence engine with one using cfg. Fences
+// and driver presence are reset, so call it before loading stations.
+func (s *Server) SetGeofenceConfig(cfg GeofenceConfig) {
+	s.geofences = NewGeofencer(cfg)
+}
+
+// WatchStations loads fences from StationService and refreshes them every
+// interval until ctx is cancelled. The first load happens before it returns.
+func (s *Server) WatchStations(ctx context.Context, stations stationpb.StationServiceClient, interval time.Duration) {
+	load := func() {
+		loadCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		if err := s.geofences.Load(loadCtx, stations); err != nil {
+			s.logger.Warn("geofence load failed", "err", err)
+			return
+		}
+		s.logger.Info("geofences loaded", "fences", s.geofences.Fences())
+	}
+	load()
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				load()
+			}
+		}
+	}()
+}
+
+func (s *Server) publishGeofenceEvent(ev GeofenceEvent) {
+	msg := &pb.GeofenceEvent{
+		DriverId:     ev.DriverID,
+		FenceId:      ev.Fence.ID,
+		Kind:         ev.Fence.Kind,
+		StationId:    ev.Fence.StationID,
+		Latitude:     ev.Latitude,
+		Longitude:    ev.Longitude,
+		OccurredAt:   ev.At.Format(time.RFC3339),
+		DwellSeconds: int64(ev.Dwell / time.Second),
+	}
+	switch ev.Type {
+	case GeofenceEnter:
+		msg.Type = pb.GeofenceEventType_GEOFENCE_EVENT_TYPE_ENTER
+	case GeofenceDwell:
+		msg.Type = pb.GeofenceEventType_GEOFENCE_EVENT_TYPE_DWELL
+	case GeofenceExit:
+		msg.Type = pb.GeofenceEventType_GEOFENCE_EVENT_TYPE_EXIT
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch, filter := range s.geoSubs {
+		if !filter.matches(msg) {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+			// Skip if channel is full to avoid blocking
+		}
+	}
+}
+
+// WatchGeofenceEvents streams enter, dwell and exit events, optionally
+// limited to some drivers and stations.
+func (s *Server) WatchGeofenceEvents(req *pb.WatchGeofenceEventsRequest, stream pb.LocationService_WatchGeofenceEventsServer) error {
+	ch := make(chan *pb.GeofenceEvent, 32)
+	s.mu.Lock()
+	s.geoSubs[ch] = newGeofenceFilter(req)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.geoSubs, ch)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case ev := <-ch:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
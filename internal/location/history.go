@@ -0,0 +1,200 @@
+package location
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/location"
+)
+
+const (
+	// defaultHistorySize is how many recent points are kept per driver in memory.
+	defaultHistorySize = 1000
+	// trackWriteBuffer bounds the points waiting to be written to the track store.
+	trackWriteBuffer = 1024
+)
+
+// TrackPoint is one recorded driver position.
+type TrackPoint struct {
+	Latitude   float64
+	Longitude  float64
+	RecordedAt time.Time
+}
+
+// TrackStore keeps driver history beyond what fits in memory.
+type TrackStore interface {
+	AppendTrack(ctx context.Context, driverID string, point TrackPoint) error
+	// Track returns the driver's points recorded in [from, to], oldest first.
+	// A zero bound is open.
+	Track(ctx context.Context, driverID string, from, to time.Time) ([]TrackPoint, error)
+}
+
+// trackRing is a fixed-size ring of a driver's most recent points.
+type trackRing struct {
+	points []TrackPoint
+	next   int
+	full   bool
+}
+
+func newTrackRing(size int) *trackRing {
+	return &trackRing{points: make([]TrackPoint, size)}
+}
+
+func (r *trackRing) add(p TrackPoint) {
+	r.points[r.next] = p
+	r.next = (r.next + 1) % len(r.points)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// ordered returns the points oldest first.
+func (r *trackRing) ordered() []TrackPoint {
+	if !r.full {
+		return append([]TrackPoint(nil), r.points[:r.next]...)
+	}
+	out := make([]TrackPoint, 0, len(r.points))
+	out = append(out, r.points[r.next:]...)
+	return append(out, r.points[:r.next]...)
+}
+
+type trackWrite struct {
+	driverID string
+	point    TrackPoint
+}
+
+// SetTrackHistory sets how many points per driver are kept in memory and an
+// optional store that receives every point. Call it before serving.
+func (s *Server) SetTrackHistory(size int, store TrackStore) {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	s.mu.Lock()
+	s.historySize = size
+	s.history = make(map[string]*trackRing)
+	s.tracks = store
+	s.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+	s.trackWrites = make(chan trackWrite, trackWriteBuffer)
+	go func(writes <-chan trackWrite) {
+		for w := range writes {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if err := store.AppendTrack(ctx, w.driverID, w.point); err != nil {
+				s.logger.Warn("track store append failed", "driverId", w.driverID, "err", err)
+			}
+			cancel()
+		}
+	}(s.trackWrites)
+}
+
+// recordTrackLocked appends the point to the driver's history and queues it
+// for the track store.
+func (s *Server) recordTrackLocked(driverID string, point TrackPoint) {
+	ring, ok := s.history[driverID]
+	if !ok {
+		ring = newTrackRing(s.historySize)
+		s.history[driverID] = ring
+	}
+	ring.add(point)
+
+	if s.trackWrites == nil {
+		return
+	}
+	select {
+	case s.trackWrites <- trackWrite{driverID: driverID, point: point}:
+	default:
+		s.logger.Warn("track store backlog full, dropping point", "driverId", driverID)
+	}
+}
+
+// GetDriverTrack returns the driver's recorded positions between from and to.
+// With a track store configured the range is read from it, since it holds
+// the points every replica recorded; this replica's recent points only fill
+// in what the store has not been written yet. Without one the track is what
+// this replica has in memory.
+func (s *Server) GetDriverTrack(ctx context.Context, req *pb.GetDriverTrackRequest) (*pb.GetDriverTrackResponse, error) {
+	if req.DriverId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "driver_id is required")
+	}
+	from, err := parseBound(req.From)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+	}
+	to, err := parseBound(req.To)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+	}
+
+	s.mu.RLock()
+	var recent []TrackPoint
+	if ring, ok := s.history[req.DriverId]; ok {
+		recent = ring.ordered()
+	}
+	store := s.tracks
+	s.mu.RUnlock()
+
+	var points []TrackPoint
+	if store != nil {
+		points, err = store.Track(ctx, req.DriverId, from, to)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "track store: %v", err)
+		}
+	}
+	points = mergeTrack(points, recent, from, to)
+
+	resp := &pb.GetDriverTrackResponse{DriverId: req.DriverId, Points: make([]*pb.TrackPoint, 0, len(points))}
+	for i, p := range points {
+		resp.Points = append(resp.Points, &pb.TrackPoint{
+			Latitude:   p.Latitude,
+			Longitude:  p.Longitude,
+			RecordedAt: p.RecordedAt.UTC().Format(time.RFC3339Nano),
+		})
+		if i > 0 {
+			resp.DistanceMeters += haversineMeters(points[i-1].Latitude, points[i-1].Longitude, p.Latitude, p.Longitude)
+		}
+	}
+	return resp, nil
+}
+
+// mergeTrack adds the recent points in [from, to] that stored does not
+// already hold and returns the result oldest first.
+func mergeTrack(stored, recent []TrackPoint, from, to time.Time) []TrackPoint {
+	type pointKey struct {
+		lat, lon float64
+		at       int64
+	}
+	seen := make(map[pointKey]bool, len(stored))
+	for _, p := range stored {
+		seen[pointKey{p.Latitude, p.Longitude, p.RecordedAt.UnixNano()}] = true
+	}
+	points := stored
+	added := false
+	for _, p := range recent {
+		if (!from.IsZero() && p.RecordedAt.Before(from)) || (!to.IsZero() && p.RecordedAt.After(to)) {
+			continue
+		}
+		if seen[pointKey{p.Latitude, p.Longitude, p.RecordedAt.UnixNano()}] {
+			continue
+		}
+		points = append(points, p)
+		added = true
+	}
+	if added && len(stored) > 0 {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].RecordedAt.Before(points[j].RecordedAt) })
+	}
+	return points
+}
+
+func parseBound(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, v)
+}
@@ -0,0 +1,130 @@
+package location
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/location"
+)
+
+type memoryTrackStore struct {
+	mu     sync.Mutex
+	points map[string][]TrackPoint
+}
+
+func (m *memoryTrackStore) AppendTrack(_ context.Context, driverID string, p TrackPoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.points[driverID] = append(m.points[driverID], p)
+	return nil
+}
+
+func (m *memoryTrackStore) Track(_ context.Context, driverID string, from, to time.Time) ([]TrackPoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []TrackPoint
+	for _, p := range m.points[driverID] {
+		if (from.IsZero() || !p.RecordedAt.Before(from)) && (to.IsZero() || !p.RecordedAt.After(to)) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryTrackStore) len(driverID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.points[driverID])
+}
+
+func recordAt(s *Server, driverID string, lat float64, at time.Time) {
+	s.mu.Lock()
+	s.recordTrackLocked(driverID, TrackPoint{Latitude: lat, Longitude: 77.66, RecordedAt: at})
+	s.mu.Unlock()
+}
+
+func TestTrackRingKeepsNewestInOrder(t *testing.T) {
+	r := newTrackRing(3)
+	base := time.Now()
+	for i := 0; i < 5; i++ {
+		r.add(TrackPoint{Latitude: float64(i), RecordedAt: base.Add(time.Duration(i) * time.Second)})
+	}
+	got := r.ordered()
+	require.Len(t, got, 3)
+	assert.Equal(t, []float64{2, 3, 4}, []float64{got[0].Latitude, got[1].Latitude, got[2].Latitude})
+}
+
+func TestGetDriverTrackFiltersByTimeAndMeasuresDistance(t *testing.T) {
+	s := NewServer()
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		recordAt(s, "driver-1", 12.84+float64(i)*0.01, base.Add(time.Duration(i)*time.Minute))
+	}
+
+	resp, err := s.GetDriverTrack(context.Background(), &pb.GetDriverTrackRequest{
+		DriverId: "driver-1",
+		From:     base.Add(time.Minute).Format(time.RFC3339),
+		To:       base.Add(2 * time.Minute).Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Points, 2)
+	assert.InDelta(t, 12.85, resp.Points[0].Latitude, 1e-9)
+	assert.InDelta(t, 1113, resp.DistanceMeters, 5)
+
+	_, err = s.GetDriverTrack(context.Background(), &pb.GetDriverTrackRequest{DriverId: "driver-1", From: "yesterday"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetDriverTrackReadsOlderPointsFromStore(t *testing.T) {
+	store := &memoryTrackStore{points: make(map[string][]TrackPoint)}
+	s := NewServer()
+	s.SetTrackHistory(2, store)
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		recordAt(s, "driver-1", 12.84+float64(i)*0.01, base.Add(time.Duration(i)*time.Minute))
+	}
+	require.Eventually(t, func() bool { return store.len("driver-1") == 5 }, time.Second, 10*time.Millisecond)
+
+	resp, err := s.GetDriverTrack(context.Background(), &pb.GetDriverTrackRequest{DriverId: "driver-1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Points, 5, "three points from the store, two from memory, no duplicates")
+	for i, p := range resp.Points {
+		assert.InDelta(t, 12.84+float64(i)*0.01, p.Latitude, 1e-9)
+	}
+}
+
+func TestGetDriverTrackIncludesOtherReplicasPoints(t *testing.T) {
+	store := &memoryTrackStore{points: make(map[string][]TrackPoint)}
+	a, b := NewServer(), NewServer()
+	a.SetTrackHistory(10, store)
+	b.SetTrackHistory(10, store)
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	// The driver's samples alternate between the replicas.
+	for i := 0; i < 4; i++ {
+		replica := a
+		if i%2 == 1 {
+			replica = b
+		}
+		recordAt(replica, "driver-1", 12.84+float64(i)*0.01, base.Add(time.Duration(i)*time.Minute))
+	}
+	require.Eventually(t, func() bool { return store.len("driver-1") == 4 }, time.Second, 10*time.Millisecond)
+
+	// A point a holds that has not reached the store yet is served from memory.
+	a.mu.Lock()
+	a.history["driver-1"].add(TrackPoint{Latitude: 12.88, Longitude: 77.66, RecordedAt: base.Add(4 * time.Minute)})
+	a.mu.Unlock()
+
+	resp, err := a.GetDriverTrack(context.Background(), &pb.GetDriverTrackRequest{DriverId: "driver-1", From: base.Add(time.Minute).Format(time.RFC3339)})
+	require.NoError(t, err)
+	require.Len(t, resp.Points, 4)
+	for i, p := range resp.Points {
+		assert.InDelta(t, 12.85+float64(i)*0.01, p.Latitude, 1e-9)
+	}
+}
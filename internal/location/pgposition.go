@@ -0,0 +1,153 @@
+package location
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pb "lastmile/gen/go/location"
+)
+
+// PostgresPositionStore keeps each driver's last position in the
+// driver_last_locations table, shared by every location replica.
+type PostgresPositionStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresPositionStore connects to dsn and creates the positions table if
+// it does not exist yet.
+func NewPostgresPositionStore(ctx context.Context, dsn string) (*PostgresPositionStore, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = pool.Exec(ctx, `
+		create table if not exists driver_last_locations (
+			driver_id text primary key,
+			latitude double precision not null,
+			longitude double precision not null,
+			recorded_at timestamptz not null,
+			speed_mps double precision not null default 0,
+			heading_degrees double precision not null default 0,
+			accuracy_meters double precision not null default 0,
+			simulated boolean not null default false
+		);
+		create index if not exists idx_driver_last_locations_position
+			on driver_last_locations (latitude, longitude);
+	`)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return &PostgresPositionStore{pool: pool}, nil
+}
+
+// Close releases the connection pool.
+func (p *PostgresPositionStore) Close() {
+	p.pool.Close()
+}
+
+// SavePosition upserts the driver's row; a replica holding an older fix
+// cannot overwrite a newer one.
+func (p *PostgresPositionStore) SavePosition(ctx context.Context, loc *pb.Location) error {
+	recordedAt, err := time.Parse(time.RFC3339Nano, loc.RecordedAt)
+	if err != nil {
+		return err
+	}
+	_, err = p.pool.Exec(ctx, `
+		insert into driver_last_locations
+			(driver_id, latitude, longitude, recorded_at, speed_mps, heading_degrees, accuracy_meters, simulated)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		on conflict (driver_id) do update set
+			latitude = excluded.latitude,
+			longitude = excluded.longitude,
+			recorded_at = excluded.recorded_at,
+			speed_mps = excluded.speed_mps,
+			heading_degrees = excluded.heading_degrees,
+			accuracy_meters = excluded.accuracy_meters,
+			simulated = excluded.simulated
+		where driver_last_locations.recorded_at <= excluded.recorded_at
+	`, loc.DriverId, loc.Latitude, loc.Longitude, recordedAt, loc.SpeedMps, loc.HeadingDegrees, loc.AccuracyMeters, loc.Simulated)
+	return err
+}
+
+func (p *PostgresPositionStore) Positions(ctx context.Context, driverIDs []string) ([]*pb.Location, error) {
+	var idsArg []string
+	if len(driverIDs) > 0 {
+		idsArg = driverIDs
+	}
+	rows, err := p.pool.Query(ctx, `
+		select driver_id, latitude, longitude, recorded_at, speed_mps, heading_degrees, accuracy_meters, simulated
+		from driver_last_locations
+		where $1::text[] is null or driver_id = any($1)
+	`, idsArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPositions(rows)
+}
+
+// PositionsNear narrows the table to a bounding box around the point and
+// orders what is left by great-circle distance.
+func (p *PostgresPositionStore) PositionsNear(ctx context.Context, lat, lon, radiusMeters float64, limit int) ([]*pb.NearbyDriver, error) {
+	const metersPerDegree = 111320.0
+	latSpan := radiusMeters / metersPerDegree
+	lonSpan := 180.0
+	if scale := math.Cos(lat * math.Pi / 180); scale > 0.01 {
+		lonSpan = radiusMeters / (metersPerDegree * scale)
+	}
+	var limitArg *int
+	if limit > 0 {
+		limitArg = &limit
+	}
+	rows, err := p.pool.Query(ctx, `
+		select driver_id, latitude, longitude, recorded_at, speed_mps, heading_degrees, accuracy_meters, simulated, distance
+		from (
+			select *, 2 * 6371000 * asin(sqrt(
+				power(sin(radians(latitude - $1) / 2), 2) +
+				cos(radians($1)) * cos(radians(latitude)) * power(sin(radians(longitude - $2) / 2), 2)
+			)) as distance
+			from driver_last_locations
+			where latitude between $1 - $4 and $1 + $4
+				and longitude between $2 - $5 and $2 + $5
+		) near
+		where distance <= $3
+		order by distance, driver_id
+		limit $6
+	`, lat, lon, radiusMeters, latSpan, lonSpan, limitArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*pb.NearbyDriver
+	for rows.Next() {
+		loc := &pb.Location{}
+		var recordedAt time.Time
+		var distance float64
+		if err := rows.Scan(&loc.DriverId, &loc.Latitude, &loc.Longitude, &recordedAt, &loc.SpeedMps, &loc.HeadingDegrees, &loc.AccuracyMeters, &loc.Simulated, &distance); err != nil {
+			return nil, err
+		}
+		loc.RecordedAt = recordedAt.UTC().Format(time.RFC3339Nano)
+		out = append(out, &pb.NearbyDriver{Location: loc, DistanceMeters: distance})
+	}
+	return out, rows.Err()
+}
+
+func scanPositions(rows pgx.Rows) ([]*pb.Location, error) {
+	var locs []*pb.Location
+	for rows.Next() {
+		loc := &pb.Location{}
+		var recordedAt time.Time
+		if err := rows.Scan(&loc.DriverId, &loc.Latitude, &loc.Longitude, &recordedAt, &loc.SpeedMps, &loc.HeadingDegrees, &loc.AccuracyMeters, &loc.Simulated); err != nil {
+			return nil, err
+		}
+		loc.RecordedAt = recordedAt.UTC().Format(time.RFC3339Nano)
+		locs = append(locs, loc)
+	}
+	return locs, rows.Err()
+}
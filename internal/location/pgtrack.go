@@ -0,0 +1,82 @@
+package location
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresTrackStore keeps driver history in the driver_location_history table.
+type PostgresTrackStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresTrackStore connects to dsn and creates the history table if it
+// does not exist yet.
+func NewPostgresTrackStore(ctx context.Context, dsn string) (*PostgresTrackStore, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = pool.Exec(ctx, `
+		create table if not exists driver_location_history (
+			driver_id text not null,
+			latitude double precision not null,
+			longitude double precision not null,
+			recorded_at timestamptz not null
+		);
+		create index if not exists driver_location_history_driver_time
+			on driver_location_history (driver_id, recorded_at);
+	`)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return &PostgresTrackStore{pool: pool}, nil
+}
+
+// Close releases the connection pool.
+func (p *PostgresTrackStore) Close() {
+	p.pool.Close()
+}
+
+func (p *PostgresTrackStore) AppendTrack(ctx context.Context, driverID string, point TrackPoint) error {
+	_, err := p.pool.Exec(ctx, `
+		insert into driver_location_history (driver_id, latitude, longitude, recorded_at)
+		values ($1, $2, $3, $4)
+	`, driverID, point.Latitude, point.Longitude, point.RecordedAt)
+	return err
+}
+
+func (p *PostgresTrackStore) Track(ctx context.Context, driverID string, from, to time.Time) ([]TrackPoint, error) {
+	var fromArg, toArg *time.Time
+	if !from.IsZero() {
+		fromArg = &from
+	}
+	if !to.IsZero() {
+		toArg = &to
+	}
+	rows, err := p.pool.Query(ctx, `
+		select latitude, longitude, recorded_at
+		from driver_location_history
+		where driver_id = $1
+			and ($2::timestamptz is null or recorded_at >= $2)
+			and ($3::timestamptz is null or recorded_at <= $3)
+		order by recorded_at
+	`, driverID, fromArg, toArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []TrackPoint
+	for rows.Next() {
+		var pt TrackPoint
+		if err := rows.Scan(&pt.Latitude, &pt.Longitude, &pt.RecordedAt); err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+	}
+	return points, rows.Err()
+}
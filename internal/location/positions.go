@@ -0,0 +1,170 @@
+package location
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "lastmile/gen/go/location"
+	"lastmile/internal/pkg/geoindex"
+)
+
+// positionWriteBuffer bounds the positions waiting to be written to the
+// position store.
+const positionWriteBuffer = 1024
+
+// PositionStore keeps each driver's last known position outside the process,
+// so positions survive restarts and every replica sharing the store can
+// answer GetDriverLocations.
+type PositionStore interface {
+	// SavePosition records loc unless the store already holds a newer fix
+	// for the driver.
+	SavePosition(ctx context.Context, loc *pb.Location) error
+	// Positions returns the stored positions of the given drivers, or of
+	// every driver when none are given.
+	Positions(ctx context.Context, driverIDs []string) ([]*pb.Location, error)
+	// PositionsNear returns the stored positions within radiusMeters of a
+	// point, nearest first. A positive limit caps the number returned.
+	PositionsNear(ctx context.Context, lat, lon, radiusMeters float64, limit int) ([]*pb.NearbyDriver, error)
+}
+
+// MemoryPositionStore is a PositionStore for a single process. It is the
+// default when no database is configured.
+type MemoryPositionStore struct {
+	mu        sync.RWMutex
+	positions map[string]*pb.Location
+	index     *geoindex.Grid
+}
+
+// NewMemoryPositionStore returns an empty MemoryPositionStore.
+func NewMemoryPositionStore() *MemoryPositionStore {
+	return &MemoryPositionStore{positions: make(map[string]*pb.Location), index: geoindex.New(0)}
+}
+
+func (m *MemoryPositionStore) SavePosition(_ context.Context, loc *pb.Location) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.positions[loc.DriverId]; ok && newerPosition(prev, loc) {
+		return nil
+	}
+	m.positions[loc.DriverId] = loc
+	m.index.Upsert(loc.DriverId, loc.Latitude, loc.Longitude)
+	return nil
+}
+
+func (m *MemoryPositionStore) PositionsNear(_ context.Context, lat, lon, radiusMeters float64, limit int) ([]*pb.NearbyDriver, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	hits := m.index.Nearby(lat, lon, radiusMeters, limit)
+	out := make([]*pb.NearbyDriver, 0, len(hits))
+	for _, hit := range hits {
+		if loc, ok := m.positions[hit.ID]; ok {
+			out = append(out, &pb.NearbyDriver{Location: loc, DistanceMeters: hit.DistanceMeters})
+		}
+	}
+	return out, nil
+}
+
+func (m *MemoryPositionStore) Positions(_ context.Context, driverIDs []string) ([]*pb.Location, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(driverIDs) == 0 {
+		out := make([]*pb.Location, 0, len(m.positions))
+		for _, loc := range m.positions {
+			out = append(out, loc)
+		}
+		return out, nil
+	}
+	var out []*pb.Location
+	for _, id := range driverIDs {
+		if loc, ok := m.positions[id]; ok {
+			out = append(out, loc)
+		}
+	}
+	return out, nil
+}
+
+// newerPosition reports whether a was recorded after b.
+func newerPosition(a, b *pb.Location) bool {
+	at, errA := time.Parse(time.RFC3339Nano, a.RecordedAt)
+	bt, errB := time.Parse(time.RFC3339Nano, b.RecordedAt)
+	if errA != nil || errB != nil {
+		return errB != nil && errA == nil
+	}
+	return at.After(bt)
+}
+
+// SetPositionStore persists every accepted position to store and reloads the
+// positions it already holds, so a restarted replica answers for drivers it
+// has not heard from yet. Call it before serving; the store stays in use even
+// when the reload fails.
+func (s *Server) SetPositionStore(ctx context.Context, store PositionStore) error {
+	s.mu.Lock()
+	s.positions = store
+	s.mu.Unlock()
+	if store == nil {
+		return nil
+	}
+
+	s.positionWrites = make(chan *pb.Location, positionWriteBuffer)
+	go func(writes <-chan *pb.Location) {
+		for loc := range writes {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if err := store.SavePosition(ctx, loc); err != nil {
+				s.logger.Warn("position store save failed", "driverId", loc.DriverId, "err", err)
+			}
+			cancel()
+		}
+	}(s.positionWrites)
+
+	stored, err := store.Positions(ctx, nil)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, loc := range stored {
+		if prev, ok := s.lastLocations[loc.DriverId]; ok && !newerPosition(loc, prev) {
+			continue
+		}
+		s.lastLocations[loc.DriverId] = loc
+		s.index.Upsert(loc.DriverId, loc.Latitude, loc.Longitude)
+	}
+	s.logger.Info("positions restored", "drivers", len(stored))
+	return nil
+}
+
+// recordPositionLocked queues the driver's new last position for the
+// position store.
+func (s *Server) recordPositionLocked(loc *pb.Location) {
+	if s.positionWrites == nil {
+		return
+	}
+	select {
+	case s.positionWrites <- loc:
+	default:
+		s.logger.Warn("position store backlog full, dropping position", "driverId", loc.DriverId)
+	}
+}
+
+// storedPositions reads the drivers' positions from the position store, which
+// other replicas also write to. It returns nil when there is no store or it
+// cannot be reached, leaving the caller with what this replica has seen.
+func (s *Server) storedPositions(ctx context.Context, driverIDs []string) map[string]*pb.Location {
+	s.mu.RLock()
+	store := s.positions
+	s.mu.RUnlock()
+	if store == nil || len(driverIDs) == 0 {
+		return nil
+	}
+	locs, err := store.Positions(ctx, driverIDs)
+	if err != nil {
+		s.logger.Warn("position store read failed, serving local positions", "err", err)
+		return nil
+	}
+	out := make(map[string]*pb.Location, len(locs))
+	for _, loc := range locs {
+		out[loc.DriverId] = loc
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package location
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "lastmile/gen/go/location"
+)
+
+func positionAt(driverID string, lat float64, at time.Time) *pb.Location {
+	return &pb.Location{DriverId: driverID, Latitude: lat, Longitude: 77.66, RecordedAt: at.UTC().Format(time.RFC3339Nano)}
+}
+
+func TestMemoryPositionStoreKeepsNewestFix(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryPositionStore()
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.SavePosition(ctx, positionAt("driver-1", 12.85, base.Add(time.Minute))))
+	require.NoError(t, store.SavePosition(ctx, positionAt("driver-1", 12.84, base)))
+	require.NoError(t, store.SavePosition(ctx, positionAt("driver-2", 12.90, base)))
+
+	locs, err := store.Positions(ctx, []string{"driver-1", "driver-3"})
+	require.NoError(t, err)
+	require.Len(t, locs, 1)
+	assert.Equal(t, 12.85, locs[0].Latitude, "an older fix must not replace a newer one")
+
+	all, err := store.Positions(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestSetPositionStoreRestoresPositions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryPositionStore()
+	base := time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, store.SavePosition(ctx, positionAt("driver-1", 12.84, base)))
+
+	s := NewServer()
+	require.NoError(t, s.SetPositionStore(ctx, store))
+
+	resp, err := s.GetDriverLocations(ctx, &pb.GetDriverLocationsRequest{DriverIds: []string{"driver-1"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Locations, 1)
+	assert.Equal(t, 12.84, resp.Locations[0].Latitude)
+
+	near, err := s.FindDriversNear(ctx, &pb.FindDriversNearRequest{Latitude: 12.84, Longitude: 77.66, RadiusMeters: 100})
+	require.NoError(t, err)
+	require.Len(t, near.Drivers, 1, "restored positions are indexed for radius queries")
+}
+
+func TestReplicasShareLastPositions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryPositionStore()
+	a, b := NewServer(), NewServer()
+	require.NoError(t, a.SetPositionStore(ctx, store))
+	require.NoError(t, b.SetPositionStore(ctx, store))
+	base := time.Now().UTC().Add(-time.Minute)
+
+	b.mu.Lock()
+	b.lastLocations["driver-1"] = positionAt("driver-1", 12.84, base)
+	b.mu.Unlock()
+	a.mu.Lock()
+	a.recordPositionLocked(positionAt("driver-1", 12.86, base.Add(30*time.Second)))
+	a.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		resp, err := b.GetDriverLocations(ctx, &pb.GetDriverLocationsRequest{DriverIds: []string{"driver-1"}})
+		return err == nil && len(resp.Locations) == 1 && resp.Locations[0].Latitude == 12.86
+	}, time.Second, 10*time.Millisecond, "the newer fix written by another replica wins")
+}
+
+func TestFindDriversNearSearchesSharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryPositionStore()
+	a, b := NewServer(), NewServer()
+	require.NoError(t, a.SetPositionStore(ctx, store))
+	require.NoError(t, b.SetPositionStore(ctx, store))
+	base := time.Now().UTC().Add(-time.Minute)
+
+	// driver-1 reports to replica a only; replica b still finds it.
+	a.mu.Lock()
+	a.recordPositionLocked(positionAt("driver-1", 12.8456, base))
+	a.mu.Unlock()
+	require.Eventually(t, func() bool {
+		near, err := b.FindDriversNear(ctx, &pb.FindDriversNearRequest{Latitude: 12.8456, Longitude: 77.66, RadiusMeters: 500})
+		return err == nil && len(near.Drivers) == 1 && near.Drivers[0].Location.DriverId == "driver-1"
+	}, time.Second, 10*time.Millisecond)
+	near, err := b.FindDriversNear(ctx, &pb.FindDriversNearRequest{
+		Latitude: 12.8456, Longitude: 77.66, RadiusMeters: 500,
+		UnpositionedCandidates: []string{"driver-1", "driver-2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"driver-2"}, near.UnpositionedDriverIds)
+
+	// A newer fix b holds locally, outside the radius, wins over the stored one.
+	b.mu.Lock()
+	b.lastLocations["driver-1"] = positionAt("driver-1", 12.95, base.Add(30*time.Second))
+	b.mu.Unlock()
+	near, err = b.FindDriversNear(ctx, &pb.FindDriversNearRequest{Latitude: 12.8456, Longitude: 77.66, RadiusMeters: 500})
+	require.NoError(t, err)
+	assert.Empty(t, near.Drivers)
+}
@@ -0,0 +1,72 @@
+package location
+
+import (
+	"math"
+	"time"
+
+	pb "lastmile/gen/go/location"
+)
+
+const (
+	// defaultMaxAccuracy is the widest accuracy radius, in metres, a sample
+	// may report and still be used.
+	defaultMaxAccuracy = 100.0
+	// maxClockSkew is how far ahead of the server clock a device timestamp
+	// may be.
+	maxClockSkew = 30 * time.Second
+)
+
+// SetMaxAccuracy sets the widest accuracy radius, in metres, a sample may
+// report before it is dropped. Zero or less restores the default.
+func (s *Server) SetMaxAccuracy(meters float64) {
+	if meters <= 0 {
+		meters = defaultMaxAccuracy
+	}
+	s.mu.Lock()
+	s.maxAccuracy = meters
+	s.mu.Unlock()
+}
+
+// acceptSampleLocked validates the sample against the driver's previous one
+// and returns when it was recorded. Samples without a device timestamp are
+// stamped with now. A non-empty kind means the sample must be dropped.
+func (s *Server) acceptSampleLocked(loc *pb.Location, now time.Time) (time.Time, anomalyKind) {
+	if math.IsNaN(loc.Latitude) || math.IsNaN(loc.Longitude) || loc.Latitude < -90 || loc.Latitude > 90 || loc.Longitude < -180 || loc.Longitude > 180 {
+		return time.Time{}, anomalyInvalid
+	}
+	if loc.AccuracyMeters > s.maxAccuracy {
+		return time.Time{}, anomalyLowAccuracy
+	}
+	if loc.RecordedAt == "" {
+		return now, ""
+	}
+	at, err := time.Parse(time.RFC3339Nano, loc.RecordedAt)
+	if err != nil {
+		return time.Time{}, anomalyInvalid
+	}
+	at = at.UTC()
+	// A timestamp far in the future would block every later sample as out
+	// of order.
+	if at.After(now.Add(maxClockSkew)) {
+		return time.Time{}, anomalyInvalid
+	}
+	// Only device timestamps are compared; server stamps and device clocks
+	// cannot be ordered against each other.
+	if last, ok := s.sampleTimes[loc.DriverId]; ok && !at.After(last) {
+		return time.Time{}, anomalyOutOfOrder
+	}
+	s.sampleTimes[loc.DriverId] = at
+	return at, ""
+}
+
+func toLocationUpdate(loc *pb.Location) *pb.LocationUpdate {
+	return &pb.LocationUpdate{
+		DriverId:       loc.DriverId,
+		Latitude:       loc.Latitude,
+		Longitude:      loc.Longitude,
+		RecordedAt:     loc.RecordedAt,
+		SpeedMps:       loc.SpeedMps,
+		HeadingDegrees: loc.HeadingDegrees,
+		AccuracyMeters: loc.AccuracyMeters,
+	}
+}
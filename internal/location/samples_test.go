@@ -0,0 +1,87 @@
+package location
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "lastmile/gen/go/location"
+)
+
+func TestAcceptSampleDropsLateAndInaccurateSamples(t *testing.T) {
+	s := NewServer()
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	sample := func(at time.Time, accuracy float64) anomalyKind {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, reason := s.acceptSampleLocked(&pb.Location{
+			DriverId:       "driver-1",
+			Latitude:       12.84,
+			Longitude:      77.66,
+			RecordedAt:     at.Format(time.RFC3339Nano),
+			AccuracyMeters: accuracy,
+		}, now)
+		return reason
+	}
+
+	assert.Empty(t, sample(now.Add(-10*time.Second), 8))
+	assert.Equal(t, anomalyOutOfOrder, sample(now.Add(-20*time.Second), 8))
+	assert.Equal(t, anomalyOutOfOrder, sample(now.Add(-10*time.Second), 8), "duplicates are dropped too")
+	assert.Equal(t, anomalyLowAccuracy, sample(now.Add(-5*time.Second), 250))
+	assert.Empty(t, sample(now.Add(-5*time.Second), 0), "unknown accuracy is accepted")
+	assert.Equal(t, anomalyInvalid, sample(now.Add(time.Hour), 8), "far-future timestamps would block later samples")
+
+	s.mu.Lock()
+	at, reason := s.acceptSampleLocked(&pb.Location{DriverId: "driver-1", Latitude: 12.84, Longitude: 77.66}, now)
+	s.mu.Unlock()
+	assert.Empty(t, reason)
+	assert.Equal(t, now, at, "samples without a timestamp are stamped by the server")
+}
+
+func TestUpdateLocationForwardsMotionAndReportsDrops(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewLocationServiceClient(conn)
+
+	sub, err := client.SubscribeLocationUpdates(ctx, &pb.SubscribeLocationRequest{DriverId: "driver-motion"})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	base := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	stream, err := client.UpdateLocation(ctx)
+	require.NoError(t, err)
+	// Far from the other tests' drivers, which share this server.
+	for _, loc := range []*pb.Location{
+		{DriverId: "driver-motion", Latitude: 48.84, Longitude: 2.35, RecordedAt: base.Add(2 * time.Second).Format(time.RFC3339), SpeedMps: 8.5, HeadingDegrees: 90, AccuracyMeters: 6},
+		// Delayed on the network; older than what was already received.
+		{DriverId: "driver-motion", Latitude: 48.83, Longitude: 2.35, RecordedAt: base.Format(time.RFC3339), AccuracyMeters: 6},
+		{DriverId: "driver-motion", Latitude: 48.85, Longitude: 2.35, RecordedAt: base.Add(4 * time.Second).Format(time.RFC3339), AccuracyMeters: 900},
+	} {
+		require.NoError(t, stream.Send(&pb.UpdateLocationRequest{Location: loc}))
+	}
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, resp.Accepted)
+	assert.EqualValues(t, 2, resp.Dropped)
+
+	update, err := sub.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 48.84, update.Latitude)
+	assert.Equal(t, 8.5, update.SpeedMps)
+	assert.Equal(t, 90.0, update.HeadingDegrees)
+	assert.Equal(t, 6.0, update.AccuracyMeters)
+	assert.Equal(t, base.Add(2*time.Second).Format(time.RFC3339Nano), update.RecordedAt)
+
+	locs, err := client.GetDriverLocations(ctx, &pb.GetDriverLocationsRequest{DriverIds: []string{"driver-motion"}})
+	require.NoError(t, err)
+	require.Len(t, locs.Locations, 1)
+	assert.Equal(t, 48.84, locs.Locations[0].Latitude, "dropped samples must not replace the last position")
+}
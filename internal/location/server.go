@@ -2,19 +2,40 @@ package location
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"math"
+	"sort"
 	"sync"
+	"time"
 
 	pb "lastmile/gen/go/location"
 	"lastmile/gen/go/matching"
+	"lastmile/internal/pkg/geoindex"
+	"lastmile/internal/pkg/gpsfilter"
 	"lastmile/internal/pkg/logging"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
+// matchAttempts bounds retries of a proximity match that failed transiently.
+const matchAttempts = 3
+
+// stationVisit is one stay of a driver inside a station zone, identified by
+// the dwell window the entering sample's device time falls in. Geofence state
+// is per replica, so two replicas can see a driver enter on different
+// samples; keying on the window rather than the exact sample lets them agree
+// on the visit.
+type stationVisit struct {
+	stationID string
+	window    time.Time
+}
+
 // Server implements the LocationServiceServer interface.
 type Server struct {
 	pb.UnimplementedLocationServiceServer
@@ -22,8 +43,38 @@ type Server struct {
 	matchingClient matching.MatchingServiceClient
 
 	mu            sync.RWMutex
-	subscribers   map[string][]chan *pb.LocationUpdate
 	lastLocations map[string]*pb.Location
+	// subscribers are the open location streams; nextSubscriber numbers them
+	// for their ids.
+	subscribers      map[*locationSub]struct{}
+	nextSubscriber   int
+	subscriberMaxLag time.Duration
+	// sampleTimes is the device timestamp of each driver's last accepted
+	// sample, for dropping late arrivals.
+	sampleTimes map[string]time.Time
+	maxAccuracy float64
+	// simulatorToken is what the gateway's simulator presents to have its
+	// samples trusted as simulated.
+	simulatorToken string
+	// filter smooths accepted samples and flags impossible jumps, which are
+	// quarantined and counted in anomalies.
+	filter    *gpsfilter.Filter
+	anomalies map[string]*driverAnomalies
+	// index holds every driver's last position for radius queries.
+	index *geoindex.Grid
+	// history keeps each driver's recent points; tracks, when set, receives
+	// every point through trackWrites.
+	history     map[string]*trackRing
+	historySize int
+	tracks      TrackStore
+	trackWrites chan trackWrite
+	// positions, when set, receives every new last position through
+	// positionWrites and is read back so replicas share positions.
+	positions      PositionStore
+	positionWrites chan *pb.Location
+
+	geofences *Geofencer
+	geoSubs   map[chan *pb.GeofenceEvent]geofenceFilter
 }
 
 // NewServer creates a new Server.
@@ -34,9 +85,19 @@ func NewServer(logger ...*slog.Logger) *Server {
 	}
 
 	return &Server{
-		logger:        l,
-		subscribers:   make(map[string][]chan *pb.LocationUpdate),
-		lastLocations: make(map[string]*pb.Location),
+		logger:           l,
+		subscribers:      make(map[*locationSub]struct{}),
+		subscriberMaxLag: defaultSubscriberMaxLag,
+		lastLocations:    make(map[string]*pb.Location),
+		sampleTimes:      make(map[string]time.Time),
+		maxAccuracy:      defaultMaxAccuracy,
+		filter:           gpsfilter.New(gpsfilter.Config{}),
+		anomalies:        make(map[string]*driverAnomalies),
+		index:            geoindex.New(0),
+		history:          make(map[string]*trackRing),
+		historySize:      defaultHistorySize,
+		geofences:        NewGeofencer(GeofenceConfig{}),
+		geoSubs:          make(map[chan *pb.GeofenceEvent]geofenceFilter),
 	}
 }
 
@@ -61,21 +122,26 @@ func NewServerWithMatching(targetAddr string, logger *slog.Logger) *Server {
 		}
 	}
 
-	return &Server{
-		logger:         l,
-		matchingClient: client,
-		subscribers:    make(map[string][]chan *pb.LocationUpdate),
-		lastLocations:  make(map[string]*pb.Location),
-	}
+	s := NewServer(l)
+	s.matchingClient = client
+	return s
 }
 
+// GetDriverLocations returns the drivers' last positions. Positions other
+// replicas saved to the shared position store win when they are newer.
 func (s *Server) GetDriverLocations(ctx context.Context, req *pb.GetDriverLocationsRequest) (*pb.GetDriverLocationsResponse, error) {
+	stored := s.storedPositions(ctx, req.DriverIds)
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var locations []*pb.Location
 	for _, id := range req.DriverIds {
-		if loc, ok := s.lastLocations[id]; ok {
+		loc, ok := s.lastLocations[id]
+		if shared, found := stored[id]; found && (!ok || newerPosition(shared, loc)) {
+			loc, ok = shared, true
+		}
+		if ok {
 			locations = append(locations, loc)
 		}
 	}
@@ -85,6 +151,96 @@ func (s *Server) GetDriverLocations(ctx context.Context, req *pb.GetDriverLocati
 	}, nil
 }
 
+// FindDriversNear returns the drivers whose last position is within the
+// radius of a point, nearest first. The shared position store is searched
+// so drivers reporting to other replicas are found too; this replica's own
+// positions fill in fixes the store has not been written yet.
+func (s *Server) FindDriversNear(ctx context.Context, req *pb.FindDriversNearRequest) (*pb.FindDriversNearResponse, error) {
+	if req.RadiusMeters <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "radius_meters must be positive")
+	}
+	if req.Latitude < -90 || req.Latitude > 90 || req.Longitude < -180 || req.Longitude > 180 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid coordinates %v,%v", req.Latitude, req.Longitude)
+	}
+
+	s.mu.RLock()
+	store := s.positions
+	s.mu.RUnlock()
+	var stored []*pb.NearbyDriver
+	if store != nil {
+		var err error
+		stored, err = store.PositionsNear(ctx, req.Latitude, req.Longitude, req.RadiusMeters, int(req.Limit))
+		if err != nil {
+			s.logger.Warn("position store search failed, serving local positions", "err", err)
+		}
+	}
+	hits := s.index.Nearby(req.Latitude, req.Longitude, req.RadiusMeters, int(req.Limit))
+
+	s.mu.RLock()
+	latest := make(map[string]*pb.Location, len(stored)+len(hits))
+	for _, near := range stored {
+		loc := near.GetLocation()
+		if local, ok := s.lastLocations[loc.GetDriverId()]; ok && newerPosition(local, loc) {
+			loc = local
+		}
+		latest[loc.GetDriverId()] = loc
+	}
+	for _, hit := range hits {
+		loc, ok := s.lastLocations[hit.ID]
+		if !ok {
+			continue
+		}
+		if prev, seen := latest[hit.ID]; seen && !newerPosition(loc, prev) {
+			continue
+		}
+		latest[hit.ID] = loc
+	}
+	s.mu.RUnlock()
+
+	resp := &pb.FindDriversNearResponse{Drivers: make([]*pb.NearbyDriver, 0, len(latest))}
+	for _, loc := range latest {
+		// The newest fix decides, so a driver who has since left the radius drops out.
+		if d := haversineMeters(req.Latitude, req.Longitude, loc.Latitude, loc.Longitude); d <= req.RadiusMeters {
+			resp.Drivers = append(resp.Drivers, &pb.NearbyDriver{Location: loc, DistanceMeters: d})
+		}
+	}
+	sort.Slice(resp.Drivers, func(i, j int) bool {
+		a, b := resp.Drivers[i], resp.Drivers[j]
+		if a.DistanceMeters != b.DistanceMeters {
+			return a.DistanceMeters < b.DistanceMeters
+		}
+		return a.Location.DriverId < b.Location.DriverId
+	})
+	if req.Limit > 0 && len(resp.Drivers) > int(req.Limit) {
+		resp.Drivers = resp.Drivers[:req.Limit]
+	}
+	resp.UnpositionedDriverIds = s.unpositionedDrivers(ctx, req.UnpositionedCandidates)
+	return resp, nil
+}
+
+// unpositionedDrivers returns the drivers neither this replica nor the
+// shared position store has a position for.
+func (s *Server) unpositionedDrivers(ctx context.Context, driverIDs []string) []string {
+	if len(driverIDs) == 0 {
+		return nil
+	}
+	stored := s.storedPositions(ctx, driverIDs)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var unknown []string
+	for _, id := range driverIDs {
+		if _, ok := s.lastLocations[id]; ok {
+			continue
+		}
+		if _, ok := stored[id]; ok {
+			continue
+		}
+		unknown = append(unknown, id)
+	}
+	return unknown
+}
+
 // UpdateLocation updates the location of a driver. This is a streaming RPC.
 func (s *Server) UpdateLocation(stream pb.LocationService_UpdateLocationServer) error {
 	logger := s.logger
@@ -92,114 +248,141 @@ func (s *Server) UpdateLocation(stream pb.LocationService_UpdateLocationServer)
 		logger = logging.New("location")
 	}
 
+	var accepted, dropped, quarantined int32
+	var last *pb.Location
+	fromSimulator := s.fromSimulator(stream.Context())
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
-			logger.Info("location stream closed")
-			return stream.SendAndClose(&pb.UpdateLocationResponse{Success: true})
+			logger.Info("location stream closed", "accepted", accepted, "dropped", dropped, "quarantined", quarantined)
+			return stream.SendAndClose(&pb.UpdateLocationResponse{
+				Success:     true,
+				Accepted:    accepted,
+				Dropped:     dropped,
+				Quarantined: quarantined,
+				Location:    last,
+			})
 		}
 		if err != nil {
 			logger.Error("location stream failed", "err", err)
 			return err
 		}
+		if req.Location == nil || req.Location.DriverId == "" {
+			dropped++
+			continue
+		}
 
-		// Broadcast to subscribers
-		s.broadcast(req.Location)
-
-		// Update last location
+		// Copy so the stored sample is not shared with the stream's message.
+		loc := proto.Clone(req.Location).(*pb.Location)
 		s.mu.Lock()
-		s.lastLocations[req.Location.DriverId] = req.Location
+		now := time.Now().UTC()
+		recordedAt, kind := s.acceptSampleLocked(loc, now)
+		if kind != "" {
+			s.noteAnomalyLocked(loc.DriverId, kind, now)
+			s.mu.Unlock()
+			dropped++
+			logger.Info("location sample dropped", "driverId", loc.DriverId, "reason", string(kind), "recordedAt", loc.RecordedAt, "accuracy", loc.AccuracyMeters)
+			continue
+		}
+		if s.filterSampleLocked(loc, recordedAt, fromSimulator) {
+			s.mu.Unlock()
+			quarantined++
+			continue
+		}
+		loc.RecordedAt = recordedAt.Format(time.RFC3339Nano)
+		s.lastLocations[loc.DriverId] = loc
+		s.index.Upsert(loc.DriverId, loc.Latitude, loc.Longitude)
+		s.recordPositionLocked(loc)
+		s.recordTrackLocked(loc.DriverId, TrackPoint{
+			Latitude:   loc.Latitude,
+			Longitude:  loc.Longitude,
+			RecordedAt: recordedAt,
+		})
 		s.mu.Unlock()
+		accepted++
+		last = loc
+
+		s.broadcast(loc)
 
 		logger.Info("location update received",
-			"driverId", req.Location.DriverId,
-			"lat", req.Location.Latitude,
-			"long", req.Location.Longitude)
-
-		if stationID, ok := detectStation(req.Location.Latitude, req.Location.Longitude); ok && s.matchingClient != nil {
-			logger.Info("driver in proximity, triggering match", "driverId", req.Location.DriverId, "stationId", stationID)
-			_, callErr := s.matchingClient.Match(stream.Context(), &matching.MatchRequest{
-				DriverId:  req.Location.DriverId,
-				StationId: stationID,
-			})
-			if callErr != nil {
-				logger.Warn("proximity match failed", "err", callErr)
-			}
-		}
+			"driverId", loc.DriverId,
+			"lat", loc.Latitude,
+			"long", loc.Longitude,
+			"speed", loc.SpeedMps,
+			"heading", loc.HeadingDegrees,
+			"accuracy", loc.AccuracyMeters)
+
+		s.trackGeofences(stream.Context(), loc)
 	}
 }
 
-func (s *Server) SubscribeLocationUpdates(req *pb.SubscribeLocationRequest, stream pb.LocationService_SubscribeLocationUpdatesServer) error {
-	ch := make(chan *pb.LocationUpdate, 10)
-	driverID := req.DriverId
-
-	s.mu.Lock()
-	s.subscribers[driverID] = append(s.subscribers[driverID], ch)
-	s.mu.Unlock()
-
-	// Cleanup on return
-	defer func() {
-		s.mu.Lock()
-		defer s.mu.Unlock()
-		subs := s.subscribers[driverID]
-		for i, sub := range subs {
-			if sub == ch {
-				s.subscribers[driverID] = append(subs[:i], subs[i+1:]...)
-				break
-			}
-		}
-		close(ch)
-	}()
-
-	for {
-		select {
-		case update := <-ch:
-			if err := stream.Send(update); err != nil {
-				return err
-			}
-		case <-stream.Context().Done():
-			return stream.Context().Err()
+// trackGeofences feeds the position to the geofence engine, publishes the
+// resulting events and fires a match when a driver enters a station zone.
+// Pings while the driver stays inside do nothing; the zone has to be left and
+// re-entered before the next match.
+func (s *Server) trackGeofences(ctx context.Context, loc *pb.Location) {
+	now := time.Now().UTC()
+	sampledAt, err := time.Parse(time.RFC3339Nano, loc.RecordedAt)
+	if err != nil {
+		sampledAt = now
+	}
+	for _, ev := range s.geofences.Observe(loc.DriverId, loc.Latitude, loc.Longitude, now) {
+		s.logger.Info("geofence event", "type", ev.Type.String(), "driverId", ev.DriverID, "fenceId", ev.Fence.ID, "kind", ev.Fence.Kind)
+		s.publishGeofenceEvent(ev)
+		if ev.Type != GeofenceEnter || ev.Fence.Kind != fenceStation {
+			continue
 		}
+		visit := stationVisit{stationID: ev.Fence.StationID, window: sampledAt.Truncate(s.geofences.cfg.DwellAfter)}
+		s.triggerMatch(ctx, loc.DriverId, visit)
 	}
 }
 
-func (s *Server) broadcast(loc *pb.Location) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	update := &pb.LocationUpdate{
-		DriverId:  loc.DriverId,
-		Latitude:  loc.Latitude,
-		Longitude: loc.Longitude,
+// triggerMatch asks the matching service to match riders for the visit,
+// retrying transient failures with the same idempotency key.
+func (s *Server) triggerMatch(ctx context.Context, driverID string, visit stationVisit) {
+	if s.matchingClient == nil {
+		return
 	}
-
-	for _, ch := range s.subscribers[loc.DriverId] {
+	key := idempotencyKey(driverID, visit)
+	s.logger.Info("driver entered station zone, triggering match", "driverId", driverID, "stationId", visit.stationID, "key", key)
+	req := &matching.MatchRequest{
+		DriverId:       driverID,
+		StationId:      visit.stationID,
+		IdempotencyKey: key,
+	}
+	for attempt := 1; attempt <= matchAttempts; attempt++ {
+		_, err := s.matchingClient.Match(ctx, req)
+		if err == nil {
+			return
+		}
+		if !retryable(err) || attempt == matchAttempts {
+			s.logger.Warn("proximity match failed", "driverId", driverID, "stationId", visit.stationID, "key", key, "err", err)
+			return
+		}
 		select {
-		case ch <- update:
-		default:
-			// Skip if channel is full to avoid blocking
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
 		}
 	}
 }
 
-type coord struct {
-	lat float64
-	lon float64
-}
-
-var stationAnchors = map[string]coord{
-	"station-central": {lat: 37.7936, lon: -122.3965},
-	"station-mission": {lat: 37.7599, lon: -122.4148},
-	"station-sunset":  {lat: 37.7530, lon: -122.4946},
+// idempotencyKey identifies one visit of a driver to a station. It depends
+// only on the driver, the station and the visit's dwell window, so a
+// restarted replica, or another one whose entering sample falls in the same
+// window, derives the same key. Entries on either side of a window boundary
+// still get two keys; the conditional rider claim keeps those from booking a
+// rider twice.
+func idempotencyKey(driverID string, v stationVisit) string {
+	return fmt.Sprintf("%s:%s:%d", driverID, v.stationID, v.window.UnixMilli())
 }
 
-func detectStation(lat, lon float64) (string, bool) {
-	for id, anchor := range stationAnchors {
-		if haversineMeters(lat, lon, anchor.lat, anchor.lon) <= 800 { // ~0.5mi
-			return id, true
-		}
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		return true
 	}
-	return "", false
+	return false
 }
 
 func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
@@ -2,17 +2,22 @@ package location
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	pb "lastmile/gen/go/location"
+	"lastmile/gen/go/matching"
 )
 
 const bufSize = 1024 * 1024
@@ -47,9 +52,9 @@ func TestUpdateLocation(t *testing.T) {
 	require.NoError(t, err)
 
 	locations := []*pb.Location{
-		{DriverId: "driver-1", Latitude: 34.0522, Longitude: -118.2437},
-		{DriverId: "driver-1", Latitude: 34.0523, Longitude: -118.2438},
-		{DriverId: "driver-2", Latitude: 34.0524, Longitude: -118.2439},
+		{DriverId: "driver-la-1", Latitude: 34.0522, Longitude: -118.2437},
+		{DriverId: "driver-la-1", Latitude: 34.0523, Longitude: -118.2438},
+		{DriverId: "driver-la-2", Latitude: 34.0524, Longitude: -118.2439},
 	}
 
 	for _, loc := range locations {
@@ -109,3 +114,141 @@ func TestSubscribeLocationUpdates(t *testing.T) {
 
 	pubStream.CloseAndRecv()
 }
+
+type recordingMatcher struct {
+	mu       sync.Mutex
+	requests []*matching.MatchRequest
+	failures int
+}
+
+func (m *recordingMatcher) Match(_ context.Context, req *matching.MatchRequest, _ ...grpc.CallOption) (*matching.MatchResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, req)
+	if m.failures > 0 {
+		m.failures--
+		return nil, status.Error(codes.Unavailable, "replica restarting")
+	}
+	return &matching.MatchResponse{}, nil
+}
+
+func (m *recordingMatcher) GetOwner(context.Context, *matching.GetOwnerRequest, ...grpc.CallOption) (*matching.GetOwnerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not sharded")
+}
+
+func (m *recordingMatcher) WatchMatches(context.Context, *matching.WatchMatchesRequest, ...grpc.CallOption) (grpc.ServerStreamingClient[matching.MatchEvent], error) {
+	return nil, status.Error(codes.Unimplemented, "not streaming")
+}
+
+var ecityFence = Fence{
+	ID:        "station-ecity",
+	Kind:      fenceStation,
+	StationID: "station-ecity",
+	Center:    coord{lat: 12.8456, lon: 77.66},
+	Radius:    defaultStationRadius,
+}
+
+func TestProximityMatchFiresOncePerVisit(t *testing.T) {
+	matcher := &recordingMatcher{}
+	s := NewServer()
+	s.matchingClient = matcher
+	s.geofences.SetFences([]Fence{ecityFence})
+	ctx := context.Background()
+
+	central := ecityFence.Center
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	pings := 0
+	ping := func(dLat float64) {
+		pings++
+		at := start.Add(time.Duration(pings) * time.Minute).Format(time.RFC3339Nano)
+		s.trackGeofences(ctx, &pb.Location{DriverId: "driver-1", Latitude: central.lat + dLat, Longitude: central.lon, RecordedAt: at})
+	}
+
+	ping(0.05)  // far away
+	ping(0.005) // ~550 m: enters the zone
+	ping(0.002) // still inside
+	ping(0.0)   // at the station
+	ping(0.008) // ~890 m: outside the entry radius but inside the exit radius
+	ping(0.02)  // left the zone
+	ping(0.001) // second visit
+	require.Len(t, matcher.requests, 2)
+	// Visits are keyed by the dwell window of the entering sample.
+	assert.Equal(t, fmt.Sprintf("driver-1:station-ecity:%d", start.Add(2*time.Minute).UnixMilli()), matcher.requests[0].IdempotencyKey)
+	assert.Equal(t, fmt.Sprintf("driver-1:station-ecity:%d", start.Add(6*time.Minute).UnixMilli()), matcher.requests[1].IdempotencyKey)
+	assert.Equal(t, "station-ecity", matcher.requests[1].StationId)
+
+	// A restarted replica seeing the same entering sample derives the same key.
+	restarted := NewServer()
+	restarted.matchingClient = matcher
+	restarted.geofences.SetFences([]Fence{ecityFence})
+	restarted.trackGeofences(ctx, &pb.Location{DriverId: "driver-1", Latitude: central.lat + 0.005, Longitude: central.lon, RecordedAt: start.Add(2 * time.Minute).Format(time.RFC3339Nano)})
+	require.Len(t, matcher.requests, 3)
+	assert.Equal(t, matcher.requests[0].IdempotencyKey, matcher.requests[2].IdempotencyKey)
+
+	// So does another replica that first sees the driver inside on a later
+	// sample in the same window.
+	other := NewServer()
+	other.matchingClient = matcher
+	other.geofences.SetFences([]Fence{ecityFence})
+	other.trackGeofences(ctx, &pb.Location{DriverId: "driver-1", Latitude: central.lat + 0.002, Longitude: central.lon, RecordedAt: start.Add(2*time.Minute + 50*time.Second).Format(time.RFC3339Nano)})
+	require.Len(t, matcher.requests, 4)
+	assert.Equal(t, matcher.requests[0].IdempotencyKey, matcher.requests[3].IdempotencyKey)
+}
+
+func TestProximityMatchRetriesWithSameKey(t *testing.T) {
+	matcher := &recordingMatcher{failures: 1}
+	s := NewServer()
+	s.matchingClient = matcher
+	s.geofences.SetFences([]Fence{ecityFence})
+
+	central := ecityFence.Center
+	s.trackGeofences(context.Background(), &pb.Location{DriverId: "driver-2", Latitude: central.lat, Longitude: central.lon})
+
+	require.Len(t, matcher.requests, 2)
+	assert.Equal(t, matcher.requests[0].IdempotencyKey, matcher.requests[1].IdempotencyKey)
+}
+
+func TestFindDriversNear(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewLocationServiceClient(conn)
+
+	// The move is spread over ten minutes so it is not quarantined as a jump.
+	now := time.Now().UTC()
+	stream, err := client.UpdateLocation(ctx)
+	require.NoError(t, err)
+	for _, loc := range []*pb.Location{
+		{DriverId: "near-far", Latitude: 12.87, Longitude: 77.66},
+		{DriverId: "near-close", Latitude: 12.846, Longitude: 77.66},
+		{DriverId: "near-moved", Latitude: 12.8457, Longitude: 77.66, RecordedAt: now.Add(-10 * time.Minute).Format(time.RFC3339)},
+		{DriverId: "near-moved", Latitude: 12.9121, Longitude: 77.6387, RecordedAt: now.Format(time.RFC3339)},
+	} {
+		require.NoError(t, stream.Send(&pb.UpdateLocationRequest{Location: loc}))
+	}
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	resp, err := client.FindDriversNear(ctx, &pb.FindDriversNearRequest{Latitude: 12.8456, Longitude: 77.66, RadiusMeters: 5000})
+	require.NoError(t, err)
+	require.Len(t, resp.Drivers, 2)
+	assert.Equal(t, "near-close", resp.Drivers[0].Location.DriverId)
+	assert.Equal(t, "near-far", resp.Drivers[1].Location.DriverId)
+	assert.Less(t, resp.Drivers[0].DistanceMeters, resp.Drivers[1].DistanceMeters)
+
+	resp, err = client.FindDriversNear(ctx, &pb.FindDriversNearRequest{Latitude: 12.8456, Longitude: 77.66, RadiusMeters: 5000, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Drivers, 1)
+	assert.Empty(t, resp.UnpositionedDriverIds)
+
+	resp, err = client.FindDriversNear(ctx, &pb.FindDriversNearRequest{
+		Latitude: 12.8456, Longitude: 77.66, RadiusMeters: 5000,
+		UnpositionedCandidates: []string{"near-moved", "near-silent"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"near-silent"}, resp.UnpositionedDriverIds)
+
+	_, err = client.FindDriversNear(ctx, &pb.FindDriversNearRequest{Latitude: 12.8456, Longitude: 77.66})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,333 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/location"
+)
+
+// locationFilter selects the samples a SubscribeLocationUpdates stream
+// receives. A sample matches when it satisfies any set criterion; an empty
+// filter matches every driver.
+type locationFilter struct {
+	drivers  map[string]bool
+	bounds   *pb.BoundingBox
+	stations map[string]bool
+}
+
+// defaultSubscriberMaxLag is how long a subscriber's oldest unsent update may
+// wait before the subscriber is disconnected.
+const defaultSubscriberMaxLag = 30 * time.Second
+
+// locationSub is one SubscribeLocationUpdates stream. Updates wait in
+// pending, one per driver, so a slow stream skips positions it could not send
+// in time and always resumes from each driver's newest.
+type locationSub struct {
+	id          string
+	connectedAt time.Time
+	filter      locationFilter
+	// inArea holds drivers whose last sample matched the bounds or stations,
+	// so the sample that takes them out is delivered too. Guarded by the
+	// server's lock.
+	inArea map[string]bool
+
+	// wake signals that pending has updates; evicted is closed when the
+	// subscriber fell too far behind.
+	wake    chan struct{}
+	evicted chan struct{}
+	// cancel ends the stream's sender on eviction, so a Send blocked on a
+	// stalled client does not outlive the subscription.
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	pending map[string]*pb.LocationUpdate
+	// order lists pending drivers by when their update first arrived.
+	order     []string
+	oldest    time.Time
+	delivered int64
+	dropped   int64
+	maxLag    time.Duration
+	gone      bool
+}
+
+func newLocationSub(id string, filter locationFilter, now time.Time) *locationSub {
+	return &locationSub{
+		id:          id,
+		connectedAt: now,
+		filter:      filter,
+		inArea:      make(map[string]bool),
+		wake:        make(chan struct{}, 1),
+		evicted:     make(chan struct{}),
+		pending:     make(map[string]*pb.LocationUpdate),
+	}
+}
+
+// offer queues the update, replacing an unsent one for the same driver. It
+// reports false, and evicts the subscriber, when the oldest unsent update has
+// waited longer than maxLag.
+func (sub *locationSub) offer(update *pb.LocationUpdate, now time.Time, maxLag time.Duration) bool {
+	sub.mu.Lock()
+	if sub.gone {
+		sub.mu.Unlock()
+		return false
+	}
+	if !sub.oldest.IsZero() && now.Sub(sub.oldest) > maxLag {
+		sub.gone = true
+		sub.mu.Unlock()
+		close(sub.evicted)
+		if sub.cancel != nil {
+			sub.cancel()
+		}
+		return false
+	}
+	if _, ok := sub.pending[update.DriverId]; ok {
+		sub.dropped++
+	} else {
+		sub.order = append(sub.order, update.DriverId)
+	}
+	sub.pending[update.DriverId] = update
+	if sub.oldest.IsZero() {
+		sub.oldest = now
+	}
+	sub.mu.Unlock()
+
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// take empties the queue, oldest driver first.
+func (sub *locationSub) take(now time.Time) []*pb.LocationUpdate {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if len(sub.order) == 0 {
+		return nil
+	}
+	if lag := now.Sub(sub.oldest); lag > sub.maxLag {
+		sub.maxLag = lag
+	}
+	updates := make([]*pb.LocationUpdate, 0, len(sub.order))
+	for _, id := range sub.order {
+		updates = append(updates, sub.pending[id])
+	}
+	sub.pending = make(map[string]*pb.LocationUpdate)
+	sub.order = nil
+	sub.oldest = time.Time{}
+	return updates
+}
+
+func (sub *locationSub) sent() {
+	sub.mu.Lock()
+	sub.delivered++
+	sub.mu.Unlock()
+}
+
+func (sub *locationSub) stats(now time.Time) *pb.LocationSubscriberStats {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	stats := &pb.LocationSubscriberStats{
+		Id:            sub.id,
+		ConnectedAt:   sub.connectedAt.UTC().Format(time.RFC3339),
+		Delivered:     sub.delivered,
+		Dropped:       sub.dropped,
+		Pending:       int32(len(sub.order)),
+		MaxLagSeconds: sub.maxLag.Seconds(),
+	}
+	if !sub.oldest.IsZero() {
+		stats.LagSeconds = now.Sub(sub.oldest).Seconds()
+	}
+	return stats
+}
+
+func newLocationFilter(req *pb.SubscribeLocationRequest) (locationFilter, error) {
+	f := locationFilter{}
+	ids := req.DriverIds
+	if req.DriverId != "" {
+		ids = append([]string{req.DriverId}, ids...)
+	}
+	if len(ids) > 0 {
+		f.drivers = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			f.drivers[id] = true
+		}
+	}
+	if b := req.Bounds; b != nil {
+		if b.MinLatitude > b.MaxLatitude || b.MinLongitude > b.MaxLongitude {
+			return locationFilter{}, status.Errorf(codes.InvalidArgument, "bounds min must not exceed max")
+		}
+		f.bounds = b
+	}
+	if len(req.StationIds) > 0 {
+		f.stations = make(map[string]bool, len(req.StationIds))
+		for _, id := range req.StationIds {
+			f.stations[id] = true
+		}
+	}
+	return f, nil
+}
+
+func (f locationFilter) empty() bool {
+	return f.drivers == nil && f.bounds == nil && f.stations == nil
+}
+
+// inArea reports whether the sample lies inside the filter's bounds or one of
+// its stations.
+func (f locationFilter) inArea(loc *pb.Location, geofences *Geofencer) bool {
+	if b := f.bounds; b != nil &&
+		loc.Latitude >= b.MinLatitude && loc.Latitude <= b.MaxLatitude &&
+		loc.Longitude >= b.MinLongitude && loc.Longitude <= b.MaxLongitude {
+		return true
+	}
+	for id := range f.stations {
+		if geofences.InStation(id, loc.Latitude, loc.Longitude) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver decides whether the subscriber receives the sample and builds the
+// update it gets.
+func (sub *locationSub) deliver(loc *pb.Location, geofences *Geofencer) (*pb.LocationUpdate, bool) {
+	if sub.filter.empty() || sub.filter.drivers[loc.DriverId] {
+		return toLocationUpdate(loc), true
+	}
+	if sub.filter.inArea(loc, geofences) {
+		sub.inArea[loc.DriverId] = true
+		return toLocationUpdate(loc), true
+	}
+	if sub.inArea[loc.DriverId] {
+		delete(sub.inArea, loc.DriverId)
+		update := toLocationUpdate(loc)
+		update.LeftArea = true
+		return update, true
+	}
+	return nil, false
+}
+
+// SetSubscriberMaxLag sets how long a subscriber's oldest unsent update may
+// wait before the subscriber is disconnected. Zero or less restores the
+// default.
+func (s *Server) SetSubscriberMaxLag(d time.Duration) {
+	if d <= 0 {
+		d = defaultSubscriberMaxLag
+	}
+	s.mu.Lock()
+	s.subscriberMaxLag = d
+	s.mu.Unlock()
+}
+
+// SubscribeLocationUpdates streams the samples of the drivers, bounds and
+// stations in the request over one stream. A subscriber whose oldest unsent
+// update waits longer than the max lag is disconnected with ResourceExhausted.
+func (s *Server) SubscribeLocationUpdates(req *pb.SubscribeLocationRequest, stream pb.LocationService_SubscribeLocationUpdatesServer) error {
+	filter, err := newLocationFilter(req)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	s.mu.Lock()
+	s.nextSubscriber++
+	sub := newLocationSub(fmt.Sprintf("sub-%d", s.nextSubscriber), filter, time.Now())
+	sub.cancel = cancel
+	s.subscribers[sub] = struct{}{}
+	maxLag := s.subscriberMaxLag
+	s.mu.Unlock()
+
+	// Cleanup on return
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+		stats := sub.stats(time.Now())
+		s.logger.Info("location subscriber disconnected",
+			"subscriberId", sub.id,
+			"delivered", stats.Delivered,
+			"dropped", stats.Dropped,
+			"maxLagSeconds", stats.MaxLagSeconds)
+	}()
+
+	// Sends run apart from the handler: a stalled client blocks Send, and the
+	// handler must still return on eviction so gRPC tears the stream down.
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- sendLocationUpdates(ctx, sub, stream) }()
+
+	select {
+	case err := <-sendErr:
+		select {
+		case <-sub.evicted:
+			// Eviction cancelled the sender; report it as such.
+		default:
+			return err
+		}
+	case <-sub.evicted:
+	}
+	s.logger.Warn("location subscriber fell behind, disconnecting", "subscriberId", sub.id, "maxLag", maxLag.String())
+	return status.Errorf(codes.ResourceExhausted, "subscriber %s fell more than %s behind; resubscribe to resume from current positions", sub.id, maxLag)
+}
+
+// sendLocationUpdates drains the subscriber's queue onto the stream until ctx
+// ends or a send fails.
+func sendLocationUpdates(ctx context.Context, sub *locationSub, stream pb.LocationService_SubscribeLocationUpdatesServer) error {
+	for {
+		select {
+		case <-sub.wake:
+			for _, update := range sub.take(time.Now()) {
+				if err := stream.Send(update); err != nil {
+					return err
+				}
+				sub.sent()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// broadcast queues the sample for every subscriber whose filter it matches.
+func (s *Server) broadcast(loc *pb.Location) {
+	// Locked for writing: subscribers track which drivers are in their area.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for sub := range s.subscribers {
+		if update, ok := sub.deliver(loc, s.geofences); ok {
+			sub.offer(update, now, s.subscriberMaxLag)
+		}
+	}
+}
+
+// ListLocationSubscribers reports each open location stream's delivery and
+// lag counters.
+func (s *Server) ListLocationSubscribers(ctx context.Context, req *pb.ListLocationSubscribersRequest) (*pb.ListLocationSubscribersResponse, error) {
+	s.mu.RLock()
+	subs := make([]*locationSub, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subs = append(subs, sub)
+	}
+	s.mu.RUnlock()
+
+	now := time.Now()
+	resp := &pb.ListLocationSubscribersResponse{}
+	for _, sub := range subs {
+		resp.Subscribers = append(resp.Subscribers, sub.stats(now))
+	}
+	sort.Slice(resp.Subscribers, func(i, j int) bool {
+		return resp.Subscribers[i].ConnectedAt < resp.Subscribers[j].ConnectedAt ||
+			(resp.Subscribers[i].ConnectedAt == resp.Subscribers[j].ConnectedAt && resp.Subscribers[i].Id < resp.Subscribers[j].Id)
+	})
+	return resp, nil
+}
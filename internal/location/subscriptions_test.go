@@ -0,0 +1,223 @@
+package location
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "lastmile/gen/go/location"
+)
+
+func TestSubscribeLocationUpdatesMultiplexesDriversAndBounds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewLocationServiceClient(conn)
+
+	// Around Sydney, far from the other tests' drivers.
+	sub, err := client.SubscribeLocationUpdates(ctx, &pb.SubscribeLocationRequest{
+		DriverIds: []string{"fleet-a", "fleet-b"},
+		Bounds:    &pb.BoundingBox{MinLatitude: -33.9, MinLongitude: 151.1, MaxLatitude: -33.8, MaxLongitude: 151.3},
+	})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	stream, err := client.UpdateLocation(ctx)
+	require.NoError(t, err)
+	for _, loc := range []*pb.Location{
+		{DriverId: "fleet-a", Latitude: -37.81, Longitude: 144.96},
+		{DriverId: "fleet-c", Latitude: -33.86, Longitude: 151.2, RecordedAt: base.Format(time.RFC3339)},
+		{DriverId: "fleet-d", Latitude: -33.5, Longitude: 151.2},
+		{DriverId: "fleet-c", Latitude: -33.95, Longitude: 151.2, RecordedAt: base.Add(10 * time.Minute).Format(time.RFC3339)},
+		{DriverId: "fleet-c", Latitude: -34.0, Longitude: 151.2, RecordedAt: base.Add(20 * time.Minute).Format(time.RFC3339)},
+		{DriverId: "fleet-b", Latitude: -37.82, Longitude: 144.96},
+	} {
+		require.NoError(t, stream.Send(&pb.UpdateLocationRequest{Location: loc}))
+	}
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	// Updates for one driver may be coalesced, so only the latest of each is
+	// checked.
+	latest := make(map[string]*pb.LocationUpdate)
+	for latest["fleet-b"] == nil {
+		update, err := sub.Recv()
+		require.NoError(t, err)
+		latest[update.DriverId] = update
+	}
+	require.Contains(t, latest, "fleet-a")
+	assert.NotContains(t, latest, "fleet-d")
+	require.Contains(t, latest, "fleet-c")
+	assert.True(t, latest["fleet-c"].LeftArea, "the sample leaving the box is delivered")
+	assert.Equal(t, -33.95, latest["fleet-c"].Latitude, "samples after leaving the box are not")
+}
+
+func TestSubscribeLocationUpdatesRejectsInvertedBounds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sub, err := pb.NewLocationServiceClient(conn).SubscribeLocationUpdates(ctx, &pb.SubscribeLocationRequest{
+		Bounds: &pb.BoundingBox{MinLatitude: 1, MaxLatitude: 0},
+	})
+	require.NoError(t, err)
+	_, err = sub.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLocationSubFollowsStations(t *testing.T) {
+	geofences := NewGeofencer(GeofenceConfig{})
+	geofences.SetFences([]Fence{{ID: "station-ecity", Kind: fenceStation, StationID: "station-ecity", Center: coord{lat: 12.8456, lon: 77.66}, Radius: 500}})
+	filter, err := newLocationFilter(&pb.SubscribeLocationRequest{StationIds: []string{"station-ecity"}})
+	require.NoError(t, err)
+	sub := &locationSub{filter: filter, inArea: make(map[string]bool)}
+
+	_, ok := sub.deliver(&pb.Location{DriverId: "driver-1", Latitude: 12.9, Longitude: 77.66}, geofences)
+	assert.False(t, ok)
+	update, ok := sub.deliver(&pb.Location{DriverId: "driver-1", Latitude: 12.847, Longitude: 77.66}, geofences)
+	require.True(t, ok)
+	assert.False(t, update.LeftArea)
+	update, ok = sub.deliver(&pb.Location{DriverId: "driver-1", Latitude: 12.9, Longitude: 77.66}, geofences)
+	require.True(t, ok)
+	assert.True(t, update.LeftArea)
+	_, ok = sub.deliver(&pb.Location{DriverId: "driver-1", Latitude: 12.91, Longitude: 77.66}, geofences)
+	assert.False(t, ok)
+}
+
+func TestLocationSubKeepsNewestUpdatePerDriver(t *testing.T) {
+	now := time.Now()
+	sub := newLocationSub("sub-test", locationFilter{}, now)
+
+	assert.True(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-1", Latitude: 1}, now, time.Minute))
+	assert.True(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-2", Latitude: 2}, now, time.Minute))
+	assert.True(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-1", Latitude: 3}, now.Add(time.Second), time.Minute))
+
+	updates := sub.take(now.Add(5 * time.Second))
+	require.Len(t, updates, 2)
+	assert.Equal(t, "driver-1", updates[0].DriverId)
+	assert.Equal(t, 3.0, updates[0].Latitude, "a slow subscriber gets the newest position")
+	assert.Equal(t, "driver-2", updates[1].DriverId)
+	assert.Empty(t, sub.take(now.Add(5*time.Second)))
+
+	stats := sub.stats(now.Add(5 * time.Second))
+	assert.EqualValues(t, 1, stats.Dropped)
+	assert.EqualValues(t, 0, stats.Pending)
+	assert.Equal(t, 5.0, stats.MaxLagSeconds)
+}
+
+func TestLocationSubIsEvictedWhenTooFarBehind(t *testing.T) {
+	now := time.Now()
+	sub := newLocationSub("sub-test", locationFilter{}, now)
+
+	require.True(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-1"}, now, time.Minute))
+	assert.Equal(t, 30.0, sub.stats(now.Add(30*time.Second)).LagSeconds)
+	assert.True(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-1"}, now.Add(time.Minute), time.Minute))
+	assert.False(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-1"}, now.Add(2*time.Minute), time.Minute))
+
+	select {
+	case <-sub.evicted:
+	default:
+		t.Fatal("expected the subscriber to be evicted")
+	}
+	assert.False(t, sub.offer(&pb.LocationUpdate{DriverId: "driver-1"}, now.Add(3*time.Minute), time.Minute), "evicted subscribers take no more updates")
+}
+
+// stalledSubscribeStream is a subscriber whose client never reads: Send
+// blocks until the handler's stream ends.
+type stalledSubscribeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan struct{}
+}
+
+func (s *stalledSubscribeStream) Context() context.Context { return s.ctx }
+
+func (s *stalledSubscribeStream) Send(*pb.LocationUpdate) error {
+	select {
+	case s.sent <- struct{}{}:
+	default:
+	}
+	<-s.ctx.Done()
+	return s.ctx.Err()
+}
+
+func TestSubscribeLocationUpdatesReturnsWhenStalledSubscriberIsEvicted(t *testing.T) {
+	s := NewServer()
+	s.SetSubscriberMaxLag(50 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &stalledSubscribeStream{ctx: ctx, sent: make(chan struct{}, 1)}
+
+	done := make(chan error, 1)
+	go func() { done <- s.SubscribeLocationUpdates(&pb.SubscribeLocationRequest{}, stream) }()
+	require.Eventually(t, func() bool {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return len(s.subscribers) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	s.broadcast(&pb.Location{DriverId: "driver-1"})
+	select {
+	case <-stream.sent:
+	case <-time.After(time.Second):
+		t.Fatal("expected the first update to be sent")
+	}
+	s.broadcast(&pb.Location{DriverId: "driver-1"})
+	time.Sleep(100 * time.Millisecond)
+	s.broadcast(&pb.Location{DriverId: "driver-1"})
+
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	case <-time.After(time.Second):
+		t.Fatal("handler stayed blocked in Send after eviction")
+	}
+	s.mu.RLock()
+	assert.Empty(t, s.subscribers)
+	s.mu.RUnlock()
+}
+
+func TestListLocationSubscribersReportsOpenStreams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewLocationServiceClient(conn)
+
+	sub, err := client.SubscribeLocationUpdates(ctx, &pb.SubscribeLocationRequest{DriverId: "driver-listed"})
+	require.NoError(t, err)
+	stream, err := client.UpdateLocation(ctx)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, stream.Send(&pb.UpdateLocationRequest{Location: &pb.Location{DriverId: "driver-listed", Latitude: -22.9, Longitude: -43.2}}))
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+	_, err = sub.Recv()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		resp, err := client.ListLocationSubscribers(ctx, &pb.ListLocationSubscribersRequest{})
+		if err != nil {
+			return false
+		}
+		for _, stats := range resp.Subscribers {
+			if stats.Delivered == 1 && stats.Pending == 0 {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
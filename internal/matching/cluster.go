@@ -0,0 +1,192 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "lastmile/gen/go/matching"
+	"lastmile/internal/pkg/hashring"
+)
+
+// Dialer opens a connection to the matching replica listening at addr. The
+// cluster closes it once the replica leaves.
+type Dialer func(addr string) (*grpc.ClientConn, error)
+
+// peer is another member and the connection used to reach it.
+type peer struct {
+	conn   *grpc.ClientConn
+	client pb.MatchingServiceClient
+}
+
+// cluster shards stations across matching replicas. Members are identified by
+// the address they serve on; the ring maps each station to exactly one of them.
+type cluster struct {
+	self    string
+	forward bool
+	dial    Dialer
+
+	mu    sync.Mutex
+	ring  *hashring.Ring
+	peers map[string]peer
+	// changed is closed and replaced whenever a member joins or leaves.
+	changed chan struct{}
+}
+
+// EnableSharding makes this replica one member of a sharded cluster. self is
+// the address other replicas reach it on. Requests for stations owned by
+// another member are forwarded there through dial when forward is true, and
+// rejected otherwise. The replica starts as the only member; use SetMembers to
+// add the rest.
+func (s *Server) EnableSharding(self string, dial Dialer, forward bool) {
+	ring := hashring.New(0)
+	ring.Add(self)
+	s.cluster = &cluster{
+		self:    self,
+		forward: forward,
+		dial:    dial,
+		ring:    ring,
+		peers:   make(map[string]peer),
+		changed: make(chan struct{}),
+	}
+}
+
+// SetMembers reconciles the ring with the replicas currently alive. New members
+// are dialled and added, missing ones removed, so stations rebalance as
+// replicas join or leave. This replica always stays a member.
+func (s *Server) SetMembers(members []string) error {
+	c := s.cluster
+	if c == nil {
+		return fmt.Errorf("sharding is not enabled")
+	}
+	want := map[string]bool{c.self: true}
+	for _, m := range members {
+		want[m] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := false
+	defer func() {
+		if changed {
+			close(c.changed)
+			c.changed = make(chan struct{})
+		}
+	}()
+	for _, m := range c.ring.Members() {
+		if !want[m] {
+			c.ring.Remove(m)
+			if p, ok := c.peers[m]; ok {
+				if err := p.conn.Close(); err != nil {
+					s.logger.Warn("closing connection to departed replica failed", "member", m, "err", err)
+				}
+				delete(c.peers, m)
+			}
+			changed = true
+			s.logger.Info("matching replica left", "member", m)
+		}
+	}
+	var errs []error
+	for m := range want {
+		if m == c.self {
+			continue
+		}
+		if _, ok := c.peers[m]; ok {
+			continue
+		}
+		conn, err := c.dial(m)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dial %s: %w", m, err))
+			continue
+		}
+		c.peers[m] = peer{conn: conn, client: pb.NewMatchingServiceClient(conn)}
+		c.ring.Add(m)
+		changed = true
+		s.logger.Info("matching replica joined", "member", m)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("membership update incomplete: %v", errs)
+	}
+	return nil
+}
+
+// Owner returns the replica responsible for stationID, or "" when sharding is off.
+func (s *Server) Owner(stationID string) string {
+	if s.cluster == nil {
+		return ""
+	}
+	return s.cluster.ring.Owner(stationID)
+}
+
+// peerClients returns clients for every other member by address, and a
+// channel that is closed once membership next changes. Without sharding both
+// are nil.
+func (s *Server) peerClients() (map[string]pb.MatchingServiceClient, <-chan struct{}) {
+	c := s.cluster
+	if c == nil {
+		return nil, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]pb.MatchingServiceClient, len(c.peers))
+	for addr, p := range c.peers {
+		out[addr] = p.client
+	}
+	return out, c.changed
+}
+
+func (s *Server) replicaID() string {
+	if s.cluster == nil {
+		return ""
+	}
+	return s.cluster.self
+}
+
+// GetOwner reports which replica owns a station and the current members.
+func (s *Server) GetOwner(ctx context.Context, req *pb.GetOwnerRequest) (*pb.GetOwnerResponse, error) {
+	if s.cluster == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "matching service is not sharded")
+	}
+	return &pb.GetOwnerResponse{
+		Owner:   s.cluster.ring.Owner(req.StationId),
+		Members: s.cluster.ring.Members(),
+	}, nil
+}
+
+// routeToOwner hands req to the station's owner when this replica is not it.
+// handled is false when this replica owns the station and should match itself.
+func (s *Server) routeToOwner(ctx context.Context, req *pb.MatchRequest) (res *pb.MatchResponse, handled bool, err error) {
+	c := s.cluster
+	if c == nil {
+		return nil, false, nil
+	}
+	owner := c.ring.Owner(req.StationId)
+	if owner == c.self {
+		return nil, false, nil
+	}
+	if req.Forwarded {
+		// The sender thinks we own the station but our view disagrees; let it
+		// retry once membership settles rather than bounce the request again.
+		return nil, true, status.Errorf(codes.Aborted, "station '%s' is owned by '%s', not '%s'", req.StationId, owner, c.self)
+	}
+	if !c.forward {
+		return nil, true, status.Errorf(codes.FailedPrecondition, "station '%s' is owned by matching replica '%s'", req.StationId, owner)
+	}
+
+	c.mu.Lock()
+	client := c.peers[owner].client
+	c.mu.Unlock()
+	if client == nil {
+		return nil, true, status.Errorf(codes.Unavailable, "no connection to matching replica '%s'", owner)
+	}
+	fwd := proto.Clone(req).(*pb.MatchRequest)
+	fwd.Forwarded = true
+	s.logger.Info("forwarding match to station owner", "driverId", req.DriverId, "stationId", req.StationId, "owner", owner)
+	res, err = client.Match(ctx, fwd)
+	return res, true, err
+}
@@ -0,0 +1,193 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	driverpb "lastmile/gen/go/driver"
+	pb "lastmile/gen/go/matching"
+)
+
+// newTestCluster starts n matching replicas sharing the same backends. Each
+// replica serves on its own bufconn listener and knows every other member.
+// The returned Dialer reaches any replica by its address.
+func newTestCluster(t *testing.T, clients Clients, n int, forward bool) ([]*Server, Dialer) {
+	t.Helper()
+	listeners := make(map[string]*bufconn.Listener, n)
+	servers := make([]*Server, n)
+	members := make([]string, n)
+	for i := range servers {
+		addr := fmt.Sprintf("matching-%d", i)
+		members[i] = addr
+		servers[i] = NewServerWithClients(clients, nil)
+
+		lis := bufconn.Listen(1024 * 1024)
+		listeners[addr] = lis
+		g := grpc.NewServer()
+		pb.RegisterMatchingServiceServer(g, servers[i])
+		go func() { _ = g.Serve(lis) }()
+		t.Cleanup(g.Stop)
+	}
+
+	dial := func(addr string) (*grpc.ClientConn, error) {
+		lis, ok := listeners[addr]
+		if !ok {
+			return nil, fmt.Errorf("unknown replica %s", addr)
+		}
+		conn, err := grpc.NewClient("passthrough:///"+addr,
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, err
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn, nil
+	}
+	for i, s := range servers {
+		s.EnableSharding(members[i], dial, forward)
+		require.NoError(t, s.SetMembers(members))
+	}
+	return servers, dial
+}
+
+func replicaFor(servers []*Server, addr string) *Server {
+	for _, s := range servers {
+		if s.replicaID() == addr {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestShardedReplicasAgreeOnOwnership(t *testing.T) {
+	servers, _ := newTestCluster(t, newTestBackends(t), 3, true)
+
+	owned := map[string]int{}
+	for i := 0; i < 60; i++ {
+		station := fmt.Sprintf("station-%d", i)
+		owner := servers[0].Owner(station)
+		for _, s := range servers[1:] {
+			assert.Equal(t, owner, s.Owner(station))
+		}
+		owned[owner]++
+	}
+	assert.Len(t, owned, 3, "every replica should own some stations: %v", owned)
+
+	res, err := servers[1].GetOwner(context.Background(), &pb.GetOwnerRequest{StationId: "station-1"})
+	require.NoError(t, err)
+	assert.Equal(t, servers[0].Owner("station-1"), res.Owner)
+	assert.Equal(t, []string{"matching-0", "matching-1", "matching-2"}, res.Members)
+}
+
+func TestShardedMatchForwardsToOwner(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 3)
+	for i := 2; i <= 3; i++ {
+		_, err := clients.Drivers.RegisterRoute(context.Background(), &driverpb.RegisterRouteRequest{Route: &driverpb.Route{
+			DriverId:         fmt.Sprintf("driver-%d", i),
+			TargetStationIds: []string{"station-1"},
+			AvailableSeats:   3,
+			Destination:      "Wipro Gate",
+		}})
+		require.NoError(t, err)
+	}
+	for i := 0; i < 4; i++ {
+		addRider(t, clients, fmt.Sprintf("Rider %d", i), "Wipro Gate", time.Duration(i)*time.Minute)
+	}
+	servers, _ := newTestCluster(t, clients, 3, true)
+	owner := servers[0].Owner("station-1")
+
+	// Every replica receives a match for the same station at once; only the
+	// owner runs them, so no rider ends up in two trips.
+	var wg sync.WaitGroup
+	results := make([]*pb.MatchResponse, len(servers))
+	errs := make([]error, len(servers))
+	for i, s := range servers {
+		wg.Add(1)
+		go func(i int, s *Server) {
+			defer wg.Done()
+			results[i], errs[i] = s.Match(context.Background(), &pb.MatchRequest{DriverId: fmt.Sprintf("driver-%d", i+1), StationId: "station-1"})
+		}(i, s)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	total := 0
+	for i, res := range results {
+		require.NoError(t, errs[i])
+		assert.Equal(t, owner, res.ServedBy)
+		for _, trip := range res.Trips {
+			assert.False(t, seen[trip.RiderId], "rider %s matched twice", trip.RiderId)
+			seen[trip.RiderId] = true
+			total++
+		}
+	}
+	assert.Equal(t, 4, total)
+}
+
+func TestShardedMatchRejectsWhenNotForwarding(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 1)
+	servers, _ := newTestCluster(t, clients, 3, false)
+	owner := servers[0].Owner("station-1")
+
+	for _, s := range servers {
+		_, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+		if s.replicaID() == owner {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	}
+}
+
+func TestShardedMembershipRebalances(t *testing.T) {
+	servers, _ := newTestCluster(t, newTestBackends(t), 3, true)
+	owner := servers[0].Owner("station-1")
+
+	remaining := make([]string, 0, 2)
+	for _, s := range servers {
+		if s.replicaID() != owner {
+			remaining = append(remaining, s.replicaID())
+		}
+	}
+	// The owner leaves; the survivors take over its stations and close their
+	// connections to it.
+	conns := make([]*grpc.ClientConn, 0, len(remaining))
+	for _, addr := range remaining {
+		c := replicaFor(servers, addr).cluster
+		c.mu.Lock()
+		conns = append(conns, c.peers[owner].conn)
+		c.mu.Unlock()
+	}
+	for _, addr := range remaining {
+		require.NoError(t, replicaFor(servers, addr).SetMembers(remaining))
+	}
+	for _, conn := range conns {
+		assert.Equal(t, connectivity.Shutdown, conn.GetState())
+	}
+	next := replicaFor(servers, remaining[0]).Owner("station-1")
+	assert.Contains(t, remaining, next)
+	assert.Equal(t, next, replicaFor(servers, remaining[1]).Owner("station-1"))
+
+	// It rejoins and reclaims the same stations.
+	for _, s := range servers {
+		require.NoError(t, s.SetMembers([]string{"matching-0", "matching-1", "matching-2"}))
+	}
+	assert.Equal(t, owner, servers[1].Owner("station-1"))
+
+	assert.Error(t, NewServer().SetMembers(nil))
+}
@@ -0,0 +1,164 @@
+package matching
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "lastmile/gen/go/matching"
+	tripb "lastmile/gen/go/trip"
+)
+
+// defaultRiderExpiry is how long past their arrival a waiting rider is kept
+// before being expired.
+const defaultRiderExpiry = 30 * time.Minute
+
+// eventBus fans match events out to WatchMatches streams.
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[chan *pb.MatchEvent]map[string]bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[chan *pb.MatchEvent]map[string]bool)}
+}
+
+// subscribe registers for events at the given stations, or all stations when
+// none are given. The returned func unsubscribes.
+func (b *eventBus) subscribe(stationIDs []string) (chan *pb.MatchEvent, func()) {
+	var filter map[string]bool
+	if len(stationIDs) > 0 {
+		filter = make(map[string]bool, len(stationIDs))
+		for _, id := range stationIDs {
+			filter[id] = true
+		}
+	}
+	ch := make(chan *pb.MatchEvent, 32)
+	b.mu.Lock()
+	b.subs[ch] = filter
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+	}
+}
+
+func (b *eventBus) publish(ev *pb.MatchEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, filter := range b.subs {
+		if filter != nil && !filter[ev.StationId] {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+			// Skip if channel is full to avoid blocking matches
+		}
+	}
+}
+
+func (s *Server) emit(typ pb.MatchEventType, stationID, driverID, riderID string, trip *tripb.Trip, reason string) {
+	s.events.publish(&pb.MatchEvent{
+		Type:       typ,
+		StationId:  stationID,
+		DriverId:   driverID,
+		RiderId:    riderID,
+		Trip:       trip,
+		Reason:     reason,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339),
+		ServedBy:   s.replicaID(),
+	})
+}
+
+// WatchMatches streams match events for the requested stations as they
+// happen. On a sharded replica the stream also carries events from every
+// other member, since each station is matched by its owner only. Members that
+// join while the stream is open are relayed from then on; members that leave
+// are dropped.
+func (s *Server) WatchMatches(req *pb.WatchMatchesRequest, stream grpc.ServerStreamingServer[pb.MatchEvent]) error {
+	ctx := stream.Context()
+	ch, unsubscribe := s.events.subscribe(req.StationIds)
+	defer unsubscribe()
+
+	relays := make(map[string]peerRelay)
+	var membersChanged <-chan struct{}
+	attach := func() {
+		var peers map[string]pb.MatchingServiceClient
+		peers, membersChanged = s.peerClients()
+		for addr, r := range relays {
+			if peers[addr] != r.client {
+				r.cancel()
+				delete(relays, addr)
+			}
+		}
+		for addr, peer := range peers {
+			if _, ok := relays[addr]; ok {
+				continue
+			}
+			relayCtx, cancel := context.WithCancel(ctx)
+			relays[addr] = peerRelay{client: peer, cancel: cancel}
+			go s.relayPeerEvents(relayCtx, peer, req.StationIds, ch)
+		}
+	}
+	if !req.LocalOnly {
+		attach()
+	}
+	defer func() {
+		for _, r := range relays {
+			r.cancel()
+		}
+	}()
+
+	for {
+		select {
+		case ev := <-ch:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-membersChanged:
+			attach()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// peerRelay is one member's events being relayed into a WatchMatches stream.
+type peerRelay struct {
+	client pb.MatchingServiceClient
+	cancel context.CancelFunc
+}
+
+func (s *Server) relayPeerEvents(ctx context.Context, peer pb.MatchingServiceClient, stationIDs []string, out chan<- *pb.MatchEvent) {
+	stream, err := peer.WatchMatches(ctx, &pb.WatchMatchesRequest{StationIds: stationIDs, LocalOnly: true})
+	if err != nil {
+		s.logger.Warn("watch peer failed", "err", err)
+		return
+	}
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			return
+		}
+		select {
+		case out <- ev:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SetRiderExpiry changes how long past their arrival waiting riders are kept.
+// Non-positive values restore the default.
+func (s *Server) SetRiderExpiry(expiry time.Duration) {
+	if expiry <= 0 {
+		expiry = defaultRiderExpiry
+	}
+	s.mu.Lock()
+	s.riderExpiry = expiry
+	s.mu.Unlock()
+}
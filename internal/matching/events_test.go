@@ -0,0 +1,168 @@
+package matching
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "lastmile/gen/go/matching"
+	riderpb "lastmile/gen/go/rider"
+)
+
+func serveMatching(t *testing.T, s *Server) pb.MatchingServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	g := grpc.NewServer()
+	pb.RegisterMatchingServiceServer(g, s)
+	go func() { _ = g.Serve(lis) }()
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///matching",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewMatchingServiceClient(conn)
+}
+
+// watch opens a WatchMatches stream and waits until the server has registered it.
+func watch(t *testing.T, ctx context.Context, client pb.MatchingServiceClient, s *Server, stationIDs ...string) grpc.ServerStreamingClient[pb.MatchEvent] {
+	t.Helper()
+	stream, err := client.WatchMatches(ctx, &pb.WatchMatchesRequest{StationIds: stationIDs})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		s.events.mu.Lock()
+		defer s.events.mu.Unlock()
+		return len(s.events.subs) > 0
+	}, time.Second, 10*time.Millisecond)
+	return stream
+}
+
+func TestWatchMatchesStreamsEvents(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 2)
+	rider := addRider(t, clients, "Priya", "Wipro Gate", time.Minute)
+	stale := addRider(t, clients, "Kiran", "Wipro Gate", -45*time.Minute)
+
+	s := NewServerWithClients(clients, nil)
+	client := serveMatching(t, s)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := watch(t, ctx, client, s, "station-1")
+
+	res, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	require.NoError(t, err)
+	require.Len(t, res.Trips, 1)
+
+	expired, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.MatchEventType_MATCH_EVENT_TYPE_RIDER_EXPIRED, expired.Type)
+	assert.Equal(t, stale, expired.RiderId)
+
+	created, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.MatchEventType_MATCH_EVENT_TYPE_CREATED, created.Type)
+	assert.Equal(t, rider, created.RiderId)
+	require.NotNil(t, created.Trip)
+	assert.Equal(t, res.Trips[0].Id, created.Trip.Id)
+
+	stored, err := clients.Riders.ListRiders(context.Background(), &riderpb.ListRidersRequest{StationId: "station-1", Status: "expired"})
+	require.NoError(t, err)
+	require.Len(t, stored.Riders, 1)
+
+	_, err = s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-9"})
+	require.Error(t, err)
+	// The watcher only asked for station-1, so the rejection at station-9 is not delivered.
+	s.emit(pb.MatchEventType_MATCH_EVENT_TYPE_REJECTED, "station-1", "driver-1", "", nil, "marker")
+	next, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "marker", next.Reason)
+}
+
+func TestWatchMatchesRelaysEventsFromOwner(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 1)
+	addRider(t, clients, "Priya", "Wipro Gate", time.Minute)
+	servers, dial := newTestCluster(t, clients, 3, true)
+
+	owner := replicaFor(servers, servers[0].Owner("station-1"))
+	var watcher *Server
+	for _, s := range servers {
+		if s != owner {
+			watcher = s
+			break
+		}
+	}
+	conn, err := dial(watcher.replicaID())
+	require.NoError(t, err)
+	client := pb.NewMatchingServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := watch(t, ctx, client, watcher)
+	require.Eventually(t, func() bool {
+		owner.events.mu.Lock()
+		defer owner.events.mu.Unlock()
+		return len(owner.events.subs) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = owner.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	require.NoError(t, err)
+
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.MatchEventType_MATCH_EVENT_TYPE_CREATED, ev.Type)
+	assert.Equal(t, owner.replicaID(), ev.ServedBy)
+}
+
+func TestWatchMatchesRelaysMembersThatJoinLater(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 1)
+	addRider(t, clients, "Priya", "Wipro Gate", time.Minute)
+	servers, dial := newTestCluster(t, clients, 2, true)
+
+	owner := replicaFor(servers, servers[0].Owner("station-1"))
+	watcher := servers[0]
+	if watcher == owner {
+		watcher = servers[1]
+	}
+	// The watcher starts out alone and only learns of the owner once its
+	// stream is open.
+	require.NoError(t, watcher.SetMembers(nil))
+	conn, err := dial(watcher.replicaID())
+	require.NoError(t, err)
+	client := pb.NewMatchingServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := watch(t, ctx, client, watcher)
+	require.NoError(t, watcher.SetMembers([]string{owner.replicaID()}))
+	require.Eventually(t, func() bool {
+		owner.events.mu.Lock()
+		defer owner.events.mu.Unlock()
+		return len(owner.events.subs) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = owner.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	require.NoError(t, err)
+
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.MatchEventType_MATCH_EVENT_TYPE_CREATED, ev.Type)
+	assert.Equal(t, owner.replicaID(), ev.ServedBy)
+
+	// Once the owner leaves, its relay is closed.
+	require.NoError(t, watcher.SetMembers(nil))
+	require.Eventually(t, func() bool {
+		owner.events.mu.Lock()
+		defer owner.events.mu.Unlock()
+		return len(owner.events.subs) == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,123 @@
+package matching
+
+import (
+	"strings"
+	"time"
+
+	driverpb "lastmile/gen/go/driver"
+	riderpb "lastmile/gen/go/rider"
+	"lastmile/internal/pkg/matchpolicy"
+)
+
+// defaultArrivalWindow is how far apart the driver's and a rider's arrival at
+// the station may be for them to be matched.
+const defaultArrivalWindow = 10 * time.Minute
+
+// selectRiders picks the riders a driver should take from a station.
+// Only waiting riders at the station whose destination is compatible with the
+// driver's route are considered; policy ranks them on their wait and arrival
+// gap and the best win until seats run out. Compatible riders arriving outside window of driverArrival are
+// returned as deferred so they stay queued for a later pass.
+func selectRiders(policy matchpolicy.MatchPolicy, route *driverpb.Route, stationID string, riders []*riderpb.Rider, seats int, driverArrival time.Time, window time.Duration) (selected, deferred []*riderpb.Rider) {
+	if route == nil || seats <= 0 {
+		return nil, nil
+	}
+
+	eligible := make([]*riderpb.Rider, 0, len(riders))
+	for _, r := range riders {
+		if r == nil || r.StationId != stationID {
+			continue
+		}
+		if r.Status != "" && r.Status != "waiting" {
+			continue
+		}
+		if !compatibleDestination(route.Destination, r.Destination) {
+			continue
+		}
+		if !withinArrivalWindow(driverArrival, r, window) {
+			deferred = append(deferred, r)
+			continue
+		}
+		eligible = append(eligible, r)
+	}
+
+	policy = matchpolicy.Only(policy, riderFactors...)
+	byID := make(map[string]*riderpb.Rider, len(eligible))
+	candidates := make([]matchpolicy.Candidate, 0, len(eligible))
+	now := time.Now()
+	for _, r := range eligible {
+		byID[r.Id] = r
+		candidates = append(candidates, riderCandidate(route, r, driverArrival, now))
+	}
+	for _, ranked := range matchpolicy.Rank(policy, candidates) {
+		if len(selected) == seats {
+			break
+		}
+		selected = append(selected, byID[ranked.RiderID])
+	}
+	return selected, deferred
+}
+
+// riderFactors are the policy factors that differ between the riders
+// competing for one driver. Distance, seat fill and fairness describe the
+// driver, so they are the same for every candidate here and cannot change
+// who is picked; scoring them would only pad the totals.
+var riderFactors = []string{matchpolicy.FactorWait, matchpolicy.FactorArrivalGap}
+
+// riderCandidate describes the driver/rider pair for a MatchPolicy. Only the
+// rider's timing is filled in; see riderFactors.
+func riderCandidate(route *driverpb.Route, rider *riderpb.Rider, driverArrival, now time.Time) matchpolicy.Candidate {
+	c := matchpolicy.Candidate{
+		DriverID: route.DriverId,
+		RiderID:  rider.Id,
+	}
+	if rider.GetArrivalTime() != nil {
+		arrival := rider.GetArrivalTime().AsTime()
+		c.RiderWait = now.Sub(arrival)
+		c.ArrivalGap = driverArrival.Sub(arrival)
+		if c.ArrivalGap < 0 {
+			c.ArrivalGap = -c.ArrivalGap
+		}
+	}
+	return c
+}
+
+// withinArrivalWindow reports whether the rider reaches the station within
+// window of the driver. Riders without an arrival time always qualify.
+func withinArrivalWindow(driverArrival time.Time, rider *riderpb.Rider, window time.Duration) bool {
+	if rider.GetArrivalTime() == nil {
+		return true
+	}
+	gap := driverArrival.Sub(rider.GetArrivalTime().AsTime())
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= window
+}
+
+// compatibleDestination mirrors the gateway rule: unknown destinations match
+// anything, otherwise one destination has to contain the other.
+func compatibleDestination(driverDest, riderDest string) bool {
+	driverNorm := normalizeDestination(driverDest)
+	riderNorm := normalizeDestination(riderDest)
+	if driverNorm == "" || driverNorm == "unknown" || riderNorm == "" {
+		return true
+	}
+	if driverNorm == riderNorm {
+		return true
+	}
+	return strings.Contains(driverNorm, riderNorm) || strings.Contains(riderNorm, driverNorm)
+}
+
+func normalizeDestination(value string) string {
+	return strings.TrimSpace(strings.ToLower(value))
+}
+
+func routeContains(ids []string, target string) bool {
+	for _, id := range ids {
+		if id == target {
+			return true
+		}
+	}
+	return false
+}
@@ -3,48 +3,347 @@ package matching
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	driverpb "lastmile/gen/go/driver"
 	pb "lastmile/gen/go/matching"
+	riderpb "lastmile/gen/go/rider"
+	stationpb "lastmile/gen/go/station"
 	tripb "lastmile/gen/go/trip"
 	"lastmile/internal/pkg/logging"
+	"lastmile/internal/pkg/matchpolicy"
 )
 
+// Clients bundles the downstream services Match reads from and writes to.
+// Stations and Trips are optional; Drivers and Riders are required to match.
+type Clients struct {
+	Drivers  driverpb.DriverServiceClient
+	Riders   riderpb.RiderServiceClient
+	Stations stationpb.StationServiceClient
+	Trips    tripb.TripServiceClient
+}
+
 // Server implements the MatchingServiceServer interface.
 type Server struct {
 	pb.UnimplementedMatchingServiceServer
-	logger *slog.Logger
+	logger   *slog.Logger
+	clients  Clients
+	window   time.Duration
+	policies *matchpolicy.Selector
+	// cluster is set when stations are sharded across replicas.
+	cluster *cluster
+	events  *eventBus
+	// riderExpiry is how long past their arrival waiting riders are kept.
+	riderExpiry time.Duration
+
+	// mu guards the fields below. It is only held to reserve and commit an
+	// assignment, never across calls to other services.
+	mu sync.Mutex
+	// matching holds a channel per driver with a match in progress; it is
+	// closed when the match finishes so a concurrent call can go next.
+	matching map[string]chan struct{}
+	// reserved holds riders picked by a match in progress until their claim
+	// has gone through, so two concurrent matches cannot take the same rider.
+	reserved map[string]bool
+	// completed remembers responses by idempotency key so retried requests
+	// are answered without matching again.
+	completed map[string]completedMatch
+}
+
+type completedMatch struct {
+	res *pb.MatchResponse
+	at  time.Time
 }
 
-// NewServer creates a new Server.
+// idempotencyTTL bounds how long a completed match is remembered.
+const idempotencyTTL = 30 * time.Minute
+
+// NewServer creates a new Server without downstream clients.
+// Match fails with FailedPrecondition until clients are provided via NewServerWithClients.
 func NewServer(logger ...*slog.Logger) *Server {
 	l := logging.New("matching")
 	if len(logger) > 0 && logger[0] != nil {
 		l = logger[0]
 	}
 
-	return &Server{logger: l}
+	return &Server{
+		logger:      l,
+		window:      defaultArrivalWindow,
+		completed:   make(map[string]completedMatch),
+		matching:    make(map[string]chan struct{}),
+		reserved:    make(map[string]bool),
+		events:      newEventBus(),
+		riderExpiry: defaultRiderExpiry,
+	}
+}
+
+// NewServerWithClients wires the driver, rider, station and trip services used by Match.
+func NewServerWithClients(clients Clients, logger *slog.Logger) *Server {
+	s := NewServer(logger)
+	s.clients = clients
+	return s
+}
+
+// SetArrivalWindow changes the default arrival-time tolerance used when a
+// request does not specify one. Non-positive values restore the default.
+func (s *Server) SetArrivalWindow(window time.Duration) {
+	if window <= 0 {
+		window = defaultArrivalWindow
+	}
+	s.mu.Lock()
+	s.window = window
+	s.mu.Unlock()
+}
+
+// SetMatchPolicy chooses how waiting riders are ranked for a driver. The
+// default policy applies to every station without an entry in perStation.
+// Riders are compared on their wait and arrival gap only, so seat-fill and
+// fairness rank like nearest here; they matter when ranking drivers.
+func (s *Server) SetMatchPolicy(defaultPolicy string, perStation map[string]string) error {
+	selector, err := matchpolicy.NewSelector(defaultPolicy, perStation)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.policies = selector
+	s.mu.Unlock()
+	return nil
 }
 
 // Match finds suitable riders for a driver near a station and creates trips.
 func (s *Server) Match(ctx context.Context, req *pb.MatchRequest) (*pb.MatchResponse, error) {
-	// In a real implementation, we would:
-	// 1. Get driver details from the driver service.
-	// 2. Get riders near the station from the rider service.
-	// 3. Find the best matches based on destination, arrival time, etc.
-	// 4. Create trips and store them in the trip service.
-	// 5. Notify the driver and riders.
+	if req.DriverId == "" || req.StationId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "driver_id and station_id are required")
+	}
+	if res, handled, err := s.routeToOwner(ctx, req); handled {
+		return res, err
+	}
+	if s.clients.Drivers == nil || s.clients.Riders == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "matching service has no driver/rider backends configured")
+	}
+
+	res, done, err := s.reserveDriver(ctx, req)
+	if res != nil || err != nil {
+		return res, err
+	}
+	defer done()
+
+	s.mu.Lock()
+	window := s.window
+	policy := s.policies.For(req.StationId)
+	s.mu.Unlock()
+	if req.ArrivalWindowMinutes > 0 {
+		window = time.Duration(req.ArrivalWindowMinutes) * time.Minute
+	}
+	driverArrival := time.Now().Add(time.Duration(req.DriverEtaMinutes) * time.Minute)
+
+	routeResp, err := s.clients.Drivers.GetRoute(ctx, &driverpb.GetRouteRequest{DriverId: req.DriverId})
+	if err != nil {
+		s.logger.Warn("match: route lookup failed", "driverId", req.DriverId, "err", err)
+		return nil, err
+	}
+	route := routeResp.GetRoute()
+	if !routeContains(route.GetTargetStationIds(), req.StationId) {
+		s.emit(pb.MatchEventType_MATCH_EVENT_TYPE_REJECTED, req.StationId, req.DriverId, "", nil, "driver not routed to station")
+		return nil, status.Errorf(codes.FailedPrecondition, "driver '%s' is not routed to station '%s'", req.DriverId, req.StationId)
+	}
+
+	if s.clients.Stations != nil {
+		if _, err := s.clients.Stations.GetStation(ctx, &stationpb.GetStationRequest{Id: req.StationId}); err != nil {
+			s.logger.Warn("match: station lookup failed", "stationId", req.StationId, "err", err)
+			return nil, err
+		}
+	}
+
+	seats := int(route.GetAvailableSeats())
+	if seats <= 0 {
+		s.logger.Info("match skipped: driver has no seats", "driverId", req.DriverId, "stationId", req.StationId)
+		return &pb.MatchResponse{ArrivalWindowMinutes: int32(window.Minutes()), ServedBy: s.replicaID()}, nil
+	}
 
-	// For now, we'll just return a hardcoded trip.
-	trip := &tripb.Trip{
-		Id:       uuid.New().String(),
-		DriverId: req.DriverId,
-		RiderId:  "rider-456", // Hardcoded rider
-		Status:   "pending",
+	ridersResp, err := s.clients.Riders.ListRiders(ctx, &riderpb.ListRidersRequest{StationId: req.StationId, Status: "waiting"})
+	if err != nil {
+		s.logger.Warn("match: rider lookup failed", "stationId", req.StationId, "err", err)
+		return nil, err
 	}
 
-	s.logger.Info("match triggered", "driverId", req.DriverId, "stationId", req.StationId, "tripId", trip.Id)
+	waiting := s.expireRiders(ctx, req.StationId, ridersResp.GetRiders(), time.Now())
 
-	return &pb.MatchResponse{Trips: []*tripb.Trip{trip}}, nil
+	s.mu.Lock()
+	available := make([]*riderpb.Rider, 0, len(waiting))
+	for _, r := range waiting {
+		if !s.reserved[r.Id] {
+			available = append(available, r)
+		}
+	}
+	selected, deferred := selectRiders(policy, route, req.StationId, available, seats, driverArrival, window)
+	for _, r := range selected {
+		s.reserved[r.Id] = true
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		for _, r := range selected {
+			delete(s.reserved, r.Id)
+		}
+		s.mu.Unlock()
+	}()
+
+	trips := make([]*tripb.Trip, 0, len(selected))
+	for _, rider := range selected {
+		// The claim only succeeds while the rider is still waiting, so a rider
+		// another replica or the gateway took meanwhile is skipped here.
+		if _, err := s.clients.Riders.UpdateRiderStatus(ctx, &riderpb.UpdateRiderStatusRequest{Id: rider.Id, Status: "matched", ExpectedStatus: "waiting"}); err != nil {
+			s.logger.Warn("match: claim rider failed", "riderId", rider.Id, "err", err)
+			s.emit(pb.MatchEventType_MATCH_EVENT_TYPE_REJECTED, req.StationId, req.DriverId, rider.Id, nil, "rider could not be claimed")
+			continue
+		}
+
+		trip := &tripb.Trip{
+			Id:          tripID(req.IdempotencyKey, rider.Id),
+			DriverId:    req.DriverId,
+			RiderId:     rider.Id,
+			Status:      "pending",
+			StationId:   req.StationId,
+			Destination: rider.Destination,
+			CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		}
+		if s.clients.Trips != nil {
+			if _, err := s.clients.Trips.CreateTrip(ctx, &tripb.CreateTripRequest{Trip: trip}); err != nil && status.Code(err) != codes.AlreadyExists {
+				s.logger.Warn("match: persist trip failed", "tripId", trip.Id, "err", err)
+			}
+		}
+		trips = append(trips, trip)
+		s.emit(pb.MatchEventType_MATCH_EVENT_TYPE_CREATED, req.StationId, req.DriverId, rider.Id, trip, "")
+	}
+
+	if len(trips) > 0 {
+		updated := &driverpb.Route{
+			Id:               route.Id,
+			DriverId:         route.DriverId,
+			TargetStationIds: route.TargetStationIds,
+			AvailableSeats:   int32(seats - len(trips)),
+			Destination:      route.Destination,
+		}
+		if _, err := s.clients.Drivers.RegisterRoute(ctx, &driverpb.RegisterRouteRequest{Route: updated}); err != nil {
+			s.logger.Warn("match: seat update failed", "driverId", req.DriverId, "err", err)
+		}
+	}
+
+	s.logger.Info("match triggered",
+		"driverId", req.DriverId,
+		"stationId", req.StationId,
+		"waiting", len(ridersResp.GetRiders()),
+		"matched", len(trips),
+		"deferred", len(deferred),
+		"window", window.String(),
+		"policy", policy.Name())
+
+	deferredIDs := make([]string, 0, len(deferred))
+	for _, r := range deferred {
+		deferredIDs = append(deferredIDs, r.Id)
+	}
+
+	res = &pb.MatchResponse{
+		Trips:                trips,
+		ArrivalWindowMinutes: int32(window.Minutes()),
+		DeferredRiderIds:     deferredIDs,
+		Policy:               policy.Name(),
+		ServedBy:             s.replicaID(),
+	}
+	if req.IdempotencyKey != "" {
+		s.mu.Lock()
+		s.completed[req.IdempotencyKey] = completedMatch{res: proto.Clone(res).(*pb.MatchResponse), at: time.Now()}
+		s.mu.Unlock()
+	}
+	return res, nil
+}
+
+// reserveDriver waits until no other match for the driver is in progress on
+// this replica and claims the driver, so their seats are never counted twice.
+// It returns the stored response instead when the request is a replay. done
+// releases the driver.
+func (s *Server) reserveDriver(ctx context.Context, req *pb.MatchRequest) (res *pb.MatchResponse, done func(), err error) {
+	for {
+		s.mu.Lock()
+		if res, ok := s.replayLocked(req.IdempotencyKey); ok {
+			s.mu.Unlock()
+			s.logger.Info("match replayed", "driverId", req.DriverId, "stationId", req.StationId, "key", req.IdempotencyKey)
+			return res, nil, nil
+		}
+		busy, ok := s.matching[req.DriverId]
+		if !ok {
+			release := make(chan struct{})
+			s.matching[req.DriverId] = release
+			s.mu.Unlock()
+			return nil, func() {
+				s.mu.Lock()
+				delete(s.matching, req.DriverId)
+				s.mu.Unlock()
+				close(release)
+			}, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-busy:
+		case <-ctx.Done():
+			return nil, nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// expireRiders marks riders whose arrival lies further back than the expiry as
+// expired and returns the ones still waiting.
+func (s *Server) expireRiders(ctx context.Context, stationID string, riders []*riderpb.Rider, now time.Time) []*riderpb.Rider {
+	active := make([]*riderpb.Rider, 0, len(riders))
+	for _, r := range riders {
+		if r.GetArrivalTime() == nil || now.Sub(r.GetArrivalTime().AsTime()) <= s.riderExpiry {
+			active = append(active, r)
+			continue
+		}
+		if _, err := s.clients.Riders.UpdateRiderStatus(ctx, &riderpb.UpdateRiderStatusRequest{Id: r.Id, Status: "expired", ExpectedStatus: "waiting"}); err != nil {
+			s.logger.Warn("match: expire rider failed", "riderId", r.Id, "err", err)
+			continue
+		}
+		s.logger.Info("rider expired", "riderId", r.Id, "stationId", stationID)
+		s.emit(pb.MatchEventType_MATCH_EVENT_TYPE_RIDER_EXPIRED, stationID, "", r.Id, nil, "no match before expiry")
+	}
+	return active
+}
+
+// replayLocked returns the stored response for key, pruning expired entries
+// along the way.
+func (s *Server) replayLocked(key string) (*pb.MatchResponse, bool) {
+	now := time.Now()
+	for k, c := range s.completed {
+		if now.Sub(c.at) > idempotencyTTL {
+			delete(s.completed, k)
+		}
+	}
+	if key == "" {
+		return nil, false
+	}
+	c, ok := s.completed[key]
+	if !ok {
+		return nil, false
+	}
+	res := proto.Clone(c.res).(*pb.MatchResponse)
+	res.Replayed = true
+	return res, true
+}
+
+// tripID derives trip ids from the idempotency key so a request replayed on
+// another replica collides with the original trip instead of duplicating it.
+func tripID(key, riderID string) string {
+	if key == "" {
+		return uuid.New().String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("lastmile:match:"+key+":"+riderID)).String()
 }
@@ -2,16 +2,96 @@ package matching
 
 import (
 	"context"
+	"net"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	driverpb "lastmile/gen/go/driver"
 	pb "lastmile/gen/go/matching"
+	riderpb "lastmile/gen/go/rider"
+	stationpb "lastmile/gen/go/station"
+	tripb "lastmile/gen/go/trip"
+	"lastmile/internal/driver"
+	"lastmile/internal/pkg/matchpolicy"
+	"lastmile/internal/rider"
+	"lastmile/internal/station"
+	"lastmile/internal/trip"
 )
 
+// newTestBackends starts driver, rider, station and trip services in-process and
+// returns clients wired to them.
+func newTestBackends(t *testing.T) Clients {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	driverpb.RegisterDriverServiceServer(s, driver.NewServer())
+	riderpb.RegisterRiderServiceServer(s, rider.NewServer())
+	stationpb.RegisterStationServiceServer(s, station.NewServer())
+	tripb.RegisterTripServiceServer(s, trip.NewServer())
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return Clients{
+		Drivers:  driverpb.NewDriverServiceClient(conn),
+		Riders:   riderpb.NewRiderServiceClient(conn),
+		Stations: stationpb.NewStationServiceClient(conn),
+		Trips:    tripb.NewTripServiceClient(conn),
+	}
+}
+
+func seedStation(t *testing.T, c Clients, driverID, destination string, seats int32) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := c.Stations.AddStation(ctx, &stationpb.AddStationRequest{Station: &stationpb.Station{Id: "station-1", Name: "Central"}})
+	require.NoError(t, err)
+	_, err = c.Drivers.RegisterDriver(ctx, &driverpb.RegisterDriverRequest{Driver: &driverpb.Driver{Id: driverID, Name: "Asha"}})
+	require.NoError(t, err)
+	_, err = c.Drivers.RegisterRoute(ctx, &driverpb.RegisterRouteRequest{Route: &driverpb.Route{
+		DriverId:         driverID,
+		TargetStationIds: []string{"station-1"},
+		AvailableSeats:   seats,
+		Destination:      destination,
+	}})
+	require.NoError(t, err)
+}
+
+func addRider(t *testing.T, c Clients, name, destination string, arrivesIn time.Duration) string {
+	t.Helper()
+	res, err := c.Riders.RegisterRider(context.Background(), &riderpb.RegisterRiderRequest{Rider: &riderpb.Rider{
+		Name:        name,
+		Destination: destination,
+		StationId:   "station-1",
+		ArrivalTime: timestamppb.New(time.Now().Add(arrivesIn)),
+	}})
+	require.NoError(t, err)
+	return res.Id
+}
+
 func TestMatch(t *testing.T) {
-	s := NewServer()
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-123", "Wipro Gate", 2)
+	early := addRider(t, clients, "Priya", "Wipro Gate", 2*time.Minute)
+	addRider(t, clients, "Rahul", "Silk Board", time.Minute)
+	later := addRider(t, clients, "Anita", "wipro gate", 6*time.Minute)
+	addRider(t, clients, "Kiran", "Wipro Gate", 9*time.Minute)
+
+	s := NewServerWithClients(clients, nil)
 	req := &pb.MatchRequest{
 		DriverId:  "driver-123",
 		StationId: "station-1",
@@ -19,6 +99,221 @@ func TestMatch(t *testing.T) {
 
 	res, err := s.Match(context.Background(), req)
 	require.NoError(t, err)
-	assert.NotEmpty(t, res.Trips)
+	require.Len(t, res.Trips, 2)
 	assert.Equal(t, "driver-123", res.Trips[0].DriverId)
+	assert.Equal(t, early, res.Trips[0].RiderId)
+	assert.Equal(t, later, res.Trips[1].RiderId)
+	assert.Equal(t, "station-1", res.Trips[0].StationId)
+
+	stored, err := clients.Trips.GetTrip(context.Background(), &tripb.GetTripRequest{Id: res.Trips[0].Id})
+	require.NoError(t, err)
+	assert.Equal(t, early, stored.Trip.RiderId)
+
+	// Seats are consumed, so a second pass matches nobody.
+	res, err = s.Match(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, res.Trips)
+}
+
+func TestMatchDoesNotReuseMatchedRiders(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 1)
+	_, err := clients.Drivers.RegisterRoute(context.Background(), &driverpb.RegisterRouteRequest{Route: &driverpb.Route{
+		DriverId:         "driver-2",
+		TargetStationIds: []string{"station-1"},
+		AvailableSeats:   3,
+		Destination:      "Wipro Gate",
+	}})
+	require.NoError(t, err)
+	addRider(t, clients, "Priya", "Wipro Gate", time.Minute)
+
+	s := NewServerWithClients(clients, nil)
+	first, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	require.NoError(t, err)
+	require.Len(t, first.Trips, 1)
+
+	second, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-2", StationId: "station-1"})
+	require.NoError(t, err)
+	assert.Empty(t, second.Trips)
+}
+
+// claimedElsewhereRiders marks the listed riders matched right after listing
+// them, as another replica or the gateway would between this replica's list
+// and its claim.
+type claimedElsewhereRiders struct {
+	riderpb.RiderServiceClient
+	claimed string
+}
+
+func (c *claimedElsewhereRiders) ListRiders(ctx context.Context, in *riderpb.ListRidersRequest, opts ...grpc.CallOption) (*riderpb.ListRidersResponse, error) {
+	res, err := c.RiderServiceClient.ListRiders(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.RiderServiceClient.UpdateRiderStatus(ctx, &riderpb.UpdateRiderStatusRequest{Id: c.claimed, Status: "matched"}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func TestMatchSkipsRidersClaimedElsewhere(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 2)
+	taken := addRider(t, clients, "Priya", "Wipro Gate", time.Minute)
+	free := addRider(t, clients, "Anita", "Wipro Gate", 2*time.Minute)
+	clients.Riders = &claimedElsewhereRiders{RiderServiceClient: clients.Riders, claimed: taken}
+
+	s := NewServerWithClients(clients, nil)
+	res, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	require.NoError(t, err)
+	require.Len(t, res.Trips, 1, "a rider claimed elsewhere gets no second trip")
+	assert.Equal(t, free, res.Trips[0].RiderId)
+}
+
+func TestConcurrentMatchesShareNoRidersOrSeats(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 1)
+	_, err := clients.Drivers.RegisterRoute(context.Background(), &driverpb.RegisterRouteRequest{Route: &driverpb.Route{
+		DriverId:         "driver-2",
+		TargetStationIds: []string{"station-1"},
+		AvailableSeats:   1,
+		Destination:      "Wipro Gate",
+	}})
+	require.NoError(t, err)
+	for _, name := range []string{"Priya", "Anita", "Kiran", "Rahul"} {
+		addRider(t, clients, name, "Wipro Gate", time.Minute)
+	}
+
+	s := NewServerWithClients(clients, nil)
+	results := make(chan *pb.MatchResponse, 4)
+	var wg sync.WaitGroup
+	for _, driverID := range []string{"driver-1", "driver-1", "driver-2", "driver-2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: driverID, StationId: "station-1"})
+			assert.NoError(t, err)
+			results <- res
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	// Each driver has one seat, so only two riders go, and never the same one twice.
+	riders := map[string]string{}
+	for res := range results {
+		for _, trip := range res.GetTrips() {
+			_, taken := riders[trip.RiderId]
+			assert.False(t, taken, "rider %s matched twice", trip.RiderId)
+			riders[trip.RiderId] = trip.DriverId
+		}
+	}
+	assert.Len(t, riders, 2)
+}
+
+func TestMatchRejectsUnroutedStation(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 2)
+
+	s := NewServerWithClients(clients, nil)
+	_, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-9"})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = NewServer().Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestMatchDefersRidersOutsideArrivalWindow(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 3)
+	soon := addRider(t, clients, "Priya", "Wipro Gate", 3*time.Minute)
+	late := addRider(t, clients, "Anita", "Wipro Gate", 40*time.Minute)
+
+	s := NewServerWithClients(clients, nil)
+	res, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1", ArrivalWindowMinutes: 5})
+	require.NoError(t, err)
+	require.Len(t, res.Trips, 1)
+	assert.Equal(t, soon, res.Trips[0].RiderId)
+	assert.Equal(t, []string{late}, res.DeferredRiderIds)
+	assert.Equal(t, int32(5), res.ArrivalWindowMinutes)
+
+	// The same rider is picked up once the driver's ETA lines up with theirs.
+	res, err = s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1", ArrivalWindowMinutes: 5, DriverEtaMinutes: 38})
+	require.NoError(t, err)
+	require.Len(t, res.Trips, 1)
+	assert.Equal(t, late, res.Trips[0].RiderId)
+}
+
+func TestMatchUsesStationPolicy(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 1)
+	addRider(t, clients, "Priya", "Wipro Gate", 0)
+	waiting := addRider(t, clients, "Anita", "Wipro Gate", -8*time.Minute)
+
+	s := NewServerWithClients(clients, nil)
+	require.NoError(t, s.SetMatchPolicy("nearest", map[string]string{"station-1": "longest-waiting"}))
+
+	// Nearest-in-time would pick Priya, who arrives with the driver; the
+	// station's policy favours Anita, who has been waiting longest.
+	res, err := s.Match(context.Background(), &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1"})
+	require.NoError(t, err)
+	require.Len(t, res.Trips, 1)
+	assert.Equal(t, waiting, res.Trips[0].RiderId)
+	assert.Equal(t, "longest-waiting", res.Policy)
+
+	assert.Error(t, s.SetMatchPolicy("cheapest", nil))
+}
+
+func TestSelectRidersDependsOnPolicy(t *testing.T) {
+	now := time.Now()
+	route := &driverpb.Route{DriverId: "driver-1", TargetStationIds: []string{"station-1"}, AvailableSeats: 1}
+	riders := []*riderpb.Rider{
+		{Id: "on-time", StationId: "station-1", ArrivalTime: timestamppb.New(now.Add(2 * time.Minute))},
+		{Id: "waiting", StationId: "station-1", ArrivalTime: timestamppb.New(now.Add(-7 * time.Minute))},
+	}
+
+	picks := map[string]string{}
+	for _, name := range []string{"nearest", "longest-waiting", "seat-fill", "fairness"} {
+		policy, err := matchpolicy.Lookup(name)
+		require.NoError(t, err)
+		selected, _ := selectRiders(policy, route, "station-1", riders, 1, now.Add(2*time.Minute), defaultArrivalWindow)
+		require.Len(t, selected, 1, name)
+		picks[name] = selected[0].Id
+	}
+	assert.Equal(t, map[string]string{
+		"nearest":         "on-time",
+		"longest-waiting": "waiting",
+		// Seat fill and fairness describe the driver, so riders fall back
+		// to their arrival gap.
+		"seat-fill": "on-time",
+		"fairness":  "on-time",
+	}, picks)
+}
+
+func TestMatchIsIdempotentPerKey(t *testing.T) {
+	clients := newTestBackends(t)
+	seedStation(t, clients, "driver-1", "Wipro Gate", 2)
+	first := addRider(t, clients, "Priya", "Wipro Gate", time.Minute)
+
+	s := NewServerWithClients(clients, nil)
+	req := &pb.MatchRequest{DriverId: "driver-1", StationId: "station-1", IdempotencyKey: "driver-1:station-1:1"}
+	res, err := s.Match(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, res.Trips, 1)
+	assert.False(t, res.Replayed)
+
+	// A rider arriving after the first call must not be picked up by a retry.
+	addRider(t, clients, "Anita", "Wipro Gate", 2*time.Minute)
+	retry, err := s.Match(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, retry.Replayed)
+	require.Len(t, retry.Trips, 1)
+	assert.Equal(t, res.Trips[0].Id, retry.Trips[0].Id)
+	assert.Equal(t, first, retry.Trips[0].RiderId)
+
+	// A replica without the cached response derives the same trip id, so the
+	// trip service rejects the duplicate.
+	assert.Equal(t, res.Trips[0].Id, tripID(req.IdempotencyKey, first))
+	assert.NotEqual(t, tripID("", first), tripID("", first))
 }
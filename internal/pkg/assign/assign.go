@@ -0,0 +1,139 @@
+// Package assign solves capacitated rider-to-driver assignment problems.
+//
+// The problem is modelled as a min-cost flow network:
+//
+//	source -> driver (capacity = seats) -> rider (capacity 1, cost = edge cost) -> sink
+//
+// Solve first maximises the number of riders that get a seat and, among all
+// assignments of that size, picks the one with the lowest total cost.
+package assign
+
+import "math"
+
+// Driver is a supply node with a number of free seats.
+type Driver struct {
+	ID    string
+	Seats int
+}
+
+// Rider is a demand node that needs exactly one seat.
+type Rider struct {
+	ID string
+}
+
+// CostFunc returns the cost of seating rider r with driver d (indexes into the
+// slices passed to Solve). ok=false means the pair is incompatible.
+type CostFunc func(d, r int) (cost float64, ok bool)
+
+// Pair is one rider seated with one driver.
+type Pair struct {
+	DriverID string
+	RiderID  string
+	Cost     float64
+}
+
+type edge struct {
+	to, rev int
+	cap     int
+	cost    float64
+}
+
+type graph [][]edge
+
+func (g graph) add(from, to, capacity int, cost float64) {
+	g[from] = append(g[from], edge{to: to, rev: len(g[to]), cap: capacity, cost: cost})
+	g[to] = append(g[to], edge{to: from, rev: len(g[from]) - 1, cap: 0, cost: -cost})
+}
+
+// Solve returns the min-cost maximum assignment of riders to driver seats.
+// Pairs are ordered by driver, then rider, following the input order.
+func Solve(drivers []Driver, riders []Rider, cost CostFunc) []Pair {
+	if len(drivers) == 0 || len(riders) == 0 {
+		return nil
+	}
+
+	// Node layout: 0 = source, 1..D = drivers, D+1..D+R = riders, D+R+1 = sink.
+	source := 0
+	driverNode := func(i int) int { return 1 + i }
+	riderNode := func(j int) int { return 1 + len(drivers) + j }
+	sink := 1 + len(drivers) + len(riders)
+	g := make(graph, sink+1)
+
+	for i, d := range drivers {
+		if d.Seats <= 0 {
+			continue
+		}
+		g.add(source, driverNode(i), d.Seats, 0)
+		for j := range riders {
+			if c, ok := cost(i, j); ok {
+				g.add(driverNode(i), riderNode(j), 1, c)
+			}
+		}
+	}
+	for j := range riders {
+		g.add(riderNode(j), sink, 1, 0)
+	}
+
+	for augment(g, source, sink) {
+	}
+
+	pairs := make([]Pair, 0, len(riders))
+	for i := range drivers {
+		for _, e := range g[driverNode(i)] {
+			j := e.to - riderNode(0)
+			if j < 0 || j >= len(riders) || e.cap != 0 {
+				continue
+			}
+			// A saturated forward edge means the rider was seated with this driver.
+			if g[e.to][e.rev].cap == 1 {
+				pairs = append(pairs, Pair{DriverID: drivers[i].ID, RiderID: riders[j].ID, Cost: e.cost})
+			}
+		}
+	}
+	return pairs
+}
+
+// augment pushes one unit of flow along the cheapest source->sink path
+// (Bellman-Ford, since residual edges carry negative costs). It reports
+// whether a path was found.
+func augment(g graph, source, sink int) bool {
+	dist := make([]float64, len(g))
+	prevNode := make([]int, len(g))
+	prevEdge := make([]int, len(g))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prevNode[i] = -1
+	}
+	dist[source] = 0
+
+	for iter := 0; iter < len(g); iter++ {
+		changed := false
+		for u := range g {
+			if math.IsInf(dist[u], 1) {
+				continue
+			}
+			for k, e := range g[u] {
+				if e.cap > 0 && dist[u]+e.cost < dist[e.to]-1e-9 {
+					dist[e.to] = dist[u] + e.cost
+					prevNode[e.to] = u
+					prevEdge[e.to] = k
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	if math.IsInf(dist[sink], 1) {
+		return false
+	}
+	for v := sink; v != source; v = prevNode[v] {
+		u := prevNode[v]
+		e := &g[u][prevEdge[v]]
+		e.cap--
+		g[v][e.rev].cap++
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package assign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolvePrefersGlobalOptimumOverGreedy(t *testing.T) {
+	drivers := []Driver{{ID: "d1", Seats: 1}, {ID: "d2", Seats: 1}}
+	riders := []Rider{{ID: "r1"}, {ID: "r2"}}
+	// Greedy (d1 takes its cheapest rider r1) forces d2 onto r2 at cost 10.
+	// The optimum is d1->r2, d2->r1 for a total of 3.
+	costs := map[[2]int]float64{{0, 0}: 1, {0, 1}: 2, {1, 0}: 1, {1, 1}: 10}
+
+	pairs := Solve(drivers, riders, func(d, r int) (float64, bool) {
+		c, ok := costs[[2]int{d, r}]
+		return c, ok
+	})
+
+	assert.ElementsMatch(t, []Pair{
+		{DriverID: "d1", RiderID: "r2", Cost: 2},
+		{DriverID: "d2", RiderID: "r1", Cost: 1},
+	}, pairs)
+}
+
+func TestSolveRespectsSeatsAndCompatibility(t *testing.T) {
+	drivers := []Driver{{ID: "van", Seats: 2}, {ID: "full", Seats: 0}}
+	riders := []Rider{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	pairs := Solve(drivers, riders, func(d, r int) (float64, bool) {
+		// Rider c is headed somewhere no driver goes.
+		return float64(r), riders[r].ID != "c"
+	})
+
+	assert.Len(t, pairs, 2)
+	for _, p := range pairs {
+		assert.Equal(t, "van", p.DriverID)
+		assert.NotEqual(t, "c", p.RiderID)
+	}
+}
+
+func TestSolveMaximisesSeatedRiders(t *testing.T) {
+	drivers := []Driver{{ID: "d1", Seats: 1}, {ID: "d2", Seats: 1}}
+	riders := []Rider{{ID: "r1"}, {ID: "r2"}}
+	// r2 can only ride with d1; seating both beats the cheaper single pair d1->r1.
+	pairs := Solve(drivers, riders, func(d, r int) (float64, bool) {
+		switch {
+		case d == 0 && r == 0:
+			return 0, true
+		case d == 0 && r == 1:
+			return 5, true
+		case d == 1 && r == 0:
+			return 5, true
+		}
+		return 0, false
+	})
+
+	assert.Len(t, pairs, 2)
+}
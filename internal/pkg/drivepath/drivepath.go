@@ -0,0 +1,171 @@
+// Package drivepath turns waypoints into the positions a moving vehicle
+// reports along the way.
+//
+// A Router gives the path between two points, either a straight line or a
+// road-snapped polyline from an OSRM server. Walk then steps along a path at
+// fixed distance intervals, so a simulated driver travelling at a set speed
+// and reporting at a set rate produces the same fixes a real one would.
+package drivepath
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+const earthRadiusMeters = 6371000.0
+
+// Point is a position on the path.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Sample is one position along a walked path. Heading is degrees clockwise
+// from true north.
+type Sample struct {
+	Point
+	Heading float64
+}
+
+// Router finds the path from one point to another. The path starts at from
+// and ends at to.
+type Router interface {
+	Route(ctx context.Context, from, to Point) ([]Point, error)
+}
+
+// StraightLine routes directly from one point to the other.
+type StraightLine struct{}
+
+func (StraightLine) Route(_ context.Context, from, to Point) ([]Point, error) {
+	return []Point{from, to}, nil
+}
+
+// OSRM snaps routes to roads with an OSRM server's route service.
+type OSRM struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOSRM routes through the OSRM server at baseURL, e.g.
+// http://router.project-osrm.org. A nil client uses http.DefaultClient.
+func NewOSRM(baseURL string, client *http.Client) *OSRM {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OSRM{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+func (o *OSRM) Route(ctx context.Context, from, to Point) ([]Point, error) {
+	url := fmt.Sprintf("%s/route/v1/driving/%f,%f;%f,%f?overview=full&geometries=geojson",
+		o.baseURL, from.Longitude, from.Latitude, to.Longitude, to.Latitude)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("osrm: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Code   string `json:"code"`
+		Routes []struct {
+			Geometry struct {
+				// Coordinates are [longitude, latitude] pairs.
+				Coordinates [][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"routes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("osrm: %w", err)
+	}
+	if body.Code != "Ok" || len(body.Routes) == 0 || len(body.Routes[0].Geometry.Coordinates) == 0 {
+		return nil, fmt.Errorf("osrm: no route (%s)", body.Code)
+	}
+	coords := body.Routes[0].Geometry.Coordinates
+	path := make([]Point, 0, len(coords)+2)
+	// The road network starts and ends at the nearest roads; keep the exact
+	// endpoints so the driver still reaches the stop.
+	path = append(path, from)
+	for _, c := range coords {
+		path = append(path, Point{Latitude: c[1], Longitude: c[0]})
+	}
+	return append(path, to), nil
+}
+
+// Walk returns the positions reached every stepMeters along the path, ending
+// exactly at its last point. The starting point is not included.
+func Walk(path []Point, stepMeters float64) []Sample {
+	if len(path) < 2 || stepMeters <= 0 {
+		if len(path) == 0 {
+			return nil
+		}
+		return []Sample{{Point: path[len(path)-1]}}
+	}
+	var out []Sample
+	heading := 0.0
+	// carried is how far past the last sample the walk already is on entering
+	// a segment.
+	carried := 0.0
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		length := Distance(a, b)
+		if length == 0 {
+			continue
+		}
+		heading = Bearing(a, b)
+		for at := stepMeters - carried; at < length; at += stepMeters {
+			out = append(out, Sample{Point: interpolate(a, b, at/length), Heading: heading})
+		}
+		carried = math.Mod(carried+length, stepMeters)
+	}
+	end := path[len(path)-1]
+	if n := len(out); n > 0 && out[n-1].Point == end {
+		return out
+	}
+	return append(out, Sample{Point: end, Heading: heading})
+}
+
+// Length is the path's total length in metres.
+func Length(path []Point) float64 {
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		total += Distance(path[i-1], path[i])
+	}
+	return total
+}
+
+// Distance is the great-circle distance between two points in metres.
+func Distance(a, b Point) float64 {
+	dLat := (b.Latitude - a.Latitude) * math.Pi / 180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a.Latitude*math.Pi/180)*math.Cos(b.Latitude*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusMeters * 2 * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+}
+
+// Bearing is the initial heading from a to b in degrees clockwise from north.
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// interpolate moves the fraction f of the way from a to b. Over the short
+// segments of a route a linear blend is indistinguishable from the great
+// circle.
+func interpolate(a, b Point, f float64) Point {
+	return Point{
+		Latitude:  a.Latitude + (b.Latitude-a.Latitude)*f,
+		Longitude: a.Longitude + (b.Longitude-a.Longitude)*f,
+	}
+}
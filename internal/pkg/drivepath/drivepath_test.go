@@ -0,0 +1,69 @@
+package drivepath
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	south = Point{Latitude: 12.84, Longitude: 77.66}
+	north = Point{Latitude: 12.85, Longitude: 77.66}
+	east  = Point{Latitude: 12.85, Longitude: 77.67}
+)
+
+func TestWalkStepsEvenlyAcrossSegments(t *testing.T) {
+	path := []Point{south, north, east}
+	samples := Walk(path, 100)
+
+	total := Length(path)
+	require.Len(t, samples, int(total/100)+1)
+	// The first leg is about 1112 m, so the twelfth sample turns the corner
+	// 12 m along the second leg.
+	prev := south
+	for i, s := range samples[:11] {
+		assert.InDelta(t, 100, Distance(prev, s.Point), 1, "sample %d", i)
+		prev = s.Point
+	}
+	assert.InDelta(t, 100, Distance(prev, north)+Distance(north, samples[11].Point), 1, "steps are measured along the path")
+	assert.Equal(t, east, samples[len(samples)-1].Point, "the walk ends on the last point")
+
+	assert.InDelta(t, 0, samples[0].Heading, 0.5, "heading north")
+	assert.InDelta(t, 90, samples[len(samples)-2].Heading, 0.5, "heading east")
+}
+
+func TestWalkShortPathEndsAtDestination(t *testing.T) {
+	samples := Walk([]Point{south, {Latitude: 12.8401, Longitude: 77.66}}, 100)
+	require.Len(t, samples, 1)
+	assert.Equal(t, 12.8401, samples[0].Latitude)
+	assert.Empty(t, Walk(nil, 100))
+}
+
+func TestOSRMKeepsExactEndpoints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/route/v1/driving/77.660000,12.840000;77.670000,12.850000", r.URL.Path)
+		_, _ = w.Write([]byte(`{"code":"Ok","routes":[{"geometry":{"coordinates":[[77.6601,12.8401],[77.6601,12.8499],[77.6699,12.8499]]}}]}`))
+	}))
+	defer srv.Close()
+
+	path, err := NewOSRM(srv.URL+"/", nil).Route(context.Background(), south, east)
+	require.NoError(t, err)
+	require.Len(t, path, 5)
+	assert.Equal(t, south, path[0])
+	assert.Equal(t, Point{Latitude: 12.8499, Longitude: 77.6601}, path[2])
+	assert.Equal(t, east, path[4])
+}
+
+func TestOSRMReportsMissingRoute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":"NoRoute","routes":[]}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewOSRM(srv.URL, nil).Route(context.Background(), south, east)
+	assert.Error(t, err)
+}
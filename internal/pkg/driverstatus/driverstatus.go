@@ -0,0 +1,33 @@
+// Package driverstatus names driver statuses the way they are stored and
+// shown: the DriverStatus enum value in lower case without its prefix, e.g.
+// "en_route_to_pickup".
+//
+// The driver service stores statuses by these names and the gateway mirrors
+// and accepts them over HTTP, so both use this one copy.
+package driverstatus
+
+import (
+	"strings"
+
+	pb "lastmile/gen/go/driver"
+)
+
+const prefix = "DRIVER_STATUS_"
+
+// Name returns the short name of s, or "" when s is unspecified.
+func Name(s pb.DriverStatus) string {
+	if s == pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(s.String(), prefix))
+}
+
+// Parse is the inverse of Name. It ignores case and surrounding space and
+// reports false for unknown names and for "unspecified".
+func Parse(name string) (pb.DriverStatus, bool) {
+	v, ok := pb.DriverStatus_value[prefix+strings.ToUpper(strings.TrimSpace(name))]
+	if !ok || v == int32(pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED) {
+		return pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED, false
+	}
+	return pb.DriverStatus(v), true
+}
@@ -0,0 +1,28 @@
+package driverstatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "lastmile/gen/go/driver"
+)
+
+func TestNamesRoundTrip(t *testing.T) {
+	enRoute := pb.DriverStatus_DRIVER_STATUS_EN_ROUTE_TO_PICKUP
+	assert.Equal(t, "en_route_to_pickup", Name(enRoute))
+	assert.Equal(t, "", Name(pb.DriverStatus_DRIVER_STATUS_UNSPECIFIED))
+
+	s, ok := Parse("en_route_to_pickup")
+	require.True(t, ok)
+	assert.Equal(t, enRoute, s)
+	s, ok = Parse(" Available ")
+	require.True(t, ok)
+	assert.Equal(t, pb.DriverStatus_DRIVER_STATUS_AVAILABLE, s)
+
+	_, ok = Parse("unspecified")
+	assert.False(t, ok)
+	_, ok = Parse("busy")
+	assert.False(t, ok)
+}
@@ -0,0 +1,178 @@
+// Package eta estimates when a driver reaches each of their remaining stops.
+//
+// An Estimator learns each driver's recent moving speed from location
+// samples, smoothing it so a single fast or slow reading does not swing the
+// estimate, and walks the driver's ordered stops from their current position
+// using straight-line distance stretched by a road factor plus a fixed dwell
+// at every intermediate stop.
+package eta
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const earthRadiusMeters = 6371000.0
+
+// Config tunes an Estimator. Zero values take the defaults.
+type Config struct {
+	// DefaultSpeedMPS is used for drivers with no moving samples yet; about
+	// 25 km/h of city traffic.
+	DefaultSpeedMPS float64
+	// MinSpeedMPS and MaxSpeedMPS clamp the learned speed so a driver idling
+	// at a light or a GPS jump does not produce absurd estimates.
+	MinSpeedMPS float64
+	MaxSpeedMPS float64
+	// RoadFactor stretches straight-line distance to approximate the road
+	// network.
+	RoadFactor float64
+	// StopDwell is the time spent at each stop before moving on.
+	StopDwell time.Duration
+	// Smoothing is the weight of a new sample in the moving average, in (0, 1].
+	Smoothing float64
+}
+
+func (c Config) withDefaults() Config {
+	if c.DefaultSpeedMPS <= 0 {
+		c.DefaultSpeedMPS = 7
+	}
+	if c.MinSpeedMPS <= 0 {
+		c.MinSpeedMPS = 2
+	}
+	if c.MaxSpeedMPS <= 0 {
+		c.MaxSpeedMPS = 25
+	}
+	if c.RoadFactor <= 0 {
+		c.RoadFactor = 1.3
+	}
+	if c.StopDwell <= 0 {
+		c.StopDwell = time.Minute
+	}
+	if c.Smoothing <= 0 || c.Smoothing > 1 {
+		c.Smoothing = 0.3
+	}
+	return c
+}
+
+// movingSpeedMPS is the slowest sample that counts as moving. Slower samples
+// are stops and are left to the dwell allowance instead of dragging the
+// average down.
+const movingSpeedMPS = 1.0
+
+// maxDerivationGap is the longest gap between two samples that still gives a
+// meaningful derived speed.
+const maxDerivationGap = 2 * time.Minute
+
+// Point is a stop or position.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type motion struct {
+	last   Point
+	lastAt time.Time
+	speed  float64
+	moving bool
+}
+
+// Estimator keeps per-driver speed and turns routes into arrival estimates.
+// It is safe for concurrent use.
+type Estimator struct {
+	cfg Config
+
+	mu      sync.Mutex
+	drivers map[string]*motion
+}
+
+// New creates an estimator.
+func New(cfg Config) *Estimator {
+	return &Estimator{cfg: cfg.withDefaults(), drivers: make(map[string]*motion)}
+}
+
+// Observe records a driver position. A positive speedMPS is the device's own
+// reading; otherwise the speed is derived from the previous sample.
+func (e *Estimator) Observe(driverID string, lat, lon, speedMPS float64, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	m, ok := e.drivers[driverID]
+	if !ok {
+		m = &motion{}
+		e.drivers[driverID] = m
+	}
+	if speedMPS <= 0 && !m.lastAt.IsZero() {
+		if gap := at.Sub(m.lastAt); gap > 0 && gap <= maxDerivationGap {
+			speedMPS = haversineMeters(m.last.Latitude, m.last.Longitude, lat, lon) / gap.Seconds()
+		}
+	}
+	m.last = Point{Latitude: lat, Longitude: lon}
+	m.lastAt = at
+
+	if speedMPS < movingSpeedMPS || math.IsNaN(speedMPS) {
+		return
+	}
+	speedMPS = math.Min(speedMPS, e.cfg.MaxSpeedMPS)
+	if !m.moving {
+		m.speed = speedMPS
+		m.moving = true
+		return
+	}
+	m.speed += e.cfg.Smoothing * (speedMPS - m.speed)
+}
+
+// Speed returns the driver's smoothed moving speed, or the default when the
+// driver has not been seen moving.
+func (e *Estimator) Speed(driverID string) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	m, ok := e.drivers[driverID]
+	if !ok || !m.moving {
+		return e.cfg.DefaultSpeedMPS
+	}
+	return math.Max(e.cfg.MinSpeedMPS, math.Min(m.speed, e.cfg.MaxSpeedMPS))
+}
+
+// Route estimates the travel time from the driver's position to each stop in
+// order. Each entry is cumulative from now and includes the dwell at every
+// earlier stop.
+func (e *Estimator) Route(driverID string, from Point, stops []Point) []time.Duration {
+	speed := e.Speed(driverID)
+	out := make([]time.Duration, len(stops))
+	var total time.Duration
+	prev := from
+	for i, stop := range stops {
+		if i > 0 {
+			total += e.cfg.StopDwell
+		}
+		meters := haversineMeters(prev.Latitude, prev.Longitude, stop.Latitude, stop.Longitude) * e.cfg.RoadFactor
+		total += time.Duration(meters / speed * float64(time.Second))
+		out[i] = total
+		prev = stop
+	}
+	return out
+}
+
+// Forget drops what the estimator learned about a driver.
+func (e *Estimator) Forget(driverID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.drivers, driverID)
+}
+
+// Minutes rounds an estimate up to whole minutes, so a driver is never shown
+// as arriving sooner than expected. Any non-zero estimate is at least 1.
+func Minutes(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Minutes()))
+}
+
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,55 @@
+package eta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteAccumulatesStopsAndDwell(t *testing.T) {
+	e := New(Config{DefaultSpeedMPS: 10, RoadFactor: 1, StopDwell: time.Minute})
+	from := Point{Latitude: 12.84, Longitude: 77.66}
+	stops := []Point{
+		{Latitude: 12.84 + 0.009, Longitude: 77.66}, // ~1 km
+		{Latitude: 12.84 + 0.018, Longitude: 77.66}, // another ~1 km
+	}
+
+	got := e.Route("driver-1", from, stops)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 100, got[0].Seconds(), 1)
+	assert.InDelta(t, 100+60+100, got[1].Seconds(), 2)
+	assert.Equal(t, 2, Minutes(got[0]), "estimates round up")
+	assert.Equal(t, 0, Minutes(0))
+}
+
+func TestObserveLearnsSpeed(t *testing.T) {
+	e := New(Config{DefaultSpeedMPS: 7, Smoothing: 0.5})
+	assert.Equal(t, 7.0, e.Speed("driver-1"))
+
+	at := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	e.Observe("driver-1", 12.84, 77.66, 12, at)
+	assert.Equal(t, 12.0, e.Speed("driver-1"))
+	e.Observe("driver-1", 12.84, 77.66, 4, at.Add(time.Second))
+	assert.Equal(t, 8.0, e.Speed("driver-1"))
+
+	// Waiting at a light is a stop, not slow driving.
+	e.Observe("driver-1", 12.84, 77.66, 0, at.Add(time.Minute))
+	assert.Equal(t, 8.0, e.Speed("driver-1"))
+
+	e.Forget("driver-1")
+	assert.Equal(t, 7.0, e.Speed("driver-1"))
+}
+
+func TestObserveDerivesAndClampsSpeed(t *testing.T) {
+	e := New(Config{MaxSpeedMPS: 25})
+	at := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	e.Observe("driver-1", 12.84, 77.66, 0, at)
+	e.Observe("driver-1", 12.849, 77.66, 0, at.Add(100*time.Second)) // ~1 km in 100 s
+	assert.InDelta(t, 10, e.Speed("driver-1"), 0.1)
+
+	e.Observe("driver-2", 12.84, 77.66, 0, at)
+	e.Observe("driver-2", 12.94, 77.66, 0, at.Add(time.Second)) // a GPS jump
+	assert.Equal(t, 25.0, e.Speed("driver-2"))
+}
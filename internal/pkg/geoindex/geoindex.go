@@ -0,0 +1,154 @@
+// Package geoindex finds points near a location with a uniform grid.
+//
+// Points are bucketed into cells of a fixed size in degrees, so a radius
+// query only measures the points in the cells the search circle overlaps
+// instead of every point in the index.
+package geoindex
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultCellDegrees is the cell size used when New is passed 0; about 1.1 km
+// of latitude.
+const DefaultCellDegrees = 0.01
+
+const (
+	earthRadiusMeters = 6371000.0
+	metersPerDegree   = 111320.0
+)
+
+type cell struct {
+	lat, lon int
+}
+
+type point struct {
+	lat, lon float64
+	cell     cell
+}
+
+// Hit is a point found by Nearby.
+type Hit struct {
+	ID             string
+	Latitude       float64
+	Longitude      float64
+	DistanceMeters float64
+}
+
+// Grid is a spatial index of named points. It is safe for concurrent use.
+type Grid struct {
+	mu     sync.RWMutex
+	size   float64
+	points map[string]point
+	cells  map[cell]map[string]struct{}
+}
+
+// New creates an empty grid with cells of cellDegrees on each side.
+func New(cellDegrees float64) *Grid {
+	if cellDegrees <= 0 {
+		cellDegrees = DefaultCellDegrees
+	}
+	return &Grid{size: cellDegrees, points: make(map[string]point), cells: make(map[cell]map[string]struct{})}
+}
+
+func (g *Grid) cellFor(lat, lon float64) cell {
+	return cell{lat: int(math.Floor(lat / g.size)), lon: int(math.Floor(lon / g.size))}
+}
+
+// Upsert places id at the given position, moving it if it is already indexed.
+func (g *Grid) Upsert(id string, lat, lon float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c := g.cellFor(lat, lon)
+	if old, ok := g.points[id]; ok && old.cell != c {
+		g.removeFromCell(id, old.cell)
+	}
+	g.points[id] = point{lat: lat, lon: lon, cell: c}
+	bucket := g.cells[c]
+	if bucket == nil {
+		bucket = make(map[string]struct{})
+		g.cells[c] = bucket
+	}
+	bucket[id] = struct{}{}
+}
+
+// Remove drops id from the index.
+func (g *Grid) Remove(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if old, ok := g.points[id]; ok {
+		g.removeFromCell(id, old.cell)
+		delete(g.points, id)
+	}
+}
+
+func (g *Grid) removeFromCell(id string, c cell) {
+	bucket := g.cells[c]
+	delete(bucket, id)
+	if len(bucket) == 0 {
+		delete(g.cells, c)
+	}
+}
+
+// Len returns the number of indexed points.
+func (g *Grid) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.points)
+}
+
+// Nearby returns the points within radiusMeters of the location, nearest
+// first. A positive limit caps the number of hits.
+func (g *Grid) Nearby(lat, lon, radiusMeters float64, limit int) []Hit {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var hits []Hit
+	visit := func(id string, p point) {
+		if d := haversineMeters(lat, lon, p.lat, p.lon); d <= radiusMeters {
+			hits = append(hits, Hit{ID: id, Latitude: p.lat, Longitude: p.lon, DistanceMeters: d})
+		}
+	}
+
+	latSpan := int(math.Ceil(radiusMeters / metersPerDegree / g.size))
+	lonScale := math.Cos(lat * math.Pi / 180)
+	lonSpan := latSpan
+	if lonScale > 0.01 {
+		lonSpan = int(math.Ceil(radiusMeters / (metersPerDegree * lonScale) / g.size))
+	}
+	// When the search covers more cells than exist, scanning the points is cheaper.
+	if (2*latSpan+1)*(2*lonSpan+1) >= len(g.cells) {
+		for id, p := range g.points {
+			visit(id, p)
+		}
+	} else {
+		center := g.cellFor(lat, lon)
+		for dLat := -latSpan; dLat <= latSpan; dLat++ {
+			for dLon := -lonSpan; dLon <= lonSpan; dLon++ {
+				for id := range g.cells[cell{lat: center.lat + dLat, lon: center.lon + dLon}] {
+					visit(id, g.points[id])
+				}
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].DistanceMeters != hits[j].DistanceMeters {
+			return hits[i].DistanceMeters < hits[j].DistanceMeters
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
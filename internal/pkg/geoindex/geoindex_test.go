@@ -0,0 +1,69 @@
+package geoindex
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNearbySortsByDistanceAndLimits(t *testing.T) {
+	g := New(0)
+	g.Upsert("far", 12.90, 77.66)
+	g.Upsert("near", 12.846, 77.66)
+	g.Upsert("mid", 12.85, 77.66)
+	g.Upsert("other-city", 37.77, -122.41)
+
+	hits := g.Nearby(12.8456, 77.66, 10000, 0)
+	require.Len(t, hits, 3)
+	assert.Equal(t, []string{"near", "mid", "far"}, []string{hits[0].ID, hits[1].ID, hits[2].ID})
+	assert.Less(t, hits[0].DistanceMeters, hits[1].DistanceMeters)
+
+	hits = g.Nearby(12.8456, 77.66, 10000, 2)
+	assert.Len(t, hits, 2)
+	assert.Empty(t, g.Nearby(12.8456, 77.66, 10, 0))
+}
+
+func TestUpsertMovesAndRemoveDrops(t *testing.T) {
+	g := New(0)
+	g.Upsert("driver-1", 12.8456, 77.66)
+	g.Upsert("driver-1", 12.9121, 77.6387)
+	assert.Equal(t, 1, g.Len())
+	assert.Empty(t, g.Nearby(12.8456, 77.66, 1000, 0))
+	assert.Len(t, g.Nearby(12.9121, 77.6387, 1000, 0), 1)
+
+	g.Remove("driver-1")
+	assert.Equal(t, 0, g.Len())
+	assert.Empty(t, g.Nearby(12.9121, 77.6387, 1000, 0))
+}
+
+func TestNearbyMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := New(0)
+	points := map[string][2]float64{}
+	for i := 0; i < 2000; i++ {
+		id := fmt.Sprintf("driver-%d", i)
+		lat, lon := 12.7+rng.Float64()*0.4, 77.5+rng.Float64()*0.4
+		points[id] = [2]float64{lat, lon}
+		g.Upsert(id, lat, lon)
+	}
+
+	for _, radius := range []float64{300, 2500, 8000} {
+		var want []string
+		for id, p := range points {
+			if haversineMeters(12.85, 77.66, p[0], p[1]) <= radius {
+				want = append(want, id)
+			}
+		}
+		var got []string
+		for _, h := range g.Nearby(12.85, 77.66, radius, 0) {
+			got = append(got, h.ID)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		assert.Equal(t, want, got, "radius %v", radius)
+	}
+}
@@ -0,0 +1,185 @@
+// Package gpsfilter smooths GPS fixes and flags physically impossible jumps.
+//
+// Each track runs a one-state Kalman filter over position: the estimate's
+// uncertainty grows with the time since the last fix and shrinks with every
+// fix in proportion to the fix's reported accuracy, so noisy fixes move the
+// estimate less than precise ones. A fix further from the estimate than the
+// track could have travelled at MaxSpeedMPS is flagged instead of applied.
+// A run of flagged fixes that agree with each other re-anchors the track, so
+// a driver who genuinely reappears elsewhere (after a tunnel, or a phone
+// restart) is picked up again.
+package gpsfilter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const earthRadiusMeters = 6371000.0
+
+// Config tunes a Filter. Zero values take the defaults.
+type Config struct {
+	// MaxSpeedMPS is the fastest plausible movement between fixes; about
+	// 250 km/h by default.
+	MaxSpeedMPS float64
+	// ProcessNoiseMPS is how fast the true position is assumed to drift per
+	// second when a fix does not report a faster speed.
+	ProcessNoiseMPS float64
+	// ReanchorAfter is how many consecutive, mutually consistent flagged
+	// fixes move the track to the new position.
+	ReanchorAfter int
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxSpeedMPS <= 0 {
+		c.MaxSpeedMPS = 70
+	}
+	if c.ProcessNoiseMPS <= 0 {
+		c.ProcessNoiseMPS = 3
+	}
+	if c.ReanchorAfter <= 0 {
+		c.ReanchorAfter = 3
+	}
+	return c
+}
+
+// Fix is one raw position reading.
+type Fix struct {
+	Latitude  float64
+	Longitude float64
+	// AccuracyMeters is the reported horizontal accuracy; 0 means unknown and
+	// the fix is taken as-is, since there is nothing to weigh it by.
+	AccuracyMeters float64
+	SpeedMPS       float64
+	At             time.Time
+}
+
+// Result is the outcome of applying a fix.
+type Result struct {
+	// Latitude and Longitude are the smoothed position; unchanged when the
+	// fix was flagged.
+	Latitude  float64
+	Longitude float64
+	// Flagged is set when the fix implies an impossible jump and was not applied.
+	Flagged bool
+	// JumpMeters and ImpliedSpeedMPS describe a flagged jump.
+	JumpMeters      float64
+	ImpliedSpeedMPS float64
+	// Reanchored is set when the fix moved the track after a run of flagged fixes.
+	Reanchored bool
+}
+
+type track struct {
+	lat, lon float64
+	variance float64
+	accuracy float64
+	at       time.Time
+
+	// suspect is the latest flagged fix and suspects how many consistent
+	// flagged fixes led up to it.
+	suspect  *Fix
+	suspects int
+}
+
+// Filter keeps one track per id. It is safe for concurrent use.
+type Filter struct {
+	cfg Config
+
+	mu     sync.Mutex
+	tracks map[string]*track
+}
+
+// New creates a filter.
+func New(cfg Config) *Filter {
+	return &Filter{cfg: cfg.withDefaults(), tracks: make(map[string]*track)}
+}
+
+// Apply checks the fix against the id's track and folds it in unless it is
+// an impossible jump.
+func (f *Filter) Apply(id string, fix Fix) Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t, ok := f.tracks[id]
+	if !ok {
+		t = anchor(fix)
+		f.tracks[id] = t
+		return Result{Latitude: t.lat, Longitude: t.lon}
+	}
+
+	if jump, speed, impossible := f.impossible(t.lat, t.lon, t.accuracy, t.at, fix); impossible {
+		if t.suspect != nil {
+			if _, _, stillJumping := f.impossible(t.suspect.Latitude, t.suspect.Longitude, t.suspect.AccuracyMeters, t.suspect.At, fix); !stillJumping {
+				t.suspects++
+			} else {
+				t.suspects = 1
+			}
+		} else {
+			t.suspects = 1
+		}
+		suspect := fix
+		t.suspect = &suspect
+		if t.suspects >= f.cfg.ReanchorAfter {
+			t = anchor(fix)
+			f.tracks[id] = t
+			return Result{Latitude: t.lat, Longitude: t.lon, Reanchored: true}
+		}
+		return Result{Latitude: t.lat, Longitude: t.lon, Flagged: true, JumpMeters: jump, ImpliedSpeedMPS: speed}
+	}
+
+	t.suspect, t.suspects = nil, 0
+	dt := fix.At.Sub(t.at).Seconds()
+	if dt < 0 {
+		dt = 0
+	}
+	t.at = fix.At
+	if fix.AccuracyMeters <= 0 {
+		t.lat, t.lon, t.variance, t.accuracy = fix.Latitude, fix.Longitude, 0, 0
+		return Result{Latitude: t.lat, Longitude: t.lon}
+	}
+	drift := math.Max(fix.SpeedMPS, f.cfg.ProcessNoiseMPS)
+	t.variance += dt * drift * drift
+	measured := fix.AccuracyMeters * fix.AccuracyMeters
+	gain := t.variance / (t.variance + measured)
+	t.lat += gain * (fix.Latitude - t.lat)
+	t.lon += gain * (fix.Longitude - t.lon)
+	t.variance *= 1 - gain
+	t.accuracy = math.Sqrt(t.variance)
+	return Result{Latitude: t.lat, Longitude: t.lon}
+}
+
+// impossible reports whether reaching fix from the given position would need
+// more than MaxSpeedMPS, allowing for both positions' accuracy.
+func (f *Filter) impossible(lat, lon, accuracy float64, at time.Time, fix Fix) (jump, speed float64, bad bool) {
+	jump = haversineMeters(lat, lon, fix.Latitude, fix.Longitude)
+	// Fixes closer together than a second are judged as a second apart so a
+	// burst of samples is not flagged for a few metres of jitter.
+	dt := math.Max(fix.At.Sub(at).Seconds(), 1)
+	speed = jump / dt
+	return jump, speed, jump-accuracy-fix.AccuracyMeters > f.cfg.MaxSpeedMPS*dt
+}
+
+func anchor(fix Fix) *track {
+	return &track{
+		lat:      fix.Latitude,
+		lon:      fix.Longitude,
+		variance: fix.AccuracyMeters * fix.AccuracyMeters,
+		accuracy: fix.AccuracyMeters,
+		at:       fix.At,
+	}
+}
+
+// Forget drops the id's track.
+func (f *Filter) Forget(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.tracks, id)
+}
+
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,60 @@
+package gpsfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func TestApplySmoothsJitterByAccuracy(t *testing.T) {
+	f := New(Config{})
+	f.Apply("driver-1", Fix{Latitude: 12.84, Longitude: 77.66, AccuracyMeters: 5, At: start})
+
+	// A vague fix 100 m north a second later barely moves the estimate.
+	r := f.Apply("driver-1", Fix{Latitude: 12.8409, Longitude: 77.66, AccuracyMeters: 60, At: start.Add(time.Second)})
+	require.False(t, r.Flagged)
+	assert.Less(t, haversineMeters(12.84, 77.66, r.Latitude, r.Longitude), 5.0)
+
+	// A precise fix is mostly believed.
+	r = f.Apply("driver-1", Fix{Latitude: 12.8409, Longitude: 77.66, AccuracyMeters: 3, At: start.Add(20 * time.Second)})
+	assert.Less(t, haversineMeters(12.8409, 77.66, r.Latitude, r.Longitude), 10.0)
+
+	// Without an accuracy there is nothing to weigh, so the fix is taken as-is.
+	r = f.Apply("driver-1", Fix{Latitude: 12.841, Longitude: 77.66, At: start.Add(25 * time.Second)})
+	assert.Equal(t, 12.841, r.Latitude)
+}
+
+func TestApplyFlagsTeleports(t *testing.T) {
+	f := New(Config{MaxSpeedMPS: 70})
+	f.Apply("driver-1", Fix{Latitude: 12.84, Longitude: 77.66, AccuracyMeters: 10, At: start})
+
+	// 11 km in five seconds.
+	r := f.Apply("driver-1", Fix{Latitude: 12.94, Longitude: 77.66, AccuracyMeters: 10, At: start.Add(5 * time.Second)})
+	require.True(t, r.Flagged)
+	assert.InDelta(t, 12.84, r.Latitude, 1e-9, "the track stays where it was")
+	assert.Greater(t, r.ImpliedSpeedMPS, 2000.0)
+
+	// A normal fix near the old position is accepted again.
+	r = f.Apply("driver-1", Fix{Latitude: 12.8401, Longitude: 77.66, AccuracyMeters: 10, At: start.Add(10 * time.Second)})
+	assert.False(t, r.Flagged)
+}
+
+func TestApplyReanchorsOnConsistentJumps(t *testing.T) {
+	f := New(Config{ReanchorAfter: 3})
+	f.Apply("driver-1", Fix{Latitude: 12.84, Longitude: 77.66, At: start})
+
+	var r Result
+	for i := 0; i < 3; i++ {
+		r = f.Apply("driver-1", Fix{Latitude: 12.94 + float64(i)*0.0001, Longitude: 77.66, At: start.Add(time.Duration(i+1) * time.Second)})
+	}
+	assert.True(t, r.Reanchored)
+	assert.False(t, r.Flagged)
+	assert.InDelta(t, 12.9402, r.Latitude, 1e-9)
+
+	r = f.Apply("driver-1", Fix{Latitude: 12.9403, Longitude: 77.66, At: start.Add(4 * time.Second)})
+	assert.False(t, r.Flagged)
+}
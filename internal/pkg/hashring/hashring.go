@@ -0,0 +1,114 @@
+// Package hashring assigns keys to members with consistent hashing.
+//
+// Each member is placed on the ring at several virtual points so keys spread
+// evenly, and adding or removing a member only moves the keys that member
+// gains or loses.
+package hashring
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultVirtualNodes is how many points each member gets when New is passed 0.
+const DefaultVirtualNodes = 64
+
+// Ring is a consistent hash ring. It is safe for concurrent use.
+type Ring struct {
+	mu      sync.RWMutex
+	vnodes  int
+	points  []uint64
+	owners  map[uint64]string
+	members map[string]struct{}
+}
+
+// New creates an empty ring with vnodes points per member.
+func New(vnodes int) *Ring {
+	if vnodes <= 0 {
+		vnodes = DefaultVirtualNodes
+	}
+	return &Ring{vnodes: vnodes, owners: make(map[uint64]string), members: make(map[string]struct{})}
+}
+
+// Add places members on the ring. Members already present are ignored.
+func (r *Ring) Add(members ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range members {
+		if _, ok := r.members[m]; ok {
+			continue
+		}
+		r.members[m] = struct{}{}
+		for i := 0; i < r.vnodes; i++ {
+			p := hash(m + "#" + strconv.Itoa(i))
+			if _, taken := r.owners[p]; taken {
+				continue
+			}
+			r.owners[p] = m
+			r.points = append(r.points, p)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+}
+
+// Remove takes a member off the ring.
+func (r *Ring) Remove(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.members[member]; !ok {
+		return
+	}
+	delete(r.members, member)
+	kept := r.points[:0]
+	for _, p := range r.points {
+		if r.owners[p] == member {
+			delete(r.owners, p)
+			continue
+		}
+		kept = append(kept, p)
+	}
+	r.points = kept
+}
+
+// Owner returns the member responsible for key, or "" when the ring is empty.
+func (r *Ring) Owner(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Members lists the members on the ring in sorted order.
+func (r *Ring) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.members))
+	for m := range r.members {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// hash is FNV-1a followed by a 64-bit finaliser; FNV alone clusters keys
+// that differ only in their last characters, such as "station-1" and "station-2".
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
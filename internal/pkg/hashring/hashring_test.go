@@ -0,0 +1,57 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keys(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("station-%d", i)
+	}
+	return out
+}
+
+func TestOwnerIsStableAndSpread(t *testing.T) {
+	r := New(0)
+	assert.Equal(t, "", r.Owner("station-1"))
+
+	r.Add("a", "b", "c")
+	counts := map[string]int{}
+	for _, k := range keys(3000) {
+		owner := r.Owner(k)
+		assert.Equal(t, owner, r.Owner(k))
+		counts[owner]++
+	}
+	for _, m := range []string{"a", "b", "c"} {
+		assert.Greater(t, counts[m], 500, "member %s owns too few keys: %v", m, counts)
+	}
+}
+
+func TestMembershipChangesOnlyMoveAffectedKeys(t *testing.T) {
+	r := New(0)
+	r.Add("a", "b", "c")
+	before := map[string]string{}
+	for _, k := range keys(1000) {
+		before[k] = r.Owner(k)
+	}
+
+	r.Add("d")
+	for k, owner := range before {
+		if now := r.Owner(k); now != owner {
+			assert.Equal(t, "d", now, "key %s moved between existing members", k)
+		}
+	}
+
+	r.Remove("d")
+	r.Remove("b")
+	assert.Equal(t, []string{"a", "c"}, r.Members())
+	for k, owner := range before {
+		if owner != "b" {
+			assert.Equal(t, owner, r.Owner(k), "key %s moved although its owner stayed", k)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Package matchpolicy ranks driver/rider pairings for the gateway and the
+// matching service.
+//
+// A MatchPolicy turns a Candidate into a Score: a weighted sum of factors such
+// as distance, rider wait, seat fill and driver fairness. Higher scores rank
+// first. Every score carries its breakdown so callers can explain a ranking.
+package matchpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Policy names accepted by Lookup.
+const (
+	Nearest        = "nearest"
+	LongestWaiting = "longest-waiting"
+	SeatFill       = "seat-fill"
+	Fairness       = "fairness"
+)
+
+// Factor names reported in score breakdowns.
+const (
+	FactorDistance   = "distance"
+	FactorArrivalGap = "arrivalGap"
+	FactorWait       = "wait"
+	FactorSeatFill   = "seatFill"
+	FactorFairness   = "fairness"
+	// FactorRating is only reported for drivers a RatingRule down-ranks.
+	FactorRating = "rating"
+)
+
+// Candidate describes one driver being considered for one rider. Fields the
+// caller cannot fill are left zero and simply contribute nothing.
+type Candidate struct {
+	DriverID string
+	RiderID  string
+	// DistanceMeters is how far the driver is from the pickup.
+	DistanceMeters float64
+	// ArrivalGap is the absolute difference between driver and rider arrival.
+	ArrivalGap time.Duration
+	// RiderWait is how long the rider has been waiting; negative while they
+	// are still on their way to the station.
+	RiderWait time.Duration
+	// SeatsTotal and SeatsAvailable describe the driver's car before this rider.
+	SeatsTotal     int
+	SeatsAvailable int
+	// TripsServed counts trips the driver has already been given.
+	TripsServed int
+	// Rating is the driver's average stars from riders over Ratings ratings.
+	Rating  float64
+	Ratings int
+}
+
+// Factor is one term of a score.
+type Factor struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// Score is a candidate's total along with the factors that produced it.
+type Score struct {
+	Total   float64  `json:"total"`
+	Factors []Factor `json:"factors"`
+}
+
+// MatchPolicy scores candidates; higher is better.
+type MatchPolicy interface {
+	Name() string
+	Score(c Candidate) Score
+}
+
+// weighted is a MatchPolicy built from fixed factor weights. Factors are
+// normalised so that larger raw values are always preferable.
+type weighted struct {
+	name    string
+	weights []Factor
+}
+
+func (p weighted) Name() string { return p.name }
+
+func (p weighted) Score(c Candidate) Score {
+	score := Score{Factors: make([]Factor, 0, len(p.weights))}
+	for _, w := range p.weights {
+		value := factorValue(w.Name, c)
+		f := Factor{Name: w.Name, Value: value, Weight: w.Weight, Contribution: value * w.Weight}
+		score.Total += f.Contribution
+		score.Factors = append(score.Factors, f)
+	}
+	return score
+}
+
+func factorValue(name string, c Candidate) float64 {
+	switch name {
+	case FactorDistance:
+		// Kilometres away, negated so closer drivers score higher.
+		return -c.DistanceMeters / 1000
+	case FactorArrivalGap:
+		return -c.ArrivalGap.Minutes()
+	case FactorWait:
+		return c.RiderWait.Minutes()
+	case FactorSeatFill:
+		// Share of the car already taken; partly full cars are filled first.
+		if c.SeatsTotal <= 0 {
+			return 0
+		}
+		return float64(c.SeatsTotal-c.SeatsAvailable) / float64(c.SeatsTotal)
+	case FactorFairness:
+		return -float64(c.TripsServed)
+	}
+	return 0
+}
+
+// Each policy leads with its primary factor; the small trailing weights only
+// break ties.
+var policies = map[string]MatchPolicy{
+	Nearest: weighted{name: Nearest, weights: []Factor{
+		{Name: FactorDistance, Weight: 1},
+		{Name: FactorArrivalGap, Weight: 0.01},
+	}},
+	LongestWaiting: weighted{name: LongestWaiting, weights: []Factor{
+		{Name: FactorWait, Weight: 1},
+		{Name: FactorDistance, Weight: 0.01},
+	}},
+	SeatFill: weighted{name: SeatFill, weights: []Factor{
+		{Name: FactorSeatFill, Weight: 10},
+		{Name: FactorDistance, Weight: 0.1},
+		{Name: FactorArrivalGap, Weight: 0.01},
+	}},
+	Fairness: weighted{name: Fairness, weights: []Factor{
+		{Name: FactorFairness, Weight: 10},
+		{Name: FactorDistance, Weight: 0.1},
+		{Name: FactorArrivalGap, Weight: 0.01},
+	}},
+}
+
+// Lookup returns the policy registered under name. An empty name selects Nearest.
+func Lookup(name string) (MatchPolicy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = Nearest
+	}
+	p, ok := policies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown match policy '%s'", name)
+	}
+	return p, nil
+}
+
+// RatingRule lets riders' ratings of drivers steer matching. Drivers with
+// fewer than MinRatings ratings are not judged on them. The zero RatingRule
+// changes nothing.
+type RatingRule struct {
+	MinRatings int
+	// ExcludeBelow keeps drivers rated under it out of matching altogether.
+	ExcludeBelow float64
+	// DownRankBelow costs drivers rated under it Weight per star short.
+	DownRankBelow float64
+	Weight        float64
+}
+
+func (r RatingRule) judges(c Candidate) bool {
+	return c.Ratings > 0 && c.Ratings >= r.MinRatings
+}
+
+// Excludes reports whether the driver's rating keeps them from being matched.
+func (r RatingRule) Excludes(c Candidate) bool {
+	return r.ExcludeBelow > 0 && r.judges(c) && c.Rating < r.ExcludeBelow
+}
+
+// Apply returns p with the rule's down-ranking added. The result keeps p's name.
+func (r RatingRule) Apply(p MatchPolicy) MatchPolicy {
+	if r.DownRankBelow <= 0 || r.Weight <= 0 {
+		return p
+	}
+	return rated{MatchPolicy: p, rule: r}
+}
+
+type rated struct {
+	MatchPolicy
+	rule RatingRule
+}
+
+func (p rated) Score(c Candidate) Score {
+	score := p.MatchPolicy.Score(c)
+	if !p.rule.judges(c) || c.Rating >= p.rule.DownRankBelow {
+		return score
+	}
+	value := c.Rating - p.rule.DownRankBelow
+	f := Factor{Name: FactorRating, Value: value, Weight: p.rule.Weight, Contribution: value * p.rule.Weight}
+	score.Total += f.Contribution
+	score.Factors = append(score.Factors, f)
+	return score
+}
+
+// Only returns p scoring nothing but the named factors. It keeps p's name.
+// Callers use it to drop factors that cannot tell their candidates apart.
+func Only(p MatchPolicy, factors ...string) MatchPolicy {
+	return only{MatchPolicy: p, factors: factors}
+}
+
+type only struct {
+	MatchPolicy
+	factors []string
+}
+
+func (p only) Score(c Candidate) Score {
+	full := p.MatchPolicy.Score(c)
+	score := Score{Factors: make([]Factor, 0, len(full.Factors))}
+	for _, f := range full.Factors {
+		for _, name := range p.factors {
+			if f.Name == name {
+				score.Total += f.Contribution
+				score.Factors = append(score.Factors, f)
+				break
+			}
+		}
+	}
+	return score
+}
+
+// Scored pairs a candidate with its score.
+type Scored struct {
+	Candidate
+	Score Score
+}
+
+// Rank scores every candidate and orders them best first. Ties keep the input order.
+func Rank(p MatchPolicy, candidates []Candidate) []Scored {
+	ranked := make([]Scored, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = Scored{Candidate: c, Score: p.Score(c)}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score.Total > ranked[j].Score.Total
+	})
+	return ranked
+}
+
+// Selector picks the policy for a station, falling back to a default.
+// It is immutable once built and safe for concurrent use.
+type Selector struct {
+	fallback  MatchPolicy
+	byStation map[string]MatchPolicy
+}
+
+// NewSelector builds a selector from a default policy name and per-station overrides.
+func NewSelector(defaultName string, perStation map[string]string) (*Selector, error) {
+	fallback, err := Lookup(defaultName)
+	if err != nil {
+		return nil, err
+	}
+	s := &Selector{fallback: fallback, byStation: make(map[string]MatchPolicy, len(perStation))}
+	for stationID, name := range perStation {
+		p, err := Lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("station '%s': %w", stationID, err)
+		}
+		s.byStation[stationID] = p
+	}
+	return s, nil
+}
+
+// For returns the policy configured for stationID. A nil selector uses Nearest.
+func (s *Selector) For(stationID string) MatchPolicy {
+	if s == nil {
+		return policies[Nearest]
+	}
+	if p, ok := s.byStation[stationID]; ok {
+		return p
+	}
+	return s.fallback
+}
+
+// ParseStationPolicies parses "station-a=fairness,station-b=seat-fill".
+func ParseStationPolicies(spec string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		stationID, name, ok := strings.Cut(entry, "=")
+		stationID, name = strings.TrimSpace(stationID), strings.TrimSpace(name)
+		if !ok || stationID == "" || name == "" {
+			return nil, fmt.Errorf("invalid station policy '%s', want station=policy", entry)
+		}
+		out[stationID] = name
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package matchpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rankedIDs(ranked []Scored) []string {
+	ids := make([]string, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.DriverID
+	}
+	return ids
+}
+
+func TestPoliciesRankByTheirPrimaryFactor(t *testing.T) {
+	candidates := []Candidate{
+		{DriverID: "far-busy", DistanceMeters: 4000, SeatsTotal: 4, SeatsAvailable: 1, TripsServed: 5},
+		{DriverID: "near-empty", DistanceMeters: 500, SeatsTotal: 4, SeatsAvailable: 4, TripsServed: 2},
+		{DriverID: "mid-idle", DistanceMeters: 1500, SeatsTotal: 4, SeatsAvailable: 3, TripsServed: 0},
+	}
+
+	cases := map[string][]string{
+		Nearest:  {"near-empty", "mid-idle", "far-busy"},
+		SeatFill: {"far-busy", "mid-idle", "near-empty"},
+		Fairness: {"mid-idle", "near-empty", "far-busy"},
+	}
+	for name, want := range cases {
+		p, err := Lookup(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, rankedIDs(Rank(p, candidates)), name)
+	}
+}
+
+func TestLongestWaitingPrefersEarlierRiders(t *testing.T) {
+	p, err := Lookup(LongestWaiting)
+	require.NoError(t, err)
+
+	ranked := Rank(p, []Candidate{
+		{DriverID: "d", RiderID: "new", RiderWait: -5 * time.Minute},
+		{DriverID: "d", RiderID: "old", RiderWait: 12 * time.Minute},
+	})
+	assert.Equal(t, "old", ranked[0].RiderID)
+
+	require.Len(t, ranked[0].Score.Factors, 2)
+	wait := ranked[0].Score.Factors[0]
+	assert.Equal(t, FactorWait, wait.Name)
+	assert.InDelta(t, 12, wait.Value, 1e-9)
+	assert.InDelta(t, ranked[0].Score.Total, wait.Contribution+ranked[0].Score.Factors[1].Contribution, 1e-9)
+}
+
+func TestOnlyScoresTheNamedFactors(t *testing.T) {
+	p, err := Lookup(SeatFill)
+	require.NoError(t, err)
+	p = Only(p, FactorArrivalGap)
+	assert.Equal(t, SeatFill, p.Name())
+
+	score := p.Score(Candidate{DistanceMeters: 2000, SeatsTotal: 4, SeatsAvailable: 1, ArrivalGap: 3 * time.Minute})
+	require.Len(t, score.Factors, 1)
+	assert.Equal(t, FactorArrivalGap, score.Factors[0].Name)
+	assert.InDelta(t, -0.03, score.Total, 1e-9)
+}
+
+func TestSelectorPerStation(t *testing.T) {
+	overrides, err := ParseStationPolicies("station-a=fairness, station-b = seat-fill")
+	require.NoError(t, err)
+
+	s, err := NewSelector("", overrides)
+	require.NoError(t, err)
+	assert.Equal(t, Fairness, s.For("station-a").Name())
+	assert.Equal(t, SeatFill, s.For("station-b").Name())
+	assert.Equal(t, Nearest, s.For("station-c").Name())
+
+	var unset *Selector
+	assert.Equal(t, Nearest, unset.For("station-a").Name())
+
+	_, err = NewSelector("cheapest", nil)
+	assert.Error(t, err)
+	_, err = ParseStationPolicies("station-a")
+	assert.Error(t, err)
+}
+
+func TestRatingRuleDownRanksAndExcludesLowRatedDrivers(t *testing.T) {
+	rule := RatingRule{MinRatings: 3, ExcludeBelow: 2.5, DownRankBelow: 4, Weight: 1}
+	nearest, err := Lookup(Nearest)
+	require.NoError(t, err)
+	p := rule.Apply(nearest)
+	assert.Equal(t, Nearest, p.Name())
+
+	candidates := []Candidate{
+		{DriverID: "near-poor", DistanceMeters: 500, Rating: 3.2, Ratings: 10},
+		{DriverID: "mid-good", DistanceMeters: 1000, Rating: 4.8, Ratings: 10},
+		{DriverID: "far-new", DistanceMeters: 1200, Rating: 1, Ratings: 1},
+	}
+	assert.Equal(t, []string{"mid-good", "far-new", "near-poor"}, rankedIDs(Rank(p, candidates)))
+
+	poor := p.Score(candidates[0])
+	require.Len(t, poor.Factors, 3)
+	assert.Equal(t, FactorRating, poor.Factors[2].Name)
+	assert.InDelta(t, -0.8, poor.Factors[2].Contribution, 1e-9)
+
+	assert.True(t, rule.Excludes(Candidate{Rating: 2, Ratings: 5}))
+	assert.False(t, rule.Excludes(Candidate{Rating: 2, Ratings: 2}), "too few ratings to judge")
+	assert.False(t, RatingRule{}.Excludes(Candidate{Rating: 1, Ratings: 50}))
+	assert.Equal(t, nearest, RatingRule{}.Apply(nearest))
+}
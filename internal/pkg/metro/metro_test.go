@@ -0,0 +1,29 @@
+package metro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickupPointsSitAtTheirStations(t *testing.T) {
+	stations := Stations()
+	pickups := PickupPoints()
+	require.Len(t, pickups, len(stations))
+
+	seen := map[string]bool{}
+	for i, s := range stations {
+		assert.False(t, seen[s.ID], "duplicate station %s", s.ID)
+		seen[s.ID] = true
+		assert.Equal(t, s.ID, pickups[i].StationID)
+		assert.Equal(t, s.Latitude, pickups[i].Latitude)
+		assert.Equal(t, s.Longitude, pickups[i].Longitude)
+	}
+}
+
+func TestStationsAreCopies(t *testing.T) {
+	first := Stations()
+	first[0].NearbyAreas[0] = "changed"
+	assert.NotEqual(t, "changed", Stations()[0].NearbyAreas[0])
+}
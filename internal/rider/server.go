@@ -3,10 +3,13 @@ package rider
 import (
 	"context"
 	"log/slog"
+	"sort"
+	"sync"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	pb "lastmile/gen/go/rider"
 	"lastmile/internal/pkg/logging"
@@ -15,6 +18,7 @@ import (
 // Server implements the RiderServiceServer interface.
 type Server struct {
 	pb.UnimplementedRiderServiceServer
+	mu     sync.RWMutex
 	riders map[string]*pb.Rider
 	rides  map[string]*pb.Ride
 	logger *slog.Logger
@@ -43,7 +47,12 @@ func (s *Server) RegisterRider(ctx context.Context, req *pb.RegisterRiderRequest
 
 	id := uuid.New().String()
 	req.Rider.Id = id
+	if req.Rider.Status == "" {
+		req.Rider.Status = "waiting"
+	}
+	s.mu.Lock()
 	s.riders[id] = req.Rider
+	s.mu.Unlock()
 	s.logger.Info("rider registered", "riderId", id, "name", req.Rider.Name)
 
 	return &pb.RegisterRiderResponse{Id: id}, nil
@@ -51,7 +60,9 @@ func (s *Server) RegisterRider(ctx context.Context, req *pb.RegisterRiderRequest
 
 // TrackRide tracks the status of a ride.
 func (s *Server) TrackRide(ctx context.Context, req *pb.TrackRideRequest) (*pb.TrackRideResponse, error) {
+	s.mu.RLock()
 	ride, ok := s.rides[req.RideId]
+	s.mu.RUnlock()
 	if !ok {
 		s.logger.Warn("ride not found", "rideId", req.RideId)
 		return nil, status.Errorf(codes.NotFound, "ride not found")
@@ -60,3 +71,59 @@ func (s *Server) TrackRide(ctx context.Context, req *pb.TrackRideRequest) (*pb.T
 	s.logger.Info("ride status requested", "rideId", req.RideId, "status", ride.Status)
 	return &pb.TrackRideResponse{Ride: ride}, nil
 }
+
+// ListRiders returns riders filtered by station and status, earliest arrival first.
+// Empty filters match every rider.
+func (s *Server) ListRiders(ctx context.Context, req *pb.ListRidersRequest) (*pb.ListRidersResponse, error) {
+	s.mu.RLock()
+	riders := make([]*pb.Rider, 0, len(s.riders))
+	for _, r := range s.riders {
+		if req.StationId != "" && r.StationId != req.StationId {
+			continue
+		}
+		if req.Status != "" && r.Status != req.Status {
+			continue
+		}
+		riders = append(riders, proto.Clone(r).(*pb.Rider))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(riders, func(i, j int) bool {
+		return riders[i].GetArrivalTime().AsTime().Before(riders[j].GetArrivalTime().AsTime())
+	})
+
+	return &pb.ListRidersResponse{Riders: riders}, nil
+}
+
+// UpdateRiderStatus changes the lifecycle status of a rider (e.g. waiting -> matched).
+// With expected_status set the change is a compare-and-set: a rider in any
+// other status is left alone and the call fails with FailedPrecondition, so
+// only one caller can claim a waiting rider.
+func (s *Server) UpdateRiderStatus(ctx context.Context, req *pb.UpdateRiderStatusRequest) (*pb.UpdateRiderStatusResponse, error) {
+	if req.Status == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "status is required")
+	}
+
+	s.mu.Lock()
+	rider, ok := s.riders[req.Id]
+	var current string
+	if ok {
+		current = rider.Status
+		if req.ExpectedStatus == "" || current == req.ExpectedStatus {
+			rider.Status = req.Status
+		}
+		rider = proto.Clone(rider).(*pb.Rider)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		s.logger.Warn("rider not found", "riderId", req.Id)
+		return nil, status.Errorf(codes.NotFound, "rider not found")
+	}
+	if req.ExpectedStatus != "" && current != req.ExpectedStatus {
+		return nil, status.Errorf(codes.FailedPrecondition, "rider is %s, not %s", current, req.ExpectedStatus)
+	}
+
+	s.logger.Info("rider status updated", "riderId", req.Id, "status", req.Status)
+	return &pb.UpdateRiderStatusResponse{Rider: rider}, nil
+}
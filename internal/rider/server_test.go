@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "lastmile/gen/go/rider"
@@ -48,3 +50,39 @@ func TestTrackRide(t *testing.T) {
 	assert.Equal(t, "ride-123", res.Ride.Id)
 	assert.Equal(t, "en-route", res.Ride.Status)
 }
+
+func TestListRidersFiltersAndUpdatesStatus(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+	for _, r := range []*pb.Rider{
+		{Name: "Late", StationId: "station-1", ArrivalTime: timestamppb.New(time.Now().Add(9 * time.Minute))},
+		{Name: "Early", StationId: "station-1", ArrivalTime: timestamppb.New(time.Now().Add(2 * time.Minute))},
+		{Name: "Elsewhere", StationId: "station-2", ArrivalTime: timestamppb.New(time.Now())},
+	} {
+		_, err := s.RegisterRider(ctx, &pb.RegisterRiderRequest{Rider: r})
+		require.NoError(t, err)
+	}
+
+	res, err := s.ListRiders(ctx, &pb.ListRidersRequest{StationId: "station-1", Status: "waiting"})
+	require.NoError(t, err)
+	require.Len(t, res.Riders, 2)
+	assert.Equal(t, "Early", res.Riders[0].Name)
+
+	_, err = s.UpdateRiderStatus(ctx, &pb.UpdateRiderStatusRequest{Id: res.Riders[0].Id, Status: "matched"})
+	require.NoError(t, err)
+
+	res, err = s.ListRiders(ctx, &pb.ListRidersRequest{StationId: "station-1", Status: "waiting"})
+	require.NoError(t, err)
+	require.Len(t, res.Riders, 1)
+	assert.Equal(t, "Late", res.Riders[0].Name)
+
+	// A claim expecting a waiting rider succeeds once.
+	late := res.Riders[0].Id
+	_, err = s.UpdateRiderStatus(ctx, &pb.UpdateRiderStatusRequest{Id: late, Status: "matched", ExpectedStatus: "waiting"})
+	require.NoError(t, err)
+	_, err = s.UpdateRiderStatus(ctx, &pb.UpdateRiderStatusRequest{Id: late, Status: "expired", ExpectedStatus: "waiting"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	res, err = s.ListRiders(ctx, &pb.ListRidersRequest{StationId: "station-1", Status: "matched"})
+	require.NoError(t, err)
+	assert.Len(t, res.Riders, 2, "a failed claim leaves the rider as they were")
+}
@@ -0,0 +1,53 @@
+package station
+
+import (
+	pb "lastmile/gen/go/station"
+	"lastmile/internal/pkg/metro"
+)
+
+// defaultStationRadius is the geofence radius given to catalog stations.
+const defaultStationRadius = 800.0
+
+// DefaultCatalog returns the Bengaluru metro stations served today, each with
+// a pickup point at the station itself, from the shared metro catalog.
+func DefaultCatalog() ([]*pb.Station, []*pb.PickupPoint) {
+	stations := make([]*pb.Station, 0, len(metro.Stations()))
+	for _, st := range metro.Stations() {
+		stations = append(stations, &pb.Station{
+			Id:                   st.ID,
+			Name:                 st.Name,
+			NearbyAreas:          st.NearbyAreas,
+			Latitude:             st.Latitude,
+			Longitude:            st.Longitude,
+			GeofenceRadiusMeters: defaultStationRadius,
+		})
+	}
+
+	pickups := make([]*pb.PickupPoint, 0, len(stations))
+	for _, p := range metro.PickupPoints() {
+		pickups = append(pickups, &pb.PickupPoint{
+			Id:        p.ID,
+			Name:      p.Name,
+			StationId: p.StationID,
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		})
+	}
+	return stations, pickups
+}
+
+// Seed loads stations and pickup points into the server, replacing any
+// entries with the same ids.
+func (s *Server) Seed(stations []*pb.Station, pickups []*pb.PickupPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range stations {
+		s.stations[st.Id] = st
+	}
+	if s.pickupPoints == nil {
+		s.pickupPoints = make(map[string]*pb.PickupPoint)
+	}
+	for _, p := range pickups {
+		s.pickupPoints[p.Id] = p
+	}
+}
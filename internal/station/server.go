@@ -3,6 +3,8 @@ package station
 import (
 	"context"
 	"log/slog"
+	"sort"
+	"sync"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -15,8 +17,10 @@ import (
 // Server implements the StationServiceServer interface.
 type Server struct {
 	pb.UnimplementedStationServiceServer
-	stations map[string]*pb.Station
-	logger   *slog.Logger
+	mu           sync.RWMutex
+	stations     map[string]*pb.Station
+	pickupPoints map[string]*pb.PickupPoint
+	logger       *slog.Logger
 }
 
 // NewServer creates a new Server.
@@ -27,8 +31,9 @@ func NewServer(logger ...*slog.Logger) *Server {
 	}
 
 	return &Server{
-		stations: make(map[string]*pb.Station),
-		logger:   l,
+		stations:     make(map[string]*pb.Station),
+		pickupPoints: make(map[string]*pb.PickupPoint),
+		logger:       l,
 	}
 }
 
@@ -44,9 +49,14 @@ func (s *Server) AddStation(ctx context.Context, req *pb.AddStationRequest) (*pb
 		return nil, status.Errorf(codes.InvalidArgument, "station is required")
 	}
 
-	id := uuid.New().String()
+	id := req.Station.Id
+	if id == "" {
+		id = uuid.New().String()
+	}
 	req.Station.Id = id
+	s.mu.Lock()
 	s.stations[id] = req.Station
+	s.mu.Unlock()
 	logger.Info("station added", "stationId", id, "name", req.Station.Name)
 
 	return &pb.AddStationResponse{Id: id}, nil
@@ -59,7 +69,9 @@ func (s *Server) GetStation(ctx context.Context, req *pb.GetStationRequest) (*pb
 		logger = logging.New("station")
 	}
 
+	s.mu.RLock()
 	station, ok := s.stations[req.Id]
+	s.mu.RUnlock()
 	if !ok {
 		logger.Warn("station not found", "stationId", req.Id)
 		return nil, status.Errorf(codes.NotFound, "station with id '%s' not found", req.Id)
@@ -68,3 +80,53 @@ func (s *Server) GetStation(ctx context.Context, req *pb.GetStationRequest) (*pb
 	logger.Info("station fetched", "stationId", req.Id)
 	return &pb.GetStationResponse{Station: station}, nil
 }
+
+// AddPickupPoint adds a pickup point served by an existing station.
+func (s *Server) AddPickupPoint(ctx context.Context, req *pb.AddPickupPointRequest) (*pb.AddPickupPointResponse, error) {
+	logger := s.logger
+	if logger == nil {
+		logger = logging.New("station")
+	}
+
+	point := req.GetPickupPoint()
+	if point == nil {
+		logger.Warn("add pickup point: missing pickup point payload")
+		return nil, status.Errorf(codes.InvalidArgument, "pickup point is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.stations[point.StationId]; !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "station with id '%s' not found", point.StationId)
+	}
+	if point.Id == "" {
+		point.Id = uuid.New().String()
+	}
+	if s.pickupPoints == nil {
+		s.pickupPoints = make(map[string]*pb.PickupPoint)
+	}
+	s.pickupPoints[point.Id] = point
+	logger.Info("pickup point added", "pickupId", point.Id, "stationId", point.StationId)
+
+	return &pb.AddPickupPointResponse{Id: point.Id}, nil
+}
+
+// ListStations returns every station and pickup point, ordered by id.
+func (s *Server) ListStations(ctx context.Context, req *pb.ListStationsRequest) (*pb.ListStationsResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &pb.ListStationsResponse{
+		Stations:     make([]*pb.Station, 0, len(s.stations)),
+		PickupPoints: make([]*pb.PickupPoint, 0, len(s.pickupPoints)),
+	}
+	for _, station := range s.stations {
+		resp.Stations = append(resp.Stations, station)
+	}
+	for _, point := range s.pickupPoints {
+		resp.PickupPoints = append(resp.PickupPoints, point)
+	}
+	sort.Slice(resp.Stations, func(i, j int) bool { return resp.Stations[i].Id < resp.Stations[j].Id })
+	sort.Slice(resp.PickupPoints, func(i, j int) bool { return resp.PickupPoints[i].Id < resp.PickupPoints[j].Id })
+	return resp, nil
+}
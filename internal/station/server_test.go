@@ -66,3 +66,33 @@ func TestGetStation_NotFound(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, codes.NotFound, st.Code())
 }
+
+func TestAddPickupPoint_RequiresStation(t *testing.T) {
+	s := NewServer()
+
+	_, err := s.AddPickupPoint(context.Background(), &pb.AddPickupPointRequest{
+		PickupPoint: &pb.PickupPoint{Name: "Gate 2", StationId: "missing"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestListStations(t *testing.T) {
+	s := NewServer()
+	s.Seed(DefaultCatalog())
+
+	_, err := s.AddPickupPoint(context.Background(), &pb.AddPickupPointRequest{
+		PickupPoint: &pb.PickupPoint{Id: "pickup-wipro-gate", Name: "Wipro Gate", StationId: "station-ecity", Latitude: 12.8467, Longitude: 77.6624},
+	})
+	require.NoError(t, err)
+
+	res, err := s.ListStations(context.Background(), &pb.ListStationsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Stations, 9)
+	assert.Equal(t, "station-bellandur", res.Stations[0].Id)
+	assert.Len(t, res.PickupPoints, 10)
+	for _, st := range res.Stations {
+		assert.NotZero(t, st.Latitude, st.Id)
+		assert.NotZero(t, st.GeofenceRadiusMeters, st.Id)
+	}
+}
@@ -3,9 +3,11 @@ package trip
 import (
 	"context"
 	"log/slog"
+	"sync"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	pb "lastmile/gen/go/trip"
 	"lastmile/internal/pkg/logging"
@@ -14,6 +16,7 @@ import (
 // Server implements the TripServiceServer interface.
 type Server struct {
 	pb.UnimplementedTripServiceServer
+	mu     sync.RWMutex
 	trips  map[string]*pb.Trip
 	logger *slog.Logger
 }
@@ -31,9 +34,32 @@ func NewServer(logger ...*slog.Logger) *Server {
 	}
 }
 
+// CreateTrip stores a new trip. A trip without an ID is rejected so callers
+// (the matching service) stay in charge of identifiers.
+func (s *Server) CreateTrip(ctx context.Context, req *pb.CreateTripRequest) (*pb.CreateTripResponse, error) {
+	if req.Trip == nil || req.Trip.Id == "" {
+		s.logger.Warn("create trip: missing trip payload")
+		return nil, status.Errorf(codes.InvalidArgument, "trip with id is required")
+	}
+	s.mu.Lock()
+	if _, exists := s.trips[req.Trip.Id]; exists {
+		s.mu.Unlock()
+		return nil, status.Errorf(codes.AlreadyExists, "trip '%s' already exists", req.Trip.Id)
+	}
+	s.trips[req.Trip.Id] = proto.Clone(req.Trip).(*pb.Trip)
+	s.mu.Unlock()
+	s.logger.Info("trip created", "tripId", req.Trip.Id, "driverId", req.Trip.DriverId, "riderId", req.Trip.RiderId)
+	return &pb.CreateTripResponse{Trip: req.Trip}, nil
+}
+
 // GetTrip retrieves a trip by its ID.
 func (s *Server) GetTrip(ctx context.Context, req *pb.GetTripRequest) (*pb.GetTripResponse, error) {
+	s.mu.RLock()
 	trip, ok := s.trips[req.Id]
+	if ok {
+		trip = proto.Clone(trip).(*pb.Trip)
+	}
+	s.mu.RUnlock()
 	if !ok {
 		s.logger.Warn("trip not found", "tripId", req.Id)
 		return nil, status.Errorf(codes.NotFound, "trip not found")
@@ -44,13 +70,17 @@ func (s *Server) GetTrip(ctx context.Context, req *pb.GetTripRequest) (*pb.GetTr
 
 // UpdateTrip updates the status of a trip.
 func (s *Server) UpdateTrip(ctx context.Context, req *pb.UpdateTripRequest) (*pb.UpdateTripResponse, error) {
+	s.mu.Lock()
 	trip, ok := s.trips[req.Id]
+	if ok {
+		trip.Status = req.Status
+		trip = proto.Clone(trip).(*pb.Trip)
+	}
+	s.mu.Unlock()
 	if !ok {
 		s.logger.Warn("trip not found", "tripId", req.Id)
 		return nil, status.Errorf(codes.NotFound, "trip not found")
 	}
-	trip.Status = req.Status
-	s.trips[req.Id] = trip
 	s.logger.Info("trip updated", "tripId", req.Id, "status", req.Status)
 	return &pb.UpdateTripResponse{Trip: trip}, nil
 }
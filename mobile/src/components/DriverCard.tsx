@@ -18,7 +18,7 @@ export const DriverCard: React.FC<DriverCardProps> = ({ driver }) => {
       </View>
       <Text style={styles.car}>{driver.carDetails}</Text>
       <View style={styles.metaRow}>
-        <Text style={styles.meta}>ETA · {driver.etaMinutes} min</Text>
+        <Text style={styles.meta}>ETA · {driver.etaMinutes || '--'} min</Text>
         <Text style={styles.meta}>Seats · {driver.seatsAvailable}</Text>
       </View>
       <Text style={styles.destination}>Stations → {driver.route.destination}</Text>
@@ -20,7 +20,7 @@ export const TripCard: React.FC<TripCardProps> = ({ trip, driver, rider, station
     {station ? <Text style={styles.subtitle}>Station · {station.name}</Text> : null}
     {trip.destination ? <Text style={styles.subtitle}>Dest · {trip.destination}</Text> : null}
     <View style={styles.row}>
-      <Text style={styles.meta}>ETA · {trip.etaMinutes ?? driver?.etaMinutes ?? '--'} min</Text>
+      <Text style={styles.meta}>ETA · {trip.etaMinutes || driver?.etaMinutes || '--'} min</Text>
       <Text style={styles.meta}>Seats left · {driver?.seatsAvailable ?? '—'}</Text>
     </View>
   </GlassCard>
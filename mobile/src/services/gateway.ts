@@ -5,8 +5,17 @@ import {
   DriverRequestsResponse,
   DriverRoutePayload,
   DriverRouteResponse,
+  DriverStatus,
+  DriverVehicles,
+  LocationUpdate,
   PickupPoint,
+  Rating,
+  RatingPayload,
+  RouteTemplate,
+  RouteTemplatePayload,
   Trip,
+  Vehicle,
+  VehiclePayload,
 } from '../types';
 import { createMockTrip, mockSnapshot } from './mockData';
 import { pickupCatalog } from './pickupCatalog';
@@ -122,6 +131,62 @@ export class BackendGateway {
     });
   }
 
+  async fetchRouteTemplates(driverId: string): Promise<RouteTemplate[]> {
+    return request<RouteTemplate[]>(`/drivers/schedules?driverId=${encodeURIComponent(driverId)}`);
+  }
+
+  // saveRouteTemplate stores a recurring route that the gateway starts on
+  // its days at the departure time.
+  async saveRouteTemplate(payload: RouteTemplatePayload): Promise<RouteTemplate> {
+    return request<RouteTemplate>('/drivers/schedules', {
+      method: 'POST',
+      body: JSON.stringify(payload),
+    });
+  }
+
+  async deleteRouteTemplate(id: string): Promise<void> {
+    await request(`/drivers/schedules?id=${encodeURIComponent(id)}`, { method: 'DELETE' });
+  }
+
+  // setDriverStatus takes the driver online or offline; the gateway answers
+  // 409 when the driver still has riders to pick up or drop off.
+  async setDriverStatus(driverId: string, status: DriverStatus, reason?: string): Promise<void> {
+    await request('/drivers/status', {
+      method: 'POST',
+      body: JSON.stringify({ driverId, status, reason }),
+    });
+  }
+
+  async fetchDriverVehicles(driverId: string): Promise<DriverVehicles> {
+    return request<DriverVehicles>(`/drivers/vehicles?driverId=${encodeURIComponent(driverId)}`);
+  }
+
+  // registerVehicle registers a car and links it to the driver; the first
+  // one they link is the one they drive.
+  async registerVehicle(payload: VehiclePayload): Promise<Vehicle> {
+    return request<Vehicle>('/drivers/vehicles', {
+      method: 'POST',
+      body: JSON.stringify(payload),
+    });
+  }
+
+  // selectVehicle switches the car the driver is driving. A smaller car caps
+  // the seats on their route.
+  async selectVehicle(driverId: string, vehicleId: string): Promise<Vehicle> {
+    return request<Vehicle>('/drivers/vehicles/select', {
+      method: 'POST',
+      body: JSON.stringify({ driverId, vehicleId }),
+    });
+  }
+
+  // submitRating rates the other party once the trip is completed.
+  async submitRating(payload: RatingPayload): Promise<Rating> {
+    return request<Rating>('/trips/ratings', {
+      method: 'POST',
+      body: JSON.stringify(payload),
+    });
+  }
+
   async acceptDriverRequest(driverId: string, riderId: string): Promise<Trip> {
     return request<Trip>('/drivers/requests/accept', {
       method: 'POST',
@@ -136,7 +201,7 @@ export class BackendGateway {
     });
   }
 
-  subscribeToLocationUpdates(driverId: string, onUpdate: (update: any) => void): () => void {
+  subscribeToLocationUpdates(driverId: string, onUpdate: (update: LocationUpdate) => void): () => void {
     // Replace http/https with ws/wss
     const wsProtocol = baseUrl.startsWith('https') ? 'wss' : 'ws';
     const wsUrl = baseUrl.replace(/^http(s)?/, 'ws$1');
@@ -146,7 +211,7 @@ export class BackendGateway {
 
     socket.onmessage = (event) => {
       try {
-        const update = JSON.parse(event.data);
+        const update = JSON.parse(event.data) as LocationUpdate;
         onUpdate(update);
       } catch (e) {
         console.warn('Failed to parse location update', e);
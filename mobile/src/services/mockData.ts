@@ -55,7 +55,7 @@ const drivers: Driver[] = [
     carDetails: 'Polestar 2 • EV',
     seatsAvailable: 2,
     etaMinutes: 4,
-    status: 'en_route',
+    status: 'en_route_to_pickup',
     route: {
       id: 'route-ava',
       destination: 'Financial District',
@@ -69,7 +69,7 @@ const drivers: Driver[] = [
     carDetails: 'Model Y • AWD',
     seatsAvailable: 3,
     etaMinutes: 7,
-    status: 'arriving',
+    status: 'en_route_to_pickup',
     route: {
       id: 'route-noah',
       destination: 'Mission District',
@@ -83,7 +83,7 @@ const drivers: Driver[] = [
     carDetails: 'ID.4 • Comfort',
     seatsAvailable: 1,
     etaMinutes: 2,
-    status: 'boarding',
+    status: 'available',
     route: {
       id: 'route-lina',
       destination: 'Golden Gate Heights',
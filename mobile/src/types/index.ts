@@ -37,16 +37,50 @@ export type Route = {
   pickupPoints?: PickupPoint[];
 };
 
+export type DriverStatus = 'offline' | 'available' | 'en_route_to_pickup' | 'full';
+
 export type Driver = {
   id: string;
   name: string;
   carDetails: string;
   seatsAvailable: number;
   etaMinutes: number;
-  status: 'en_route' | 'arriving' | 'boarding' | 'active';
+  status: DriverStatus | '';
   route: Route;
   latitude?: number;
   longitude?: number;
+  presence?: 'online' | 'idle' | 'stale' | 'offline';
+  lastSeenAt?: string;
+  vehicle?: Vehicle;
+  rating?: RatingSummary;
+};
+
+export type Vehicle = {
+  id: string;
+  plate: string;
+  make?: string;
+  model?: string;
+  colour?: string;
+  maxSeats: number;
+  airConditioned: boolean;
+  wheelchairAccessible: boolean;
+  luggageSpace: boolean;
+};
+
+export type VehiclePayload = Omit<Vehicle, 'id'> & { id?: string; driverId: string };
+
+export type DriverVehicles = {
+  driverId?: string;
+  activeVehicleId?: string;
+  vehicles: Vehicle[];
+};
+
+// TripVehicle is what a rider is shown about the car coming for them.
+export type TripVehicle = {
+  plate: string;
+  colour?: string;
+  make?: string;
+  model?: string;
 };
 
 export type Rider = {
@@ -58,6 +92,7 @@ export type Rider = {
   status: 'waiting' | 'matched' | 'picked_up';
   pickupPointId?: string;
   pickup?: PickupPoint;
+  rating?: RatingSummary;
 };
 
 export type Trip = {
@@ -73,6 +108,73 @@ export type Trip = {
   createdAt?: string;
   completedAt?: string;
   roomId?: string;
+  journeyId?: string;
+  vehicle?: TripVehicle;
+};
+
+export type RatingSummary = {
+  average: number;
+  count: number;
+};
+
+export type Rating = {
+  tripId: string;
+  raterId: string;
+  raterRole: 'driver' | 'rider';
+  subjectId: string;
+  stars: number;
+  tags?: string[];
+  comment?: string;
+  createdAt: string;
+};
+
+export type RatingPayload = {
+  tripId: string;
+  raterId: string;
+  stars: number;
+  tags?: string[];
+  comment?: string;
+};
+
+// RatingPrompt arrives as trip:rating-request once a trip is completed.
+export type RatingPrompt = {
+  tripId: string;
+  raterId: string;
+  raterRole: 'driver' | 'rider';
+  subjectId: string;
+  subjectName: string;
+  tags: string[];
+};
+
+export type RatingsResponse = {
+  summary?: RatingSummary;
+  ratings: Rating[];
+};
+
+export type TripLeg = {
+  tripId: string;
+  riderId: string;
+  pickupPointId?: string;
+  pickup?: PickupPoint;
+  dropoffStationId?: string;
+  destination?: string;
+  status: string;
+  matchedAt: string;
+  pickedUpAt?: string;
+  droppedOffAt?: string;
+};
+
+export type Journey = {
+  id: string;
+  driverId: string;
+  stationId?: string;
+  status: 'active' | 'completed';
+  legs: TripLeg[];
+  driverLatitude?: number;
+  driverLongitude?: number;
+  createdAt: string;
+  updatedAt: string;
+  completedAt?: string;
 };
 
 export type BackendMetrics = {
@@ -81,6 +183,8 @@ export type BackendMetrics = {
   seatsOpen: number;
   avgWaitMinutes: number;
   version: string;
+  matchWindowMinutes?: number;
+  ridersDeferred?: number;
 };
 
 export type BackendSnapshot = {
@@ -98,6 +202,7 @@ export type DriverSummary = {
   name: string;
   seatsAvailable: number;
   nextStop: string;
+  etaMinutes: number;
 };
 
 export type DriverRequest = {
@@ -109,6 +214,8 @@ export type DriverRequest = {
   pickup?: PickupPoint;
   status: string;
   distanceMeters: number;
+  etaMinutes?: number;
+  inWindow?: boolean;
 };
 
 export type DriverRequestsResponse = {
@@ -117,12 +224,22 @@ export type DriverRequestsResponse = {
   generatedAt: string;
 };
 
+export type ScoreFactor = {
+  name: string;
+  value: number;
+  weight: number;
+  contribution: number;
+};
+
 export type DriverAttempt = {
   driverId: string;
   driverName: string;
   distanceMeters: number;
   accepted: boolean;
   reason?: string;
+  policy?: string;
+  score?: number;
+  scoreBreakdown?: ScoreFactor[];
 };
 
 export type BookRidePayload = {
@@ -143,6 +260,7 @@ export type BookRideResponse = {
   pickup?: PickupPoint;
   requestedDestination: string;
   attempts: DriverAttempt[];
+  skipped?: DriverAttempt[];
   trip?: Trip;
 };
 
@@ -152,6 +270,7 @@ export type DriverRoutePayload = {
   carDetails: string;
   pickupPointIds: string[];
   seats: number;
+  vehicleId?: string;
 };
 
 export type DriverRouteResponse = {
@@ -161,4 +280,38 @@ export type DriverRouteResponse = {
   seatsAvailable: number;
   targetStations: string[];
   destination: string;
+  vehicle?: Vehicle;
+};
+
+export type Weekday = 'sun' | 'mon' | 'tue' | 'wed' | 'thu' | 'fri' | 'sat';
+
+// RouteTemplate is a route the gateway starts for the driver on each of
+// days at departure (HH:MM, in timezone or the gateway's).
+export type RouteTemplate = {
+  id: string;
+  driverId: string;
+  name?: string;
+  pickupPointIds: string[];
+  seats: number;
+  destination?: string;
+  days: Weekday[];
+  departure: string;
+  timezone?: string;
+  createdAt: string;
+  nextRunAt: string;
+  lastRunAt?: string;
+};
+
+export type RouteTemplatePayload = Omit<RouteTemplate, 'id' | 'createdAt' | 'nextRunAt' | 'lastRunAt'> & { id?: string };
+
+export type LocationUpdate = {
+  driverId: string;
+  latitude: number;
+  longitude: number;
+  recordedAt?: string;
+  speedMps: number;
+  headingDegrees: number;
+  accuracyMeters?: number;
+  // Set on the position that took the driver out of a subscribed area.
+  leftArea?: boolean;
 };
@@ -59,6 +59,38 @@ create trigger on_auth_user_created
 
 -- Realtime routing + trips ----------------------------------------------------
 
+create table if not exists drivers (
+  id text primary key,
+  name text not null default '',
+  car_details text not null default '',
+  -- offline, available, en_route_to_pickup or full
+  status text not null default 'offline',
+  active_vehicle_id text,
+  created_at timestamptz not null default now()
+);
+
+-- plate_key is the plate upper-cased without spaces or dashes.
+create table if not exists vehicles (
+  id text primary key,
+  plate text not null,
+  plate_key text not null unique,
+  make text not null default '',
+  model text not null default '',
+  colour text not null default '',
+  max_seats integer not null default 1,
+  air_conditioned boolean not null default false,
+  wheelchair_accessible boolean not null default false,
+  luggage_space boolean not null default false,
+  created_at timestamptz not null default now()
+);
+
+create table if not exists driver_vehicles (
+  driver_id text not null references drivers(id) on delete cascade,
+  vehicle_id text not null references vehicles(id) on delete cascade,
+  linked_at timestamptz not null default now(),
+  primary key (driver_id, vehicle_id)
+);
+
 create table if not exists driver_routes (
   id uuid primary key default gen_random_uuid(),
   driver_id text not null unique,
@@ -72,6 +104,38 @@ create table if not exists driver_routes (
   metadata jsonb not null default '{}'::jsonb
 );
 
+-- Recurring routes the gateway starts for a driver on the given days
+-- (mon..sun) at the departure time (HH:MM, in timezone or the gateway's).
+create table if not exists driver_route_templates (
+  id text primary key,
+  driver_id text not null,
+  name text not null default '',
+  pickup_ids text[] not null,
+  seats integer not null default 1,
+  destination text not null default '',
+  days text[] not null,
+  departure text not null,
+  timezone text not null default '',
+  created_at timestamptz not null default now(),
+  last_run_at timestamptz
+);
+
+create index if not exists idx_driver_route_templates_driver on driver_route_templates (driver_id);
+
+create table if not exists trip_ratings (
+  trip_id text not null,
+  rater_id text not null,
+  rater_role text not null,
+  subject_id text not null,
+  stars integer not null check (stars between 1 and 5),
+  tags text[] not null default '{}',
+  comment text not null default '',
+  created_at timestamptz not null default now(),
+  primary key (trip_id, rater_id)
+);
+
+create index if not exists idx_trip_ratings_subject on trip_ratings (subject_id);
+
 create table if not exists driver_route_pickups (
   route_id uuid references driver_routes(id) on delete cascade,
   sequence integer not null,
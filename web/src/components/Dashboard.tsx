@@ -5,7 +5,7 @@ import { MapPin, Navigation, Users, LogOut, Power, Search, PlayCircle } from 'lu
 import { MapContainer, TileLayer, Marker, Popup, Polyline, useMap } from 'react-leaflet';
 import 'leaflet/dist/leaflet.css';
 import L from 'leaflet';
-import { acceptDriverRequest, bookRide, fetchDriverRequests, fetchPickupPoints, fetchSnapshot, saveDriverRoute, startDriverTrip } from '../lib/backend';
+import { acceptDriverRequest, bookRide, fetchDriverRequests, fetchPickupPoints, fetchSnapshot, saveDriverRoute, startDriverTrip, watchMatches, watchPresence } from '../lib/backend';
 import type { BookRideResponse, Driver, DriverRequestsResponse, PickupPoint, TripStatusPayload } from '../lib/types';
 import { DESTINATIONS } from '../lib/destinations';
 import { DriversMap } from './DriversMap';
@@ -175,7 +175,7 @@ export function Dashboard() {
 
         watchIdRef.current = navigator.geolocation.watchPosition(
             async (position) => {
-                const { latitude, longitude } = position.coords;
+                const { latitude, longitude, speed, heading, accuracy } = position.coords;
                 setLocation({ lat: latitude, lng: longitude });
 
                 // Send update to backend if driver
@@ -190,6 +190,10 @@ export function Dashboard() {
                                 driverId: user?.id,
                                 latitude,
                                 longitude,
+                                recordedAt: new Date(position.timestamp).toISOString(),
+                                speedMps: speed ?? 0,
+                                headingDegrees: heading ?? 0,
+                                accuracyMeters: accuracy,
                             }),
                         });
                     } catch (err) {
@@ -288,9 +292,22 @@ export function Dashboard() {
 
         loadSnapshot();
         const interval = setInterval(loadSnapshot, 15000);
+        // Refresh as soon as a match is made, rejected or expires instead of waiting for the next poll.
+        const stopWatching = watchMatches(() => {
+            loadSnapshot();
+        });
+        const stopPresence = watchPresence((change) => {
+            setDrivers((current) =>
+                current.map((driver) =>
+                    driver.id === change.driverId ? { ...driver, presence: change.state, lastSeenAt: change.lastSeenAt } : driver,
+                ),
+            );
+        });
         return () => {
             cancelled = true;
             clearInterval(interval);
+            stopWatching();
+            stopPresence();
         };
     }, []);
 
@@ -1,4 +1,11 @@
-import type { Driver } from '../lib/types';
+import type { Driver, DriverPresenceState } from '../lib/types';
+
+const presenceStyles: Record<DriverPresenceState, string> = {
+  online: 'bg-emerald-500/10 text-emerald-300',
+  idle: 'bg-sky-500/10 text-sky-300',
+  stale: 'bg-amber-500/10 text-amber-300',
+  offline: 'bg-slate-700/40 text-slate-400',
+};
 
 interface DriverCardProps {
   driver: Driver;
@@ -12,9 +19,16 @@ export function DriverCard({ driver }: DriverCardProps) {
           <p className="text-base font-semibold text-white">{driver.name}</p>
           <p className="text-sm text-slate-400">{driver.carDetails}</p>
         </div>
-        <span className="px-3 py-1 rounded-full text-xs font-semibold bg-emerald-500/10 text-emerald-300">
-          {driver.status.replace('_', ' ')}
-        </span>
+        <div className="flex items-center gap-2">
+          {driver.presence && (
+            <span className={`px-3 py-1 rounded-full text-xs font-semibold ${presenceStyles[driver.presence]}`}>
+              {driver.presence}
+            </span>
+          )}
+          <span className="px-3 py-1 rounded-full text-xs font-semibold bg-emerald-500/10 text-emerald-300">
+            {driver.status.replace('_', ' ')}
+          </span>
+        </div>
       </div>
 
       <div className="grid grid-cols-2 gap-4 mt-4 text-sm text-slate-300">
@@ -24,7 +38,7 @@ export function DriverCard({ driver }: DriverCardProps) {
         </div>
         <div>
           <p className="text-slate-500 text-xs uppercase tracking-wide">ETA</p>
-          <p className="font-medium text-white">{driver.etaMinutes} min</p>
+          <p className="font-medium text-white">{driver.etaMinutes || '--'} min</p>
         </div>
         <div>
           <p className="text-slate-500 text-xs uppercase tracking-wide">Seats</p>
@@ -30,7 +30,7 @@ export function DriversMap({ drivers, userLocation }: DriversMapProps) {
             <Popup>
               <div className="text-sm text-slate-800">
                 <p className="font-semibold">{driver.name}</p>
-                <p>ETA: {driver.etaMinutes} min</p>
+                <p>ETA: {driver.etaMinutes || '--'} min</p>
                 <p>Seats: {driver.seatsAvailable}</p>
               </div>
             </Popup>
@@ -2,11 +2,29 @@ import type {
   BackendSnapshot,
   BookRidePayload,
   BookRideResponse,
+  DriverAnomalies,
+  DriverPresence,
   DriverRequestsResponse,
   DriverRoutePayload,
   DriverRouteResponse,
+  DriverStatus,
+  DriverStatusUpdate,
+  DriverVehicles,
+  LocationStreamFilter,
+  LocationUpdate,
+  MatchEvent,
   PickupPoint,
+  Rating,
+  RatingPayload,
+  RatingsResponse,
+  RouteTemplate,
+  RouteTemplatePayload,
+  TraceReplayStatus,
+  TraceSource,
   Trip,
+  Vehicle,
+  VehiclePayload,
+  TripTrack,
 } from './types';
 import { pickupCatalog } from './pickupCatalog';
 
@@ -55,6 +73,56 @@ export async function fetchSnapshot(): Promise<BackendSnapshot> {
   return request<BackendSnapshot>('/aggregates/snapshot');
 }
 
+// watchMatches subscribes to the gateway's match feed over Server-Sent Events.
+// Pass station ids to narrow the feed; the returned function closes it.
+export function watchMatches(onEvent: (event: MatchEvent) => void, stationIds: string[] = []): () => void {
+  const query = stationIds.length > 0 ? `?stationIds=${encodeURIComponent(stationIds.join(','))}` : '';
+  const source = new EventSource(`${baseUrl}${decoratePath(`/matching/watch${query}`)}`);
+  const handle = (message: MessageEvent) => {
+    try {
+      onEvent(JSON.parse(message.data) as MatchEvent);
+    } catch (err) {
+      console.warn('invalid match event', err);
+    }
+  };
+  ['match_created', 'match_rejected', 'rider_expired'].forEach((type) => source.addEventListener(type, handle));
+  return () => source.close();
+}
+
+// watchLocations follows driver positions over one WebSocket; the returned
+// function closes it.
+export function watchLocations(onUpdate: (update: LocationUpdate) => void, filter: LocationStreamFilter = {}): () => void {
+  const params = new URLSearchParams();
+  if (filter.driverIds?.length) params.set('driverIds', filter.driverIds.join(','));
+  if (filter.stationIds?.length) params.set('stationIds', filter.stationIds.join(','));
+  if (filter.bbox) params.set('bbox', filter.bbox.join(','));
+  const query = params.toString();
+  const origin = baseUrl.startsWith('http') ? baseUrl : `${window.location.origin}${baseUrl}`;
+  const socket = new WebSocket(`${origin.replace(/^http/, 'ws')}${decoratePath(`/location/stream${query ? `?${query}` : ''}`)}`);
+  socket.onmessage = (message) => {
+    try {
+      onUpdate(JSON.parse(message.data) as LocationUpdate);
+    } catch (err) {
+      console.warn('invalid location update', err);
+    }
+  };
+  return () => socket.close();
+}
+
+// watchPresence subscribes to driver presence changes over Server-Sent Events;
+// the returned function closes it.
+export function watchPresence(onChange: (presence: DriverPresence) => void): () => void {
+  const source = new EventSource(`${baseUrl}${decoratePath('/drivers/presence/watch')}`);
+  source.addEventListener('driver_presence', (message: MessageEvent) => {
+    try {
+      onChange(JSON.parse(message.data) as DriverPresence);
+    } catch (err) {
+      console.warn('invalid presence event', err);
+    }
+  });
+  return () => source.close();
+}
+
 export async function fetchDriverRequests(driverId: string): Promise<DriverRequestsResponse> {
   return request<DriverRequestsResponse>(`/drivers/requests?driverId=${driverId}`);
 }
@@ -90,9 +158,118 @@ export async function startDriverTrip(driverId: string, simulate?: boolean): Pro
   });
 }
 
+export async function fetchRouteTemplates(driverId?: string): Promise<RouteTemplate[]> {
+  const query = driverId ? `?driverId=${encodeURIComponent(driverId)}` : '';
+  return request<RouteTemplate[]>(`/drivers/schedules${query}`);
+}
+
+// saveRouteTemplate stores a recurring route; sending an existing id
+// replaces that template.
+export async function saveRouteTemplate(payload: RouteTemplatePayload): Promise<RouteTemplate> {
+  return request<RouteTemplate>('/drivers/schedules', {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  });
+}
+
+export async function deleteRouteTemplate(id: string): Promise<void> {
+  await request(`/drivers/schedules?id=${encodeURIComponent(id)}`, { method: 'DELETE' });
+}
+
+// setDriverStatus takes a driver online or offline. The gateway answers 409
+// when the driver service refuses the change.
+export async function setDriverStatus(driverId: string, status: DriverStatus, reason?: string): Promise<DriverStatusUpdate> {
+  return request<DriverStatusUpdate>('/drivers/status', {
+    method: 'POST',
+    body: JSON.stringify({ driverId, status, reason }),
+  });
+}
+
+export async function fetchDriverVehicles(driverId?: string): Promise<DriverVehicles> {
+  const query = driverId ? `?driverId=${encodeURIComponent(driverId)}` : '';
+  return request<DriverVehicles>(`/drivers/vehicles${query}`);
+}
+
+// registerVehicle registers a vehicle and links it to the driver; a
+// driver's first vehicle becomes the one they drive.
+export async function registerVehicle(payload: VehiclePayload): Promise<Vehicle> {
+  return request<Vehicle>('/drivers/vehicles', {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  });
+}
+
+export async function selectVehicle(driverId: string, vehicleId: string): Promise<Vehicle> {
+  return request<Vehicle>('/drivers/vehicles/select', {
+    method: 'POST',
+    body: JSON.stringify({ driverId, vehicleId }),
+  });
+}
+
+// submitRating rates the other party of a completed trip; each party rates
+// a trip once.
+export async function submitRating(payload: RatingPayload): Promise<Rating> {
+  return request<Rating>('/trips/ratings', {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  });
+}
+
+export async function fetchRatings(filter: { tripId?: string; driverId?: string; riderId?: string }): Promise<RatingsResponse> {
+  const params = new URLSearchParams();
+  Object.entries(filter).forEach(([key, value]) => {
+    if (value) {
+      params.set(key, value);
+    }
+  });
+  return request<RatingsResponse>(`/trips/ratings?${params.toString()}`);
+}
+
 export async function acceptDriverRequest(payload: { driverId: string; riderId: string }): Promise<Trip> {
   return request<Trip>('/drivers/requests/accept', {
     method: 'POST',
     body: JSON.stringify(payload),
   });
 }
+
+export async function fetchTripTrack(tripId: string): Promise<TripTrack> {
+  return request<TripTrack>(`/trips/track?tripId=${tripId}`);
+}
+
+export async function fetchLocationAnomalies(driverId?: string): Promise<DriverAnomalies[]> {
+  return request<DriverAnomalies[]>(driverId ? `/location/anomalies?driverId=${driverId}` : '/location/anomalies');
+}
+
+// replayTrace replays a recorded drive as the trip's driver. GPX and CSV
+// traces go in the body; the history source replays a recorded track.
+export async function replayTrace(
+  tripId: string,
+  source: TraceSource,
+  options: { trace?: string; speed?: number; driverId?: string; sourceTripId?: string; from?: string; to?: string } = {},
+): Promise<TraceReplayStatus> {
+  const params = new URLSearchParams({ tripId, trace: source });
+  if (options.speed) params.set('speed', String(options.speed));
+  if (options.driverId) params.set('driverId', options.driverId);
+  if (options.sourceTripId) params.set('sourceTripId', options.sourceTripId);
+  if (options.from) params.set('from', options.from);
+  if (options.to) params.set('to', options.to);
+  return request<TraceReplayStatus>(`/trips/simulate?${params.toString()}`, {
+    method: 'POST',
+    body: options.trace,
+  });
+}
+
+// controlTraceReplay pauses, resumes or seeks (offset in trace seconds) a replay.
+export async function controlTraceReplay(
+  tripId: string,
+  action: 'pause' | 'resume' | 'seek',
+  offsetSeconds?: number,
+): Promise<TraceReplayStatus> {
+  const params = new URLSearchParams({ tripId, action });
+  if (action === 'seek' && offsetSeconds !== undefined) params.set('offset', `${offsetSeconds}s`);
+  return request<TraceReplayStatus>(`/trips/simulate?${params.toString()}`, { method: 'POST' });
+}
+
+export async function fetchTraceReplay(tripId: string): Promise<TraceReplayStatus> {
+  return request<TraceReplayStatus>(`/trips/simulate?tripId=${tripId}`);
+}
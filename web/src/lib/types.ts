@@ -4,6 +4,8 @@ export type BackendMetrics = {
   seatsOpen: number;
   avgWaitMinutes: number;
   version: string;
+  matchWindowMinutes?: number;
+  ridersDeferred?: number;
 };
 
 export type PickupPoint = {
@@ -28,10 +30,62 @@ export type Driver = {
   carDetails: string;
   seatsAvailable: number;
   etaMinutes: number;
-  status: string;
+  status: DriverStatus | '';
   route: GatewayRoute;
   latitude?: number;
   longitude?: number;
+  presence?: DriverPresenceState;
+  lastSeenAt?: string;
+  vehicle?: Vehicle;
+  rating?: RatingSummary;
+};
+
+export type Vehicle = {
+  id: string;
+  plate: string;
+  make?: string;
+  model?: string;
+  colour?: string;
+  maxSeats: number;
+  airConditioned: boolean;
+  wheelchairAccessible: boolean;
+  luggageSpace: boolean;
+};
+
+export type VehiclePayload = Omit<Vehicle, 'id'> & { id?: string; driverId: string };
+
+export type DriverVehicles = {
+  driverId?: string;
+  activeVehicleId?: string;
+  vehicles: Vehicle[];
+};
+
+// TripVehicle is what a rider is shown about the car coming for them.
+export type TripVehicle = {
+  plate: string;
+  colour?: string;
+  make?: string;
+  model?: string;
+};
+
+export type DriverStatus = 'offline' | 'available' | 'en_route_to_pickup' | 'full';
+
+export type DriverStatusUpdate = {
+  driverId: string;
+  status: DriverStatus;
+  previous: DriverStatus | '';
+  reason?: string;
+  changedAt: string;
+};
+
+export type DriverPresenceState = 'online' | 'idle' | 'stale' | 'offline';
+
+export type DriverPresence = {
+  driverId: string;
+  state: DriverPresenceState;
+  previous?: DriverPresenceState;
+  connected: boolean;
+  lastSeenAt: string;
 };
 
 export type Rider = {
@@ -43,6 +97,7 @@ export type Rider = {
   status: string;
   pickupPointId?: string;
   pickup?: PickupPoint;
+  rating?: RatingSummary;
 };
 
 export type Trip = {
@@ -58,6 +113,73 @@ export type Trip = {
   createdAt?: string;
   completedAt?: string;
   roomId?: string;
+  journeyId?: string;
+  vehicle?: TripVehicle;
+};
+
+export type RatingSummary = {
+  average: number;
+  count: number;
+};
+
+export type Rating = {
+  tripId: string;
+  raterId: string;
+  raterRole: 'driver' | 'rider';
+  subjectId: string;
+  stars: number;
+  tags?: string[];
+  comment?: string;
+  createdAt: string;
+};
+
+export type RatingPayload = {
+  tripId: string;
+  raterId: string;
+  stars: number;
+  tags?: string[];
+  comment?: string;
+};
+
+// RatingPrompt arrives as trip:rating-request once a trip is completed.
+export type RatingPrompt = {
+  tripId: string;
+  raterId: string;
+  raterRole: 'driver' | 'rider';
+  subjectId: string;
+  subjectName: string;
+  tags: string[];
+};
+
+export type RatingsResponse = {
+  summary?: RatingSummary;
+  ratings: Rating[];
+};
+
+export type TripLeg = {
+  tripId: string;
+  riderId: string;
+  pickupPointId?: string;
+  pickup?: PickupPoint;
+  dropoffStationId?: string;
+  destination?: string;
+  status: string;
+  matchedAt: string;
+  pickedUpAt?: string;
+  droppedOffAt?: string;
+};
+
+export type Journey = {
+  id: string;
+  driverId: string;
+  stationId?: string;
+  status: 'active' | 'completed';
+  legs: TripLeg[];
+  driverLatitude?: number;
+  driverLongitude?: number;
+  createdAt: string;
+  updatedAt: string;
+  completedAt?: string;
 };
 
 export type Station = {
@@ -69,6 +191,17 @@ export type Station = {
   longitude?: number;
 };
 
+export type MatchEvent = {
+  type: 'match_created' | 'match_rejected' | 'rider_expired';
+  stationId: string;
+  driverId?: string;
+  riderId?: string;
+  trip?: Trip;
+  reason?: string;
+  occurredAt: string;
+  source: 'gateway' | 'matching';
+};
+
 export type BackendSnapshot = {
   drivers: Driver[];
   riders: Rider[];
@@ -84,6 +217,7 @@ export type DriverSummary = {
   name: string;
   seatsAvailable: number;
   nextStop: string;
+  etaMinutes: number;
 };
 
 export type DriverRequest = {
@@ -95,6 +229,8 @@ export type DriverRequest = {
   pickup?: PickupPoint;
   status: string;
   distanceMeters: number;
+  etaMinutes?: number;
+  inWindow?: boolean;
 };
 
 export type DriverRequestsResponse = {
@@ -103,12 +239,22 @@ export type DriverRequestsResponse = {
   generatedAt: string;
 };
 
+export type ScoreFactor = {
+  name: string;
+  value: number;
+  weight: number;
+  contribution: number;
+};
+
 export type DriverAttempt = {
   driverId: string;
   driverName: string;
   distanceMeters: number;
   accepted: boolean;
   reason?: string;
+  policy?: string;
+  score?: number;
+  scoreBreakdown?: ScoreFactor[];
 };
 
 export type BookRidePayload = {
@@ -129,6 +275,7 @@ export type BookRideResponse = {
   pickup?: PickupPoint;
   requestedDestination: string;
   attempts: DriverAttempt[];
+  skipped?: DriverAttempt[];
   trip?: Trip;
 };
 
@@ -139,6 +286,7 @@ export type DriverRoutePayload = {
   pickupPointIds: string[];
   seats: number;
   destination?: string;
+  vehicleId?: string;
 };
 
 export type DriverRouteResponse = {
@@ -148,8 +296,30 @@ export type DriverRouteResponse = {
   seatsAvailable: number;
   targetStations: string[];
   destination: string;
+  vehicle?: Vehicle;
+};
+
+export type Weekday = 'sun' | 'mon' | 'tue' | 'wed' | 'thu' | 'fri' | 'sat';
+
+// RouteTemplate is a route the gateway starts for the driver on each of
+// days at departure (HH:MM, in timezone or the gateway's).
+export type RouteTemplate = {
+  id: string;
+  driverId: string;
+  name?: string;
+  pickupPointIds: string[];
+  seats: number;
+  destination?: string;
+  days: Weekday[];
+  departure: string;
+  timezone?: string;
+  createdAt: string;
+  nextRunAt: string;
+  lastRunAt?: string;
 };
 
+export type RouteTemplatePayload = Omit<RouteTemplate, 'id' | 'createdAt' | 'nextRunAt' | 'lastRunAt'> & { id?: string };
+
 export type TripStatusPayload = {
   tripId: string;
   status: string;
@@ -160,7 +330,71 @@ export type TripStatusPayload = {
   latitude?: number;
   longitude?: number;
   recordedAt: string;
+  etaMinutes?: number;
   description?: string;
   trip?: Trip;
   rider?: Rider;
 };
+
+export type TripTrack = {
+  type: 'Feature';
+  geometry: {
+    type: 'LineString';
+    coordinates: [number, number][];
+  };
+  properties: {
+    tripId: string;
+    driverId: string;
+    status: string;
+    distanceMeters: number;
+    timestamps: string[];
+  };
+};
+
+export type LocationUpdate = {
+  driverId: string;
+  latitude: number;
+  longitude: number;
+  recordedAt?: string;
+  speedMps: number;
+  headingDegrees: number;
+  accuracyMeters?: number;
+  // Set on the position that took the driver out of a watched area.
+  leftArea?: boolean;
+};
+
+// LocationStreamFilter narrows /location/stream; a position is sent when it
+// matches any filter, and an empty filter follows the whole fleet.
+export type LocationStreamFilter = {
+  driverIds?: string[];
+  stationIds?: string[];
+  // [minLon, minLat, maxLon, maxLat]
+  bbox?: [number, number, number, number];
+};
+
+export type DriverAnomalies = {
+  driverId: string;
+  teleports: number;
+  reanchors: number;
+  outOfOrder: number;
+  lowAccuracy: number;
+  invalid: number;
+  lastKind: string;
+  lastAt: string;
+};
+
+export type TraceSource = 'gpx' | 'csv' | 'history';
+
+export type TraceReplayState = 'playing' | 'paused' | 'finished' | 'stopped';
+
+export type TraceReplayStatus = {
+  tripId: string;
+  driverId: string;
+  source: TraceSource;
+  state: TraceReplayState;
+  speed: number;
+  played: number;
+  points: number;
+  offsetSeconds: number;
+  durationSeconds: number;
+};